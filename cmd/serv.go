@@ -1,7 +1,16 @@
 package cmd
 
 import (
+	"WarpCloud/walm/pkg/audit"
+	auditfile "WarpCloud/walm/pkg/audit/file"
+	auditredis "WarpCloud/walm/pkg/audit/redis"
+	authPkg "WarpCloud/walm/pkg/auth"
+	clusterhttp "WarpCloud/walm/pkg/cluster/delivery/http"
+	clusterredis "WarpCloud/walm/pkg/cluster/redis"
 	migrationhttp "WarpCloud/walm/pkg/crd/migration/delivery/http"
+	"WarpCloud/walm/pkg/event"
+	eventkafka "WarpCloud/walm/pkg/event/kafka"
+	eventwebhook "WarpCloud/walm/pkg/event/webhook"
 	helmImpl "WarpCloud/walm/pkg/helm/impl"
 	cacheInformer "WarpCloud/walm/pkg/k8s/cache/informer"
 	"WarpCloud/walm/pkg/k8s/client"
@@ -9,26 +18,43 @@ import (
 	"WarpCloud/walm/pkg/k8s/elect"
 	"WarpCloud/walm/pkg/k8s/operator"
 	kafkaimpl "WarpCloud/walm/pkg/kafka/impl"
+	walmLog "WarpCloud/walm/pkg/log"
+	loghttp "WarpCloud/walm/pkg/log/delivery/http"
+	"WarpCloud/walm/pkg/metrics"
 	httpModel "WarpCloud/walm/pkg/models/http"
+	projectModel "WarpCloud/walm/pkg/models/project"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
 	nodehttp "WarpCloud/walm/pkg/node/delivery/http"
 	podhttp "WarpCloud/walm/pkg/pod/delivery/http"
 	projectcache "WarpCloud/walm/pkg/project/cache/redis"
 	projecthttp "WarpCloud/walm/pkg/project/delivery/http"
 	projectusecase "WarpCloud/walm/pkg/project/usecase"
 	pvchttp "WarpCloud/walm/pkg/pvc/delivery/http"
+	"WarpCloud/walm/pkg/ratelimit"
 	redisclient "WarpCloud/walm/pkg/redis"
 	"WarpCloud/walm/pkg/redis/impl"
 	releasecache "WarpCloud/walm/pkg/release/cache/redis"
 	releaseconfig "WarpCloud/walm/pkg/release/config"
 	releasehttp "WarpCloud/walm/pkg/release/delivery/http"
 	releaseusecase "WarpCloud/walm/pkg/release/usecase/helm"
+	"WarpCloud/walm/pkg/requestid"
+	"WarpCloud/walm/pkg/schedule"
+	schedulehttp "WarpCloud/walm/pkg/schedule/delivery/http"
+	scheduleredis "WarpCloud/walm/pkg/schedule/redis"
 	secrethttp "WarpCloud/walm/pkg/secret/delivery/http"
+	"WarpCloud/walm/pkg/semaphore"
 	"WarpCloud/walm/pkg/setting"
+	settinghttp "WarpCloud/walm/pkg/setting/delivery/http"
 	storageclasshttp "WarpCloud/walm/pkg/storageclass/delivery/http"
 	"WarpCloud/walm/pkg/sync"
+	taskPkg "WarpCloud/walm/pkg/task"
+	taskhttp "WarpCloud/walm/pkg/task/delivery/http"
 	"WarpCloud/walm/pkg/task/machinery"
 	tenanthttp "WarpCloud/walm/pkg/tenant/delivery/http"
 	tenantusecase "WarpCloud/walm/pkg/tenant/usecase"
+	"WarpCloud/walm/pkg/trace"
+	"WarpCloud/walm/pkg/util"
 	httpUtils "WarpCloud/walm/pkg/util/http"
 	"bytes"
 	"context"
@@ -138,8 +164,16 @@ func (sc *ServCmd) run() error {
 	}
 	config := &setting.Config
 	initLogLevel()
+	util.SetSensitiveKeyPatterns(config.SensitiveKeyPatterns)
 	stopChan := make(chan struct{})
 
+	traceCloser, err := trace.Init(config.TraceConfig)
+	if err != nil {
+		klog.Errorf("failed to init tracer : %s", err.Error())
+		return err
+	}
+	defer traceCloser.Close()
+
 	kubeConfig := ""
 	if config.KubeConfig != nil {
 		kubeConfig = config.KubeConfig.Config
@@ -241,7 +275,15 @@ func (sc *ServCmd) run() error {
 		klog.Errorf("failed to create helm manager: %s", err.Error())
 		return err
 	}
-	k8sOperator := operator.NewOperator(k8sClient, k8sCache, kubeClients, k8sMigrationClient)
+	k8sOperator := operator.NewOperator(k8sClient, k8sCache, kubeClients, k8sReleaseConfigClient)
+	if config.AuthConfig != nil && config.AuthConfig.Enable && config.AuthConfig.Impersonate {
+		impersonatingClients, err := client.NewImpersonatingClientFactory("", kubeConfig)
+		if err != nil {
+			klog.Errorf("failed to create impersonating kube client factory : %s", err.Error())
+			return err
+		}
+		k8sOperator.SetImpersonatingClientFactory(impersonatingClients)
+	}
 	if config.RedisConfig == nil {
 		err = errors.New("redis config can not be empty")
 		klog.Error(err.Error())
@@ -282,12 +324,39 @@ func (sc *ServCmd) run() error {
 		klog.Errorf("failed to new release use case : %s", err.Error())
 		return err
 	}
+	// one namespace can not occupy all the task workers at once : the heavy
+	// release tasks share a per namespace budget tracked in redis, enabled by
+	// the concurrency config or the tenant annotation
+	releaseUseCase.SetNamespaceSemaphore(semaphore.NewSemaphore(redis))
 	projectCache := projectcache.NewProjectCache(redis)
-	projectUseCase, err := projectusecase.NewProject(projectCache, task, releaseUseCase, helm)
+	projectUseCase, err := projectusecase.NewProject(projectCache, task, releaseUseCase, helm, k8sCache, redis)
 	if err != nil {
 		klog.Errorf("failed to new project use case : %s", err.Error())
 		return err
 	}
+	if config.AuditConfig != nil && config.AuditConfig.Enable {
+		var auditWriter audit.Writer
+		if config.AuditConfig.Backend == "redis" {
+			auditWriter = auditredis.NewWriter(redisClient, config.AuditConfig.Stream, config.AuditConfig.MaxLen)
+		} else {
+			auditWriter, err = auditfile.NewWriter(config.AuditConfig.FilePath, config.AuditConfig.MaxSizeBytes)
+			if err != nil {
+				klog.Errorf("failed to create audit writer : %s", err.Error())
+				return err
+			}
+		}
+		releaseUseCase.SetAuditWriter(auditWriter)
+		projectUseCase.SetAuditWriter(auditWriter)
+	}
+	// mutation requests carrying a scheduledAt timestamp are recorded here,
+	// the leader submits them through the normal task path when their time
+	// comes
+	scheduleStore := scheduleredis.NewStore(redis)
+	releaseUseCase.SetScheduleStore(scheduleStore)
+	projectUseCase.SetScheduleStore(scheduleStore)
+	scheduler := schedule.NewScheduler(scheduleStore, 0)
+	registerScheduledOperationExecutors(scheduler, releaseUseCase, projectUseCase)
+	stateRefresher := metrics.NewStateRefresher(releaseCache, projectUseCase, 0)
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	go func() {
@@ -305,16 +374,50 @@ func (sc *ServCmd) run() error {
 		return err
 	}
 	releaseConfigController := releaseconfig.NewReleaseConfigController(k8sCache, releaseUseCase, kafka, 0)
+	var eventPublishers event.Publishers
+	if config.KafkaConfig != nil && config.KafkaConfig.Enable &&
+		(config.KafkaConfig.ReleaseEventsTopic != "" || config.KafkaConfig.ProjectEventsTopic != "") {
+		eventPublishers = append(eventPublishers, eventkafka.NewPublisher(kafka, config.KafkaConfig, stopChan))
+	}
+	if config.EventWebhookConfig != nil && len(config.EventWebhookConfig.Webhooks) > 0 {
+		eventPublishers = append(eventPublishers, eventwebhook.NewNotifier(config.EventWebhookConfig, projectCache.GetProjectWebhooks, stopChan))
+	}
+	if len(eventPublishers) > 0 {
+		releaseUseCase.SetEventPublisher(eventPublishers)
+		projectUseCase.SetEventPublisher(eventPublishers)
+		stateRefresher.SetEventPublisher(eventPublishers, releaseUseCase)
+	}
+
+	// release and project operations can target the clusters registered here,
+	// the default cluster keeps working without a registry
+	var clusterRegistry *clusterredis.Registry
+	if config.ClusterRegistryConfig != nil {
+		clusterRegistry, err = clusterredis.NewRegistry(redis, config.ClusterRegistryConfig)
+		if err != nil {
+			klog.Errorf("failed to create cluster registry : %s", err.Error())
+			return err
+		}
+		helm.SetClusterRegistry(clusterRegistry, func(kubeConfigPath string) *k8sHelm.Client {
+			return k8sHelm.NewHelmKubeClient(kubeConfigPath, "", k8sInstanceClient)
+		})
+	}
+	// the singleton background loops only run on the leader, the task workers
+	// and the http handlers stay active on every replica
 	onStartedLeadingFunc := func(context context.Context) {
 		klog.Info("Succeed to elect leader")
+		metrics.SetIsLeader(true)
 		syncManager.Start(context.Done())
 		releaseConfigController.Start(context.Done())
+		go stateRefresher.Start(context.Done())
+		go scheduler.Start(context.Done())
+		releaseUseCase.StartPvcReaper(context.Done())
 	}
 	onNewLeaderFunc := func(identity string) {
 		klog.Infof("Now leader is changed to %s", identity)
 	}
 	onStoppedLeadingFunc := func() {
 		klog.Info("Stopped being a leader")
+		metrics.SetIsLeader(false)
 		sig <- syscall.SIGINT
 	}
 
@@ -358,24 +461,55 @@ func (sc *ServCmd) run() error {
 	// faster router
 	restful.DefaultContainer.Router(restful.CurlyRouter{})
 	restful.Filter(ServerStatsFilter)
+	restful.Filter(requestid.Filter)
+	restful.Filter(trace.Filter)
 	if err = recordLoggingInit(setting.Config.LogConfig.LogDir); err != nil {
 		return err
 	}
 	restful.Filter(RouteLogging)
+	// the auth filter only guards the api routes, so the probe, metrics and
+	// swagger endpoints stay open
+	restful.Filter(authPkg.NewAuth(config.AuthConfig).Filter)
+	limiter := ratelimit.NewLimiter(config.RateLimitConfig)
+	restful.Filter(limiter.Filter)
+
+	// the reload-safe subset of the config (repo list, log levels, rate limits)
+	// is picked up from the config file without a restart, the rest is rejected
+	// with a logged warning
+	configWatcher := setting.NewConfigWatcher(sc.cfgFile)
+	configWatcher.AddReloadHook(func(newConfig *setting.WalmConfig) {
+		initLogLevel()
+		limiter.Reload(newConfig.RateLimitConfig)
+		helm.ReloadRepoList(newConfig.RepoList)
+	})
+	configWatcher.Start(stopChan)
+
 	klog.Infoln("Adding Route...")
 
-	restful.Add(InitRootRouter(NewRootHandler(k8sClient, redisClient, helm)))
+	restful.Add(InitRootRouter(NewRootHandler(k8sClient, redisClient, helm, task, elector)))
 	restful.Add(nodehttp.RegisterNodeHandler(k8sCache, k8sOperator))
 	restful.Add(migrationhttp.RegisterCrdHandler(k8sCache, k8sOperator))
 	restful.Add(secrethttp.RegisterSecretHandler(k8sCache, k8sOperator))
 	restful.Add(storageclasshttp.RegisterStorageClassHandler(k8sCache))
 	restful.Add(pvchttp.RegisterPvcHandler(k8sCache, k8sOperator))
-	tenantUseCase := tenantusecase.NewTenant(k8sCache, k8sOperator, releaseUseCase)
+	tenantUseCase := tenantusecase.NewTenant(k8sCache, k8sOperator, releaseUseCase, projectUseCase)
+	go tenantUseCase.StartQuotaPressureChecker(stopChan)
 	restful.Add(tenanthttp.RegisterTenantHandler(tenantUseCase))
-	restful.Add(projecthttp.RegisterProjectHandler(projecthttp.NewProjectHandler(projectUseCase)))
-	restful.Add(releasehttp.RegisterReleaseHandler(releasehttp.NewReleaseHandler(releaseUseCase)))
+	projectHandler := projecthttp.NewProjectHandler(projectUseCase, redis)
+	projectHandler.SetScheduleStore(scheduleStore)
+	restful.Add(projecthttp.RegisterProjectHandler(projectHandler))
+	releaseHandler := releasehttp.NewReleaseHandler(releaseUseCase, k8sCache)
+	releaseHandler.SetScheduleStore(scheduleStore)
+	restful.Add(releasehttp.RegisterReleaseHandler(releaseHandler))
+	restful.Add(schedulehttp.RegisterScheduledOperationHandler(schedulehttp.NewScheduledOperationHandler(scheduleStore)))
+	if clusterRegistry != nil {
+		restful.Add(clusterhttp.RegisterClusterHandler(clusterhttp.NewClusterHandler(clusterRegistry)))
+	}
 	restful.Add(podhttp.RegisterPodHandler(k8sCache, k8sOperator))
 	restful.Add(releasehttp.RegisterChartHandler(helm))
+	restful.Add(taskhttp.RegisterTaskHandler(task))
+	restful.Add(loghttp.RegisterLogHandler())
+	restful.Add(settinghttp.RegisterConfigHandler(configWatcher))
 	klog.Infoln("Add Route Success")
 	restConfig := restfulspec.Config{
 		// You control what services are visible
@@ -427,6 +561,55 @@ func (sc *ServCmd) run() error {
 	return nil
 }
 
+// registerScheduledOperationExecutors wires the scheduled operations to the
+// usecases, each executor replaying the stored mutation request through the
+// normal task path, so the usual per release and per project serialization
+// applies when the operation fires.
+func registerScheduledOperationExecutors(scheduler *schedule.Scheduler, releaseUseCase *releaseusecase.Helm, projectUseCase *projectusecase.Project) {
+	scheduler.RegisterExecutor(scheduleModel.ReleaseKind, scheduleModel.InstallOperation,
+		func(ctx context.Context, operation *scheduleModel.ScheduledOperation) error {
+			releaseRequest := &releaseModel.ReleaseRequestV2{}
+			err := json.Unmarshal([]byte(operation.Args), releaseRequest)
+			if err != nil {
+				klog.Errorf("failed to unmarshal scheduled release request : %s", err.Error())
+				return err
+			}
+			return releaseUseCase.InstallUpgradeRelease(ctx, operation.Namespace, releaseRequest, nil, true, operation.TimeoutSec, nil)
+		})
+	scheduler.RegisterExecutor(scheduleModel.ReleaseKind, scheduleModel.DeleteOperation,
+		func(ctx context.Context, operation *scheduleModel.ScheduledOperation) error {
+			args := &scheduleModel.ScheduledDeleteReleaseArgs{}
+			err := json.Unmarshal([]byte(operation.Args), args)
+			if err != nil {
+				klog.Errorf("failed to unmarshal scheduled delete release args : %s", err.Error())
+				return err
+			}
+			return releaseUseCase.DeleteRelease(ctx, operation.Namespace, operation.Name, args.DeletePvcs, true, operation.TimeoutSec)
+		})
+	scheduler.RegisterExecutor(scheduleModel.ProjectKind, scheduleModel.CreateOperation,
+		func(ctx context.Context, operation *scheduleModel.ScheduledOperation) error {
+			projectParams := &projectModel.ProjectParams{}
+			err := json.Unmarshal([]byte(operation.Args), projectParams)
+			if err != nil {
+				klog.Errorf("failed to unmarshal scheduled project params : %s", err.Error())
+				return err
+			}
+			_, err = projectUseCase.CreateProject(ctx, operation.Namespace, operation.Name, projectParams, true, operation.TimeoutSec, operation.Principal, operation.RequestId)
+			return err
+		})
+	scheduler.RegisterExecutor(scheduleModel.ProjectKind, scheduleModel.DeleteOperation,
+		func(ctx context.Context, operation *scheduleModel.ScheduledOperation) error {
+			args := &scheduleModel.ScheduledDeleteProjectArgs{}
+			err := json.Unmarshal([]byte(operation.Args), args)
+			if err != nil {
+				klog.Errorf("failed to unmarshal scheduled delete project args : %s", err.Error())
+				return err
+			}
+			_, err = projectUseCase.DeleteProject(ctx, operation.Namespace, operation.Name, true, operation.TimeoutSec, args.DeletePvcs, args.Fast, operation.Principal, operation.RequestId)
+			return err
+		})
+}
+
 func RouteLogging(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 	now := time.Now()
 
@@ -457,11 +640,12 @@ func RouteLogging(req *restful.Request, resp *restful.Response, chain *restful.F
 	// logging record
 	if req.Request.Method != "GET" {
 		logrus.WithFields(logrus.Fields{
-			"method":  req.Request.Method,
-			"addr":    req.Request.RemoteAddr,
-			"subPath": req.Request.RequestURI,
-			"body":    string(body),
-			"status":  resp.StatusCode(),
+			"method":    req.Request.Method,
+			"addr":      req.Request.RemoteAddr,
+			"subPath":   req.Request.RequestURI,
+			"body":      string(body),
+			"status":    resp.StatusCode(),
+			"requestId": requestid.GetRequestId(req),
 		}).Info()
 	}
 }
@@ -560,17 +744,89 @@ func livenessProbe(request *restful.Request, response *restful.Response) {
 	response.WriteEntity("OK")
 }
 
+const dependencyCheckTimeout = time.Second * 5
+
+type DependencyStatus struct {
+	Name    string `json:"name" description:"dependency name"`
+	Healthy bool   `json:"healthy" description:"whether the dependency is reachable"`
+	Message string `json:"message,omitempty" description:"failure detail"`
+}
+
+type ReadyzResponse struct {
+	Ready        bool                `json:"ready" description:"whether walm can serve traffic"`
+	Dependencies []*DependencyStatus `json:"dependencies" description:"status per dependency"`
+}
+
+func healthz(request *restful.Request, response *restful.Response) {
+	response.WriteEntity("OK")
+}
+
+// readyz checks the dependencies walm needs to serve traffic, so kubernetes
+// pulls the pod out of rotation instead of every request failing slowly.
+func (handler *RootHandler) readyz(request *restful.Request, response *restful.Response) {
+	readyzResponse := &ReadyzResponse{
+		Ready: true,
+		Dependencies: []*DependencyStatus{
+			checkDependency("redis", func() error {
+				return handler.redisClient.Ping().Err()
+			}),
+			checkDependency("kubernetes", func() error {
+				_, err := handler.k8sClient.Discovery().ServerVersion()
+				return err
+			}),
+			checkDependency("task-broker", func() error {
+				return handler.task.CheckHealth()
+			}),
+		},
+	}
+	for _, dependency := range readyzResponse.Dependencies {
+		if !dependency.Healthy {
+			readyzResponse.Ready = false
+		}
+	}
+	if !readyzResponse.Ready {
+		response.WriteHeaderAndEntity(http.StatusServiceUnavailable, readyzResponse)
+		return
+	}
+	response.WriteEntity(readyzResponse)
+}
+
+// checkDependency runs the check with a short timeout : a dead dependency must
+// not make the probe itself hang.
+func checkDependency(name string, check func() error) *DependencyStatus {
+	status := &DependencyStatus{Name: name, Healthy: true}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- check()
+	}()
+	select {
+	case err := <-errChan:
+		if err != nil {
+			status.Healthy = false
+			status.Message = err.Error()
+		}
+	case <-time.After(dependencyCheckTimeout):
+		status.Healthy = false
+		status.Message = fmt.Sprintf("check timed out after %v", dependencyCheckTimeout)
+	}
+	return status
+}
+
 type RootHandler struct {
 	k8sClient   *kubernetes.Clientset
 	redisClient *redis.Client
 	helm        *helmImpl.Helm
+	task        taskPkg.Task
+	elector     *elect.Elector
 }
 
-func NewRootHandler(k8sClient *kubernetes.Clientset, redisClient *redis.Client, helm *helmImpl.Helm) *RootHandler {
+func NewRootHandler(k8sClient *kubernetes.Clientset, redisClient *redis.Client, helm *helmImpl.Helm, task taskPkg.Task, elector *elect.Elector) *RootHandler {
 	return &RootHandler{
 		k8sClient:   k8sClient,
 		redisClient: redisClient,
 		helm:        helm,
+		task:        task,
+		elector:     elector,
 	}
 }
 func InitRootRouter(handler *RootHandler) *restful.WebService {
@@ -594,12 +850,31 @@ func InitRootRouter(handler *RootHandler) *restful.WebService {
 		Returns(200, "OK", nil).
 		Returns(500, "Internal Error", httpModel.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/healthz").To(healthz).
+		Doc("进程存活检查").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Returns(200, "OK", nil))
+
+	ws.Route(ws.GET("/readyz").To(handler.readyz).
+		Doc("依赖健康检查(redis, kubernetes, 任务队列)").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Writes(ReadyzResponse{}).
+		Returns(200, "OK", ReadyzResponse{}).
+		Returns(503, "Not Ready", ReadyzResponse{}))
+
 	ws.Route(ws.GET("/stats").To(ServerStatsData).
 		Doc("获取服务Stats").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Returns(200, "OK", nil).
 		Returns(500, "Internal Error", httpModel.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/leader").To(handler.leaderStatus).
+		Doc("获取当前Leader信息(运行后台单例任务的副本)").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Writes(LeaderStatus{}).
+		Returns(200, "OK", LeaderStatus{}).
+		Returns(500, "Internal Error", httpModel.ErrorMessageResponse{}))
+
 	ws.Route(ws.GET("/network").To(networkData).
 		Doc("获取服务Network信息(集群服务cluster-ip段和容器网络ip段)").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -608,6 +883,20 @@ func InitRootRouter(handler *RootHandler) *restful.WebService {
 	return ws
 }
 
+// LeaderStatus reports which replica currently runs the singleton background
+// loops.
+type LeaderStatus struct {
+	Leader   string `json:"leader" description:"identity of the current leader"`
+	IsLeader bool   `json:"isLeader" description:"whether this replica is the leader"`
+}
+
+func (handler *RootHandler) leaderStatus(request *restful.Request, response *restful.Response) {
+	response.WriteEntity(&LeaderStatus{
+		Leader:   handler.elector.GetLeader(),
+		IsLeader: handler.elector.IsLeader(),
+	})
+}
+
 func networkData(request *restful.Request, response *restful.Response) {
 	response.WriteEntity(map[string]string{
 		"clusterCIDR": ClusterCIDR,
@@ -620,6 +909,16 @@ func initLogLevel() {
 		if setting.Config.LogConfig.Level == "debug" {
 			pflag.CommandLine.Set("v", "2")
 		}
+		if setting.Config.LogConfig.Level != "" {
+			if err := walmLog.SetDefaultLevel(setting.Config.LogConfig.Level); err != nil {
+				klog.Errorf("failed to set default log level : %s", err.Error())
+			}
+		}
+		for module, level := range setting.Config.LogConfig.ModuleLevels {
+			if err := walmLog.SetLevel(module, level); err != nil {
+				klog.Errorf("failed to set log level of module %s : %s", module, err.Error())
+			}
+		}
 		if setting.Config.LogConfig.LogDir == "" {
 			setting.Config.LogConfig.LogDir = "/var/log"
 		}
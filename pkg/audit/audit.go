@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"WarpCloud/walm/pkg/models/audit"
+)
+
+// Writer appends audit records to an append-only backend. Implementations
+// must be safe for concurrent use, as records are written from the http
+// handlers and the task workers.
+type Writer interface {
+	Write(record *audit.Record) error
+}
@@ -0,0 +1,88 @@
+package file
+
+import (
+	auditModel "WarpCloud/walm/pkg/models/audit"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"os"
+	"sync"
+)
+
+const defaultMaxSizeBytes int64 = 100 * 1024 * 1024
+
+// Writer appends audit records as json lines to a local file. When the file
+// grows over maxSizeBytes it is rotated once to <path>.1, so the audit log
+// disk usage stays capped at roughly twice maxSizeBytes.
+type Writer struct {
+	path         string
+	maxSizeBytes int64
+	mutex        sync.Mutex
+	file         *os.File
+}
+
+func (writer *Writer) Write(record *auditModel.Record) error {
+	recordStr, err := json.Marshal(record)
+	if err != nil {
+		logrus.Errorf("failed to marshal audit record : %s", err.Error())
+		return err
+	}
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	err = writer.rotateIfNeeded(int64(len(recordStr) + 1))
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.file.Write(append(recordStr, '\n'))
+	if err != nil {
+		logrus.Errorf("failed to write audit record to %s : %s", writer.path, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (writer *Writer) rotateIfNeeded(recordSize int64) error {
+	fileInfo, err := writer.file.Stat()
+	if err != nil {
+		logrus.Errorf("failed to stat audit log %s : %s", writer.path, err.Error())
+		return err
+	}
+	if fileInfo.Size()+recordSize <= writer.maxSizeBytes {
+		return nil
+	}
+
+	err = writer.file.Close()
+	if err != nil {
+		logrus.Errorf("failed to close audit log %s : %s", writer.path, err.Error())
+		return err
+	}
+	err = os.Rename(writer.path, writer.path+".1")
+	if err != nil {
+		logrus.Errorf("failed to rotate audit log %s : %s", writer.path, err.Error())
+		return err
+	}
+	writer.file, err = os.OpenFile(writer.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logrus.Errorf("failed to reopen audit log %s : %s", writer.path, err.Error())
+		return err
+	}
+	return nil
+}
+
+func NewWriter(path string, maxSizeBytes int64) (*Writer, error) {
+	if maxSizeBytes <= 0 {
+		maxSizeBytes = defaultMaxSizeBytes
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logrus.Errorf("failed to open audit log %s : %s", path, err.Error())
+		return nil, err
+	}
+	return &Writer{
+		path:         path,
+		maxSizeBytes: maxSizeBytes,
+		file:         file,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package file
+
+import (
+	auditModel "WarpCloud/walm/pkg/models/audit"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter_Write(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "walm-audit-test")
+	assert.IsType(t, nil, err)
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "audit.log")
+	writer, err := NewWriter(path, 300)
+	assert.IsType(t, nil, err)
+
+	record := &auditModel.Record{
+		Time:      time.Now(),
+		Operation: "createProject",
+		Namespace: "test-ns",
+		Name:      "test-name",
+		TaskUUID:  "test-uuid",
+		Succeeded: true,
+	}
+	err = writer.Write(record)
+	assert.IsType(t, nil, err)
+
+	content, err := ioutil.ReadFile(path)
+	assert.IsType(t, nil, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, 1, len(lines))
+
+	writtenRecord := &auditModel.Record{}
+	err = json.Unmarshal([]byte(lines[0]), writtenRecord)
+	assert.IsType(t, nil, err)
+	assert.Equal(t, "createProject", writtenRecord.Operation)
+	assert.Equal(t, "test-ns", writtenRecord.Namespace)
+	assert.Equal(t, "test-uuid", writtenRecord.TaskUUID)
+	assert.Equal(t, true, writtenRecord.Succeeded)
+
+	// the size cap only fits one record, so the next write rotates the file
+	err = writer.Write(record)
+	assert.IsType(t, nil, err)
+
+	_, err = os.Stat(path + ".1")
+	assert.IsType(t, nil, err)
+
+	content, err = ioutil.ReadFile(path)
+	assert.IsType(t, nil, err)
+	lines = strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, 1, len(lines))
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	auditModel "WarpCloud/walm/pkg/models/audit"
+	"encoding/json"
+	"github.com/go-redis/redis"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStream       = "walm-audit"
+	defaultMaxLen int64 = 100000
+)
+
+// Writer appends audit records to a capped redis stream, so the audit log is
+// shared by all the walm replicas and can be consumed incrementally.
+type Writer struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func (writer *Writer) Write(record *auditModel.Record) error {
+	recordStr, err := json.Marshal(record)
+	if err != nil {
+		logrus.Errorf("failed to marshal audit record : %s", err.Error())
+		return err
+	}
+	_, err = writer.client.XAddExt(&redis.XAddExt{
+		Stream:       writer.stream,
+		MaxLenApprox: writer.maxLen,
+		Values:       map[string]interface{}{"record": string(recordStr)},
+	}).Result()
+	if err != nil {
+		logrus.Errorf("failed to write audit record to stream %s : %s", writer.stream, err.Error())
+		return err
+	}
+	return nil
+}
+
+func NewWriter(client *redis.Client, stream string, maxLen int64) *Writer {
+	if stream == "" {
+		stream = defaultStream
+	}
+	if maxLen <= 0 {
+		maxLen = defaultMaxLen
+	}
+	return &Writer{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
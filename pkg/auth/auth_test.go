@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"WarpCloud/walm/pkg/setting"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuth_Filter(t *testing.T) {
+	config := &setting.AuthConfig{
+		Enable: true,
+		Tokens: []*setting.AuthToken{
+			{
+				Token:      "admin-token",
+				Principal:  "admin",
+				Namespaces: []string{"*"},
+				Verbs:      []string{"*"},
+				Groups:     []string{"system:masters"},
+				Privileged: true,
+			},
+			{
+				Token:      "reader-token",
+				Principal:  "reader",
+				Namespaces: []string{"testns"},
+				Verbs:      []string{VerbRead},
+			},
+		},
+	}
+
+	tests := []struct {
+		config     *setting.AuthConfig
+		method     string
+		path       string
+		token      string
+		statusCode int
+		principal  string
+		privileged bool
+	}{
+		{
+			config:     nil,
+			method:     "POST",
+			path:       "/api/v1/project/testns/name/testnm",
+			statusCode: 200,
+			principal:  "",
+			privileged: true,
+		},
+		{
+			config:     config,
+			method:     "POST",
+			path:       "/api/v1/project/testns/name/testnm",
+			statusCode: 401,
+		},
+		{
+			config:     config,
+			method:     "POST",
+			path:       "/api/v1/project/testns/name/testnm",
+			token:      "unknown-token",
+			statusCode: 401,
+		},
+		{
+			config:     config,
+			method:     "POST",
+			path:       "/api/v1/project/testns/name/testnm",
+			token:      "admin-token",
+			statusCode: 200,
+			principal:  "admin",
+			privileged: true,
+		},
+		{
+			config:     config,
+			method:     "GET",
+			path:       "/api/v1/project/testns/name/testnm",
+			token:      "reader-token",
+			statusCode: 200,
+			principal:  "reader",
+		},
+		{
+			config:     config,
+			method:     "POST",
+			path:       "/api/v1/project/testns/name/testnm",
+			token:      "reader-token",
+			statusCode: 403,
+		},
+		{
+			config:     config,
+			method:     "GET",
+			path:       "/api/v1/project/otherns/name/testnm",
+			token:      "reader-token",
+			statusCode: 403,
+		},
+		{
+			// list-all routes resolve to an empty namespace requiring a wildcard grant
+			config:     config,
+			method:     "GET",
+			path:       "/api/v1/project",
+			token:      "reader-token",
+			statusCode: 403,
+		},
+		{
+			// routes out of the api stay open, e.g. the probe endpoints
+			config:     config,
+			method:     "GET",
+			path:       "/readiness",
+			statusCode: 200,
+			principal:  "",
+			privileged: true,
+		},
+	}
+
+	for _, test := range tests {
+		auth := NewAuth(test.config)
+
+		principal := ""
+		privileged := false
+		var groups []string
+		container := restful.NewContainer()
+		container.Filter(auth.Filter)
+		ws := new(restful.WebService)
+		ws.Produces(restful.MIME_JSON)
+		handler := func(request *restful.Request, response *restful.Response) {
+			principal = GetRequestPrincipal(request)
+			privileged = IsRequestPrivileged(request)
+			groups = GetRequestPrincipalGroups(request)
+			response.WriteHeader(200)
+		}
+		ws.Route(ws.GET("/api/v1/project").To(handler))
+		ws.Route(ws.GET("/api/v1/project/{namespace}/name/{project}").To(handler))
+		ws.Route(ws.POST("/api/v1/project/{namespace}/name/{project}").To(handler))
+		ws.Route(ws.GET("/readiness").To(handler))
+		container.Add(ws)
+
+		httpRequest, _ := http.NewRequest(test.method, test.path, nil)
+		if test.token != "" {
+			httpRequest.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		httpWriter := httptest.NewRecorder()
+		container.ServeHTTP(httpWriter, httpRequest)
+
+		assert.Equal(t, test.statusCode, httpWriter.Code)
+		if test.statusCode == 200 {
+			assert.Equal(t, test.principal, principal)
+			assert.Equal(t, test.privileged, privileged)
+			if test.principal == "admin" {
+				assert.Equal(t, []string{"system:masters"}, groups)
+			}
+		}
+	}
+}
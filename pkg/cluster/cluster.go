@@ -0,0 +1,20 @@
+package cluster
+
+import (
+	"WarpCloud/walm/pkg/models/cluster"
+)
+
+// Registry stores the kubernetes clusters release and project operations can
+// be routed to. Kubeconfigs are encrypted at rest, the default cluster is
+// implicit and never stored.
+type Registry interface {
+	// RegisterCluster stores the cluster, overwriting a cluster with the same name.
+	RegisterCluster(cluster *cluster.Cluster) error
+	GetCluster(name string) (*cluster.Cluster, error)
+	ListClusters() (*cluster.ClusterList, error)
+	DeleteCluster(name string) error
+	// GetClusterKubeConfigPath materializes the decrypted kubeconfig of the
+	// cluster as a file and returns its path, for kube clients built from a
+	// kubeconfig path.
+	GetClusterKubeConfigPath(name string) (string, error)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"WarpCloud/walm/pkg/cluster"
+	clusterModel "WarpCloud/walm/pkg/models/cluster"
+	errorModel "WarpCloud/walm/pkg/models/error"
+	"WarpCloud/walm/pkg/models/http"
+	httpUtils "WarpCloud/walm/pkg/util/http"
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful-openapi"
+)
+
+const (
+	clusterRootPath = http.ApiV1 + "/cluster"
+)
+
+type ClusterHandler struct {
+	registry cluster.Registry
+}
+
+func NewClusterHandler(registry cluster.Registry) *ClusterHandler {
+	return &ClusterHandler{registry: registry}
+}
+
+func RegisterClusterHandler(handler *ClusterHandler) *restful.WebService {
+	ws := new(restful.WebService)
+
+	ws.Path(clusterRootPath).
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML)
+
+	tags := []string{"cluster"}
+
+	ws.Route(ws.GET("/").To(handler.ListClusters).
+		Doc("获取已注册的集群列表, kubeconfig用******屏蔽").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Writes(clusterModel.ClusterList{}).
+		Returns(200, "OK", clusterModel.ClusterList{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{cluster}").To(handler.GetCluster).
+		Doc("获取集群详情, kubeconfig用******屏蔽").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("cluster", "集群名字").DataType("string")).
+		Writes(clusterModel.Cluster{}).
+		Returns(200, "OK", clusterModel.Cluster{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.POST("/").To(handler.RegisterCluster).
+		Doc("注册集群, 同名集群会被覆盖, kubeconfig加密存储").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Reads(clusterModel.Cluster{}).
+		Returns(200, "OK", nil).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.DELETE("/{cluster}").To(handler.DeleteCluster).
+		Doc("删除已注册的集群, 不影响集群内已安装的Release").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("cluster", "集群名字").DataType("string")).
+		Returns(200, "OK", nil).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	return ws
+}
+
+func (handler *ClusterHandler) ListClusters(request *restful.Request, response *restful.Response) {
+	clusterList, err := handler.registry.ListClusters()
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to list clusters : %s", err.Error()))
+		return
+	}
+	for _, clusterInfo := range clusterList.Items {
+		clusterInfo.MaskSensitiveValues()
+	}
+	response.WriteEntity(clusterList)
+}
+
+func (handler *ClusterHandler) GetCluster(request *restful.Request, response *restful.Response) {
+	clusterName := request.PathParameter("cluster")
+	clusterInfo, err := handler.registry.GetCluster(clusterName)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("cluster %s is not found", clusterName))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get cluster %s : %s", clusterName, err.Error()))
+		return
+	}
+	clusterInfo.MaskSensitiveValues()
+	response.WriteEntity(clusterInfo)
+}
+
+func (handler *ClusterHandler) RegisterCluster(request *restful.Request, response *restful.Response) {
+	clusterInfo := &clusterModel.Cluster{}
+	err := request.ReadEntity(clusterInfo)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read cluster : %s", err.Error()))
+		return
+	}
+	err = handler.registry.RegisterCluster(clusterInfo)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to register cluster %s : %s", clusterInfo.Name, err.Error()))
+		return
+	}
+}
+
+func (handler *ClusterHandler) DeleteCluster(request *restful.Request, response *restful.Response) {
+	clusterName := request.PathParameter("cluster")
+	err := handler.registry.DeleteCluster(clusterName)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete cluster %s : %s", clusterName, err.Error()))
+		return
+	}
+}
@@ -0,0 +1,188 @@
+package redis
+
+import (
+	clusterModel "WarpCloud/walm/pkg/models/cluster"
+	"WarpCloud/walm/pkg/redis"
+	"WarpCloud/walm/pkg/setting"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Registry stores registered clusters in redis, their kubeconfigs encrypted
+// with aes gcm so the redis content never holds cluster credentials in clear.
+type Registry struct {
+	redis             redis.Redis
+	aead              cipher.AEAD
+	kubeConfigRootDir string
+}
+
+func (registry *Registry) RegisterCluster(cluster *clusterModel.Cluster) error {
+	if cluster == nil || cluster.Name == "" {
+		return fmt.Errorf("failed to register cluster : cluster name can not be empty")
+	}
+	if clusterModel.IsDefaultCluster(cluster.Name) {
+		return fmt.Errorf("failed to register cluster : %s is the implicit default cluster", clusterModel.DefaultClusterName)
+	}
+	if cluster.KubeConfig == "" {
+		return fmt.Errorf("failed to register cluster %s : kube config can not be empty", cluster.Name)
+	}
+
+	encryptedKubeConfig, err := registry.encrypt(cluster.KubeConfig)
+	if err != nil {
+		logrus.Errorf("failed to encrypt kube config of cluster %s : %s", cluster.Name, err.Error())
+		return err
+	}
+	storedCluster := *cluster
+	storedCluster.KubeConfig = encryptedKubeConfig
+	if storedCluster.CreateTime.IsZero() {
+		storedCluster.CreateTime = time.Now()
+	}
+
+	err = registry.redis.SetFieldValues(redis.WalmClustersKey, map[string]interface{}{redis.BuildFieldName("", cluster.Name): &storedCluster})
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("succeed to register cluster %s", cluster.Name)
+	return nil
+}
+
+func (registry *Registry) GetCluster(name string) (*clusterModel.Cluster, error) {
+	clusterStr, err := registry.redis.GetFieldValue(redis.WalmClustersKey, "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &clusterModel.Cluster{}
+	err = json.Unmarshal([]byte(clusterStr), cluster)
+	if err != nil {
+		logrus.Errorf("failed to unmarshal clusterStr %s : %s", clusterStr, err.Error())
+		return nil, err
+	}
+	cluster.KubeConfig, err = registry.decrypt(cluster.KubeConfig)
+	if err != nil {
+		logrus.Errorf("failed to decrypt kube config of cluster %s : %s", name, err.Error())
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func (registry *Registry) ListClusters() (*clusterModel.ClusterList, error) {
+	clusterStrs, err := registry.redis.GetFieldValues(redis.WalmClustersKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	clusterList := &clusterModel.ClusterList{
+		Items: []*clusterModel.Cluster{},
+	}
+	for _, clusterStr := range clusterStrs {
+		cluster := &clusterModel.Cluster{}
+		err = json.Unmarshal([]byte(clusterStr), cluster)
+		if err != nil {
+			logrus.Errorf("failed to unmarshal clusterStr %s : %s", clusterStr, err.Error())
+			return nil, err
+		}
+		cluster.KubeConfig, err = registry.decrypt(cluster.KubeConfig)
+		if err != nil {
+			logrus.Errorf("failed to decrypt kube config of cluster %s : %s", cluster.Name, err.Error())
+			return nil, err
+		}
+		clusterList.Items = append(clusterList.Items, cluster)
+	}
+	clusterList.Num = len(clusterList.Items)
+	return clusterList, nil
+}
+
+func (registry *Registry) DeleteCluster(name string) error {
+	err := registry.redis.DeleteField(redis.WalmClustersKey, "", name)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetClusterKubeConfigPath writes the decrypted kubeconfig of the cluster
+// under the configured root dir and returns the file path. The file is only
+// readable by the walm process.
+func (registry *Registry) GetClusterKubeConfigPath(name string) (string, error) {
+	cluster, err := registry.GetCluster(name)
+	if err != nil {
+		return "", err
+	}
+
+	rootDir := registry.kubeConfigRootDir
+	if rootDir == "" {
+		rootDir = filepath.Join(os.TempDir(), "walm-cluster-kubeconfigs")
+	}
+	err = os.MkdirAll(rootDir, 0700)
+	if err != nil {
+		logrus.Errorf("failed to create kube config dir %s : %s", rootDir, err.Error())
+		return "", err
+	}
+	kubeConfigPath := filepath.Join(rootDir, name+".kubeconfig")
+	err = ioutil.WriteFile(kubeConfigPath, []byte(cluster.KubeConfig), 0600)
+	if err != nil {
+		logrus.Errorf("failed to write kube config of cluster %s : %s", name, err.Error())
+		return "", err
+	}
+	return kubeConfigPath, nil
+}
+
+// encrypt seals the plain text with aes gcm, a random nonce prepended, and
+// encodes the result with base64.
+func (registry *Registry) encrypt(plainText string) (string, error) {
+	nonce := make([]byte, registry.aead.NonceSize())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+	cipherText := registry.aead.Seal(nonce, nonce, []byte(plainText), nil)
+	return base64.StdEncoding.EncodeToString(cipherText), nil
+}
+
+func (registry *Registry) decrypt(encodedCipherText string) (string, error) {
+	cipherText, err := base64.StdEncoding.DecodeString(encodedCipherText)
+	if err != nil {
+		return "", err
+	}
+	if len(cipherText) < registry.aead.NonceSize() {
+		return "", fmt.Errorf("cipher text is shorter than the nonce")
+	}
+	nonce := cipherText[:registry.aead.NonceSize()]
+	plainText, err := registry.aead.Open(nil, nonce, cipherText[registry.aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plainText), nil
+}
+
+func NewRegistry(redisClient redis.Redis, config *setting.ClusterRegistryConfig) (*Registry, error) {
+	if config == nil || config.EncryptionKey == "" {
+		return nil, fmt.Errorf("failed to build cluster registry : encryption key is not configured")
+	}
+	block, err := aes.NewCipher([]byte(config.EncryptionKey))
+	if err != nil {
+		logrus.Errorf("failed to build cipher from the encryption key : %s", err.Error())
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		logrus.Errorf("failed to build aes gcm : %s", err.Error())
+		return nil, err
+	}
+	return &Registry{
+		redis:             redisClient,
+		aead:              aead,
+		kubeConfigRootDir: config.KubeConfigRootDir,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	clusterModel "WarpCloud/walm/pkg/models/cluster"
+	"WarpCloud/walm/pkg/redis"
+	"WarpCloud/walm/pkg/redis/mocks"
+	"WarpCloud/walm/pkg/setting"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+const testEncryptionKey = "0123456789abcdef"
+
+func TestNewRegistry(t *testing.T) {
+	_, err := NewRegistry(&mocks.Redis{}, nil)
+	assert.NotNil(t, err)
+
+	_, err = NewRegistry(&mocks.Redis{}, &setting.ClusterRegistryConfig{EncryptionKey: "tooshort"})
+	assert.NotNil(t, err)
+
+	_, err = NewRegistry(&mocks.Redis{}, &setting.ClusterRegistryConfig{EncryptionKey: testEncryptionKey})
+	assert.Nil(t, err)
+}
+
+func TestRegistry_RegisterCluster(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	registry, err := NewRegistry(mockRedis, &setting.ClusterRegistryConfig{EncryptionKey: testEncryptionKey})
+	assert.Nil(t, err)
+
+	err = registry.RegisterCluster(&clusterModel.Cluster{Name: clusterModel.DefaultClusterName, KubeConfig: "kubeconfig"})
+	assert.NotNil(t, err)
+
+	err = registry.RegisterCluster(&clusterModel.Cluster{Name: "prod"})
+	assert.NotNil(t, err)
+
+	storedFieldValues := map[string]interface{}{}
+	mockRedis.On("SetFieldValues", redis.WalmClustersKey, mock.Anything).Run(func(args mock.Arguments) {
+		storedFieldValues = args.Get(1).(map[string]interface{})
+	}).Return(nil)
+
+	err = registry.RegisterCluster(&clusterModel.Cluster{Name: "prod", KubeConfig: "kubeconfig"})
+	assert.Nil(t, err)
+	mockRedis.AssertExpectations(t)
+
+	storedCluster, ok := storedFieldValues[redis.BuildFieldName("", "prod")].(*clusterModel.Cluster)
+	assert.True(t, ok)
+	// the kubeconfig is stored encrypted and round trips through decrypt
+	assert.NotEqual(t, "kubeconfig", storedCluster.KubeConfig)
+	assert.False(t, storedCluster.CreateTime.IsZero())
+	decrypted, err := registry.decrypt(storedCluster.KubeConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "kubeconfig", decrypted)
+}
+
+func TestRegistry_GetCluster(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	registry, err := NewRegistry(mockRedis, &setting.ClusterRegistryConfig{EncryptionKey: testEncryptionKey})
+	assert.Nil(t, err)
+
+	encryptedKubeConfig, err := registry.encrypt("kubeconfig")
+	assert.Nil(t, err)
+	clusterBytes, err := json.Marshal(&clusterModel.Cluster{Name: "prod", KubeConfig: encryptedKubeConfig})
+	assert.Nil(t, err)
+	mockRedis.On("GetFieldValue", redis.WalmClustersKey, "", "prod").Return(string(clusterBytes), nil)
+
+	cluster, err := registry.GetCluster("prod")
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", cluster.Name)
+	assert.Equal(t, "kubeconfig", cluster.KubeConfig)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestRegistry_GetClusterKubeConfigPath(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "walm-cluster-registry-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	mockRedis := &mocks.Redis{}
+	registry, err := NewRegistry(mockRedis, &setting.ClusterRegistryConfig{
+		EncryptionKey:     testEncryptionKey,
+		KubeConfigRootDir: tmpDir,
+	})
+	assert.Nil(t, err)
+
+	encryptedKubeConfig, err := registry.encrypt("kubeconfig")
+	assert.Nil(t, err)
+	clusterBytes, err := json.Marshal(&clusterModel.Cluster{Name: "prod", KubeConfig: encryptedKubeConfig})
+	assert.Nil(t, err)
+	mockRedis.On("GetFieldValue", redis.WalmClustersKey, "", "prod").Return(string(clusterBytes), nil)
+
+	kubeConfigPath, err := registry.GetClusterKubeConfigPath("prod")
+	assert.Nil(t, err)
+	kubeConfigBytes, err := ioutil.ReadFile(kubeConfigPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "kubeconfig", string(kubeConfigBytes))
+	fileInfo, err := os.Stat(kubeConfigPath)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), fileInfo.Mode().Perm())
+}
@@ -0,0 +1,22 @@
+package event
+
+import (
+	"WarpCloud/walm/pkg/models/event"
+)
+
+// Publisher forwards lifecycle events to an external system, best effort.
+// Implementations must never block the caller, as events are published from
+// the task runners.
+type Publisher interface {
+	Publish(lifecycleEvent *event.LifecycleEvent)
+}
+
+// Publishers fans every lifecycle event out to several publishers, so kafka
+// and webhook delivery can be enabled at the same time.
+type Publishers []Publisher
+
+func (publishers Publishers) Publish(lifecycleEvent *event.LifecycleEvent) {
+	for _, publisher := range publishers {
+		publisher.Publish(lifecycleEvent)
+	}
+}
@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"WarpCloud/walm/pkg/kafka"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	"WarpCloud/walm/pkg/setting"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultBufferSize = 1024
+
+// dropObserver is called with the topic of every dropped event. The metrics
+// package wires a prometheus counter here to avoid an import cycle.
+var dropObserver func(topic string)
+
+func SetDropObserver(observer func(topic string)) {
+	dropObserver = observer
+}
+
+func observeDrop(topic string) {
+	if dropObserver != nil {
+		dropObserver(topic)
+	}
+}
+
+// Publisher publishes lifecycle events to kafka asynchronously. Events are
+// buffered in memory and dropped with a metric when the buffer is full or the
+// send fails, so kafka being unavailable never blocks task execution.
+type Publisher struct {
+	kafkaClient  kafka.Kafka
+	releaseTopic string
+	projectTopic string
+	buffer       chan *eventModel.LifecycleEvent
+}
+
+func (publisher *Publisher) Publish(lifecycleEvent *eventModel.LifecycleEvent) {
+	topic := publisher.getTopic(lifecycleEvent.Kind)
+	if topic == "" {
+		return
+	}
+	select {
+	case publisher.buffer <- lifecycleEvent:
+	default:
+		observeDrop(topic)
+		logrus.Warnf("lifecycle event buffer is full, dropped %s event of %s %s/%s",
+			lifecycleEvent.Type, lifecycleEvent.Kind, lifecycleEvent.Namespace, lifecycleEvent.Name)
+	}
+}
+
+func (publisher *Publisher) getTopic(kind string) string {
+	switch kind {
+	case eventModel.ReleaseEventKind:
+		return publisher.releaseTopic
+	case eventModel.ProjectEventKind:
+		return publisher.projectTopic
+	}
+	return ""
+}
+
+func (publisher *Publisher) run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case lifecycleEvent := <-publisher.buffer:
+			publisher.send(lifecycleEvent)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (publisher *Publisher) send(lifecycleEvent *eventModel.LifecycleEvent) {
+	topic := publisher.getTopic(lifecycleEvent.Kind)
+	eventStr, err := json.Marshal(lifecycleEvent)
+	if err != nil {
+		logrus.Errorf("failed to marshal lifecycle event : %s", err.Error())
+		return
+	}
+	err = publisher.kafkaClient.SyncSendMessage(topic, string(eventStr))
+	if err != nil {
+		observeDrop(topic)
+		logrus.Warnf("failed to publish %s event of %s %s/%s : %s",
+			lifecycleEvent.Type, lifecycleEvent.Kind, lifecycleEvent.Namespace, lifecycleEvent.Name, err.Error())
+	}
+}
+
+// NewPublisher starts the background sender consuming the event buffer. Events
+// of a kind whose topic is not configured are silently discarded.
+func NewPublisher(kafkaClient kafka.Kafka, kafkaConfig *setting.KafkaConfig, stopCh <-chan struct{}) *Publisher {
+	if kafkaConfig == nil {
+		kafkaConfig = &setting.KafkaConfig{}
+	}
+	publisher := &Publisher{
+		kafkaClient:  kafkaClient,
+		releaseTopic: kafkaConfig.ReleaseEventsTopic,
+		projectTopic: kafkaConfig.ProjectEventsTopic,
+		buffer:       make(chan *eventModel.LifecycleEvent, defaultBufferSize),
+	}
+	go publisher.run(stopCh)
+	return publisher
+}
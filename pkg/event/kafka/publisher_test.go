@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	kafkaMocks "WarpCloud/walm/pkg/kafka/mocks"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	"WarpCloud/walm/pkg/setting"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPublisher_Publish(t *testing.T) {
+	mockKafka := &kafkaMocks.Kafka{}
+	sent := make(chan string, 1)
+	mockKafka.On("SyncSendMessage", "release-events", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		sent <- args.String(1)
+	})
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	publisher := NewPublisher(mockKafka, &setting.KafkaConfig{
+		ReleaseEventsTopic: "release-events",
+	}, stopCh)
+
+	// events of a kind without a configured topic are discarded
+	publisher.Publish(&eventModel.LifecycleEvent{
+		Kind:      eventModel.ProjectEventKind,
+		Type:      "Create-Project-Task",
+		Namespace: "test-ns",
+		Name:      "test-project",
+	})
+
+	publisher.Publish(&eventModel.LifecycleEvent{
+		Kind:      eventModel.ReleaseEventKind,
+		Type:      "Installed",
+		Namespace: "test-ns",
+		Name:      "test-name",
+	})
+
+	select {
+	case message := <-sent:
+		lifecycleEvent := &eventModel.LifecycleEvent{}
+		err := json.Unmarshal([]byte(message), lifecycleEvent)
+		assert.IsType(t, nil, err)
+		assert.Equal(t, "Installed", lifecycleEvent.Type)
+		assert.Equal(t, "test-ns", lifecycleEvent.Namespace)
+		assert.Equal(t, "test-name", lifecycleEvent.Name)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting for the event to be published")
+	}
+
+	mockKafka.AssertNumberOfCalls(t, "SyncSendMessage", 1)
+}
+
+func TestPublisher_PublishDropsWhenBufferFull(t *testing.T) {
+	droppedTopics := []string{}
+	SetDropObserver(func(topic string) {
+		droppedTopics = append(droppedTopics, topic)
+	})
+	defer SetDropObserver(nil)
+
+	mockKafka := &kafkaMocks.Kafka{}
+	// no run goroutine and an unbuffered channel, so the publish can not be accepted
+	publisher := &Publisher{
+		kafkaClient:  mockKafka,
+		releaseTopic: "release-events",
+		buffer:       make(chan *eventModel.LifecycleEvent),
+	}
+
+	publisher.Publish(&eventModel.LifecycleEvent{
+		Kind:      eventModel.ReleaseEventKind,
+		Type:      "Installed",
+		Namespace: "test-ns",
+		Name:      "test-name",
+	})
+
+	assert.Equal(t, []string{"release-events"}, droppedTopics)
+	mockKafka.AssertExpectations(t)
+}
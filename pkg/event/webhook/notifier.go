@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	eventModel "WarpCloud/walm/pkg/models/event"
+	projectModel "WarpCloud/walm/pkg/models/project"
+	"WarpCloud/walm/pkg/setting"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBufferSize = 1024
+	maxSendAttempts   = 4
+
+	signatureHeader = "X-Walm-Signature"
+)
+
+// dropObserver is called with the url of every endpoint an event could not be
+// delivered to. The metrics package wires a prometheus counter here to avoid
+// an import cycle.
+var dropObserver func(url string)
+
+func SetDropObserver(observer func(url string)) {
+	dropObserver = observer
+}
+
+func observeDrop(url string) {
+	if dropObserver != nil {
+		dropObserver(url)
+	}
+}
+
+// ProjectWebhooksGetter returns the webhook overrides of a project, an empty
+// list when none are configured. The project cache implements it.
+type ProjectWebhooksGetter func(namespace, name string) ([]*projectModel.ProjectWebhook, error)
+
+// Notifier delivers lifecycle events to http receivers asynchronously, as an
+// alternative to the kafka publisher. Failed deliveries are retried with
+// exponential backoff and appended to a dead letter log once the retries are
+// exhausted, so a broken receiver never blocks task execution.
+type Notifier struct {
+	webhooks        []*setting.EventWebhook
+	projectWebhooks ProjectWebhooksGetter
+	deadLetterPath  string
+	deadLetterMux   sync.Mutex
+	client          *http.Client
+	retryBackoff    time.Duration
+	buffer          chan *eventModel.LifecycleEvent
+}
+
+func (notifier *Notifier) Publish(lifecycleEvent *eventModel.LifecycleEvent) {
+	select {
+	case notifier.buffer <- lifecycleEvent:
+	default:
+		for _, webhook := range notifier.webhooks {
+			observeDrop(webhook.Url)
+		}
+		logrus.Warnf("lifecycle event buffer is full, dropped %s event of %s %s/%s",
+			lifecycleEvent.Type, lifecycleEvent.Kind, lifecycleEvent.Namespace, lifecycleEvent.Name)
+	}
+}
+
+func (notifier *Notifier) run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case lifecycleEvent := <-notifier.buffer:
+			notifier.deliver(lifecycleEvent)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// deliver posts the event to every matching endpoint. Webhooks configured on
+// the event's project replace the global endpoints for that event.
+func (notifier *Notifier) deliver(lifecycleEvent *eventModel.LifecycleEvent) {
+	webhooks := notifier.webhooks
+	if lifecycleEvent.Project != "" && notifier.projectWebhooks != nil {
+		overrides, err := notifier.projectWebhooks(lifecycleEvent.Namespace, lifecycleEvent.Project)
+		if err != nil {
+			logrus.Warnf("failed to get webhooks of project %s/%s : %s",
+				lifecycleEvent.Namespace, lifecycleEvent.Project, err.Error())
+		} else if len(overrides) > 0 {
+			webhooks = convertProjectWebhooks(overrides)
+		}
+	}
+
+	body, err := json.Marshal(lifecycleEvent)
+	if err != nil {
+		logrus.Errorf("failed to marshal lifecycle event : %s", err.Error())
+		return
+	}
+	for _, webhook := range webhooks {
+		if !matchesEventTypes(webhook.EventTypes, lifecycleEvent.Type) {
+			continue
+		}
+		notifier.post(webhook, body, lifecycleEvent)
+	}
+}
+
+func convertProjectWebhooks(projectWebhooks []*projectModel.ProjectWebhook) []*setting.EventWebhook {
+	webhooks := make([]*setting.EventWebhook, 0, len(projectWebhooks))
+	for _, projectWebhook := range projectWebhooks {
+		webhooks = append(webhooks, &setting.EventWebhook{
+			Url:        projectWebhook.Url,
+			Secret:     projectWebhook.Secret,
+			EventTypes: projectWebhook.EventTypes,
+		})
+	}
+	return webhooks
+}
+
+func matchesEventTypes(eventTypes []string, eventType string) bool {
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, candidate := range eventTypes {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// post delivers the event body to one endpoint, retrying with exponential
+// backoff. An event that exhausts its retries is counted as dropped and
+// appended to the dead letter log.
+func (notifier *Notifier) post(webhook *setting.EventWebhook, body []byte, lifecycleEvent *eventModel.LifecycleEvent) {
+	backoff := notifier.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := notifier.doPost(webhook, body)
+		if err == nil {
+			return
+		}
+		if attempt == maxSendAttempts {
+			observeDrop(webhook.Url)
+			logrus.Warnf("failed to deliver %s event of %s %s/%s to %s after %d attempts : %s",
+				lifecycleEvent.Type, lifecycleEvent.Kind, lifecycleEvent.Namespace, lifecycleEvent.Name,
+				webhook.Url, attempt, err.Error())
+			notifier.writeDeadLetter(webhook.Url, body)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (notifier *Notifier) doPost(webhook *setting.EventWebhook, body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(webhook.Secret))
+		mac.Write(body)
+		request.Header.Set(signatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	response, err := notifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", response.Status)
+	}
+	return nil
+}
+
+// writeDeadLetter appends the event to the dead letter log, so operators can
+// replay events lost while a receiver was down.
+func (notifier *Notifier) writeDeadLetter(url string, body []byte) {
+	if notifier.deadLetterPath == "" {
+		return
+	}
+	notifier.deadLetterMux.Lock()
+	defer notifier.deadLetterMux.Unlock()
+	file, err := os.OpenFile(notifier.deadLetterPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logrus.Errorf("failed to open dead letter log %s : %s", notifier.deadLetterPath, err.Error())
+		return
+	}
+	defer file.Close()
+	deadLetter, err := json.Marshal(map[string]interface{}{
+		"time":     time.Now(),
+		"endpoint": url,
+		"event":    json.RawMessage(body),
+	})
+	if err != nil {
+		logrus.Errorf("failed to marshal dead letter : %s", err.Error())
+		return
+	}
+	_, err = file.Write(append(deadLetter, '\n'))
+	if err != nil {
+		logrus.Errorf("failed to write dead letter to %s : %s", notifier.deadLetterPath, err.Error())
+	}
+}
+
+// NewNotifier starts the background sender consuming the event buffer. The
+// getter is optional and only used to look up per project webhook overrides.
+func NewNotifier(webhookConfig *setting.EventWebhookConfig, projectWebhooks ProjectWebhooksGetter, stopCh <-chan struct{}) *Notifier {
+	if webhookConfig == nil {
+		webhookConfig = &setting.EventWebhookConfig{}
+	}
+	notifier := &Notifier{
+		webhooks:        webhookConfig.Webhooks,
+		projectWebhooks: projectWebhooks,
+		deadLetterPath:  webhookConfig.DeadLetterPath,
+		client:          &http.Client{Timeout: time.Second * 10},
+		retryBackoff:    time.Second,
+		buffer:          make(chan *eventModel.LifecycleEvent, defaultBufferSize),
+	}
+	go notifier.run(stopCh)
+	return notifier
+}
@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	eventModel "WarpCloud/walm/pkg/models/event"
+	projectModel "WarpCloud/walm/pkg/models/project"
+	"WarpCloud/walm/pkg/setting"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotifier_Publish(t *testing.T) {
+	receivedChan := make(chan *http.Request, 10)
+	receivedBodyChan := make(chan []byte, 10)
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		receivedChan <- request
+		receivedBodyChan <- body
+	}))
+	defer testServer.Close()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	notifier := NewNotifier(&setting.EventWebhookConfig{
+		Webhooks: []*setting.EventWebhook{
+			{
+				Url:    testServer.URL,
+				Secret: "test-secret",
+			},
+			{
+				Url:        testServer.URL,
+				EventTypes: []string{"Installed"},
+			},
+		},
+	}, nil, stopCh)
+
+	notifier.Publish(&eventModel.LifecycleEvent{
+		Kind:      eventModel.ReleaseEventKind,
+		Type:      "Deleted",
+		Namespace: "testns",
+		Name:      "testnm",
+	})
+
+	// only the unfiltered webhook matches the Deleted event
+	select {
+	case request := <-receivedChan:
+		body := <-receivedBodyChan
+		lifecycleEvent := &eventModel.LifecycleEvent{}
+		err := json.Unmarshal(body, lifecycleEvent)
+		assert.Nil(t, err)
+		assert.Equal(t, "Deleted", lifecycleEvent.Type)
+		assert.Equal(t, "testns", lifecycleEvent.Namespace)
+
+		mac := hmac.New(sha256.New, []byte("test-secret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), request.Header.Get(signatureHeader))
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting for the webhook delivery")
+	}
+
+	select {
+	case <-receivedChan:
+		t.Fatal("the filtered webhook should not receive the Deleted event")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestNotifier_PublishProjectOverrides(t *testing.T) {
+	receivedChan := make(chan []byte, 10)
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		receivedChan <- body
+	}))
+	defer testServer.Close()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	projectWebhooks := func(namespace, name string) ([]*projectModel.ProjectWebhook, error) {
+		assert.Equal(t, "testns", namespace)
+		assert.Equal(t, "testproject", name)
+		return []*projectModel.ProjectWebhook{{Url: testServer.URL}}, nil
+	}
+	// no global webhooks : only the project override receives the event
+	notifier := NewNotifier(&setting.EventWebhookConfig{}, projectWebhooks, stopCh)
+
+	notifier.Publish(&eventModel.LifecycleEvent{
+		Kind:      eventModel.ProjectEventKind,
+		Type:      "Create-Project-Task",
+		Namespace: "testns",
+		Name:      "testproject",
+		Project:   "testproject",
+	})
+
+	select {
+	case body := <-receivedChan:
+		lifecycleEvent := &eventModel.LifecycleEvent{}
+		err := json.Unmarshal(body, lifecycleEvent)
+		assert.Nil(t, err)
+		assert.Equal(t, "testproject", lifecycleEvent.Project)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting for the webhook delivery")
+	}
+}
+
+func TestNotifier_PublishDeadLetter(t *testing.T) {
+	attemptsChan := make(chan struct{}, 10)
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		attemptsChan <- struct{}{}
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer testServer.Close()
+
+	droppedUrls := []string{}
+	SetDropObserver(func(url string) {
+		droppedUrls = append(droppedUrls, url)
+	})
+	defer SetDropObserver(nil)
+
+	tmpDir, err := ioutil.TempDir("", "walm-dead-letter-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmpDir)
+	deadLetterPath := filepath.Join(tmpDir, "dead-letter.log")
+
+	notifier := &Notifier{
+		webhooks:       []*setting.EventWebhook{{Url: testServer.URL}},
+		deadLetterPath: deadLetterPath,
+		client:         &http.Client{Timeout: time.Second},
+		retryBackoff:   time.Millisecond,
+	}
+	notifier.deliver(&eventModel.LifecycleEvent{
+		Kind:      eventModel.ReleaseEventKind,
+		Type:      "Installed",
+		Namespace: "testns",
+		Name:      "testnm",
+	})
+
+	assert.Equal(t, maxSendAttempts, len(attemptsChan))
+	assert.Equal(t, []string{testServer.URL}, droppedUrls)
+
+	deadLetterBytes, err := ioutil.ReadFile(deadLetterPath)
+	assert.Nil(t, err)
+	deadLetter := map[string]interface{}{}
+	err = json.Unmarshal(deadLetterBytes, &deadLetter)
+	assert.Nil(t, err)
+	assert.Equal(t, testServer.URL, deadLetter["endpoint"])
+}
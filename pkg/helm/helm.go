@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/models/common"
 	"WarpCloud/walm/pkg/helm/impl/plugins"
@@ -12,20 +14,36 @@ type Helm interface {
 	// 1. nil: nothing to do
 	// 2. true: enable pause release plugin
 	// 3. false: disable pause release plugin
-	InstallOrCreateRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
+	// the ctx only carries the trace, spans around the chart fetch, the
+	// rendering and the helm apply are started from it
+	InstallOrCreateRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
 		dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error)
-	InstallOrCreateReleaseWithStrict(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
+	InstallOrCreateReleaseWithStrict(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
 		dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2, fullUpdate bool, strict bool) (*release.ReleaseCache, error)
-	DeleteRelease(namespace string, name string) error
+	DeleteRelease(ctx context.Context, namespace string, name string) error
+	// DeleteClusterRelease deletes a release installed in a registered cluster,
+	// an empty cluster name targets the default cluster like DeleteRelease
+	DeleteClusterRelease(ctx context.Context, clusterName string, namespace string, name string) error
+	GetReleaseCache(namespace, name string) (*release.ReleaseCache, error)
+	GetClusterReleaseCache(clusterName string, namespace, name string) (*release.ReleaseCache, error)
 	PauseOrRecoverRelease(paused bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error)
 	ListAllReleases() ([]*release.ReleaseCache, error)
 	GetDependencyOutputConfigs(namespace string, dependencies map[string]string, chartInfo *release.ChartDetailInfo, strict bool) (dependencyConfigs map[string]interface{}, err error)
 
 	GetChartDetailInfo(repoName, chartName, chartVersion string) (*release.ChartDetailInfo, error)
 	GetChartList(repoName string) (*release.ChartInfoList, error)
+	// ListCharts pages through the charts of a repo for the browse view, refresh
+	// forces fetching the repo index instead of serving the cached one
+	ListCharts(repoName string, limit int, continueToken string, refresh bool) (*release.ChartBrowseList, error)
+	ListChartVersions(repoName, chartName string, refresh bool) (*release.ChartVersionList, error)
+	// GetChartMetadata returns the Chart.yaml metadata of the chart version
+	// from the repo index, an empty version means the latest one
+	GetChartMetadata(repoName, chartName, chartVersion string) (*release.ChartMetadata, error)
+	GetChartMetainfo(repoName, chartName, chartVersion string) (*release.ChartMetainfo, error)
 	GetDetailChartInfoByImage(chartImage string) (*release.ChartDetailInfo, error)
 	GetRepoList() *release.RepoInfoList
 	GetChartAutoDependencies(repoName, chartName, chartVersion string) (subChartNames []string, err error)
+	GetChartAutoDependenciesFromPath(chartPath string) (subChartNames []string, err error)
 }
 
 func BuildReleasePluginsByConfigValues(configValues map[string]interface{}) (releasePlugins []*k8s.ReleasePlugin, hasPauseReleasePlugin bool, err error){
@@ -51,4 +69,21 @@ func BuildReleasePluginsByConfigValues(configValues map[string]interface{}) (rel
 		}
 	}
 	return
+}
+
+// BuildReleaseExternalDependenciesByConfigValues pops the external dependency
+// declarations stored in the config values, the way the walm plugins are
+// stored, and rebuilds the map of dependency chart name and inline output
+// config values.
+func BuildReleaseExternalDependenciesByConfigValues(configValues map[string]interface{}) (externalDependencies map[string]map[string]interface{}) {
+	externalDependencies = map[string]map[string]interface{}{}
+	if configValues != nil {
+		if storedDependencies, ok := configValues[release.ExternalDependenciesConfigKey]; ok {
+			delete(configValues, release.ExternalDependenciesConfigKey)
+			for dependencyName, outputConfig := range storedDependencies.(map[string]interface{}) {
+				externalDependencies[dependencyName] = outputConfig.(map[string]interface{})
+			}
+		}
+	}
+	return
 }
\ No newline at end of file
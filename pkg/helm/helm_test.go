@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"WarpCloud/walm/pkg/helm/impl/plugins"
 	"WarpCloud/walm/pkg/models/k8s"
+	"WarpCloud/walm/pkg/models/release"
 )
 
 func Test_BuildReleasePluginsByConfigValues(t *testing.T) {
@@ -109,3 +110,36 @@ func Test_BuildReleasePluginsByConfigValues(t *testing.T) {
 		assert.Equal(t, test.hasPauseReleasePlugin, hasPauseReleasePlugin)
 	}
 }
+
+func Test_BuildReleaseExternalDependenciesByConfigValues(t *testing.T) {
+	tests := []struct {
+		configValues         map[string]interface{}
+		externalDependencies map[string]map[string]interface{}
+	}{
+		{
+			configValues:         map[string]interface{}{},
+			externalDependencies: map[string]map[string]interface{}{},
+		},
+		{
+			configValues: map[string]interface{}{
+				release.ExternalDependenciesConfigKey: map[string]interface{}{
+					"zookeeper": map[string]interface{}{
+						"zookeeper_addresses": "external-zk:2181",
+					},
+				},
+			},
+			externalDependencies: map[string]map[string]interface{}{
+				"zookeeper": {
+					"zookeeper_addresses": "external-zk:2181",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		externalDependencies := BuildReleaseExternalDependenciesByConfigValues(test.configValues)
+		assert.Equal(t, test.externalDependencies, externalDependencies)
+		_, ok := test.configValues[release.ExternalDependenciesConfigKey]
+		assert.False(t, ok)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"WarpCloud/walm/pkg/models/common"
 	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/models/release"
+	"WarpCloud/walm/pkg/setting"
 	"WarpCloud/walm/pkg/util/transwarpjsonnet"
 	"encoding/base64"
 	"encoding/json"
@@ -13,41 +14,64 @@ import (
 	"github.com/pkg/errors"
 	"helm.sh/helm/pkg/chart"
 	"helm.sh/helm/pkg/chart/loader"
+	"helm.sh/helm/pkg/provenance"
 	"helm.sh/helm/pkg/registry"
 	"helm.sh/helm/pkg/repo"
 	"io/ioutil"
 	"k8s.io/klog"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 func (helmImpl *Helm) GetChartAutoDependencies(repoName, chartName, chartVersion string) (subChartNames []string, err error) {
 	klog.V(2).Infof("Enter GetAutoDependencies %s %s\n", chartName, chartVersion)
 
-	subChartNames = []string{}
 	detailChartInfo, err := helmImpl.GetChartDetailInfo(repoName, chartName, chartVersion)
 	if err != nil {
 		return nil, err
 	}
 
+	return getAutoDependencies(detailChartInfo), nil
+}
+
+// GetChartAutoDependenciesFromPath resolves the auto dependencies of a chart loaded from
+// a local path, so local chart installs get the same dependency processing as repo charts.
+func (helmImpl *Helm) GetChartAutoDependenciesFromPath(chartPath string) (subChartNames []string, err error) {
+	rawChart, err := loadChartFromPath(chartPath)
+	if err != nil {
+		return nil, err
+	}
+
+	detailChartInfo, err := buildChartInfo(rawChart)
+	if err != nil {
+		return nil, err
+	}
+
+	return getAutoDependencies(detailChartInfo), nil
+}
+
+func getAutoDependencies(detailChartInfo *release.ChartDetailInfo) (subChartNames []string) {
+	subChartNames = []string{}
 	if detailChartInfo.WalmVersion == common.WalmVersionV2 {
-	if detailChartInfo.MetaInfo != nil && detailChartInfo.MetaInfo.ChartDependenciesInfo != nil {
-		for _, dependency := range detailChartInfo.MetaInfo.ChartDependenciesInfo {
-			if dependency.AutoDependency() {
-				subChartNames = append(subChartNames, dependency.Name)
+		if detailChartInfo.MetaInfo != nil && detailChartInfo.MetaInfo.ChartDependenciesInfo != nil {
+			for _, dependency := range detailChartInfo.MetaInfo.ChartDependenciesInfo {
+				if dependency.AutoDependency() {
+					subChartNames = append(subChartNames, dependency.Name)
+				}
 			}
 		}
-	}
 	} else if detailChartInfo.WalmVersion == common.WalmVersionV1 {
 		for _, dependencyChart := range detailChartInfo.DependencyCharts {
 			subChartNames = append(subChartNames, dependencyChart.ChartName)
 		}
 	}
-
-	return subChartNames, nil
+	return subChartNames
 }
 
 func (helmImpl *Helm) GetRepoList() *release.RepoInfoList {
@@ -63,10 +87,10 @@ func (helmImpl *Helm) GetRepoList() *release.RepoInfoList {
 }
 
 func (helmImpl *Helm) GetChartDetailInfo(repoName, chartName, chartVersion string) (*release.ChartDetailInfo, error) {
-	rawChart, err := helmImpl.getRawChartFromRepo(repoName, chartName, chartVersion)
+	rawChart, _, err := helmImpl.getRawChartFromRepo(repoName, chartName, chartVersion)
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			err = errorModel.NotFoundError{ Message: err.Error() }
+			err = errorModel.ChartNotFoundError{Message: err.Error()}
 		}
 		return nil, err
 	}
@@ -74,6 +98,66 @@ func (helmImpl *Helm) GetChartDetailInfo(repoName, chartName, chartVersion strin
 	return buildChartInfo(rawChart)
 }
 
+// GetChartMetainfo returns a structured description of the chart's configurable
+// parameters for ui form building. Parsed results are cached by chart digest as
+// the ui calls this api constantly.
+func (helmImpl *Helm) GetChartMetainfo(repoName, chartName, chartVersion string) (*release.ChartMetainfo, error) {
+	digest := helmImpl.getChartDigest(repoName, chartName, chartVersion)
+	if digest != "" {
+		if cachedMetainfo, ok := helmImpl.metainfoCache.Get(digest); ok {
+			return cachedMetainfo.(*release.ChartMetainfo), nil
+		}
+	}
+
+	detailChartInfo, err := helmImpl.GetChartDetailInfo(repoName, chartName, chartVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	chartMetainfo := &release.ChartMetainfo{
+		ChartName:    detailChartInfo.ChartName,
+		ChartVersion: detailChartInfo.ChartVersion,
+	}
+	if detailChartInfo.MetaInfo != nil {
+		chartMetainfo.HasMetaInfo = true
+		chartMetainfo.Params = detailChartInfo.MetaInfo.BuildParamSchemas(detailChartInfo.DefaultValue)
+	} else if detailChartInfo.DefaultValue != "" {
+		// charts without metainfo fall back to a best effort flattening of values.yaml
+		values := map[string]interface{}{}
+		err = json.Unmarshal([]byte(detailChartInfo.DefaultValue), &values)
+		if err != nil {
+			klog.Errorf("failed to unmarshal default values of chart %s-%s : %s", chartName, chartVersion, err.Error())
+			return nil, err
+		}
+		chartMetainfo.Params = release.BuildParamSchemasFromValues(values)
+	}
+
+	if digest != "" {
+		helmImpl.metainfoCache.Add(digest, chartMetainfo)
+	}
+	return chartMetainfo, nil
+}
+
+func (helmImpl *Helm) getChartDigest(repoName, chartName, chartVersion string) string {
+	if repoName == "" {
+		repoName = "stable"
+	}
+	chartRepository, ok := helmImpl.chartRepoMap[repoName]
+	if !ok {
+		return ""
+	}
+	indexFile, err := getChartIndexFile(chartRepository.URL, chartRepository.Username, chartRepository.Password, helmImpl.restyClient)
+	if err != nil {
+		klog.Warningf("failed to get chart index file of repo %s : %s", repoName, err.Error())
+		return ""
+	}
+	cv, err := indexFile.Get(chartName, chartVersion)
+	if err != nil {
+		return ""
+	}
+	return cv.Digest
+}
+
 func (helmImpl *Helm) GetChartList(repoName string) (*release.ChartInfoList, error) {
 	chartInfoList := new(release.ChartInfoList)
 	chartInfoList.Items = make([]*release.ChartInfo, 0)
@@ -99,11 +183,138 @@ func (helmImpl *Helm) GetChartList(repoName string) (*release.ChartInfoList, err
 	return chartInfoList, nil
 }
 
+// getRepoIndexFile returns the repo's index, served from the index cache unless
+// refresh is set or the index has not been cached yet.
+func (helmImpl *Helm) getRepoIndexFile(repoName string, refresh bool) (*repo.IndexFile, error) {
+	if repoName == "" {
+		repoName = "stable"
+	}
+	chartRepository, ok := helmImpl.chartRepoMap[repoName]
+	if !ok {
+		return nil, fmt.Errorf("can't find repo name %s", repoName)
+	}
+	if !refresh {
+		if cachedIndexFile, ok := helmImpl.repoIndexCache.Get(repoName); ok {
+			return cachedIndexFile.(*repo.IndexFile), nil
+		}
+	}
+	indexFile, err := getChartIndexFile(chartRepository.URL, chartRepository.Username, chartRepository.Password, helmImpl.restyClient)
+	if err != nil {
+		return nil, err
+	}
+	// versions sorted descending, so the first version of an entry is the latest
+	indexFile.SortEntries()
+	helmImpl.repoIndexCache.Add(repoName, indexFile)
+	return indexFile, nil
+}
+
+// ListCharts pages through the charts of a repo for the browse view. Charts are
+// sorted by name, a page starts after continueToken and holds at most limit
+// charts, limit <= 0 returns all of them. The returned continue token is empty
+// on the last page.
+func (helmImpl *Helm) ListCharts(repoName string, limit int, continueToken string, refresh bool) (*release.ChartBrowseList, error) {
+	indexFile, err := helmImpl.getRepoIndexFile(repoName, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	chartNames := make([]string, 0, len(indexFile.Entries))
+	for chartName, chartVersions := range indexFile.Entries {
+		if len(chartVersions) == 0 {
+			continue
+		}
+		chartNames = append(chartNames, chartName)
+	}
+	sort.Strings(chartNames)
+
+	chartBrowseList := &release.ChartBrowseList{Items: []*release.ChartBrowseInfo{}}
+	for _, chartName := range chartNames {
+		if continueToken != "" && chartName <= continueToken {
+			continue
+		}
+		if limit > 0 && len(chartBrowseList.Items) == limit {
+			chartBrowseList.Continue = chartBrowseList.Items[limit-1].ChartName
+			break
+		}
+		latestVersion := indexFile.Entries[chartName][0]
+		chartBrowseList.Items = append(chartBrowseList.Items, &release.ChartBrowseInfo{
+			ChartName:        chartName,
+			LatestVersion:    latestVersion.Version,
+			VersionCount:     len(indexFile.Entries[chartName]),
+			ChartDescription: latestVersion.Description,
+			ChartAppVersion:  latestVersion.AppVersion,
+			IconUrl:          latestVersion.Icon,
+		})
+	}
+	return chartBrowseList, nil
+}
+
+// ListChartVersions returns all the versions of a chart the repo serves, latest
+// first, for the version picker of the browse view.
+func (helmImpl *Helm) ListChartVersions(repoName, chartName string, refresh bool) (*release.ChartVersionList, error) {
+	indexFile, err := helmImpl.getRepoIndexFile(repoName, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	chartVersions, ok := indexFile.Entries[chartName]
+	if !ok || len(chartVersions) == 0 {
+		return nil, errorModel.NewChartNotFoundError(fmt.Sprintf("chart %s is not found in repo %s", chartName, repoName))
+	}
+
+	chartVersionList := &release.ChartVersionList{
+		ChartName: chartName,
+		Items:     []*release.ChartVersionInfo{},
+	}
+	for _, chartVersion := range chartVersions {
+		chartVersionList.Items = append(chartVersionList.Items, &release.ChartVersionInfo{
+			ChartVersion:     chartVersion.Version,
+			ChartAppVersion:  chartVersion.AppVersion,
+			ChartDescription: chartVersion.Description,
+			Created:          chartVersion.Created.Format(time.RFC3339),
+			Digest:           chartVersion.Digest,
+		})
+	}
+	return chartVersionList, nil
+}
+
+// GetChartMetadata returns the Chart.yaml metadata of the chart version from
+// the repo index, so showing a chart card does not download the chart. An
+// empty version means the latest one.
+func (helmImpl *Helm) GetChartMetadata(repoName, chartName, chartVersion string) (*release.ChartMetadata, error) {
+	indexFile, err := helmImpl.getRepoIndexFile(repoName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cv, err := indexFile.Get(chartName, chartVersion)
+	if err != nil {
+		return nil, errorModel.NewChartNotFoundError(fmt.Sprintf("chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+	}
+
+	chartMetadata := &release.ChartMetadata{
+		ChartName:        cv.Name,
+		ChartVersion:     cv.Version,
+		ChartAppVersion:  cv.AppVersion,
+		ChartDescription: cv.Description,
+		Keywords:         cv.Keywords,
+		Maintainers:      []*release.ChartMaintainer{},
+		IconUrl:          cv.Icon,
+	}
+	for _, maintainer := range cv.Maintainers {
+		chartMetadata.Maintainers = append(chartMetadata.Maintainers, &release.ChartMaintainer{
+			Name:  maintainer.Name,
+			Email: maintainer.Email,
+		})
+	}
+	return chartMetadata, nil
+}
+
 func (helmImpl *Helm) GetDetailChartInfoByImage(chartImage string) (*release.ChartDetailInfo, error) {
 	rawChart, err := helmImpl.getRawChartByImage(chartImage)
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			err = errorModel.NotFoundError{ Message: err.Error() }
+			err = errorModel.ChartNotFoundError{Message: err.Error()}
 		}
 		return nil, err
 	}
@@ -111,19 +322,33 @@ func (helmImpl *Helm) GetDetailChartInfoByImage(chartImage string) (*release.Cha
 	return buildChartInfo(rawChart)
 }
 
-func (helmImpl *Helm) getRawChartFromRepo(repoName, chartName, chartVersion string) (rawChart *chart.Chart, err error) {
-	chartPath, err := helmImpl.downloadChartFromRepo(repoName, chartName, chartVersion)
+func (helmImpl *Helm) getRawChartFromRepo(repoName, chartName, chartVersion string) (rawChart *chart.Chart, chartProvenance *release.ChartProvenance, err error) {
+	chartPath, chartProvenance, err := helmImpl.downloadChartFromRepo(repoName, chartName, chartVersion)
 	if err != nil {
 		klog.Errorf("failed to download chart : %s", err.Error())
-		return nil, err
+		return nil, chartProvenance, err
 	}
 
 	chartLoader, err := loader.Loader(chartPath)
 	if err != nil {
 		klog.Errorf("failed to init chartLoader : %s", err.Error())
-		return nil, errors.Wrap(err, "failed to init chartLoader ")
+		return nil, chartProvenance, errors.Wrap(err, "failed to init chartLoader ")
 	}
 	defer os.RemoveAll(filepath.Dir(chartPath))
+	rawChart, err = chartLoader.Load()
+	return rawChart, chartProvenance, err
+}
+
+func loadChartFromPath(chartPath string) (*chart.Chart, error) {
+	if !setting.Config.EnableLocalChart {
+		return nil, fmt.Errorf("loading charts from local paths is disabled, enable it with enableLocalChart in walm config")
+	}
+
+	chartLoader, err := loader.Loader(chartPath)
+	if err != nil {
+		klog.Errorf("failed to init chartLoader for local chart %s : %s", chartPath, err.Error())
+		return nil, errors.Wrapf(err, "failed to init chartLoader for local chart %s", chartPath)
+	}
 	return chartLoader.Load()
 }
 
@@ -259,58 +484,112 @@ func getChartIndexFile(repoURL, username, password string, client *resty.Client)
 	return repoIndex, nil
 }
 
-func loadChartFromRepo(repoUrl, username, password, chartName, chartVersion, dest string, client *resty.Client) (string, error) {
+func loadChartFromRepo(repoUrl, username, password, chartName, chartVersion, dest string, client *resty.Client) (string, *release.ChartProvenance, error) {
 
 	indexFile, err := getChartIndexFile(repoUrl, username, password, client)
 	if err != nil {
 		klog.Errorf("failed to get chart index file : %s", err.Error())
-		return "", errors.Wrap(err, "failed to get chart index file ")
+		return "", nil, errors.Wrap(err, "failed to get chart index file ")
 	}
 
 	cv, err := indexFile.Get(chartName, chartVersion)
 	if err != nil {
-		return "", fmt.Errorf("chart %s-%s is not found: %s", chartName, chartVersion, err.Error())
+		return "", nil, errorModel.NewChartNotFoundError(fmt.Sprintf("chart %s-%s is not found: %s", chartName, chartVersion, err.Error()))
 	}
 	if len(cv.URLs) == 0 {
-		return "", fmt.Errorf("chart %s has no downloadable URLs", chartName)
+		return "", nil, fmt.Errorf("chart %s has no downloadable URLs", chartName)
 	}
 	chartUrl := cv.URLs[0]
 	absoluteChartURL, err := repo.ResolveReferenceURL(repoUrl, chartUrl)
 	if err != nil {
-		return "", fmt.Errorf("failed to make absolute chart url: %v", err)
+		return "", nil, fmt.Errorf("failed to make absolute chart url: %v", err)
 	}
 	resp, err := client.R().Get(absoluteChartURL)
 	if err != nil {
 		klog.Errorf("failed to get chart : %s", err.Error())
-		return "", err
+		return "", nil, err
 	}
 
 	name := filepath.Base(absoluteChartURL)
 	destfile := filepath.Join(dest, name)
 	if err := ioutil.WriteFile(destfile, resp.Body(), 0644); err != nil {
 		klog.Errorf("failed to write file : %s", err.Error())
-		return "", err
+		return "", nil, err
+	}
+
+	chartProvenance, err := verifyChartProvenance(destfile, absoluteChartURL, client)
+	if err != nil {
+		return "", chartProvenance, err
+	}
+	return destfile, chartProvenance, nil
+}
+
+// verifyChartProvenance checks the chart tarball against the .prov file published
+// next to it in the repo, according to the configured chart provenance mode.
+// It returns nil when verification is off, and an error only in enforce mode, so
+// repos without provenance files keep working in warn mode.
+func verifyChartProvenance(chartPath, chartUrl string, client *resty.Client) (*release.ChartProvenance, error) {
+	provenanceConfig := setting.Config.ChartProvenanceConfig
+	if provenanceConfig == nil || provenanceConfig.Mode == "" || provenanceConfig.Mode == setting.ChartProvenanceModeOff {
+		return nil, nil
+	}
+
+	chartProvenance := &release.ChartProvenance{}
+	provUrl := chartUrl + ".prov"
+	resp, err := client.R().Get(provUrl)
+	if err != nil {
+		chartProvenance.Message = fmt.Sprintf("failed to get provenance file %s : %s", provUrl, err.Error())
+	} else if resp.StatusCode() != http.StatusOK {
+		chartProvenance.Message = fmt.Sprintf("provenance file %s is not published by the repo", provUrl)
+	} else {
+		provPath := chartPath + ".prov"
+		err = ioutil.WriteFile(provPath, resp.Body(), 0644)
+		if err != nil {
+			klog.Errorf("failed to write provenance file : %s", err.Error())
+			return nil, err
+		}
+		signatory, err := provenance.NewFromKeyring(provenanceConfig.Keyring, "")
+		if err != nil {
+			klog.Errorf("failed to load keyring %s : %s", provenanceConfig.Keyring, err.Error())
+			return nil, errors.Wrapf(err, "failed to load keyring %s", provenanceConfig.Keyring)
+		}
+		verification, err := signatory.Verify(chartPath, provPath)
+		if err != nil {
+			chartProvenance.Message = err.Error()
+		} else {
+			chartProvenance.Verified = true
+			for identity := range verification.SignedBy.Identities {
+				chartProvenance.SignedBy = append(chartProvenance.SignedBy, identity)
+			}
+		}
+	}
+
+	if !chartProvenance.Verified {
+		if provenanceConfig.Mode == setting.ChartProvenanceModeEnforce {
+			return chartProvenance, fmt.Errorf("failed to verify provenance of chart %s : %s", filepath.Base(chartPath), chartProvenance.Message)
+		}
+		klog.Warningf("chart %s is not verified : %s", filepath.Base(chartPath), chartProvenance.Message)
 	}
-	return destfile, nil
+	return chartProvenance, nil
 }
 
-func (helmImpl *Helm) downloadChartFromRepo(repoName, chartName, version string) (string, error) {
+func (helmImpl *Helm) downloadChartFromRepo(repoName, chartName, version string) (string, *release.ChartProvenance, error) {
 	if repoName == "" {
 		repoName = "stable"
 	}
 	repo, ok := helmImpl.chartRepoMap[repoName]
 	if !ok {
-		return "", fmt.Errorf("can not find repo name: %s", repoName)
+		return "", nil, fmt.Errorf("can not find repo name: %s", repoName)
 	}
 	tmpDir, err := ioutil.TempDir("", "")
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	filename, err := loadChartFromRepo(repo.URL, repo.Username, repo.Password, chartName, version, tmpDir, helmImpl.restyClient)
+	filename, chartProvenance, err := loadChartFromRepo(repo.URL, repo.Username, repo.Password, chartName, version, tmpDir, helmImpl.restyClient)
 	if err != nil {
 		klog.Infof("DownloadTo err %v", err)
-		return "", err
+		return "", chartProvenance, err
 	}
 
-	return filename, nil
+	return filename, chartProvenance, nil
 }
@@ -1,12 +1,18 @@
 package impl
 
 import (
+	"context"
+
+	"WarpCloud/walm/pkg/cluster"
 	"WarpCloud/walm/pkg/helm/impl/plugins"
 	"WarpCloud/walm/pkg/k8s"
 	k8sHelm "WarpCloud/walm/pkg/k8s/client/helm"
 	"WarpCloud/walm/pkg/k8s/utils"
+	clusterModel "WarpCloud/walm/pkg/models/cluster"
 	"WarpCloud/walm/pkg/models/common"
+	errorModel "WarpCloud/walm/pkg/models/error"
 	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	projectModel "WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/redis"
 	"WarpCloud/walm/pkg/setting"
@@ -20,6 +26,7 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/go-resty/resty"
 	"github.com/hashicorp/golang-lru"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"helm.sh/helm/pkg/action"
 	"helm.sh/helm/pkg/chart"
@@ -52,6 +59,8 @@ type ChartRepository struct {
 	URL      string
 	Username string
 	Password string
+	// default config values merged into every release installed from this repo
+	DefaultValues map[string]interface{}
 }
 
 type Helm struct {
@@ -62,13 +71,60 @@ type Helm struct {
 	kubeClients    *k8sHelm.Client
 	restyClient    *resty.Client
 	actionConfigs  *lru.Cache
+	metainfoCache  *lru.Cache
+	repoIndexCache *lru.Cache
+
+	// routing to registered clusters, nil unless a cluster registry is configured
+	clusterRegistry       cluster.Registry
+	newClusterKubeClients func(kubeConfigPath string) *k8sHelm.Client
+	clusterKubeClients    *lru.Cache
+}
+
+// SetClusterRegistry enables routing helm actions to the clusters registered
+// in the registry. The factory builds the kube clients of one cluster from its
+// kubeconfig file. The registry stays optional : without it only the default
+// cluster can be targeted.
+func (helmImpl *Helm) SetClusterRegistry(clusterRegistry cluster.Registry, newKubeClients func(kubeConfigPath string) *k8sHelm.Client) {
+	helmImpl.clusterRegistry = clusterRegistry
+	helmImpl.newClusterKubeClients = newKubeClients
+}
+
+// getClusterKubeClients returns the kube clients reaching the cluster, the
+// in-cluster clients for the default cluster. Clients of registered clusters
+// are built once and cached.
+func (helmImpl *Helm) getClusterKubeClients(clusterName string) (*k8sHelm.Client, error) {
+	if clusterModel.IsDefaultCluster(clusterName) {
+		return helmImpl.kubeClients, nil
+	}
+	if kubeClients, ok := helmImpl.clusterKubeClients.Get(clusterName); ok {
+		return kubeClients.(*k8sHelm.Client), nil
+	}
+	if helmImpl.clusterRegistry == nil || helmImpl.newClusterKubeClients == nil {
+		return nil, fmt.Errorf("failed to route to cluster %s : cluster registry is not configured", clusterName)
+	}
+	kubeConfigPath, err := helmImpl.clusterRegistry.GetClusterKubeConfigPath(clusterName)
+	if err != nil {
+		klog.Errorf("failed to get kube config of cluster %s : %s", clusterName, err.Error())
+		return nil, err
+	}
+	kubeClients := helmImpl.newClusterKubeClients(kubeConfigPath)
+	helmImpl.clusterKubeClients.Add(clusterName, kubeClients)
+	return kubeClients, nil
 }
 
-func (helmImpl *Helm) getActionConfig(namespace string) (*action.Configuration, error) {
-	if actionConfig, ok := helmImpl.actionConfigs.Get(namespace); ok {
+func (helmImpl *Helm) getActionConfig(clusterName, namespace string) (*action.Configuration, error) {
+	cacheKey := namespace
+	if !clusterModel.IsDefaultCluster(clusterName) {
+		cacheKey = clusterName + "/" + namespace
+	}
+	if actionConfig, ok := helmImpl.actionConfigs.Get(cacheKey); ok {
 		return actionConfig.(*action.Configuration), nil
 	} else {
-		kubeConfig, kubeClient := helmImpl.kubeClients.GetKubeClient(namespace)
+		kubeClients, err := helmImpl.getClusterKubeClients(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		kubeConfig, kubeClient := kubeClients.GetKubeClient(namespace)
 		clientset, err := kubeClient.Factory.KubernetesClientSet()
 		if err != nil {
 			klog.Errorf("failed to get clientset: %s", err.Error())
@@ -84,7 +140,7 @@ func (helmImpl *Helm) getActionConfig(namespace string) (*action.Configuration,
 			RESTClientGetter: kubeConfig,
 			Log:              klog.Infof,
 		}
-		helmImpl.actionConfigs.Add(namespace, config)
+		helmImpl.actionConfigs.Add(cacheKey, config)
 		return config, nil
 	}
 }
@@ -133,13 +189,21 @@ func filterHelmReleases(releases []*helmRelease.Release) (filteredReleases map[s
 	return
 }
 
-func (helmImpl *Helm) DeleteRelease(namespace string, name string) error {
-	action, err := helmImpl.getDeleteAction(namespace)
+func (helmImpl *Helm) DeleteRelease(ctx context.Context, namespace string, name string) error {
+	return helmImpl.DeleteClusterRelease(ctx, "", namespace, name)
+}
+
+// DeleteClusterRelease deletes a release from the cluster it was installed in,
+// the default cluster when the cluster name is empty.
+func (helmImpl *Helm) DeleteClusterRelease(ctx context.Context, clusterName string, namespace string, name string) error {
+	action, err := helmImpl.getDeleteAction(clusterName, namespace)
 	if err != nil {
 		klog.Errorf("failed to get current helm client : %s", err.Error())
 		return err
 	}
 
+	deleteSpan, _ := opentracing.StartSpanFromContext(ctx, "helm-delete")
+	defer deleteSpan.Finish()
 	_, err = action.Run(name)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
@@ -152,15 +216,56 @@ func (helmImpl *Helm) DeleteRelease(namespace string, name string) error {
 	return nil
 }
 
+// GetReleaseCache reads a release from helm storage and rebuilds its release cache entry.
+func (helmImpl *Helm) GetReleaseCache(namespace, name string) (*release.ReleaseCache, error) {
+	return helmImpl.GetClusterReleaseCache("", namespace, name)
+}
+
+// GetClusterReleaseCache reads a release from the helm storage of the cluster,
+// the default cluster when the cluster name is empty.
+func (helmImpl *Helm) GetClusterReleaseCache(clusterName string, namespace, name string) (*release.ReleaseCache, error) {
+	getAction, err := helmImpl.getGetAction(clusterName, namespace)
+	if err != nil {
+		klog.Errorf("failed to get GetReleaseAction : %s", err.Error())
+		return nil, err
+	}
+
+	helmRelease, err := getAction.Run(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			klog.Warningf("release %s/%s is not found from helm", namespace, name)
+			return nil, errorModel.NotFoundError{}
+		}
+		klog.Errorf("failed to get release %s/%s from helm : %s", namespace, name, err.Error())
+		return nil, err
+	}
+	return helmImpl.convertClusterHelmRelease(clusterName, helmRelease)
+}
+
+// getRepoDefaultValues returns the default value overlay configured on the repo,
+// nil when the repo is unknown or has no overlay.
+func (helmImpl *Helm) getRepoDefaultValues(repoName string) map[string]interface{} {
+	if repoName == "" {
+		repoName = "stable"
+	}
+	if chartRepository, ok := helmImpl.chartRepoMap[repoName]; ok {
+		return chartRepository.DefaultValues
+	}
+	return nil
+}
+
 func (helmImpl *Helm) loadChart(chartFiles []*common.BufferedFile, releaseRequest *release.ReleaseRequestV2) (
-	rawChart *chart.Chart, err error) {
-	// priority: chartFiles > chartImage > chartName
+	rawChart *chart.Chart, chartProvenance *release.ChartProvenance, err error) {
+	// priority: chartFiles > chartPath > chartImage > chartName
+	// only charts fetched from a repo can be verified against the repo's provenance files
 	if chartFiles != nil {
 		rawChart, err = loader.LoadFiles(convertBufferFiles(chartFiles))
+	} else if releaseRequest.ChartPath != "" {
+		rawChart, err = loadChartFromPath(releaseRequest.ChartPath)
 	} else if releaseRequest.ChartImage != "" {
 		rawChart, err = helmImpl.getRawChartByImage(releaseRequest.ChartImage)
 	} else {
-		rawChart, err = helmImpl.getRawChartFromRepo(releaseRequest.RepoName, releaseRequest.ChartName, releaseRequest.ChartVersion)
+		rawChart, chartProvenance, err = helmImpl.getRawChartFromRepo(releaseRequest.RepoName, releaseRequest.ChartName, releaseRequest.ChartVersion)
 	}
 	return
 }
@@ -198,14 +303,16 @@ func processMetaInfoParams(metaInfo *release.ChartMetaInfo, metaInfoParams *rele
 	return nil
 }
 
-func (helmImpl *Helm) InstallOrCreateRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
+func (helmImpl *Helm) InstallOrCreateRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
 	dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error) {
-	return helmImpl.InstallOrCreateReleaseWithStrict(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, false,true)
+	return helmImpl.InstallOrCreateReleaseWithStrict(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, false,true)
 }
 
-func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
+func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile,
 	dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2, fullUpdate bool, strict bool) (*release.ReleaseCache, error) {
-	rawChart, err := helmImpl.loadChart(chartFiles, releaseRequest)
+	loadChartSpan, _ := opentracing.StartSpanFromContext(ctx, "load-chart")
+	rawChart, chartProvenance, err := helmImpl.loadChart(chartFiles, releaseRequest)
+	loadChartSpan.Finish()
 	if err != nil {
 		klog.Errorf("failed to load chart : %s", err.Error())
 		return nil, err
@@ -217,6 +324,13 @@ func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, release
 		return nil, err
 	}
 
+	// dependency outputs are only watched in the default cluster, so a release
+	// targeting a registered cluster can not depend on releases yet. external
+	// dependencies carry their output values inline, so they are fine
+	if !clusterModel.IsDefaultCluster(releaseRequest.Cluster) && len(releaseRequest.Dependencies) > 0 {
+		return nil, fmt.Errorf("failed to install release %s to cluster %s : cross cluster dependencies are not supported", releaseRequest.Name, releaseRequest.Cluster)
+	}
+
 	if releaseRequest.ConfigValues == nil {
 		releaseRequest.ConfigValues = map[string]interface{}{}
 	}
@@ -228,13 +342,14 @@ func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, release
 		}
 
 	dependencies := releaseRequest.Dependencies
+	externalDependencies := releaseRequest.ExternalDependencies
 	releaseLabels := releaseRequest.ReleaseLabels
 	releasePlugins := releaseRequest.Plugins
 	configValues := releaseRequest.ConfigValues
 	isomateConfig := releaseRequest.IsomateConfig
 	if update {
 		// reuse config values, dependencies, release labels, walm plugins
-		configValues, dependencies, releaseLabels, releasePlugins, err = reuseReleaseRequest(oldReleaseInfo, releaseRequest, fullUpdate)
+		configValues, dependencies, externalDependencies, releaseLabels, releasePlugins, err = reuseReleaseRequest(oldReleaseInfo, releaseRequest, fullUpdate)
 		if err != nil {
 			klog.Errorf("failed to reuse release request : %s", err.Error())
 			return nil, err
@@ -247,6 +362,22 @@ func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, release
 		}
 	}
 
+	// ownership and tenant accounting labels, chart provided labels win
+	if releaseLabels == nil {
+		releaseLabels = map[string]string{}
+	}
+	if _, ok := releaseLabels[k8sModel.WalmReleaseLabelKey]; !ok {
+		releaseLabels[k8sModel.WalmReleaseLabelKey] = releaseRequest.Name
+	}
+	if _, ok := releaseLabels[k8sModel.WalmNamespaceLabelKey]; !ok {
+		releaseLabels[k8sModel.WalmNamespaceLabelKey] = namespace
+	}
+	if projectName, ok := releaseLabels[projectModel.ProjectNameLabelKey]; ok && projectName != "" {
+		if _, ok := releaseLabels[k8sModel.WalmProjectLabelKey]; !ok {
+			releaseLabels[k8sModel.WalmProjectLabelKey] = projectName
+		}
+	}
+
 	// merge chart default plugins
 	if chartInfo.MetaInfo != nil {
 		releasePlugins, err = mergeReleasePlugins(releasePlugins, chartInfo.MetaInfo.Plugins)
@@ -256,6 +387,14 @@ func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, release
 		}
 	}
 
+	// a dependency is either backed by a release or declared external, the
+	// two would race to provide the same output config values
+	for dependencyName := range externalDependencies {
+		if _, ok := dependencies[dependencyName]; ok {
+			return nil, fmt.Errorf("failed to install release %s : dependency %s is declared both with a depended release and with inline output values", releaseRequest.Name, dependencyName)
+		}
+	}
+
 	// get all the dependency releases' output configs from ReleaseConfig or dummy service(for compatible)
 	dependencyConfigs, err := helmImpl.GetDependencyOutputConfigs(namespace, dependencies, chartInfo, strict)
 	if err != nil {
@@ -263,6 +402,12 @@ func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, release
 		return nil, err
 	}
 
+	err = mergeExternalDependencyConfigs(dependencyConfigs, externalDependencies, chartInfo)
+	if err != nil {
+		klog.Errorf("failed to merge the external dependencies' output configs : %s", err.Error())
+		return nil, err
+	}
+
 	// add default plugin
 	releasePlugins = addDefaultPlugins(releasePlugins)
 
@@ -275,31 +420,54 @@ func (helmImpl *Helm) InstallOrCreateReleaseWithStrict(namespace string, release
 	}
 
 	valueOverride := map[string]interface{}{}
+	// repo level default values only apply to charts installed from the repo,
+	// merged lowest so dependency configs and user supplied values always win.
+	// they are evaluated on every install or upgrade, so a changed overlay only
+	// reaches a running release on its next upgrade.
+	if chartFiles == nil && releaseRequest.ChartPath == "" && releaseRequest.ChartImage == "" {
+		util.MergeValues(valueOverride, helmImpl.getRepoDefaultValues(releaseRequest.RepoName), false)
+	}
 	util.MergeValues(valueOverride, dependencyConfigs, false)
+	// mapped dependency values sit at the same precedence as the dependency
+	// configs, so user supplied config values still win
+	applyDependencyConfigMappings(valueOverride, dependencyConfigs, externalDependencies, releaseRequest.DependenciesConfigMapping, chartInfo)
 	util.MergeValues(valueOverride, configValues, false)
 
 	valueOverride[plugins.WalmPluginConfigKey] = releasePlugins
+	if len(externalDependencies) > 0 {
+		// stored the way the walm plugins are, so the release info and
+		// upgrades can rebuild the declarations from the computed values
+		valueOverride[release.ExternalDependenciesConfigKey] = externalDependencies
+	}
 
+	processChartSpan, _ := opentracing.StartSpanFromContext(ctx, "process-chart")
 	if releaseRequest.IsomateConfig != nil && len(releaseRequest.IsomateConfig.Isomates) > 0 {
 		err = helmImpl.processChartWithIsomate(chartInfo, releaseRequest,
 			rawChart, namespace, configValues, dependencyConfigs, dependencies,
 			releaseLabels, releasePlugins, valueOverride, update)
 	if err != nil {
 			klog.Errorf("failed to process chart with isomate config : %s", err.Error())
+			processChartSpan.Finish()
 		return nil, err
 	}
 	} else {
 		err = transwarpjsonnet.ProcessChart(chartInfo, releaseRequest, rawChart, namespace, configValues, dependencyConfigs, dependencies, releaseLabels, nil)
 		if err != nil {
+			processChartSpan.Finish()
 			return nil, err
 		}
 	}
+	processChartSpan.Finish()
 
-	releaseCache, err := helmImpl.doInstallUpgradeReleaseFromChart(namespace, releaseRequest.Name, rawChart, valueOverride, update, dryRun, releasePlugins)
+	applySpan, _ := opentracing.StartSpanFromContext(ctx, "helm-apply")
+	applySpan.SetTag("dryRun", dryRun)
+	releaseCache, err := helmImpl.doInstallUpgradeReleaseFromChart(releaseRequest.Cluster, namespace, releaseRequest.Name, rawChart, valueOverride, update, dryRun, releasePlugins)
+	applySpan.Finish()
 	if err != nil {
 		klog.Errorf("failed to create or update release from chart : %s", err.Error())
 		return nil, err
 	}
+	releaseCache.ChartProvenance = chartProvenance
 
 	return releaseCache, nil
 }
@@ -378,7 +546,7 @@ func (helmImpl *Helm) processChartWithIsomate(chartInfo *release.ChartDetailInfo
 		util.MergeValues(isomateValueOverride, valueOverride, false)
 		util.MergeValues(isomateValueOverride, isomate.ConfigValues, false)
 
-		releaseCache, err := helmImpl.doInstallUpgradeReleaseFromChart(namespace, releaseRequest.Name, rawChart, isomateValueOverride, update, true, isomateReleasePlugins)
+		releaseCache, err := helmImpl.doInstallUpgradeReleaseFromChart(releaseRequest.Cluster, namespace, releaseRequest.Name, rawChart, isomateValueOverride, update, true, isomateReleasePlugins)
 		if err != nil {
 			klog.Errorf("failed to create or update release from chart : %s", err.Error())
 			return err
@@ -407,7 +575,7 @@ func (helmImpl *Helm) processChartWithIsomate(chartInfo *release.ChartDetailInfo
 }
 
 func (helmImpl *Helm) PauseOrRecoverRelease(paused bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error) {
-	getAction, err := helmImpl.getGetAction(oldReleaseInfo.Namespace)
+	getAction, err := helmImpl.getGetAction(oldReleaseInfo.Cluster, oldReleaseInfo.Namespace)
 	if err != nil {
 		klog.Errorf("failed to get GetReleaseAction : %s", err.Error())
 		return nil, err
@@ -434,7 +602,7 @@ func (helmImpl *Helm) PauseOrRecoverRelease(paused bool, oldReleaseInfo *release
 	valueOverride := helmRel.Config
 	valueOverride[plugins.WalmPluginConfigKey] = releasePlugins
 
-	releaseCache, err := helmImpl.doInstallUpgradeReleaseFromChart(oldReleaseInfo.Namespace, oldReleaseInfo.Name, rawChart, valueOverride, true, false, releasePlugins)
+	releaseCache, err := helmImpl.doInstallUpgradeReleaseFromChart(oldReleaseInfo.Cluster, oldReleaseInfo.Namespace, oldReleaseInfo.Name, rawChart, valueOverride, true, false, releasePlugins)
 	if err != nil {
 		klog.Errorf("failed to update release from chart : %s", err.Error())
 		return nil, err
@@ -442,14 +610,18 @@ func (helmImpl *Helm) PauseOrRecoverRelease(paused bool, oldReleaseInfo *release
 	return releaseCache, nil
 }
 
-func (helmImpl *Helm) doInstallUpgradeReleaseFromChart(namespace, name string, rawChart *chart.Chart, valueOverride map[string]interface{},
+func (helmImpl *Helm) doInstallUpgradeReleaseFromChart(clusterName, namespace, name string, rawChart *chart.Chart, valueOverride map[string]interface{},
 	update bool, dryRun bool, releasePlugins []*k8sModel.ReleasePlugin) (releaseCache *release.ReleaseCache, err error) {
 
 	releaseChan := make(chan *helmRelease.Release, 1)
 	releaseErrChan := make(chan error, 1)
 
 	expChan := make(chan struct{})
-	_, kubeClient := helmImpl.kubeClients.GetKubeClient(namespace)
+	kubeClients, err := helmImpl.getClusterKubeClients(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	_, kubeClient := kubeClients.GetKubeClient(namespace)
 
 	// execute pre_install plugins
 	go func() {
@@ -489,7 +661,7 @@ func (helmImpl *Helm) doInstallUpgradeReleaseFromChart(namespace, name string, r
 
 	var helmRelease *helmRelease.Release
 	if update {
-		action, err := helmImpl.getUpgradeAction(namespace)
+		action, err := helmImpl.getUpgradeAction(clusterName, namespace)
 		if err != nil {
 			return nil, err
 		}
@@ -504,7 +676,7 @@ func (helmImpl *Helm) doInstallUpgradeReleaseFromChart(namespace, name string, r
 			return nil, err
 		}
 	} else {
-		action, err := helmImpl.getInstallAction(namespace)
+		action, err := helmImpl.getInstallAction(clusterName, namespace)
 		if err != nil {
 			return nil, err
 		}
@@ -517,7 +689,7 @@ func (helmImpl *Helm) doInstallUpgradeReleaseFromChart(namespace, name string, r
 		if err != nil {
 			klog.Errorf("failed to install release %s/%s from chart : %s", namespace, name, err.Error())
 			if !dryRun {
-				action1, err1 := helmImpl.getDeleteAction(namespace)
+				action1, err1 := helmImpl.getDeleteAction(clusterName, namespace)
 				if err1 != nil {
 					klog.Errorf("failed to get helm delete action : %s", err.Error())
 				} else {
@@ -541,7 +713,7 @@ func (helmImpl *Helm) doInstallUpgradeReleaseFromChart(namespace, name string, r
 	if err != nil {
 		return nil, err
 	}
-	return helmImpl.convertHelmRelease(helmRelease)
+	return helmImpl.convertClusterHelmRelease(clusterName, helmRelease)
 }
 
 func buildContext(kubeClient *kube.Client, release *helmRelease.Release) (*plugins.PluginContext, error) {
@@ -594,6 +766,10 @@ func buildManifest(resources []runtime.Object) (string, error) {
 }
 
 func (helmImpl *Helm) convertHelmRelease(helmRelease *helmRelease.Release) (releaseCache *release.ReleaseCache, err error) {
+	return helmImpl.convertClusterHelmRelease("", helmRelease)
+}
+
+func (helmImpl *Helm) convertClusterHelmRelease(clusterName string, helmRelease *helmRelease.Release) (releaseCache *release.ReleaseCache, err error) {
 	releaseSpec := release.ReleaseSpec{}
 	releaseSpec.Name = helmRelease.Name
 	releaseSpec.Namespace = helmRelease.Namespace
@@ -604,6 +780,9 @@ func (helmImpl *Helm) convertHelmRelease(helmRelease *helmRelease.Release) (rele
 	releaseSpec.ChartAppVersion = helmRelease.Chart.Metadata.AppVersion
 	releaseSpec.ConfigValues = map[string]interface{}{}
 	util.MergeValues(releaseSpec.ConfigValues, helmRelease.Config, false)
+	if !clusterModel.IsDefaultCluster(clusterName) {
+		releaseSpec.Cluster = clusterName
+	}
 	releaseCache = &release.ReleaseCache{
 		ReleaseSpec: releaseSpec,
 	}
@@ -615,7 +794,7 @@ func (helmImpl *Helm) convertHelmRelease(helmRelease *helmRelease.Release) (rele
 	}
 
 	releaseCache.MetaInfoValues, releaseCache.PrettyParams, _ = buildMetaInfoValues(helmRelease.Chart, releaseCache.ComputedValues)
-	releaseCache.ReleaseResourceMetas, err = helmImpl.getReleaseResourceMetas(helmRelease)
+	releaseCache.ReleaseResourceMetas, err = helmImpl.getReleaseResourceMetas(clusterName, helmRelease)
 	if err != nil {
 		return nil, err
 	}
@@ -624,12 +803,16 @@ func (helmImpl *Helm) convertHelmRelease(helmRelease *helmRelease.Release) (rele
 	return
 }
 
-func (helmImpl *Helm) getReleaseResourceMetas(helmRelease *helmRelease.Release) (resources []release.ReleaseResourceMeta, err error) {
+func (helmImpl *Helm) getReleaseResourceMetas(clusterName string, helmRelease *helmRelease.Release) (resources []release.ReleaseResourceMeta, err error) {
 	resources = []release.ReleaseResourceMeta{}
 	if helmRelease.Name == "app-manager" {
 		return nil, nil
 	}
-	_, kubeClient := helmImpl.kubeClients.GetKubeClient(helmRelease.Namespace)
+	kubeClients, err := helmImpl.getClusterKubeClients(clusterName)
+	if err != nil {
+		return resources, err
+	}
+	_, kubeClient := kubeClients.GetKubeClient(helmRelease.Namespace)
 	results, err := kubeClient.Build(bytes.NewBufferString(helmRelease.Manifest))
 	if err != nil {
 		klog.Errorf("failed to get release resource metas of %s", helmRelease.Name)
@@ -663,32 +846,32 @@ func buildMetaInfoValues(chart *chart.Chart, computedValues map[string]interface
 	return nil, nil, nil
 }
 
-func (helmImpl *Helm) getGetAction(namespace string) (*action.Get, error) {
-	config, err := helmImpl.getActionConfig(namespace)
+func (helmImpl *Helm) getGetAction(clusterName, namespace string) (*action.Get, error) {
+	config, err := helmImpl.getActionConfig(clusterName, namespace)
 	if err != nil {
 			return nil, err
 		}
 	return action.NewGet(config), nil
 }
 
-func (helmImpl *Helm) getInstallAction(namespace string) (*action.Install, error) {
-	config, err := helmImpl.getActionConfig(namespace)
+func (helmImpl *Helm) getInstallAction(clusterName, namespace string) (*action.Install, error) {
+	config, err := helmImpl.getActionConfig(clusterName, namespace)
 	if err != nil {
 		return nil, err
 	}
 	return action.NewInstall(config), nil
 }
 
-func (helmImpl *Helm) getUpgradeAction(namespace string) (*action.Upgrade, error) {
-	config, err := helmImpl.getActionConfig(namespace)
+func (helmImpl *Helm) getUpgradeAction(clusterName, namespace string) (*action.Upgrade, error) {
+	config, err := helmImpl.getActionConfig(clusterName, namespace)
 	if err != nil {
 		return nil, err
 	}
 	return action.NewUpgrade(config), nil
 }
 
-func (helmImpl *Helm) getDeleteAction(namespace string) (*action.Uninstall, error) {
-	config, err := helmImpl.getActionConfig(namespace)
+func (helmImpl *Helm) getDeleteAction(clusterName, namespace string) (*action.Uninstall, error) {
+	config, err := helmImpl.getActionConfig(clusterName, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -749,8 +932,8 @@ func buildResourceKey(resource *resource.Info) string {
 }
 
 func reuseReleaseRequest(releaseInfo *release.ReleaseInfoV2, releaseRequest *release.ReleaseRequestV2, fullUpdate bool) (
-	configValues map[string]interface{}, dependencies map[string]string, releaseLabels map[string]string,
-	walmPlugins []*k8sModel.ReleasePlugin, err error,
+	configValues map[string]interface{}, dependencies map[string]string, externalDependencies map[string]map[string]interface{},
+	releaseLabels map[string]string, walmPlugins []*k8sModel.ReleasePlugin, err error,
 ) {
 	configValues = map[string]interface{}{}
 
@@ -768,7 +951,16 @@ func reuseReleaseRequest(releaseInfo *release.ReleaseInfoV2, releaseRequest *rel
 		util.MergeValues(configValues, releaseRequest.ConfigValues, true)
 	} else {
 	util.MergeValues(configValues, releaseInfo.ConfigValues, false)
-	util.MergeValues(configValues, releaseRequest.ConfigValues, false)
+	switch releaseRequest.ListMergeStrategy {
+	case "", release.ListMergeStrategyReplace:
+		util.MergeValues(configValues, releaseRequest.ConfigValues, releaseRequest.NullToDelete)
+	case release.ListMergeStrategyAppend:
+		util.MergeValuesWithAppendList(configValues, releaseRequest.ConfigValues, releaseRequest.NullToDelete)
+	default:
+		err = fmt.Errorf("list merge strategy %s is not supported, supported strategies : %s, %s",
+			releaseRequest.ListMergeStrategy, release.ListMergeStrategyReplace, release.ListMergeStrategyAppend)
+		return
+	}
 	}
 
 	dependencies = map[string]string{}
@@ -776,6 +968,9 @@ func reuseReleaseRequest(releaseInfo *release.ReleaseInfoV2, releaseRequest *rel
 		dependencies[key] = value
 	}
 	for key, value := range releaseRequest.Dependencies {
+		// the soft marker only matters to the project dep parse, the
+		// release config should store the plain release name
+		value, _ = release.ParseDependencyValue(value)
 		if value == "" {
 			if _, ok := dependencies[key]; ok {
 				delete(dependencies, key)
@@ -785,6 +980,20 @@ func reuseReleaseRequest(releaseInfo *release.ReleaseInfoV2, releaseRequest *rel
 		}
 	}
 
+	externalDependencies = map[string]map[string]interface{}{}
+	for key, value := range releaseInfo.ExternalDependencies {
+		externalDependencies[key] = value
+	}
+	for key, value := range releaseRequest.ExternalDependencies {
+		if len(value) == 0 {
+			if _, ok := externalDependencies[key]; ok {
+				delete(externalDependencies, key)
+			}
+		} else {
+			externalDependencies[key] = value
+		}
+	}
+
 	releaseLabels = map[string]string{}
 	for key, value := range releaseInfo.ReleaseLabels {
 		releaseLabels[key] = value
@@ -806,6 +1015,76 @@ func reuseReleaseRequest(releaseInfo *release.ReleaseInfoV2, releaseRequest *rel
 	return
 }
 
+// mergeExternalDependencyConfigs injects the inline output config values of
+// the external dependencies as if they were published by the depended
+// releases' release configs, so the chart templates do not care whether a
+// dependency is walm managed or external.
+func mergeExternalDependencyConfigs(dependencyConfigs map[string]interface{}, externalDependencies map[string]map[string]interface{}, chartInfo *release.ChartDetailInfo) error {
+	if len(externalDependencies) == 0 {
+		return nil
+	}
+
+	dependencyAliasConfigVars := map[string]string{}
+	if chartInfo.MetaInfo != nil {
+		for _, chartDependency := range chartInfo.MetaInfo.ChartDependenciesInfo {
+			dependencyAliasConfigVars[chartDependency.Name] = chartDependency.AliasConfigVar
+		}
+	}
+
+	for dependencyName, outputConfig := range externalDependencies {
+		dependencyAliasConfigVar, ok := dependencyAliasConfigVars[dependencyName]
+		if !ok {
+			return fmt.Errorf("external dependency key %s is not valid, you can see valid keys in chart metainfo", dependencyName)
+		}
+		if len(outputConfig) > 0 {
+			dependencyConfigs[dependencyAliasConfigVar] = outputConfig
+		}
+	}
+	return nil
+}
+
+// applyDependencyConfigMappings renames keys from the dependencies' output
+// values into the config values the chart is rendered with, for charts
+// expecting the values at other paths than the dependency publishes them.
+// The dry run renders with the same values, so mappings show up there too.
+// An unknown source key only logs a warning, a silently injected empty
+// value would be much harder to debug.
+func applyDependencyConfigMappings(valueOverride map[string]interface{}, dependencyConfigs map[string]interface{},
+	externalDependencies map[string]map[string]interface{}, dependenciesConfigMapping map[string]map[string]string, chartInfo *release.ChartDetailInfo) {
+	if len(dependenciesConfigMapping) == 0 {
+		return
+	}
+
+	dependencyAliasConfigVars := map[string]string{}
+	if chartInfo.MetaInfo != nil {
+		for _, chartDependency := range chartInfo.MetaInfo.ChartDependenciesInfo {
+			dependencyAliasConfigVars[chartDependency.Name] = chartDependency.AliasConfigVar
+		}
+	}
+
+	for dependencyName, configMapping := range dependenciesConfigMapping {
+		var outputConfig map[string]interface{}
+		if externalOutputConfig, ok := externalDependencies[dependencyName]; ok {
+			outputConfig = externalOutputConfig
+		} else if dependencyAliasConfigVar, ok := dependencyAliasConfigVars[dependencyName]; ok {
+			outputConfig, _ = dependencyConfigs[dependencyAliasConfigVar].(map[string]interface{})
+		}
+		if len(outputConfig) == 0 {
+			klog.Warningf("dependency %s has no output config values, its config mapping is skipped", dependencyName)
+			continue
+		}
+
+		for sourceKey, targetPath := range configMapping {
+			value, ok := util.GetValueByPath(outputConfig, sourceKey)
+			if !ok {
+				klog.Warningf("output config of dependency %s has no key %s, mapping it to %s is skipped", dependencyName, sourceKey, targetPath)
+				continue
+			}
+			util.SetValueByPath(valueOverride, targetPath, value)
+		}
+	}
+}
+
 func mergeIsomateConfig(isomateConfig, oldIsomateConfig *k8sModel.IsomateConfig) (err error) {
 	if isomateConfig == nil || oldIsomateConfig == nil || len(oldIsomateConfig.Isomates) == 0 {
 		return nil
@@ -863,32 +1142,56 @@ func convertBufferFiles(chartFiles []*common.BufferedFile) []*loader.BufferedFil
 	return result
 }
 
+// ReloadRepoList applies a changed repo list at runtime. The repo index cache
+// is dropped so charts are fetched from the new repos right away.
+func (helmImpl *Helm) ReloadRepoList(repoList []*setting.ChartRepo) {
+	chartRepoMap := make(map[string]*ChartRepository)
+	for _, chartRepo := range repoList {
+		chartRepoMap[chartRepo.Name] = &ChartRepository{
+			Name:          chartRepo.Name,
+			URL:           chartRepo.URL,
+			Username:      "",
+			Password:      "",
+			DefaultValues: chartRepo.DefaultValues,
+		}
+	}
+	helmImpl.chartRepoMap = chartRepoMap
+	helmImpl.repoIndexCache.Purge()
+}
+
 func NewHelm(repoList []*setting.ChartRepo, registryClient *registry.Client, k8sCache k8s.Cache, kubeClients *k8sHelm.Client) (*Helm, error) {
 	chartRepoMap := make(map[string]*ChartRepository)
 
 	for _, chartRepo := range repoList {
 		chartRepository := ChartRepository{
-			Name:     chartRepo.Name,
-			URL:      chartRepo.URL,
-			Username: "",
-			Password: "",
+			Name:          chartRepo.Name,
+			URL:           chartRepo.URL,
+			Username:      "",
+			Password:      "",
+			DefaultValues: chartRepo.DefaultValues,
 		}
 		chartRepoMap[chartRepo.Name] = &chartRepository
 	}
 
 	actionConfigs, _ := lru.New(100)
+	metainfoCache, _ := lru.New(100)
+	repoIndexCache, _ := lru.New(100)
+	clusterKubeClients, _ := lru.New(20)
 	restyClient := resty.New()
 	restyClient.SetTimeout(defaultDownloadTimeout)
 	helm := &Helm{
-		k8sCache:       k8sCache,
-		kubeClients:    kubeClients,
-		registryClient: registryClient,
-		chartRepoMap:   chartRepoMap,
-		actionConfigs:  actionConfigs,
-		restyClient:    restyClient,
-	}
-
-	actionConfig, err := helm.getActionConfig("")
+		k8sCache:           k8sCache,
+		kubeClients:        kubeClients,
+		registryClient:     registryClient,
+		chartRepoMap:       chartRepoMap,
+		actionConfigs:      actionConfigs,
+		metainfoCache:      metainfoCache,
+		repoIndexCache:     repoIndexCache,
+		restyClient:        restyClient,
+		clusterKubeClients: clusterKubeClients,
+	}
+
+	actionConfig, err := helm.getActionConfig("", "")
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	common "WarpCloud/walm/pkg/models/common"
 
 	mock "github.com/stretchr/testify/mock"
@@ -15,13 +17,27 @@ type Helm struct {
 	mock.Mock
 }
 
-// DeleteRelease provides a mock function with given fields: namespace, name
-func (_m *Helm) DeleteRelease(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeleteClusterRelease provides a mock function with given fields: ctx, clusterName, namespace, name
+func (_m *Helm) DeleteClusterRelease(ctx context.Context, clusterName string, namespace string, name string) error {
+	ret := _m.Called(ctx, clusterName, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, clusterName, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRelease provides a mock function with given fields: ctx, namespace, name
+func (_m *Helm) DeleteRelease(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -52,6 +68,29 @@ func (_m *Helm) GetChartAutoDependencies(repoName string, chartName string, char
 	return r0, r1
 }
 
+// GetChartAutoDependenciesFromPath provides a mock function with given fields: chartPath
+func (_m *Helm) GetChartAutoDependenciesFromPath(chartPath string) ([]string, error) {
+	ret := _m.Called(chartPath)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(chartPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(chartPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChartDetailInfo provides a mock function with given fields: repoName, chartName, chartVersion
 func (_m *Helm) GetChartDetailInfo(repoName string, chartName string, chartVersion string) (*release.ChartDetailInfo, error) {
 	ret := _m.Called(repoName, chartName, chartVersion)
@@ -98,6 +137,75 @@ func (_m *Helm) GetChartList(repoName string) (*release.ChartInfoList, error) {
 	return r0, r1
 }
 
+// GetChartMetadata provides a mock function with given fields: repoName, chartName, chartVersion
+func (_m *Helm) GetChartMetadata(repoName string, chartName string, chartVersion string) (*release.ChartMetadata, error) {
+	ret := _m.Called(repoName, chartName, chartVersion)
+
+	var r0 *release.ChartMetadata
+	if rf, ok := ret.Get(0).(func(string, string, string) *release.ChartMetadata); ok {
+		r0 = rf(repoName, chartName, chartVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ChartMetadata)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(repoName, chartName, chartVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChartMetainfo provides a mock function with given fields: repoName, chartName, chartVersion
+func (_m *Helm) GetChartMetainfo(repoName string, chartName string, chartVersion string) (*release.ChartMetainfo, error) {
+	ret := _m.Called(repoName, chartName, chartVersion)
+
+	var r0 *release.ChartMetainfo
+	if rf, ok := ret.Get(0).(func(string, string, string) *release.ChartMetainfo); ok {
+		r0 = rf(repoName, chartName, chartVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ChartMetainfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(repoName, chartName, chartVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClusterReleaseCache provides a mock function with given fields: clusterName, namespace, name
+func (_m *Helm) GetClusterReleaseCache(clusterName string, namespace string, name string) (*release.ReleaseCache, error) {
+	ret := _m.Called(clusterName, namespace, name)
+
+	var r0 *release.ReleaseCache
+	if rf, ok := ret.Get(0).(func(string, string, string) *release.ReleaseCache); ok {
+		r0 = rf(clusterName, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ReleaseCache)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(clusterName, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDependencyOutputConfigs provides a mock function with given fields: namespace, dependencies, chartInfo, strict
 func (_m *Helm) GetDependencyOutputConfigs(namespace string, dependencies map[string]string, chartInfo *release.ChartDetailInfo, strict bool) (map[string]interface{}, error) {
 	ret := _m.Called(namespace, dependencies, chartInfo, strict)
@@ -121,6 +229,29 @@ func (_m *Helm) GetDependencyOutputConfigs(namespace string, dependencies map[st
 	return r0, r1
 }
 
+// GetReleaseCache provides a mock function with given fields: namespace, name
+func (_m *Helm) GetReleaseCache(namespace string, name string) (*release.ReleaseCache, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *release.ReleaseCache
+	if rf, ok := ret.Get(0).(func(string, string) *release.ReleaseCache); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ReleaseCache)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDetailChartInfoByImage provides a mock function with given fields: chartImage
 func (_m *Helm) GetDetailChartInfoByImage(chartImage string) (*release.ChartDetailInfo, error) {
 	ret := _m.Called(chartImage)
@@ -160,13 +291,13 @@ func (_m *Helm) GetRepoList() *release.RepoInfoList {
 	return r0
 }
 
-// InstallOrCreateRelease provides a mock function with given fields: namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo
-func (_m *Helm) InstallOrCreateRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error) {
-	ret := _m.Called(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo)
+// InstallOrCreateRelease provides a mock function with given fields: ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo
+func (_m *Helm) InstallOrCreateRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error) {
+	ret := _m.Called(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo)
 
 	var r0 *release.ReleaseCache
-	if rf, ok := ret.Get(0).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2) *release.ReleaseCache); ok {
-		r0 = rf(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2) *release.ReleaseCache); ok {
+		r0 = rf(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*release.ReleaseCache)
@@ -174,8 +305,8 @@ func (_m *Helm) InstallOrCreateRelease(namespace string, releaseRequest *release
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2) error); ok {
-		r1 = rf(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2) error); ok {
+		r1 = rf(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -183,13 +314,13 @@ func (_m *Helm) InstallOrCreateRelease(namespace string, releaseRequest *release
 	return r0, r1
 }
 
-// InstallOrCreateReleaseWithStrict provides a mock function with given fields: namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict
-func (_m *Helm) InstallOrCreateReleaseWithStrict(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2, fullUpdate bool, strict bool) (*release.ReleaseCache, error) {
-	ret := _m.Called(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict)
+// InstallOrCreateReleaseWithStrict provides a mock function with given fields: ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict
+func (_m *Helm) InstallOrCreateReleaseWithStrict(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, dryRun bool, update bool, oldReleaseInfo *release.ReleaseInfoV2, fullUpdate bool, strict bool) (*release.ReleaseCache, error) {
+	ret := _m.Called(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict)
 
 	var r0 *release.ReleaseCache
-	if rf, ok := ret.Get(0).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2, bool, bool) *release.ReleaseCache); ok {
-		r0 = rf(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2, bool, bool) *release.ReleaseCache); ok {
+		r0 = rf(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*release.ReleaseCache)
@@ -197,8 +328,8 @@ func (_m *Helm) InstallOrCreateReleaseWithStrict(namespace string, releaseReques
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2, bool, bool) error); ok {
-		r1 = rf(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, bool, *release.ReleaseInfoV2, bool, bool) error); ok {
+		r1 = rf(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, fullUpdate, strict)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -229,6 +360,52 @@ func (_m *Helm) ListAllReleases() ([]*release.ReleaseCache, error) {
 	return r0, r1
 }
 
+// ListChartVersions provides a mock function with given fields: repoName, chartName, refresh
+func (_m *Helm) ListChartVersions(repoName string, chartName string, refresh bool) (*release.ChartVersionList, error) {
+	ret := _m.Called(repoName, chartName, refresh)
+
+	var r0 *release.ChartVersionList
+	if rf, ok := ret.Get(0).(func(string, string, bool) *release.ChartVersionList); ok {
+		r0 = rf(repoName, chartName, refresh)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ChartVersionList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+		r1 = rf(repoName, chartName, refresh)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCharts provides a mock function with given fields: repoName, limit, continueToken, refresh
+func (_m *Helm) ListCharts(repoName string, limit int, continueToken string, refresh bool) (*release.ChartBrowseList, error) {
+	ret := _m.Called(repoName, limit, continueToken, refresh)
+
+	var r0 *release.ChartBrowseList
+	if rf, ok := ret.Get(0).(func(string, int, string, bool) *release.ChartBrowseList); ok {
+		r0 = rf(repoName, limit, continueToken, refresh)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ChartBrowseList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int, string, bool) error); ok {
+		r1 = rf(repoName, limit, continueToken, refresh)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PauseOrRecoverRelease provides a mock function with given fields: paused, oldReleaseInfo
 func (_m *Helm) PauseOrRecoverRelease(paused bool, oldReleaseInfo *release.ReleaseInfoV2) (*release.ReleaseCache, error) {
 	ret := _m.Called(paused, oldReleaseInfo)
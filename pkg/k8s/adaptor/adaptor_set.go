@@ -2,14 +2,20 @@ package adaptor
 
 import (
 	"WarpCloud/walm/pkg/k8s/handler"
+	"sync"
 )
 
 var defaultAdaptorSet *AdaptorSet
+var defaultAdaptorSetOnce sync.Once
+
+func NewAdaptorSet(handlerSet *handler.HandlerSet) *AdaptorSet {
+	return &AdaptorSet{handlerSet: handlerSet}
+}
 
 func GetDefaultAdaptorSet() *AdaptorSet {
-	if defaultAdaptorSet == nil {
-		defaultAdaptorSet = &AdaptorSet{handlerSet: handler.GetDefaultHandlerSet()}
-	}
+	defaultAdaptorSetOnce.Do(func() {
+		defaultAdaptorSet = NewAdaptorSet(handler.GetDefaultHandlerSet())
+	})
 	return defaultAdaptorSet
 }
 
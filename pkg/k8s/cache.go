@@ -8,15 +8,29 @@ import (
 
 type Cache interface {
 	GetResourceSet(releaseResourceMetas []release.ReleaseResourceMeta) (resourceSet *k8s.ResourceSet,err error)
+	// GetResourceSetLive is GetResourceSet with the workload kinds driving readiness
+	// read from the apiserver instead of the informer caches, for callers needing
+	// strict consistency
+	GetResourceSetLive(releaseResourceMetas []release.ReleaseResourceMeta) (resourceSet *k8s.ResourceSet, err error)
 	GetResource(kind k8s.ResourceKind, namespace, name string) (k8s.Resource, error)
+	// GetCacheSyncInfo reports whether the informer caches finished their initial
+	// sync, data served before that is cache-only and may be incomplete
+	GetCacheSyncInfo() *k8s.CacheSyncInfo
 
 	AddReleaseConfigHandler(OnAdd func(obj interface{}), OnUpdate func(oldObj, newObj interface{}), OnDelete func(obj interface{}))
 	ListReleaseConfigs(namespace, labelSelectorStr string) ([]*k8s.ReleaseConfig, error)
+	// ListReleaseConfigsByDependency returns the release configs depending on the given
+	// release, served by the dependency index instead of a full scan
+	ListReleaseConfigsByDependency(namespace, name string) ([]*k8s.ReleaseConfig, error)
 
 	ListPersistentVolumeClaims(namespace string, labelSelectorStr string) ([]*k8s.PersistentVolumeClaim, error)
 
 	ListTenants(labelSelectorStr string) (*tenant.TenantInfoList, error)
+	// diff with ListTenants : quota usage queries are skipped, only the basic
+	// tenant fields are filled
+	ListTenantsSummary(labelSelectorStr string) (*tenant.TenantInfoList, error)
 	GetTenant(tenantName string) (*tenant.TenantInfo, error)
+	ListTenantServices(tenantName string) (*tenant.TenantServiceInfoList, error)
 
 	GetNodes(labelSelector string) ([]*k8s.Node, error)
 
@@ -24,6 +38,8 @@ type Cache interface {
 
 	GetPodEventList(namespace string, name string) (*k8s.EventList, error)
 	GetPodLogs(namespace string, podName string, containerName string, tailLines int64) (string, error)
+	// pod events and the tail of each container's logs in one call
+	GetPodDetail(namespace string, podName string, containerName string, previous bool, tailLines int64) (*k8s.PodDetail, error)
 
 	ListSecrets(namespace string, name string) (*k8s.SecretList, error)
 
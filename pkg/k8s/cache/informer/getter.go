@@ -131,6 +131,84 @@ func (informer *Informer) getStatefulSet(namespace, name string) (k8s.Resource,
 	return converter.ConvertStatefulSetFromK8s(resource, pods)
 }
 
+// the live getters read the resources from the apiserver instead of the informer
+// caches, so callers needing strict consistency pay the query cost only when
+// they ask for it
+
+func (informer *Informer) getDaemonSetLive(namespace, name string) (k8s.Resource, error) {
+	resource, err := informer.client.ExtensionsV1beta1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return convertResourceError(err, &k8s.DaemonSet{
+			Meta: k8s.NewNotFoundMeta(k8s.DaemonSetKind, namespace, name),
+		})
+	}
+	pods, err := informer.listPodsLive(namespace, resource.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return converter.ConvertDaemonSetFromK8s(resource, pods)
+}
+
+func (informer *Informer) getDeploymentLive(namespace, name string) (k8s.Resource, error) {
+	resource, err := informer.client.ExtensionsV1beta1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return convertResourceError(err, &k8s.Deployment{
+			Meta: k8s.NewNotFoundMeta(k8s.DeploymentKind, namespace, name),
+		})
+	}
+	pods, err := informer.listPodsLive(namespace, resource.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return converter.ConvertDeploymentFromK8s(resource, pods)
+}
+
+func (informer *Informer) getJobLive(namespace, name string) (k8s.Resource, error) {
+	resource, err := informer.client.BatchV1().Jobs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return convertResourceError(err, &k8s.Job{
+			Meta: k8s.NewNotFoundMeta(k8s.JobKind, namespace, name),
+		})
+	}
+	pods, err := informer.listPodsLive(namespace, resource.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return converter.ConvertJobFromK8s(resource, pods)
+}
+
+func (informer *Informer) getStatefulSetLive(namespace, name string) (k8s.Resource, error) {
+	resource, err := informer.client.AppsV1beta1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return convertResourceError(err, &k8s.StatefulSet{
+			Meta: k8s.NewNotFoundMeta(k8s.StatefulSetKind, namespace, name),
+		})
+	}
+	pods, err := informer.listPodsLive(namespace, resource.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return converter.ConvertStatefulSetFromK8s(resource, pods)
+}
+
+func (informer *Informer) listPodsLive(namespace string, labelSelector *metav1.LabelSelector) ([]*corev1.Pod, error) {
+	selector, err := utils.ConvertLabelSelectorToSelector(labelSelector)
+	if err != nil {
+		logrus.Errorf("failed to convert label selector : %s", err.Error())
+		return nil, err
+	}
+	podList, err := informer.client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		logrus.Errorf("failed to list pods : %s", err.Error())
+		return nil, err
+	}
+	pods := []*corev1.Pod{}
+	for i := range podList.Items {
+		pods = append(pods, &podList.Items[i])
+	}
+	return pods, nil
+}
+
 func (informer *Informer) listPods(namespace string, labelSelector *metav1.LabelSelector) ([]*corev1.Pod, error) {
 	selector, err := utils.ConvertLabelSelectorToSelector(labelSelector)
 	if err != nil {
@@ -7,8 +7,10 @@ import (
 	listv1beta1 "k8s.io/client-go/listers/extensions/v1beta1"
 	"k8s.io/client-go/listers/core/v1"
 	batchv1 "k8s.io/client-go/listers/batch/v1"
+	rbaclistv1 "k8s.io/client-go/listers/rbac/v1"
 	"k8s.io/client-go/listers/apps/v1beta1"
 	storagev1 "k8s.io/client-go/listers/storage/v1"
+	transwarpv1beta1 "transwarp/release-config/pkg/apis/transwarp/v1beta1"
 	releaseconfigexternalversions "transwarp/release-config/pkg/client/informers/externalversions"
 	releaseconfigv1beta1 "transwarp/release-config/pkg/client/listers/transwarp/v1beta1"
 	releaseconfigclientset "transwarp/release-config/pkg/client/clientset/versioned"
@@ -21,6 +23,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"sort"
 	"WarpCloud/walm/pkg/k8s/utils"
+	releaseUtils "WarpCloud/walm/pkg/release/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sync"
@@ -28,7 +31,7 @@ import (
 )
 
 type Informer struct {
-	client                      *kubernetes.Clientset
+	client                      kubernetes.Interface
 	factory                     informers.SharedInformerFactory
 	deploymentLister            listv1beta1.DeploymentLister
 	configMapLister             v1.ConfigMapLister
@@ -46,9 +49,37 @@ type Informer struct {
 	storageClassLister          storagev1.StorageClassLister
 	endpointsLister             v1.EndpointsLister
 	limitRangeLister            v1.LimitRangeLister
+	roleBindingLister           rbaclistv1.RoleBindingLister
 
 	releaseConifgFactory releaseconfigexternalversions.SharedInformerFactory
 	releaseConfigLister  releaseconfigv1beta1.ReleaseConfigLister
+	releaseConfigIndexer cache.Indexer
+
+	// zero until the informer caches finished their initial sync
+	syncTimeMux sync.RWMutex
+	syncTime    time.Time
+}
+
+const releaseConfigDependencyIndex = "dependedRelease"
+
+const initialCacheSyncTimeout = 30 * time.Second
+
+// releaseConfigDependencyIndexFunc indexes release configs by the namespace/name of every
+// release they depend on, so dependent lookup does not need to scan all release configs.
+func releaseConfigDependencyIndexFunc(obj interface{}) ([]string, error) {
+	releaseConfig, ok := obj.(*transwarpv1beta1.ReleaseConfig)
+	if !ok {
+		return []string{}, nil
+	}
+	indexKeys := []string{}
+	for _, dependedRelease := range releaseConfig.Spec.Dependencies {
+		dependedReleaseNamespace, dependedReleaseName, err := releaseUtils.ParseDependedRelease(releaseConfig.Namespace, dependedRelease)
+		if err != nil {
+			continue
+		}
+		indexKeys = append(indexKeys, dependedReleaseNamespace+"/"+dependedReleaseName)
+	}
+	return indexKeys, nil
 }
 
 func (informer *Informer)ListStorageClasses(namespace string, labelSelectorStr string) ([]*k8s.StorageClass, error) {
@@ -92,6 +123,81 @@ func (informer *Informer) GetPodLogs(namespace string, podName string, container
 	return string(logs), nil
 }
 
+const (
+	podDetailDefaultTailLines int64 = 100
+	podDetailMaxTailLines     int64 = 1000
+)
+
+func (informer *Informer) GetPodDetail(namespace string, podName string, containerName string, previous bool, tailLines int64) (*k8s.PodDetail, error) {
+	pod, err := informer.podLister.Pods(namespace).Get(podName)
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			logrus.Warnf("pod %s/%s is not found", namespace, podName)
+			return nil, errorModel.NotFoundError{}
+		}
+		logrus.Errorf("failed to get pod %s/%s : %s", namespace, podName, err.Error())
+		return nil, err
+	}
+
+	eventList, err := informer.GetPodEventList(namespace, podName)
+	if err != nil {
+		logrus.Errorf("failed to get pod events : %s", err.Error())
+		return nil, err
+	}
+
+	containerNames := buildPodContainerNames(pod, containerName)
+	if len(containerNames) == 0 {
+		logrus.Warnf("container %s is not found in pod %s/%s", containerName, namespace, podName)
+		return nil, errorModel.NotFoundError{}
+	}
+
+	if tailLines <= 0 {
+		tailLines = podDetailDefaultTailLines
+	}
+	if tailLines > podDetailMaxTailLines {
+		tailLines = podDetailMaxTailLines
+	}
+
+	podDetail := &k8s.PodDetail{
+		Events: eventList.Events,
+		Logs:   []k8s.PodContainerLog{},
+	}
+	for _, name := range containerNames {
+		containerTailLines := tailLines
+		podLogOptions := &corev1.PodLogOptions{
+			Container: name,
+			TailLines: &containerTailLines,
+			Previous:  previous,
+		}
+		logs, err := informer.client.CoreV1().Pods(namespace).GetLogs(podName, podLogOptions).Do().Raw()
+		if err != nil {
+			// a container may not be started yet or have no previous instance
+			logrus.Warnf("failed to get logs of container %s in pod %s/%s : %s", name, namespace, podName, err.Error())
+			continue
+		}
+		podDetail.Logs = append(podDetail.Logs, k8s.PodContainerLog{
+			ContainerName: name,
+			Log:           string(logs),
+		})
+	}
+	return podDetail, nil
+}
+
+func buildPodContainerNames(pod *corev1.Pod, containerName string) []string {
+	containerNames := []string{}
+	for _, container := range pod.Spec.InitContainers {
+		if containerName == "" || containerName == container.Name {
+			containerNames = append(containerNames, container.Name)
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if containerName == "" || containerName == container.Name {
+			containerNames = append(containerNames, container.Name)
+		}
+	}
+	return containerNames
+}
+
 func (informer *Informer) GetPodEventList(namespace string, name string) (*k8s.EventList, error) {
 	pod, err := informer.podLister.Pods(namespace).Get(name)
 	if err != nil {
@@ -291,6 +397,31 @@ func (informer *Informer) ListReleaseConfigs(namespace, labelSelectorStr string)
 	return releaseConfigs, nil
 }
 
+// ListReleaseConfigsByDependency returns the release configs whose dependencies point at
+// the given release, served by the dependency index instead of a full scan.
+func (informer *Informer) ListReleaseConfigsByDependency(namespace, name string) ([]*k8s.ReleaseConfig, error) {
+	objs, err := informer.releaseConfigIndexer.ByIndex(releaseConfigDependencyIndex, namespace+"/"+name)
+	if err != nil {
+		logrus.Errorf("failed to list release configs depending on %s/%s : %s", namespace, name, err.Error())
+		return nil, err
+	}
+
+	releaseConfigs := []*k8s.ReleaseConfig{}
+	for _, obj := range objs {
+		resource, ok := obj.(*transwarpv1beta1.ReleaseConfig)
+		if !ok {
+			continue
+		}
+		releaseConfig, err := converter.ConvertReleaseConfigFromK8s(resource)
+		if err != nil {
+			logrus.Errorf("failed to convert release config %s/%s: %s", resource.Namespace, resource.Name, err.Error())
+			return nil, err
+		}
+		releaseConfigs = append(releaseConfigs, releaseConfig)
+	}
+	return releaseConfigs, nil
+}
+
 func (informer *Informer) GetResourceSet(releaseResourceMetas []release.ReleaseResourceMeta) (resourceSet *k8s.ResourceSet, err error) {
 	resourceSet = k8s.NewResourceSet()
 	for _, resourceMeta := range releaseResourceMetas {
@@ -304,6 +435,38 @@ func (informer *Informer) GetResourceSet(releaseResourceMetas []release.ReleaseR
 	return
 }
 
+// GetResourceSetLive is GetResourceSet with the workload kinds driving readiness
+// read from the apiserver instead of the informer caches, for callers needing
+// strict consistency.
+func (informer *Informer) GetResourceSetLive(releaseResourceMetas []release.ReleaseResourceMeta) (resourceSet *k8s.ResourceSet, err error) {
+	resourceSet = k8s.NewResourceSet()
+	for _, resourceMeta := range releaseResourceMetas {
+		resource, err := informer.getResourceLive(resourceMeta.Kind, resourceMeta.Namespace, resourceMeta.Name)
+		// if resource is not found , do not return error, add it into resource set, so resource should not be nil
+		if err != nil && !errorModel.IsNotFoundError(err) {
+			return nil, err
+		}
+		resource.AddToResourceSet(resourceSet)
+	}
+	return
+}
+
+func (informer *Informer) getResourceLive(kind k8s.ResourceKind, namespace, name string) (k8s.Resource, error) {
+	switch kind {
+	case k8s.DaemonSetKind:
+		return informer.getDaemonSetLive(namespace, name)
+	case k8s.DeploymentKind:
+		return informer.getDeploymentLive(namespace, name)
+	case k8s.StatefulSetKind:
+		return informer.getStatefulSetLive(namespace, name)
+	case k8s.JobKind:
+		return informer.getJobLive(namespace, name)
+	default:
+		// the other kinds rarely drive readiness, they stay on the informer caches
+		return informer.GetResource(kind, namespace, name)
+	}
+}
+
 func (informer *Informer) GetResource(kind k8s.ResourceKind, namespace, name string) (k8s.Resource, error) {
 	switch kind {
 	case k8s.ReleaseConfigKind:
@@ -345,11 +508,25 @@ func (informer *Informer) waitForCacheSync(stopCh <-chan struct{}) {
 	informer.releaseConifgFactory.WaitForCacheSync(stopCh)
 }
 
+// GetCacheSyncInfo reports whether the informer caches finished their initial
+// sync, so callers can tell the cache-only data served during startup from
+// synced data.
+func (informer *Informer) GetCacheSyncInfo() *k8s.CacheSyncInfo {
+	informer.syncTimeMux.RLock()
+	defer informer.syncTimeMux.RUnlock()
+	return &k8s.CacheSyncInfo{
+		Synced:   !informer.syncTime.IsZero(),
+		SyncTime: informer.syncTime,
+	}
+}
+
 func (informer *Informer) searchEvents(namespace string, objOrRef runtime.Object) (*corev1.EventList, error) {
 	return informer.client.CoreV1().Events(namespace).Search(runtime.NewScheme(), objOrRef)
 }
 
-func NewInformer(client *kubernetes.Clientset, releaseConfigClient *releaseconfigclientset.Clientset, resyncPeriod time.Duration, stopCh <-chan struct{}) (*Informer) {
+// NewInformer takes the client interfaces instead of the concrete clientsets,
+// so tests can inject the generated fake clientsets.
+func NewInformer(client kubernetes.Interface, releaseConfigClient releaseconfigclientset.Interface, resyncPeriod time.Duration, stopCh <-chan struct{}) (*Informer) {
 	informer := &Informer{}
 	informer.client = client
 	informer.factory = informers.NewSharedInformerFactory(client, resyncPeriod)
@@ -369,12 +546,34 @@ func NewInformer(client *kubernetes.Clientset, releaseConfigClient *releaseconfi
 	informer.storageClassLister = informer.factory.Storage().V1().StorageClasses().Lister()
 	informer.endpointsLister = informer.factory.Core().V1().Endpoints().Lister()
 	informer.limitRangeLister = informer.factory.Core().V1().LimitRanges().Lister()
+	informer.roleBindingLister = informer.factory.Rbac().V1().RoleBindings().Lister()
 
 	informer.releaseConifgFactory = releaseconfigexternalversions.NewSharedInformerFactory(releaseConfigClient, resyncPeriod)
 	informer.releaseConfigLister = informer.releaseConifgFactory.Transwarp().V1beta1().ReleaseConfigs().Lister()
+	releaseConfigInformer := informer.releaseConifgFactory.Transwarp().V1beta1().ReleaseConfigs().Informer()
+	// indexers can only be added before the informer is started
+	err := releaseConfigInformer.AddIndexers(cache.Indexers{releaseConfigDependencyIndex: releaseConfigDependencyIndexFunc})
+	if err != nil {
+		logrus.Errorf("failed to add release config dependency index : %s", err.Error())
+	}
+	informer.releaseConfigIndexer = releaseConfigInformer.GetIndexer()
 
 	informer.start(stopCh)
-	informer.waitForCacheSync(stopCh)
-	logrus.Info("k8s cache sync finished")
+	synced := make(chan struct{})
+	go func() {
+		informer.waitForCacheSync(stopCh)
+		informer.syncTimeMux.Lock()
+		informer.syncTime = time.Now()
+		informer.syncTimeMux.Unlock()
+		logrus.Info("k8s cache sync finished")
+		close(synced)
+	}()
+	// wait a bounded time for the initial sync, then serve cache-only data until
+	// it finishes instead of blocking startup on the apiserver
+	select {
+	case <-synced:
+	case <-time.After(initialCacheSyncTimeout):
+		logrus.Warn("k8s cache is not synced yet, serving cache-only data until the sync finishes")
+	}
 	return informer
 }
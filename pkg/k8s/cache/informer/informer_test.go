@@ -0,0 +1,155 @@
+package informer
+
+import (
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sort"
+	"testing"
+	"transwarp/release-config/pkg/apis/transwarp/v1beta1"
+	releaseconfigfake "transwarp/release-config/pkg/client/clientset/versioned/fake"
+
+	errorModel "WarpCloud/walm/pkg/models/error"
+	"WarpCloud/walm/pkg/models/k8s"
+	"WarpCloud/walm/pkg/models/release"
+)
+
+func Test_BuildPodContainerNames(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "test-init-container"},
+			},
+			Containers: []corev1.Container{
+				{Name: "test-container1"},
+				{Name: "test-container2"},
+			},
+		},
+	}
+
+	tests := []struct {
+		containerName  string
+		containerNames []string
+	}{
+		{
+			containerName:  "",
+			containerNames: []string{"test-init-container", "test-container1", "test-container2"},
+		},
+		{
+			containerName:  "test-container2",
+			containerNames: []string{"test-container2"},
+		},
+		{
+			containerName:  "not-existed-container",
+			containerNames: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		containerNames := buildPodContainerNames(pod, test.containerName)
+		assert.Equal(t, test.containerNames, containerNames)
+	}
+}
+
+func TestInformer_ReleaseConfigFromFakeClient(t *testing.T) {
+	releaseConfig := &v1beta1.ReleaseConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "test-name",
+		},
+		Spec: v1beta1.ReleaseConfigSpec{
+			ChartName: "test-chart",
+			OutputConfig: map[string]interface{}{
+				"test-key": "test-value",
+			},
+		},
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informer := NewInformer(k8sfake.NewSimpleClientset(), releaseconfigfake.NewSimpleClientset(releaseConfig), 0, stopCh)
+
+	// the output config feeds the dependency value injection, so it must
+	// survive the round trip through the fake informer cache
+	resource, err := informer.GetResource(k8s.ReleaseConfigKind, "test-ns", "test-name")
+	assert.IsType(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"test-key": "test-value"}, resource.(*k8s.ReleaseConfig).OutputConfig)
+	assert.Equal(t, "test-chart", resource.(*k8s.ReleaseConfig).ChartName)
+
+	releaseConfigs, err := informer.ListReleaseConfigs("test-ns", "")
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 1, len(releaseConfigs))
+
+	_, err = informer.GetResource(k8s.ReleaseConfigKind, "test-ns", "not-existed")
+	assert.IsType(t, errorModel.NotFoundError{}, err)
+}
+
+func TestInformer_ListReleaseConfigsByDependency(t *testing.T) {
+	buildReleaseConfig := func(namespace, name string, dependencies map[string]string) *v1beta1.ReleaseConfig {
+		return &v1beta1.ReleaseConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Spec: v1beta1.ReleaseConfigSpec{
+				Dependencies: dependencies,
+			},
+		}
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informer := NewInformer(k8sfake.NewSimpleClientset(), releaseconfigfake.NewSimpleClientset(
+		buildReleaseConfig("test-ns", "test-name", map[string]string{"zookeeper": "zk-name"}),
+		buildReleaseConfig("test-ns", "dependent-name", map[string]string{"zookeeper": "test-name"}),
+		// dependencies can point at releases in other namespaces
+		buildReleaseConfig("test-ns2", "dependent-name2", map[string]string{"zookeeper": "test-ns/test-name"}),
+		buildReleaseConfig("test-ns2", "not-dependent-name", map[string]string{"zookeeper": "test-name"}),
+	), 0, stopCh)
+
+	releaseConfigs, err := informer.ListReleaseConfigsByDependency("test-ns", "test-name")
+	assert.IsType(t, nil, err)
+	dependentNames := []string{}
+	for _, releaseConfig := range releaseConfigs {
+		dependentNames = append(dependentNames, releaseConfig.Namespace+"/"+releaseConfig.Name)
+	}
+	sort.Strings(dependentNames)
+	assert.Equal(t, []string{"test-ns/dependent-name", "test-ns2/dependent-name2"}, dependentNames)
+
+	releaseConfigs, err = informer.ListReleaseConfigsByDependency("test-ns", "not-existed")
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 0, len(releaseConfigs))
+}
+
+func TestInformer_GetResourceSetLive(t *testing.T) {
+	deployment := &extv1beta1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "test-name",
+		},
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informer := NewInformer(k8sfake.NewSimpleClientset(deployment), releaseconfigfake.NewSimpleClientset(), 0, stopCh)
+
+	syncInfo := informer.GetCacheSyncInfo()
+	assert.Equal(t, true, syncInfo.Synced)
+	assert.Equal(t, false, syncInfo.SyncTime.IsZero())
+
+	resourceSet, err := informer.GetResourceSetLive([]release.ReleaseResourceMeta{
+		{Kind: k8s.DeploymentKind, Namespace: "test-ns", Name: "test-name"},
+	})
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 1, len(resourceSet.Deployments))
+
+	// like GetResourceSet, resources not found any more do not fail the whole set
+	resourceSet, err = informer.GetResourceSetLive([]release.ReleaseResourceMeta{
+		{Kind: k8s.DeploymentKind, Namespace: "test-ns", Name: "not-existed"},
+	})
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 1, len(resourceSet.Deployments))
+	assert.Equal(t, "NotFound", resourceSet.Deployments[0].State.Status)
+}
@@ -8,6 +8,14 @@ import (
 	"WarpCloud/walm/pkg/models/tenant"
 	errorModel "WarpCloud/walm/pkg/models/error"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"WarpCloud/walm/pkg/setting"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -36,6 +44,80 @@ func (informer *Informer) ListTenants(labelSelectorStr string) (*tenant.TenantIn
 	return tenantInfoList, nil
 }
 
+func (informer *Informer) ListTenantsSummary(labelSelectorStr string) (*tenant.TenantInfoList, error) {
+	tenantInfoList := &tenant.TenantInfoList{}
+	selector, err := labels.Parse(labelSelectorStr)
+	if err != nil {
+		logrus.Errorf("failed to parse label string %s : %s", labelSelectorStr, err.Error())
+		return nil, err
+	}
+	namespaces, err := informer.namespaceLister.List(selector)
+	if err != nil {
+		logrus.Errorf("failed to list namespaces : %s", err.Error())
+		return nil, err
+	}
+
+	for _, namespace := range namespaces {
+		tenantInfoList.Items = append(tenantInfoList.Items, buildBasicTenantInfo(namespace))
+	}
+
+	return tenantInfoList, nil
+}
+
+func (informer *Informer) ListTenantServices(tenantName string) (*tenant.TenantServiceInfoList, error) {
+	services, err := informer.serviceLister.Services(tenantName).List(labels.NewSelector())
+	if err != nil {
+		logrus.Errorf("failed to list services : %s", err.Error())
+		return nil, err
+	}
+	ingresses, err := informer.ingressLister.Ingresses(tenantName).List(labels.NewSelector())
+	if err != nil {
+		logrus.Errorf("failed to list ingresses : %s", err.Error())
+		return nil, err
+	}
+	return &tenant.TenantServiceInfoList{Items: buildTenantServiceInfos(services, ingresses)}, nil
+}
+
+func buildTenantServiceInfos(services []*corev1.Service, ingresses []*extv1beta1.Ingress) []*tenant.TenantServiceInfo {
+	serviceIngresses := map[string][]*tenant.TenantServiceIngress{}
+	for _, ingress := range ingresses {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				serviceIngresses[path.Backend.ServiceName] = append(serviceIngresses[path.Backend.ServiceName],
+					&tenant.TenantServiceIngress{Host: rule.Host, Path: path.Path})
+			}
+		}
+	}
+
+	tenantServiceInfos := []*tenant.TenantServiceInfo{}
+	for _, service := range services {
+		ports := []*tenant.TenantServicePort{}
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, &tenant.TenantServicePort{
+				Name:     port.Name,
+				Protocol: string(port.Protocol),
+				Port:     port.Port,
+				NodePort: port.NodePort,
+			})
+		}
+		tenantServiceInfos = append(tenantServiceInfos, &tenant.TenantServiceInfo{
+			ServiceName: service.Name,
+			ServiceType: string(service.Spec.Type),
+			ClusterIp:   service.Spec.ClusterIP,
+			Ports:       ports,
+			Ingresses:   serviceIngresses[service.Name],
+			ReleaseName: service.Labels[k8s.WalmReleaseLabelKey],
+		})
+	}
+	sort.Slice(tenantServiceInfos, func(i, j int) bool {
+		return tenantServiceInfos[i].ServiceName < tenantServiceInfos[j].ServiceName
+	})
+	return tenantServiceInfos
+}
+
 func (informer *Informer) GetTenant(tenantName string) (*tenant.TenantInfo, error) {
 	namespace, err := informer.namespaceLister.Get(tenantName)
 	if err != nil {
@@ -64,10 +146,93 @@ func (informer *Informer)buildTenantInfo(namespace *corev1.Namespace)(*tenant.Te
 	if err != nil {
 		return nil, err
 	}
+	tenantInfo.AggregatedTenantQuota = buildAggregatedTenantQuota(tenantInfo.UnifyUnitTenantQuotas)
+	tenantInfo.QuotaPressures = buildQuotaPressures(tenantInfo.UnifyUnitTenantQuotas, quotaPressureThreshold())
+
+	limitRanges, err := informer.limitRangeLister.LimitRanges(namespace.Name).List(labels.NewSelector())
+	if err != nil {
+		logrus.Errorf("failed to get limit ranges : %s", err.Error())
+		return nil, err
+	}
+
+	tenantInfo.TenantLimitRanges, err = buildTenantLimitRanges(limitRanges)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantInfo.Ready {
+		tenantInfo.Ready, tenantInfo.NotReadyMessage = checkDeclaredTenantObjects(namespace.Annotations, resourceQuotas, limitRanges)
+	}
+
+	// only walm managed role bindings count as tenant members
+	roleBindings, err := informer.roleBindingLister.RoleBindings(namespace.Name).List(labels.Set{tenant.MemberLabelKey: "true"}.AsSelector())
+	if err != nil {
+		logrus.Errorf("failed to get role bindings : %s", err.Error())
+		return nil, err
+	}
+
+	tenantInfo.TenantMembers, err = buildTenantMembers(roleBindings)
+	if err != nil {
+		return nil, err
+	}
 
 	return tenantInfo, nil
 }
 
+func buildTenantMembers(roleBindings []*rbacv1.RoleBinding) ([]*tenant.TenantMember, error) {
+	clusterRoleTenantRoles := map[string]string{}
+	for role, clusterRole := range tenant.TenantRoleClusterRoles {
+		clusterRoleTenantRoles[clusterRole] = role
+	}
+
+	tenantMembers := []*tenant.TenantMember{}
+	for _, roleBinding := range roleBindings {
+		walmRoleBinding, err := converter.ConvertRoleBindingFromK8s(roleBinding)
+		if err != nil {
+			logrus.Errorf("failed to convert role binding %s/%s : %s", roleBinding.Namespace, roleBinding.Name, err.Error())
+			return nil, err
+		}
+		role, ok := clusterRoleTenantRoles[walmRoleBinding.ClusterRoleName]
+		if !ok {
+			logrus.Warnf("role binding %s/%s references unknown cluster role %s", roleBinding.Namespace, roleBinding.Name, walmRoleBinding.ClusterRoleName)
+			continue
+		}
+		tenantMembers = append(tenantMembers, &tenant.TenantMember{
+			Kind: walmRoleBinding.SubjectKind,
+			Name: walmRoleBinding.SubjectName,
+			Role: role,
+		})
+	}
+	return tenantMembers, nil
+}
+
+func buildTenantLimitRanges(limitRanges []*corev1.LimitRange) ([]*tenant.TenantLimitRange, error) {
+	tenantLimitRanges := []*tenant.TenantLimitRange{}
+	for _, limitRange := range limitRanges {
+		walmLimitRange, err := converter.ConvertLimitRangeFromK8s(limitRange)
+		if err != nil {
+			logrus.Errorf("failed to convert limit range %s/%s : %s", limitRange.Namespace, limitRange.Name, err.Error())
+			return nil, err
+		}
+		tenantLimitRanges = append(tenantLimitRanges, &tenant.TenantLimitRange{
+			LimitRangeName: walmLimitRange.Name,
+			DefaultLimit:   buildTenantLimitRangeLimits(walmLimitRange.DefaultLimit),
+			DefaultRequest: buildTenantLimitRangeLimits(walmLimitRange.DefaultRequest),
+			Max:            buildTenantLimitRangeLimits(walmLimitRange.Max),
+			Min:            buildTenantLimitRangeLimits(walmLimitRange.Min),
+		})
+	}
+	return tenantLimitRanges, nil
+}
+
+func buildTenantLimitRangeLimits(limits map[k8s.ResourceName]string) map[string]string {
+	tenantLimits := map[string]string{}
+	for key, value := range limits {
+		tenantLimits[string(key)] = value
+	}
+	return tenantLimits
+}
+
 func buildBasicTenantInfo(namespace *corev1.Namespace) *tenant.TenantInfo {
 	tenantInfo := &tenant.TenantInfo{
 		TenantName:            namespace.Name,
@@ -77,6 +242,8 @@ func buildBasicTenantInfo(namespace *corev1.Namespace) *tenant.TenantInfo {
 		TenantStatus:          namespace.Status.String(),
 		TenantQuotas:          []*tenant.TenantQuota{},
 		UnifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{},
+		TenantLimitRanges:     []*tenant.TenantLimitRange{},
+		TenantMembers:         []*tenant.TenantMember{},
 	}
 	if tenantInfo.TenantLabels == nil {
 		tenantInfo.TenantLabels = map[string]string{}
@@ -87,12 +254,77 @@ func buildBasicTenantInfo(namespace *corev1.Namespace) *tenant.TenantInfo {
 	if _, ok := namespace.Labels[tenant.MultiTenantLabelKey]; ok {
 		tenantInfo.MultiTenant = true
 	}
+	if whitelistStr := namespace.Annotations[tenant.StorageClassWhitelistAnnotationKey]; whitelistStr != "" {
+		tenantInfo.StorageClassWhitelist = strings.Split(whitelistStr, ",")
+	}
 	if namespace.Status.Phase == corev1.NamespaceActive {
 		tenantInfo.Ready = true
 	}
 	return tenantInfo
 }
 
+// checkDeclaredTenantObjects verifies that every quota and limit range the
+// tenant was declared with exists and carries the declared hard limits : a
+// tenant whose quota creation failed is functionally broken even when the
+// namespace itself is active. Updating the tenant repairs the drift.
+func checkDeclaredTenantObjects(annotations map[string]string, resourceQuotas []*corev1.ResourceQuota, limitRanges []*corev1.LimitRange) (bool, string) {
+	declaredStr, ok := annotations[tenant.DeclaredObjectsAnnotationKey]
+	if !ok {
+		return true, ""
+	}
+	declared := tenant.DeclaredTenantObjects{}
+	err := json.Unmarshal([]byte(declaredStr), &declared)
+	if err != nil {
+		logrus.Warnf("failed to parse declared objects annotation : %s", err.Error())
+		return true, ""
+	}
+
+	actualQuotas := map[string]*corev1.ResourceQuota{}
+	for _, resourceQuota := range resourceQuotas {
+		actualQuotas[resourceQuota.Name] = resourceQuota
+	}
+	quotaNames := []string{}
+	for quotaName := range declared.ResourceQuotas {
+		quotaNames = append(quotaNames, quotaName)
+	}
+	sort.Strings(quotaNames)
+	for _, quotaName := range quotaNames {
+		actualQuota, ok := actualQuotas[quotaName]
+		if !ok {
+			return false, fmt.Sprintf("resource quota %s is not created", quotaName)
+		}
+		resourceNames := []string{}
+		for resourceName := range declared.ResourceQuotas[quotaName] {
+			resourceNames = append(resourceNames, resourceName)
+		}
+		sort.Strings(resourceNames)
+		for _, resourceName := range resourceNames {
+			declaredValue := declared.ResourceQuotas[quotaName][resourceName]
+			declaredQuantity, err := resource.ParseQuantity(declaredValue)
+			if err != nil {
+				logrus.Warnf("failed to parse quantity %s : %s", declaredValue, err.Error())
+				continue
+			}
+			actualQuantity, ok := actualQuota.Spec.Hard[corev1.ResourceName(resourceName)]
+			if !ok || actualQuantity.Cmp(declaredQuantity) != 0 {
+				return false, fmt.Sprintf("resource quota %s hard limit of %s does not match : declared %s, actual %s",
+					quotaName, resourceName, declaredValue, actualQuantity.String())
+			}
+		}
+	}
+
+	actualLimitRanges := map[string]bool{}
+	for _, limitRange := range limitRanges {
+		actualLimitRanges[limitRange.Name] = true
+	}
+	for _, limitRangeName := range declared.LimitRanges {
+		if !actualLimitRanges[limitRangeName] {
+			return false, fmt.Sprintf("limit range %s is not created", limitRangeName)
+		}
+	}
+	return true, ""
+}
+
 func buildTenantQuotas(resourceQuotas []*corev1.ResourceQuota) ([]*tenant.TenantQuota, []*tenant.UnifyUnitTenantQuota, error) {
 	tenantQuotas := []*tenant.TenantQuota{}
 	unifyUnitTenantQuotas := []*tenant.UnifyUnitTenantQuota{}
@@ -102,22 +334,8 @@ func buildTenantQuotas(resourceQuotas []*corev1.ResourceQuota) ([]*tenant.Tenant
 			logrus.Errorf("failed to convert resource quota %s: %s", resourceQuota.Name, err.Error())
 			return nil, nil, err
 		}
-		hard := &tenant.TenantQuotaInfo{
-			Pods:            walmResourceQuota.ResourceLimits[k8s.ResourcePods],
-			LimitCpu:        walmResourceQuota.ResourceLimits[k8s.ResourceLimitsCPU],
-			LimitMemory:     walmResourceQuota.ResourceLimits[k8s.ResourceLimitsMemory],
-			RequestsStorage: walmResourceQuota.ResourceLimits[k8s.ResourceRequestsStorage],
-			RequestsMemory:  walmResourceQuota.ResourceLimits[k8s.ResourceRequestsMemory],
-			RequestsCPU:     walmResourceQuota.ResourceLimits[k8s.ResourceRequestsCPU],
-		}
-		used := &tenant.TenantQuotaInfo{
-			Pods:            walmResourceQuota.ResourceUsed[k8s.ResourcePods],
-			LimitCpu:        walmResourceQuota.ResourceUsed[k8s.ResourceLimitsCPU],
-			LimitMemory:     walmResourceQuota.ResourceUsed[k8s.ResourceLimitsMemory],
-			RequestsStorage: walmResourceQuota.ResourceUsed[k8s.ResourceRequestsStorage],
-			RequestsMemory:  walmResourceQuota.ResourceUsed[k8s.ResourceRequestsMemory],
-			RequestsCPU:     walmResourceQuota.ResourceUsed[k8s.ResourceRequestsCPU],
-		}
+		hard := buildTenantQuotaInfo(walmResourceQuota.ResourceLimits)
+		used := buildTenantQuotaInfo(walmResourceQuota.ResourceUsed)
 		tenantQuotas = append(tenantQuotas, &tenant.TenantQuota{
 			QuotaName: walmResourceQuota.Name,
 			Hard:      hard,
@@ -127,6 +345,23 @@ func buildTenantQuotas(resourceQuotas []*corev1.ResourceQuota) ([]*tenant.Tenant
 	return tenantQuotas, unifyUnitTenantQuotas, nil
 }
 
+func buildTenantQuotaInfo(resources map[k8s.ResourceName]string) *tenant.TenantQuotaInfo {
+	return &tenant.TenantQuotaInfo{
+		Pods:                     resources[k8s.ResourcePods],
+		LimitCpu:                 resources[k8s.ResourceLimitsCPU],
+		LimitMemory:              resources[k8s.ResourceLimitsMemory],
+		RequestsStorage:          resources[k8s.ResourceRequestsStorage],
+		RequestsMemory:           resources[k8s.ResourceRequestsMemory],
+		RequestsCPU:              resources[k8s.ResourceRequestsCPU],
+		RequestsEphemeralStorage: resources[k8s.ResourceRequestsEphemeralStorage],
+		Services:                 resources[k8s.ResourceServices],
+		ServicesNodePorts:        resources[k8s.ResourceServicesNodePorts],
+		PersistentVolumeClaims:   resources[k8s.ResourcePersistentVolumeClaims],
+		ConfigMaps:               resources[k8s.ResourceCountConfigMaps],
+		ExtendedResources:        buildExtendedResources(resources),
+	}
+}
+
 func buildUnifyUnitTenantQuota(name string, hard *tenant.TenantQuotaInfo, used *tenant.TenantQuotaInfo) *tenant.UnifyUnitTenantQuota {
 	return &tenant.UnifyUnitTenantQuota{
 		QuotaName: name,
@@ -137,11 +372,226 @@ func buildUnifyUnitTenantQuota(name string, hard *tenant.TenantQuotaInfo, used *
 
 func buildUnifyUnitTenantInfo(info *tenant.TenantQuotaInfo) *tenant.UnifyUnitTenantQuotaInfo {
 	return &tenant.UnifyUnitTenantQuotaInfo{
-		RequestsCPU:     utils.ParseK8sResourceCpu(info.RequestsCPU),
-		RequestsMemory:  utils.ParseK8sResourceMemory(info.RequestsMemory),
-		RequestsStorage: utils.ParseK8sResourceStorage(info.RequestsStorage),
-		LimitMemory:     utils.ParseK8sResourceMemory(info.LimitMemory),
-		LimitCpu:        utils.ParseK8sResourceCpu(info.LimitCpu),
-		Pods:            utils.ParseK8sResourcePod(info.Pods),
+		RequestsCPU:              utils.ParseK8sResourceCpu(info.RequestsCPU),
+		RequestsMemory:           utils.ParseK8sResourceMemory(info.RequestsMemory),
+		RequestsStorage:          utils.ParseK8sResourceStorage(info.RequestsStorage),
+		RequestsEphemeralStorage: utils.ParseK8sResourceStorage(info.RequestsEphemeralStorage),
+		LimitMemory:              utils.ParseK8sResourceMemory(info.LimitMemory),
+		LimitCpu:                 utils.ParseK8sResourceCpu(info.LimitCpu),
+		Pods:                     utils.ParseK8sResourcePod(info.Pods),
+		Services:                 utils.ParseK8sResourceCount(info.Services),
+		ServicesNodePorts:        utils.ParseK8sResourceCount(info.ServicesNodePorts),
+		PersistentVolumeClaims:   utils.ParseK8sResourceCount(info.PersistentVolumeClaims),
+		ConfigMaps:               utils.ParseK8sResourceCount(info.ConfigMaps),
+		// extended resources have no unified unit, pass the raw quantities through
+		ExtendedResources: info.ExtendedResources,
+	}
+}
+
+const defaultQuotaPressureThreshold = 0.85
+
+func quotaPressureThreshold() float64 {
+	if setting.Config.TenantConfig != nil && setting.Config.TenantConfig.QuotaPressureThreshold > 0 {
+		return setting.Config.TenantConfig.QuotaPressureThreshold
+	}
+	return defaultQuotaPressureThreshold
+}
+
+type quotaResourceValue struct {
+	resourceName string
+	hard         float64
+	used         float64
+}
+
+// buildQuotaPressures reuses the unified quota values to flag every resource
+// whose used/hard ratio reached the pressure threshold.
+func buildQuotaPressures(unifyUnitTenantQuotas []*tenant.UnifyUnitTenantQuota, threshold float64) []*tenant.TenantQuotaPressure {
+	quotaPressures := []*tenant.TenantQuotaPressure{}
+	for _, unifyUnitTenantQuota := range unifyUnitTenantQuotas {
+		hard := unifyUnitTenantQuota.Hard
+		used := unifyUnitTenantQuota.Used
+		quotaResources := []quotaResourceValue{
+			{string(k8s.ResourceLimitsCPU), hard.LimitCpu, used.LimitCpu},
+			{string(k8s.ResourceLimitsMemory), float64(hard.LimitMemory), float64(used.LimitMemory)},
+			{string(k8s.ResourceRequestsCPU), hard.RequestsCPU, used.RequestsCPU},
+			{string(k8s.ResourceRequestsMemory), float64(hard.RequestsMemory), float64(used.RequestsMemory)},
+			{string(k8s.ResourceRequestsStorage), float64(hard.RequestsStorage), float64(used.RequestsStorage)},
+			{string(k8s.ResourceRequestsEphemeralStorage), float64(hard.RequestsEphemeralStorage), float64(used.RequestsEphemeralStorage)},
+			{string(k8s.ResourcePods), float64(hard.Pods), float64(used.Pods)},
+			{string(k8s.ResourceServices), float64(hard.Services), float64(used.Services)},
+			{string(k8s.ResourceServicesNodePorts), float64(hard.ServicesNodePorts), float64(used.ServicesNodePorts)},
+			{string(k8s.ResourcePersistentVolumeClaims), float64(hard.PersistentVolumeClaims), float64(used.PersistentVolumeClaims)},
+			{string(k8s.ResourceCountConfigMaps), float64(hard.ConfigMaps), float64(used.ConfigMaps)},
+		}
+		for resourceName, hardValue := range hard.ExtendedResources {
+			hardQuantity, err := resource.ParseQuantity(hardValue)
+			if err != nil {
+				logrus.Warnf("failed to parse quantity %s : %s", hardValue, err.Error())
+				continue
+			}
+			usedQuantity, err := resource.ParseQuantity(used.ExtendedResources[resourceName])
+			if err != nil {
+				continue
+			}
+			quotaResources = append(quotaResources, quotaResourceValue{resourceName, float64(hardQuantity.Value()), float64(usedQuantity.Value())})
+		}
+		for _, quotaResource := range quotaResources {
+			if quotaResource.hard <= 0 {
+				continue
+			}
+			ratio := quotaResource.used / quotaResource.hard
+			if ratio >= threshold {
+				quotaPressures = append(quotaPressures, &tenant.TenantQuotaPressure{
+					QuotaName:    unifyUnitTenantQuota.QuotaName,
+					ResourceName: quotaResource.resourceName,
+					Hard:         quotaResource.hard,
+					Used:         quotaResource.used,
+					Ratio:        ratio,
+				})
+			}
+		}
+	}
+	if len(quotaPressures) == 0 {
+		return nil
+	}
+	return quotaPressures
+}
+
+// buildAggregatedTenantQuota merges the unified quotas of a namespace into one :
+// the hard limit per resource is the minimum across the resource quotas declaring
+// it, matching the kubernetes semantics of multiple quotas, used takes the maximum.
+func buildAggregatedTenantQuota(unifyUnitTenantQuotas []*tenant.UnifyUnitTenantQuota) *tenant.UnifyUnitTenantQuota {
+	if len(unifyUnitTenantQuotas) == 0 {
+		return nil
+	}
+	aggregated := &tenant.UnifyUnitTenantQuota{
+		QuotaName: "aggregated",
+		Hard:      &tenant.UnifyUnitTenantQuotaInfo{},
+		Used:      &tenant.UnifyUnitTenantQuotaInfo{},
+	}
+	for _, unifyUnitTenantQuota := range unifyUnitTenantQuotas {
+		aggregateHardTenantQuotaInfo(aggregated.Hard, unifyUnitTenantQuota.Hard)
+		aggregateUsedTenantQuotaInfo(aggregated.Used, unifyUnitTenantQuota.Used)
+	}
+	return aggregated
+}
+
+func aggregateHardTenantQuotaInfo(aggregated *tenant.UnifyUnitTenantQuotaInfo, info *tenant.UnifyUnitTenantQuotaInfo) {
+	aggregated.LimitCpu = minNonZeroFloat64(aggregated.LimitCpu, info.LimitCpu)
+	aggregated.LimitMemory = minNonZeroInt64(aggregated.LimitMemory, info.LimitMemory)
+	aggregated.RequestsCPU = minNonZeroFloat64(aggregated.RequestsCPU, info.RequestsCPU)
+	aggregated.RequestsMemory = minNonZeroInt64(aggregated.RequestsMemory, info.RequestsMemory)
+	aggregated.RequestsStorage = minNonZeroInt64(aggregated.RequestsStorage, info.RequestsStorage)
+	aggregated.RequestsEphemeralStorage = minNonZeroInt64(aggregated.RequestsEphemeralStorage, info.RequestsEphemeralStorage)
+	aggregated.Pods = minNonZeroInt64(aggregated.Pods, info.Pods)
+	aggregated.Services = minNonZeroInt64(aggregated.Services, info.Services)
+	aggregated.ServicesNodePorts = minNonZeroInt64(aggregated.ServicesNodePorts, info.ServicesNodePorts)
+	aggregated.PersistentVolumeClaims = minNonZeroInt64(aggregated.PersistentVolumeClaims, info.PersistentVolumeClaims)
+	aggregated.ConfigMaps = minNonZeroInt64(aggregated.ConfigMaps, info.ConfigMaps)
+	aggregated.ExtendedResources = mergeExtendedResources(aggregated.ExtendedResources, info.ExtendedResources, true)
+}
+
+func aggregateUsedTenantQuotaInfo(aggregated *tenant.UnifyUnitTenantQuotaInfo, info *tenant.UnifyUnitTenantQuotaInfo) {
+	aggregated.LimitCpu = maxFloat64(aggregated.LimitCpu, info.LimitCpu)
+	aggregated.LimitMemory = maxInt64(aggregated.LimitMemory, info.LimitMemory)
+	aggregated.RequestsCPU = maxFloat64(aggregated.RequestsCPU, info.RequestsCPU)
+	aggregated.RequestsMemory = maxInt64(aggregated.RequestsMemory, info.RequestsMemory)
+	aggregated.RequestsStorage = maxInt64(aggregated.RequestsStorage, info.RequestsStorage)
+	aggregated.RequestsEphemeralStorage = maxInt64(aggregated.RequestsEphemeralStorage, info.RequestsEphemeralStorage)
+	aggregated.Pods = maxInt64(aggregated.Pods, info.Pods)
+	aggregated.Services = maxInt64(aggregated.Services, info.Services)
+	aggregated.ServicesNodePorts = maxInt64(aggregated.ServicesNodePorts, info.ServicesNodePorts)
+	aggregated.PersistentVolumeClaims = maxInt64(aggregated.PersistentVolumeClaims, info.PersistentVolumeClaims)
+	aggregated.ConfigMaps = maxInt64(aggregated.ConfigMaps, info.ConfigMaps)
+	aggregated.ExtendedResources = mergeExtendedResources(aggregated.ExtendedResources, info.ExtendedResources, false)
+}
+
+func mergeExtendedResources(aggregated map[string]string, extendedResources map[string]string, takeMin bool) map[string]string {
+	for name, value := range extendedResources {
+		if aggregated == nil {
+			aggregated = map[string]string{}
+		}
+		existedValue, ok := aggregated[name]
+		if !ok {
+			aggregated[name] = value
+			continue
+		}
+		existedQuantity, err := resource.ParseQuantity(existedValue)
+		if err != nil {
+			logrus.Warnf("failed to parse quantity %s : %s", existedValue, err.Error())
+			aggregated[name] = value
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			logrus.Warnf("failed to parse quantity %s : %s", value, err.Error())
+			continue
+		}
+		if (takeMin && quantity.Cmp(existedQuantity) < 0) || (!takeMin && quantity.Cmp(existedQuantity) > 0) {
+			aggregated[name] = value
+		}
+	}
+	return aggregated
+}
+
+func minNonZeroInt64(existedValue int64, value int64) int64 {
+	if existedValue == 0 {
+		return value
+	}
+	if value != 0 && value < existedValue {
+		return value
+	}
+	return existedValue
+}
+
+func minNonZeroFloat64(existedValue float64, value float64) float64 {
+	if existedValue == 0 {
+		return value
+	}
+	if value != 0 && value < existedValue {
+		return value
+	}
+	return existedValue
+}
+
+func maxInt64(existedValue int64, value int64) int64 {
+	if value > existedValue {
+		return value
+	}
+	return existedValue
+}
+
+func maxFloat64(existedValue float64, value float64) float64 {
+	if value > existedValue {
+		return value
+	}
+	return existedValue
+}
+
+var typedQuotaResourceNames = map[k8s.ResourceName]bool{
+	k8s.ResourcePods:                     true,
+	k8s.ResourceLimitsCPU:                true,
+	k8s.ResourceLimitsMemory:             true,
+	k8s.ResourceRequestsCPU:              true,
+	k8s.ResourceRequestsMemory:           true,
+	k8s.ResourceRequestsStorage:          true,
+	k8s.ResourceRequestsEphemeralStorage: true,
+	k8s.ResourceServices:                 true,
+	k8s.ResourceServicesNodePorts:        true,
+	k8s.ResourcePersistentVolumeClaims:   true,
+	k8s.ResourceCountConfigMaps:          true,
+}
+
+func buildExtendedResources(resourceLimits map[k8s.ResourceName]string) map[string]string {
+	var extendedResources map[string]string
+	for resourceName, value := range resourceLimits {
+		if typedQuotaResourceNames[resourceName] {
+			continue
+		}
+		if extendedResources == nil {
+			extendedResources = map[string]string{}
+		}
+		extendedResources[string(resourceName)] = value
 	}
+	return extendedResources
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"WarpCloud/walm/pkg/models/tenant"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"time"
 	"github.com/stretchr/testify/assert"
@@ -32,6 +34,8 @@ func Test_BuildBasicTenantInfo(t *testing.T) {
 				TenantStatus:          "&NamespaceStatus{Phase:Active,}",
 				TenantCreationTime:    "2019-01-01 01:00:00 +0000 UTC",
 				UnifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{},
+				TenantLimitRanges:     []*tenant.TenantLimitRange{},
+				TenantMembers:         []*tenant.TenantMember{},
 				TenantQuotas:          []*tenant.TenantQuota{},
 				TenantLabels:          map[string]string{},
 				TenantAnnotitions:     map[string]string{},
@@ -55,6 +59,8 @@ func Test_BuildBasicTenantInfo(t *testing.T) {
 				TenantStatus:          "&NamespaceStatus{Phase:Terminating,}",
 				TenantCreationTime:    "2019-01-01 01:00:00 +0000 UTC",
 				UnifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{},
+				TenantLimitRanges:     []*tenant.TenantLimitRange{},
+				TenantMembers:         []*tenant.TenantMember{},
 				TenantQuotas:          []*tenant.TenantQuota{},
 				TenantLabels:          map[string]string{tenant.MultiTenantLabelKey: "true"},
 				TenantAnnotitions:     map[string]string{},
@@ -147,6 +153,54 @@ func Test_BuildTenantQuotas(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			resourceQuotas: []*corev1.ResourceQuota{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-gpu-rq",
+					},
+					Spec: corev1.ResourceQuotaSpec{
+						Hard: corev1.ResourceList{
+							corev1.ResourcePods:                          resource.MustParse("100"),
+							corev1.ResourceName("requests.nvidia.com/gpu"): resource.MustParse("8"),
+						},
+					},
+					Status: corev1.ResourceQuotaStatus{
+						Used: corev1.ResourceList{
+							corev1.ResourcePods:                          resource.MustParse("10"),
+							corev1.ResourceName("requests.nvidia.com/gpu"): resource.MustParse("2"),
+						},
+					},
+				},
+			},
+			tenantQuotas: []*tenant.TenantQuota{
+				{
+					QuotaName: "test-gpu-rq",
+					Hard: &tenant.TenantQuotaInfo{
+						Pods:              "100",
+						ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "8"},
+					},
+					Used: &tenant.TenantQuotaInfo{
+						Pods:              "10",
+						ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "2"},
+					},
+				},
+			},
+			unifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{
+				{
+					QuotaName: "test-gpu-rq",
+					Hard: &tenant.UnifyUnitTenantQuotaInfo{
+						Pods:              100,
+						ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "8"},
+					},
+					Used: &tenant.UnifyUnitTenantQuotaInfo{
+						Pods:              10,
+						ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "2"},
+					},
+				},
+			},
+			err: nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -156,3 +210,375 @@ func Test_BuildTenantQuotas(t *testing.T) {
 		assert.Equal(t, test.unifyUnitTenantQuotas, unifyTenantQuotas)
 	}
 }
+
+func Test_BuildTenantMembers(t *testing.T) {
+	tests := []struct {
+		roleBindings  []*rbacv1.RoleBinding
+		tenantMembers []*tenant.TenantMember
+	}{
+		{
+			roleBindings:  []*rbacv1.RoleBinding{},
+			tenantMembers: []*tenant.TenantMember{},
+		},
+		{
+			roleBindings: []*rbacv1.RoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "test-namespace",
+						Name:      "walm-member-user-test-user",
+						Labels:    map[string]string{tenant.MemberLabelKey: "true"},
+					},
+					Subjects: []rbacv1.Subject{
+						{
+							APIGroup: rbacv1.GroupName,
+							Kind:     tenant.MemberKindUser,
+							Name:     "test-user",
+						},
+					},
+					RoleRef: rbacv1.RoleRef{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "ClusterRole",
+						Name:     "walm-tenant-admin",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "test-namespace",
+						Name:      "walm-member-group-test-group",
+						Labels:    map[string]string{tenant.MemberLabelKey: "true"},
+					},
+					Subjects: []rbacv1.Subject{
+						{
+							APIGroup: rbacv1.GroupName,
+							Kind:     tenant.MemberKindGroup,
+							Name:     "test-group",
+						},
+					},
+					RoleRef: rbacv1.RoleRef{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "ClusterRole",
+						Name:     "unknown-cluster-role",
+					},
+				},
+			},
+			tenantMembers: []*tenant.TenantMember{
+				{
+					Kind: tenant.MemberKindUser,
+					Name: "test-user",
+					Role: tenant.TenantRoleAdmin,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tenantMembers, err := buildTenantMembers(test.roleBindings)
+		assert.NoError(t, err)
+		assert.Equal(t, test.tenantMembers, tenantMembers)
+	}
+}
+
+func Test_BuildAggregatedTenantQuota(t *testing.T) {
+	tests := []struct {
+		unifyUnitTenantQuotas []*tenant.UnifyUnitTenantQuota
+		aggregatedTenantQuota *tenant.UnifyUnitTenantQuota
+	}{
+		{
+			unifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{},
+			aggregatedTenantQuota: nil,
+		},
+		{
+			unifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{
+				{
+					QuotaName: "test-rq1",
+					Hard: &tenant.UnifyUnitTenantQuotaInfo{
+						Pods:                     100,
+						LimitCpu:                 20,
+						RequestsStorage:          500,
+						RequestsEphemeralStorage: 10,
+						Services:                 50,
+						ExtendedResources:        map[string]string{"requests.nvidia.com/gpu": "8"},
+					},
+					Used: &tenant.UnifyUnitTenantQuotaInfo{
+						Pods:              10,
+						LimitCpu:          2,
+						ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "2"},
+					},
+				},
+				{
+					QuotaName: "test-rq2",
+					Hard: &tenant.UnifyUnitTenantQuotaInfo{
+						Pods:                   50,
+						LimitCpu:               40,
+						PersistentVolumeClaims: 20,
+						ConfigMaps:             30,
+						ExtendedResources:      map[string]string{"requests.nvidia.com/gpu": "4"},
+					},
+					Used: &tenant.UnifyUnitTenantQuotaInfo{
+						Pods:              12,
+						LimitCpu:          1,
+						ConfigMaps:        3,
+						ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "3"},
+					},
+				},
+			},
+			aggregatedTenantQuota: &tenant.UnifyUnitTenantQuota{
+				QuotaName: "aggregated",
+				Hard: &tenant.UnifyUnitTenantQuotaInfo{
+					Pods:                     50,
+					LimitCpu:                 20,
+					RequestsStorage:          500,
+					RequestsEphemeralStorage: 10,
+					Services:                 50,
+					PersistentVolumeClaims:   20,
+					ConfigMaps:               30,
+					ExtendedResources:        map[string]string{"requests.nvidia.com/gpu": "4"},
+				},
+				Used: &tenant.UnifyUnitTenantQuotaInfo{
+					Pods:              12,
+					LimitCpu:          2,
+					ConfigMaps:        3,
+					ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "3"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		aggregatedTenantQuota := buildAggregatedTenantQuota(test.unifyUnitTenantQuotas)
+		assert.Equal(t, test.aggregatedTenantQuota, aggregatedTenantQuota)
+	}
+}
+
+func Test_BuildQuotaPressures(t *testing.T) {
+	unifyUnitTenantQuotas := []*tenant.UnifyUnitTenantQuota{
+		{
+			QuotaName: "test-rq",
+			Hard: &tenant.UnifyUnitTenantQuotaInfo{
+				Pods:              100,
+				LimitCpu:          20,
+				LimitMemory:       1000,
+				ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "8"},
+			},
+			Used: &tenant.UnifyUnitTenantQuotaInfo{
+				Pods:              90,
+				LimitCpu:          10,
+				LimitMemory:       850,
+				ExtendedResources: map[string]string{"requests.nvidia.com/gpu": "8"},
+			},
+		},
+	}
+
+	tests := []struct {
+		unifyUnitTenantQuotas []*tenant.UnifyUnitTenantQuota
+		threshold             float64
+		quotaPressures        []*tenant.TenantQuotaPressure
+	}{
+		{
+			unifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{},
+			threshold:             0.85,
+			quotaPressures:        nil,
+		},
+		{
+			unifyUnitTenantQuotas: unifyUnitTenantQuotas,
+			threshold:             0.85,
+			quotaPressures: []*tenant.TenantQuotaPressure{
+				{
+					QuotaName:    "test-rq",
+					ResourceName: "limits.memory",
+					Hard:         1000,
+					Used:         850,
+					Ratio:        0.85,
+				},
+				{
+					QuotaName:    "test-rq",
+					ResourceName: "pods",
+					Hard:         100,
+					Used:         90,
+					Ratio:        0.9,
+				},
+				{
+					QuotaName:    "test-rq",
+					ResourceName: "requests.nvidia.com/gpu",
+					Hard:         8,
+					Used:         8,
+					Ratio:        1,
+				},
+			},
+		},
+		{
+			unifyUnitTenantQuotas: unifyUnitTenantQuotas,
+			threshold:             0.95,
+			quotaPressures: []*tenant.TenantQuotaPressure{
+				{
+					QuotaName:    "test-rq",
+					ResourceName: "requests.nvidia.com/gpu",
+					Hard:         8,
+					Used:         8,
+					Ratio:        1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		quotaPressures := buildQuotaPressures(test.unifyUnitTenantQuotas, test.threshold)
+		assert.Equal(t, test.quotaPressures, quotaPressures)
+	}
+}
+
+func Test_BuildTenantServiceInfos(t *testing.T) {
+	services := []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-service2",
+				Labels: map[string]string{"walm.release": "test-release"},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeNodePort,
+				ClusterIP: "172.16.1.1",
+				Ports: []corev1.ServicePort{
+					{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-service1",
+			},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "172.16.1.2",
+			},
+		},
+	}
+	ingresses := []*extv1beta1.Ingress{
+		{
+			Spec: extv1beta1.IngressSpec{
+				Rules: []extv1beta1.IngressRule{
+					{
+						Host: "test.walm.io",
+						IngressRuleValue: extv1beta1.IngressRuleValue{
+							HTTP: &extv1beta1.HTTPIngressRuleValue{
+								Paths: []extv1beta1.HTTPIngressPath{
+									{
+										Path: "/test",
+										Backend: extv1beta1.IngressBackend{
+											ServiceName: "test-service2",
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Host: "no-http-rule.walm.io",
+					},
+				},
+			},
+		},
+	}
+
+	tenantServiceInfos := buildTenantServiceInfos(services, ingresses)
+	expectedTenantServiceInfos := []*tenant.TenantServiceInfo{
+		{
+			ServiceName: "test-service1",
+			ServiceType: "ClusterIP",
+			ClusterIp:   "172.16.1.2",
+			Ports:       []*tenant.TenantServicePort{},
+		},
+		{
+			ServiceName: "test-service2",
+			ServiceType: "NodePort",
+			ClusterIp:   "172.16.1.1",
+			Ports: []*tenant.TenantServicePort{
+				{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30080},
+			},
+			Ingresses: []*tenant.TenantServiceIngress{
+				{Host: "test.walm.io", Path: "/test"},
+			},
+			ReleaseName: "test-release",
+		},
+	}
+	assert.Equal(t, expectedTenantServiceInfos, tenantServiceInfos)
+}
+
+func Test_CheckDeclaredTenantObjects(t *testing.T) {
+	resourceQuotas := []*corev1.ResourceQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-quota",
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{
+					corev1.ResourcePods:         resource.MustParse("5"),
+					corev1.ResourceLimitsMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+	}
+	limitRanges := []*corev1.LimitRange{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-limitrange",
+			},
+		},
+	}
+
+	tests := []struct {
+		annotations map[string]string
+		ready       bool
+		message     string
+	}{
+		{
+			annotations: map[string]string{},
+			ready:       true,
+		},
+		{
+			annotations: map[string]string{
+				tenant.DeclaredObjectsAnnotationKey: "not-json",
+			},
+			ready: true,
+		},
+		{
+			annotations: map[string]string{
+				tenant.DeclaredObjectsAnnotationKey: `{"resourceQuotas":{"test-quota":{"pods":"5","limits.memory":"2048Mi"}},"limitRanges":["test-limitrange"]}`,
+			},
+			ready: true,
+		},
+		{
+			annotations: map[string]string{
+				tenant.DeclaredObjectsAnnotationKey: `{"resourceQuotas":{"test-missing-quota":{"pods":"5"}}}`,
+			},
+			ready:   false,
+			message: "resource quota test-missing-quota is not created",
+		},
+		{
+			annotations: map[string]string{
+				tenant.DeclaredObjectsAnnotationKey: `{"resourceQuotas":{"test-quota":{"pods":"10"}}}`,
+			},
+			ready:   false,
+			message: "resource quota test-quota hard limit of pods does not match : declared 10, actual 5",
+		},
+		{
+			annotations: map[string]string{
+				tenant.DeclaredObjectsAnnotationKey: `{"resourceQuotas":{"test-quota":{"limits.cpu":"2"}}}`,
+			},
+			ready:   false,
+			message: "resource quota test-quota hard limit of limits.cpu does not match : declared 2, actual 0",
+		},
+		{
+			annotations: map[string]string{
+				tenant.DeclaredObjectsAnnotationKey: `{"limitRanges":["test-missing-limitrange"]}`,
+			},
+			ready:   false,
+			message: "limit range test-missing-limitrange is not created",
+		},
+	}
+
+	for _, test := range tests {
+		ready, message := checkDeclaredTenantObjects(test.annotations, resourceQuotas, limitRanges)
+		assert.Equal(t, test.ready, ready)
+		assert.Equal(t, test.message, message)
+	}
+}
@@ -0,0 +1,79 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+)
+
+// ResourceReleaseOwner returns the release the live object is labeled as owned by, or
+// an empty string when it carries no ownership label.
+func ResourceReleaseOwner(live runtime.Object) string {
+	accessor, err := meta.Accessor(live)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetLabels()[k8sModel.WalmReleaseLabelKey]
+}
+
+// CanAdoptResource reports whether an already existing resource may be adopted by the
+// release. Unowned objects and objects already owned by the release are safe to adopt,
+// objects owned by another release are only adopted with forceAdopt.
+func CanAdoptResource(live runtime.Object, releaseName string, forceAdopt bool) (canAdopt bool, owner string) {
+	owner = ResourceReleaseOwner(live)
+	if owner == "" || owner == releaseName || forceAdopt {
+		return true, owner
+	}
+	return false, owner
+}
+
+// createOrAdoptResource creates the resource, and when it already exists, typically as
+// a leftover of a previously force deleted release, patches it to the chart rendered
+// state instead of failing the install.
+func (c *Client) createOrAdoptResource(info *resource.Info, releaseName string, forceAdopt bool) error {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	obj, err := helper.Create(info.Namespace, true, info.Object, nil)
+	if err == nil {
+		return info.Refresh(obj, true)
+	}
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	live, err := helper.Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return err
+	}
+	canAdopt, owner := CanAdoptResource(live, releaseName, forceAdopt)
+	if !canAdopt {
+		return fmt.Errorf("%s %s/%s already exists and is owned by release %s, it is not adopted into release %s",
+			info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name, owner, releaseName)
+	}
+	logrus.Infof("adopting existing %s %s/%s into release %s", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name, releaseName)
+
+	return patchWithConflictRetry(live,
+		func() (runtime.Object, error) {
+			return helper.Get(info.Namespace, info.Name, false)
+		},
+		func(live runtime.Object) error {
+			// an adopted object has no recorded original manifest, its extra live fields are kept
+			patch, patchType, err := buildThreeWayMergePatch(nil, info, live)
+			if err != nil {
+				return err
+			}
+			if len(patch) == 0 || string(patch) == "{}" {
+				return info.Refresh(live, true)
+			}
+			patched, err := helper.Patch(info.Namespace, info.Name, patchType, patch, nil)
+			if err != nil {
+				return err
+			}
+			return info.Refresh(patched, true)
+		})
+}
@@ -0,0 +1,65 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+)
+
+func buildTestConfigMap(releaseOwner string) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-config-map"},
+	}
+	if releaseOwner != "" {
+		configMap.Labels = map[string]string{k8sModel.WalmReleaseLabelKey: releaseOwner}
+	}
+	return configMap
+}
+
+func Test_CanAdoptResource(t *testing.T) {
+	tests := []struct {
+		live        runtime.Object
+		releaseName string
+		forceAdopt  bool
+		canAdopt    bool
+		owner       string
+	}{
+		{
+			// a leftover object without ownership labels is safe to adopt
+			live:        buildTestConfigMap(""),
+			releaseName: "test-release",
+			canAdopt:    true,
+		},
+		{
+			live:        buildTestConfigMap("test-release"),
+			releaseName: "test-release",
+			canAdopt:    true,
+			owner:       "test-release",
+		},
+		{
+			// refuse to steal an object another release owns
+			live:        buildTestConfigMap("test-other-release"),
+			releaseName: "test-release",
+			canAdopt:    false,
+			owner:       "test-other-release",
+		},
+		{
+			live:        buildTestConfigMap("test-other-release"),
+			releaseName: "test-release",
+			forceAdopt:  true,
+			canAdopt:    true,
+			owner:       "test-other-release",
+		},
+	}
+
+	for _, test := range tests {
+		canAdopt, owner := CanAdoptResource(test.live, test.releaseName, test.forceAdopt)
+		assert.Equal(t, test.canAdopt, canAdopt)
+		assert.Equal(t, test.owner, owner)
+	}
+}
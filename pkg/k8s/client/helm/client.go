@@ -1,7 +1,17 @@
 package helm
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	errorModel "WarpCloud/walm/pkg/models/error"
+	"WarpCloud/walm/pkg/setting"
+	errorUtils "WarpCloud/walm/pkg/util/error"
 	"github.com/hashicorp/golang-lru"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/helm/pkg/kube"
 )
 
@@ -24,14 +34,82 @@ func (c *Client) GetKubeClient(namespace string) *kube.Client {
 	}
 }
 
+// CreateResourcesWithWalmLabels builds the manifest objects, injects walm ownership
+// labels into them and creates the resources. Resources which already exist and are
+// not owned by another release are adopted instead of failing the create; forceAdopt
+// adopts them even when another release owns them. The returned refs inventory every
+// resource the release now owns, adopted ones included, so the release cache does not
+// have to re-parse the manifest.
+func (c *Client) CreateResourcesWithWalmLabels(namespace string, manifest string, releaseName, projectName string, timeout int64, shouldWait bool, forceAdopt bool) ([]kube.ResourceRef, error) {
+	kubeClient := c.GetKubeClient(namespace)
+	infos, err := kubeClient.BuildUnstructured(namespace, bytes.NewBufferString(manifest))
+	if err != nil {
+		if validationErrors, ok := err.(kube.ValidationErrors); ok {
+			return nil, errorModel.ValidationError{Message: validationErrors.Error()}
+		}
+		return nil, err
+	}
+	InjectWalmLabels(infos, namespace, releaseName, projectName)
+	SortInfosByKindOrder(infos, InstallOrder)
+
+	docs := make([]string, 0, len(infos))
+	for _, info := range infos {
+		objBytes, err := json.Marshal(info.Object)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(objBytes))
+	}
+	refs, err := kubeClient.CreateWithOptions(namespace, bytes.NewBufferString(strings.Join(docs, "\n---\n")), kube.CreateOptions{Timeout: timeout, ShouldWait: shouldWait})
+	if err == nil {
+		return refs, nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return nil, errorUtils.TranslateKubeError(err)
+	}
+
+	// some resources are left over from a previously deleted release, adopt what this
+	// release may own and create the rest
+	for _, info := range infos {
+		err = c.createOrAdoptResource(info, releaseName, forceAdopt)
+		if err != nil {
+			return nil, errorUtils.TranslateKubeError(err)
+		}
+	}
+	if shouldWait {
+		if err = c.WaitForResourcesReady(namespace, manifest, timeout); err != nil {
+			return nil, errorUtils.TranslateKubeError(err)
+		}
+	}
+	refs = make([]kube.ResourceRef, 0, len(infos))
+	for _, info := range infos {
+		refs = append(refs, kube.NewResourceRef(info))
+	}
+	return refs, nil
+}
+
+// discoveryCacheTTL bounds how stale the cached api discovery data may get before it
+// is refreshed from the server.
+const discoveryCacheTTL = 5 * time.Minute
+
 func createKubeClient(kubeConfig string, namespace string) (*kube.Client) {
-	cfg := kube.GetConfig(kubeConfig, "", namespace)
-	client := kube.New(cfg)
+	qps, burst, timeout := kubeClientTuning()
+	cfg := kube.GetConfigWithRateLimits(kubeConfig, "", namespace, qps, burst, timeout)
+	client := kube.New(kube.NewCachedRESTClientGetter(cfg, discoveryCacheTTL))
 
 	return client
 }
 
+func kubeClientTuning() (qps float32, burst int, timeout string) {
+	if setting.Config.KubeConfig == nil {
+		return 0, 0, ""
+	}
+	return setting.Config.KubeConfig.Qps, setting.Config.KubeConfig.Burst, setting.Config.KubeConfig.Timeout
+}
+
 func NewHelmKubeClient(kubeConfig string) *Client {
+	qps, burst, timeout := kubeClientTuning()
+	logrus.Infof("kube client initialized with qps %v, burst %d, timeout %q (zero values keep the client-go defaults)", qps, burst, timeout)
 	kubeClients, _ := lru.New(100)
 	return &Client{
 		kubeClients: kubeClients,
@@ -0,0 +1,74 @@
+package helm
+
+import (
+	"sort"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+)
+
+// InstallOrder is the canonical order resources are created in, so resources a later
+// one depends on, like namespaces and service accounts, exist first. The table is
+// exported so callers can slot crd typed kinds where they belong.
+var InstallOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ServiceAccount",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"Ingress",
+	"APIService",
+}
+
+// UninstallOrder is the canonical order resources are deleted in, the reverse of
+// InstallOrder.
+var UninstallOrder = buildUninstallOrder()
+
+func buildUninstallOrder() []string {
+	uninstallOrder := make([]string, 0, len(InstallOrder))
+	for index := len(InstallOrder) - 1; index >= 0; index-- {
+		uninstallOrder = append(uninstallOrder, InstallOrder[index])
+	}
+	return uninstallOrder
+}
+
+// SortInfosByKindOrder sorts the infos by the given kind order, in place. Kinds not in
+// the order, like crd based resources, keep their manifest order at the end, and infos
+// of the same kind keep their manifest order too.
+func SortInfosByKindOrder(infos []*resource.Info, kindOrder []string) {
+	kindRanks := make(map[string]int, len(kindOrder))
+	for rank, kind := range kindOrder {
+		kindRanks[kind] = rank
+	}
+	rankOf := func(info *resource.Info) int {
+		if info.Mapping == nil {
+			return len(kindOrder)
+		}
+		if rank, ok := kindRanks[info.Mapping.GroupVersionKind.Kind]; ok {
+			return rank
+		}
+		return len(kindOrder)
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return rankOf(infos[i]) < rankOf(infos[j])
+	})
+}
@@ -0,0 +1,47 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+)
+
+func buildTestInfo(name, kind string) *resource.Info {
+	return &resource.Info{
+		Name: name,
+		Mapping: &meta.RESTMapping{
+			GroupVersionKind: schema.GroupVersionKind{Kind: kind},
+		},
+	}
+}
+
+func Test_SortInfosByKindOrder(t *testing.T) {
+	infos := []*resource.Info{
+		buildTestInfo("test-deployment", "Deployment"),
+		buildTestInfo("test-instance2", "ApplicationInstance"),
+		buildTestInfo("test-service", "Service"),
+		buildTestInfo("test-namespace", "Namespace"),
+		buildTestInfo("test-instance1", "ApplicationInstance"),
+		buildTestInfo("test-config-map", "ConfigMap"),
+	}
+
+	SortInfosByKindOrder(infos, InstallOrder)
+	sortedNames := make([]string, 0, len(infos))
+	for _, info := range infos {
+		sortedNames = append(sortedNames, info.Name)
+	}
+	// unknown kinds go last and keep their manifest order
+	assert.Equal(t, []string{"test-namespace", "test-config-map", "test-service",
+		"test-deployment", "test-instance2", "test-instance1"}, sortedNames)
+
+	SortInfosByKindOrder(infos, UninstallOrder)
+	sortedNames = sortedNames[:0]
+	for _, info := range infos {
+		sortedNames = append(sortedNames, info.Name)
+	}
+	assert.Equal(t, []string{"test-deployment", "test-service", "test-config-map",
+		"test-namespace", "test-instance2", "test-instance1"}, sortedNames)
+}
@@ -0,0 +1,57 @@
+package helm
+
+import (
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+)
+
+// InjectWalmLabels stamps walm ownership labels on every manifest object and its pod
+// template, so resources of charts that forgot to set labels are covered too. Labels
+// already set by the chart win, walm only fills the gaps.
+func InjectWalmLabels(infos []*resource.Info, namespace, releaseName, projectName string) {
+	for _, info := range infos {
+		if obj, ok := info.Object.(*unstructured.Unstructured); ok {
+			injectWalmObjectLabels(obj, namespace, releaseName, projectName)
+		}
+	}
+}
+
+func injectWalmObjectLabels(obj *unstructured.Unstructured, namespace, releaseName, projectName string) {
+	walmLabels := buildWalmLabels(namespace, releaseName, projectName)
+	obj.SetLabels(fillLabelGaps(obj.GetLabels(), walmLabels))
+
+	// workload objects carry the labels on their pod template too
+	_, hasTemplate, err := unstructured.NestedMap(obj.Object, "spec", "template")
+	if err != nil || !hasTemplate {
+		return
+	}
+	templateLabels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+	if err != nil {
+		return
+	}
+	unstructured.SetNestedStringMap(obj.Object, fillLabelGaps(templateLabels, walmLabels), "spec", "template", "metadata", "labels")
+}
+
+func buildWalmLabels(namespace, releaseName, projectName string) map[string]string {
+	walmLabels := map[string]string{
+		k8sModel.WalmReleaseLabelKey:   releaseName,
+		k8sModel.WalmNamespaceLabelKey: namespace,
+	}
+	if projectName != "" {
+		walmLabels[k8sModel.WalmProjectLabelKey] = projectName
+	}
+	return walmLabels
+}
+
+func fillLabelGaps(labels map[string]string, walmLabels map[string]string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range walmLabels {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+		}
+	}
+	return labels
+}
@@ -0,0 +1,109 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+)
+
+const testDeploymentYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+  labels:
+    walm.release: chart-set-release
+spec:
+  template:
+    metadata:
+      labels:
+        app: test-app
+    spec:
+      containers: []
+`
+
+const testServiceYaml = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: test-service
+spec:
+  ports: []
+`
+
+const testCrdResourceYaml = `
+apiVersion: apiextensions.transwarp.io/v1beta1
+kind: ApplicationInstance
+metadata:
+  name: test-instance
+spec:
+  applicationRef:
+    name: test-app
+`
+
+func buildTestUnstructured(t *testing.T, objYaml string) *unstructured.Unstructured {
+	obj := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(objYaml), &obj)
+	assert.IsType(t, nil, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func Test_InjectWalmLabels(t *testing.T) {
+	deployment := buildTestUnstructured(t, testDeploymentYaml)
+	service := buildTestUnstructured(t, testServiceYaml)
+	crdResource := buildTestUnstructured(t, testCrdResourceYaml)
+
+	infos := []*resource.Info{
+		{Object: deployment},
+		{Object: service},
+		{Object: crdResource},
+	}
+
+	InjectWalmLabels(infos, "test-ns", "test-release", "test-project")
+
+	// chart provided labels win, walm only fills the gaps
+	assert.Equal(t, map[string]string{
+		"walm.release":   "chart-set-release",
+		"walm.project":   "test-project",
+		"walm.namespace": "test-ns",
+	}, deployment.GetLabels())
+
+	templateLabels, found, err := unstructured.NestedStringMap(deployment.Object, "spec", "template", "metadata", "labels")
+	assert.IsType(t, nil, err)
+	assert.Equal(t, true, found)
+	assert.Equal(t, map[string]string{
+		"app":            "test-app",
+		"walm.release":   "test-release",
+		"walm.project":   "test-project",
+		"walm.namespace": "test-ns",
+	}, templateLabels)
+
+	assert.Equal(t, map[string]string{
+		"walm.release":   "test-release",
+		"walm.project":   "test-project",
+		"walm.namespace": "test-ns",
+	}, service.GetLabels())
+	_, found, err = unstructured.NestedStringMap(service.Object, "spec", "template", "metadata", "labels")
+	assert.IsType(t, nil, err)
+	assert.Equal(t, false, found)
+
+	assert.Equal(t, map[string]string{
+		"walm.release":   "test-release",
+		"walm.project":   "test-project",
+		"walm.namespace": "test-ns",
+	}, crdResource.GetLabels())
+}
+
+func Test_InjectWalmLabelsWithoutProject(t *testing.T) {
+	service := buildTestUnstructured(t, testServiceYaml)
+
+	InjectWalmLabels([]*resource.Info{{Object: service}}, "test-ns", "test-release", "")
+
+	assert.Equal(t, map[string]string{
+		"walm.release":   "test-release",
+		"walm.namespace": "test-ns",
+	}, service.GetLabels())
+}
@@ -0,0 +1,41 @@
+package helm
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/helm/pkg/kube"
+)
+
+var (
+	resourceOpsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "walm_kube_operations_total",
+		Help: "Total number of Kubernetes resource operations, by kind and verb.",
+	}, []string{"kind", "verb"})
+
+	resourceOpErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "walm_kube_operation_errors_total",
+		Help: "Total number of failed Kubernetes resource operations, by kind, verb and status code.",
+	}, []string{"kind", "verb", "code"})
+
+	resourceOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "walm_kube_operation_duration_seconds",
+		Help: "Latencies of Kubernetes resource operations in seconds, by kind and verb.",
+	}, []string{"kind", "verb"})
+)
+
+// The operation metrics share the default registry with walm's http metrics.
+func init() {
+	prometheus.MustRegister(
+		resourceOpsTotal,
+		resourceOpErrorsTotal,
+		resourceOpDuration,
+	)
+	kube.SetResourceOpObserver(func(kind, verb string, duration time.Duration, errCode string) {
+		resourceOpsTotal.WithLabelValues(kind, verb).Inc()
+		resourceOpDuration.WithLabelValues(kind, verb).Observe(duration.Seconds())
+		if errCode != "" {
+			resourceOpErrorsTotal.WithLabelValues(kind, verb, errCode).Inc()
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+)
+
+// UpdateResourcesWithThreeWayMerge patches the live resources to the target manifest with
+// three way merge patches computed from the original manifest, the target manifest and the
+// live objects, like kubectl apply does. Changes made on the live objects out of band do
+// not survive the update, and fields removed from the chart are really deleted.
+func (c *Client) UpdateResourcesWithThreeWayMerge(namespace string, originalManifest, targetManifest string) error {
+	kubeClient := c.GetKubeClient(namespace)
+	originalInfos, err := kubeClient.BuildUnstructured(namespace, bytes.NewBufferString(originalManifest))
+	if err != nil {
+		return err
+	}
+	targetInfos, err := kubeClient.BuildUnstructured(namespace, bytes.NewBufferString(targetManifest))
+	if err != nil {
+		return err
+	}
+
+	SortInfosByKindOrder(targetInfos, InstallOrder)
+	for _, target := range targetInfos {
+		helper := resource.NewHelper(target.Client, target.Mapping)
+		live, err := helper.Get(target.Namespace, target.Name, false)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				_, err = helper.Create(target.Namespace, true, target.Object, nil)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		original := findMatchingInfo(originalInfos, target)
+		err = patchWithConflictRetry(live,
+			func() (runtime.Object, error) {
+				return helper.Get(target.Namespace, target.Name, false)
+			},
+			func(live runtime.Object) error {
+				patch, patchType, err := buildThreeWayMergePatch(original, target, live)
+				if err != nil {
+					return err
+				}
+				if len(patch) == 0 || string(patch) == "{}" {
+					logrus.Debugf("no changes for %s %s/%s", target.Mapping.GroupVersionKind.Kind, target.Namespace, target.Name)
+					return nil
+				}
+				_, err = helper.Patch(target.Namespace, target.Name, patchType, patch, nil)
+				return err
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	// prune the resources the original manifest recorded but the target manifest dropped,
+	// in the reverse of the creation order
+	prunedInfos := make([]*resource.Info, 0)
+	for _, original := range originalInfos {
+		if findMatchingInfo(targetInfos, original) == nil {
+			prunedInfos = append(prunedInfos, original)
+		}
+	}
+	return deleteInfos(prunedInfos)
+}
+
+// DeleteResources deletes the resources built from the manifest in the reverse of the
+// creation order, so dependents go before what they depend on. Resources already gone
+// are skipped.
+func (c *Client) DeleteResources(namespace string, manifest string) error {
+	kubeClient := c.GetKubeClient(namespace)
+	infos, err := kubeClient.BuildUnstructured(namespace, bytes.NewBufferString(manifest))
+	if err != nil {
+		return err
+	}
+	return deleteInfos(infos)
+}
+
+func deleteInfos(infos []*resource.Info) error {
+	SortInfosByKindOrder(infos, UninstallOrder)
+	for _, info := range infos {
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		_, err := helper.Delete(info.Namespace, info.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				logrus.Debugf("%s %s/%s is already deleted", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// patchRetryBackoff bounds how often a conflicting patch is recomputed and resent
+// before the error is surfaced.
+var patchRetryBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 50 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// patchWithConflictRetry sends the patch built from the live object, and when another
+// client updated the object concurrently, re-fetches it and recomputes the patch a
+// bounded number of times. Only genuine conflicts are retried, other errors surface
+// immediately.
+func patchWithConflictRetry(live runtime.Object, getLive func() (runtime.Object, error), sendPatch func(live runtime.Object) error) error {
+	return retry.RetryOnConflict(patchRetryBackoff, func() error {
+		if live == nil {
+			var err error
+			live, err = getLive()
+			if err != nil {
+				return err
+			}
+		}
+		err := sendPatch(live)
+		// the live object is stale once a patch was attempted, re-fetch it on the next try
+		live = nil
+		return err
+	})
+}
+
+func buildThreeWayMergePatch(original, target *resource.Info, live runtime.Object) ([]byte, types.PatchType, error) {
+	var originalData []byte
+	var err error
+	if original != nil {
+		originalData, err = json.Marshal(original.Object)
+		if err != nil {
+			return nil, types.StrategicMergePatchType, err
+		}
+	} else {
+		// the resource is not recorded in the original manifest, nothing to revert
+		originalData = []byte("{}")
+	}
+	modifiedData, err := json.Marshal(target.Object)
+	if err != nil {
+		return nil, types.StrategicMergePatchType, err
+	}
+	liveData, err := json.Marshal(live)
+	if err != nil {
+		return nil, types.StrategicMergePatchType, err
+	}
+	return createThreeWayMergePatch(originalData, modifiedData, liveData, versionedObjectForInfo(target))
+}
+
+// createThreeWayMergePatch computes the patch to apply to the live object from the old
+// manifest (original), the new manifest (modified) and the live object itself (current).
+// Kinds not registered in the scheme, such as crd based resources, do not support
+// strategic merge and fall back to a three way json merge patch.
+func createThreeWayMergePatch(originalData, modifiedData, liveData []byte, versionedObject runtime.Object) ([]byte, types.PatchType, error) {
+	_, isUnstructured := versionedObject.(runtime.Unstructured)
+	if versionedObject == nil || isUnstructured {
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(originalData, modifiedData, liveData)
+		return patch, types.MergePatchType, err
+	}
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObject)
+	if err != nil {
+		return nil, types.StrategicMergePatchType, err
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(originalData, modifiedData, liveData, patchMeta, true)
+	return patch, types.StrategicMergePatchType, err
+}
+
+// versionedObjectForInfo returns the empty versioned object of the info's kind, or the
+// info's object itself when the kind is not registered in the scheme.
+func versionedObjectForInfo(info *resource.Info) runtime.Object {
+	if info.Mapping == nil {
+		return info.Object
+	}
+	versionedObject, err := legacyscheme.Scheme.New(info.Mapping.GroupVersionKind)
+	if err != nil {
+		return info.Object
+	}
+	return versionedObject
+}
+
+func findMatchingInfo(infos []*resource.Info, target *resource.Info) *resource.Info {
+	for _, info := range infos {
+		if info.Namespace == target.Namespace && info.Name == target.Name &&
+			info.Mapping.GroupVersionKind == target.Mapping.GroupVersionKind {
+			return info
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package helm
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+func Test_CreateThreeWayMergePatch(t *testing.T) {
+	// the chart originally shipped an env var and 3 replicas
+	originalData := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test-deployment"},"spec":{"replicas":3,"template":{"spec":{"containers":[{"name":"test-container","env":[{"name":"TEST_ENV","value":"test"}]}]}}}}`)
+	// the new chart version removes the env var and keeps 3 replicas
+	modifiedData := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test-deployment"},"spec":{"replicas":3,"template":{"spec":{"containers":[{"name":"test-container"}]}}}}`)
+	// the live object still holds the env var and its replicas drifted to 5
+	liveData := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test-deployment"},"spec":{"replicas":5,"template":{"spec":{"containers":[{"name":"test-container","env":[{"name":"TEST_ENV","value":"test"}]}]}}}}`)
+
+	patch, patchType, err := createThreeWayMergePatch(originalData, modifiedData, liveData, &appsv1.Deployment{})
+	assert.IsType(t, nil, err)
+	assert.Equal(t, types.StrategicMergePatchType, patchType)
+
+	patchedData, err := strategicpatch.StrategicMergePatch(liveData, patch, &appsv1.Deployment{})
+	assert.IsType(t, nil, err)
+
+	patchedDeployment := &appsv1.Deployment{}
+	err = json.Unmarshal(patchedData, patchedDeployment)
+	assert.IsType(t, nil, err)
+
+	assert.Equal(t, int32(3), *patchedDeployment.Spec.Replicas)
+	assert.Equal(t, 0, len(patchedDeployment.Spec.Template.Spec.Containers[0].Env))
+}
+
+func Test_CreateThreeWayMergePatch_Unstructured(t *testing.T) {
+	originalData := []byte(`{"apiVersion":"apiextensions.transwarp.io/v1beta1","kind":"ApplicationInstance","metadata":{"name":"test-instance"},"spec":{"configs":{"testKey":"testValue"}}}`)
+	modifiedData := []byte(`{"apiVersion":"apiextensions.transwarp.io/v1beta1","kind":"ApplicationInstance","metadata":{"name":"test-instance"},"spec":{"configs":{}}}`)
+	liveData := []byte(`{"apiVersion":"apiextensions.transwarp.io/v1beta1","kind":"ApplicationInstance","metadata":{"name":"test-instance"},"spec":{"configs":{"testKey":"driftedValue"}}}`)
+
+	patch, patchType, err := createThreeWayMergePatch(originalData, modifiedData, liveData, &unstructured.Unstructured{})
+	assert.IsType(t, nil, err)
+	assert.Equal(t, types.MergePatchType, patchType)
+	assert.Contains(t, string(patch), "\"testKey\":null")
+}
+
+func Test_PatchWithConflictRetry(t *testing.T) {
+	testResource := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	sendPatchTimes := 0
+	getLiveTimes := 0
+	err := patchWithConflictRetry(&appsv1.Deployment{},
+		func() (runtime.Object, error) {
+			getLiveTimes++
+			return &appsv1.Deployment{}, nil
+		},
+		func(live runtime.Object) error {
+			sendPatchTimes++
+			if sendPatchTimes <= 2 {
+				return apierrors.NewConflict(testResource, "test-deployment", errors.New("the object has been modified"))
+			}
+			return nil
+		})
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 3, sendPatchTimes)
+	assert.Equal(t, 2, getLiveTimes)
+
+	sendPatchTimes = 0
+	err = patchWithConflictRetry(&appsv1.Deployment{},
+		func() (runtime.Object, error) {
+			return &appsv1.Deployment{}, nil
+		},
+		func(live runtime.Object) error {
+			sendPatchTimes++
+			return apierrors.NewForbidden(testResource, "test-deployment", errors.New("not allowed"))
+		})
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Equal(t, 1, sendPatchTimes)
+}
@@ -0,0 +1,184 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions/resource"
+)
+
+const ingressClassAnnotationKey = "kubernetes.io/ingress.class"
+
+// WaitForResourcesReady supplements the readiness checks of the vendored wait logic,
+// which reports success while pvcs are still pending, ingresses have no address and
+// daemon sets are not scheduled anywhere. It polls the live resources built from the
+// manifest until they are all ready or the timeout elapses.
+func (c *Client) WaitForResourcesReady(namespace string, manifest string, timeoutSec int64) error {
+	kubeClient := c.GetKubeClient(namespace)
+	infos, err := kubeClient.BuildUnstructured(namespace, bytes.NewBufferString(manifest))
+	if err != nil {
+		return err
+	}
+
+	return wait.Poll(2*time.Second, time.Duration(timeoutSec)*time.Second, func() (bool, error) {
+		for _, info := range infos {
+			ready, note, err := c.isResourceReady(info)
+			if err != nil {
+				return false, err
+			}
+			if !ready {
+				logrus.Debugf("still waiting for %s %s/%s to be ready", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name)
+				return false, nil
+			}
+			if note != "" {
+				logrus.Infof("%s %s/%s : %s", info.Mapping.GroupVersionKind.Kind, info.Namespace, info.Name, note)
+			}
+		}
+		return true, nil
+	})
+}
+
+func (c *Client) isResourceReady(info *resource.Info) (ready bool, note string, err error) {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	live, err := helper.Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch info.Mapping.GroupVersionKind.Kind {
+	case "PersistentVolumeClaim":
+		pvc := &corev1.PersistentVolumeClaim{}
+		err = convertLiveObject(live, pvc)
+		if err != nil {
+			return false, "", err
+		}
+		storageClass, err := c.getPvcStorageClass(info.Namespace, pvc)
+		if err != nil {
+			return false, "", err
+		}
+		ready, note = IsPvcReady(pvc, storageClass)
+		return ready, note, nil
+	case "Ingress":
+		ingress := &extv1beta1.Ingress{}
+		err = convertLiveObject(live, ingress)
+		if err != nil {
+			return false, "", err
+		}
+		return IsIngressReady(ingress), "", nil
+	case "DaemonSet":
+		daemonSet := &extv1beta1.DaemonSet{}
+		err = convertLiveObject(live, daemonSet)
+		if err != nil {
+			return false, "", err
+		}
+		return IsDaemonSetReady(daemonSet), "", nil
+	case "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		err = convertLiveObject(live, statefulSet)
+		if err != nil {
+			return false, "", err
+		}
+		ready, note = IsStatefulSetReady(statefulSet)
+		return ready, note, nil
+	}
+	// other kinds are covered by the vendored wait logic
+	return true, "", nil
+}
+
+func (c *Client) getPvcStorageClass(namespace string, pvc *corev1.PersistentVolumeClaim) (*storagev1.StorageClass, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil, nil
+	}
+	clientSet, err := c.GetKubeClient(namespace).KubernetesClientSet()
+	if err != nil {
+		return nil, err
+	}
+	storageClass, err := clientSet.StorageV1().StorageClasses().Get(*pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		logrus.Warnf("failed to get storage class %s : %s", *pvc.Spec.StorageClassName, err.Error())
+		return nil, nil
+	}
+	return storageClass, nil
+}
+
+// IsPvcReady reports whether the pvc is bound. Pvcs of a WaitForFirstConsumer storage
+// class stay pending until a pod uses them, which is treated as ready with a note.
+func IsPvcReady(pvc *corev1.PersistentVolumeClaim, storageClass *storagev1.StorageClass) (ready bool, note string) {
+	if pvc.Status.Phase == corev1.ClaimBound {
+		return true, ""
+	}
+	if storageClass != nil && storageClass.VolumeBindingMode != nil &&
+		*storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		return true, "pvc is pending, its storage class binds on first consumer"
+	}
+	return false, ""
+}
+
+// IsIngressReady reports whether the ingress got a load balancer address. Ingresses
+// without an explicit class annotation may be served by controllers which never report
+// an address, so only classed ingresses are required to have one.
+func IsIngressReady(ingress *extv1beta1.Ingress) bool {
+	if _, hasClass := ingress.Annotations[ingressClassAnnotationKey]; !hasClass {
+		return true
+	}
+	return len(ingress.Status.LoadBalancer.Ingress) > 0
+}
+
+// IsDaemonSetReady reports whether all the pods the daemon set wants to schedule are ready.
+func IsDaemonSetReady(daemonSet *extv1beta1.DaemonSet) bool {
+	return daemonSet.Status.DesiredNumberScheduled == daemonSet.Status.NumberReady
+}
+
+// IsStatefulSetReady reports whether the stateful set finished rolling out the intended
+// pods of the new revision. With a RollingUpdate partition only the pods above the
+// partition are expected on the update revision; the pods below it stay on the old one.
+// The OnDelete strategy never rolls pods automatically, which is treated as ready with
+// a note instead of blocking forever.
+func IsStatefulSetReady(statefulSet *appsv1.StatefulSet) (ready bool, note string) {
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return true, "stateful set uses the OnDelete update strategy, its pods roll to the new revision only when deleted manually"
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	if statefulSet.Status.ReadyReplicas < replicas {
+		return false, ""
+	}
+
+	expectedUpdatedReplicas := replicas
+	if statefulSet.Spec.UpdateStrategy.RollingUpdate != nil &&
+		statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		expectedUpdatedReplicas = replicas - *statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	if expectedUpdatedReplicas <= 0 {
+		return true, ""
+	}
+	if statefulSet.Status.UpdatedReplicas < expectedUpdatedReplicas {
+		return false, ""
+	}
+	// once the rollout is complete the update revision becomes the current revision,
+	// unless a partition deliberately keeps part of the pods on the old one
+	if statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision && expectedUpdatedReplicas == replicas {
+		return false, ""
+	}
+	return true, ""
+}
+
+func convertLiveObject(live runtime.Object, target interface{}) error {
+	liveData, err := json.Marshal(live)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(liveData, target)
+}
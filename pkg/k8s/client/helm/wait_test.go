@@ -0,0 +1,219 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_IsPvcReady(t *testing.T) {
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	immediate := storagev1.VolumeBindingImmediate
+
+	tests := []struct {
+		pvc          *corev1.PersistentVolumeClaim
+		storageClass *storagev1.StorageClass
+		ready        bool
+		hasNote      bool
+	}{
+		{
+			pvc: &corev1.PersistentVolumeClaim{
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+			ready: true,
+		},
+		{
+			pvc: &corev1.PersistentVolumeClaim{
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			ready: false,
+		},
+		{
+			pvc: &corev1.PersistentVolumeClaim{
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			storageClass: &storagev1.StorageClass{VolumeBindingMode: &waitForFirstConsumer},
+			ready:        true,
+			hasNote:      true,
+		},
+		{
+			pvc: &corev1.PersistentVolumeClaim{
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			storageClass: &storagev1.StorageClass{VolumeBindingMode: &immediate},
+			ready:        false,
+		},
+	}
+
+	for _, test := range tests {
+		ready, note := IsPvcReady(test.pvc, test.storageClass)
+		assert.Equal(t, test.ready, ready)
+		assert.Equal(t, test.hasNote, note != "")
+	}
+}
+
+func Test_IsIngressReady(t *testing.T) {
+	tests := []struct {
+		ingress *extv1beta1.Ingress
+		ready   bool
+	}{
+		{
+			ingress: &extv1beta1.Ingress{},
+			ready:   true,
+		},
+		{
+			ingress: &extv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ingressClassAnnotationKey: "nginx"},
+				},
+			},
+			ready: false,
+		},
+		{
+			ingress: &extv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{ingressClassAnnotationKey: "nginx"},
+				},
+				Status: extv1beta1.IngressStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{IP: "172.16.1.1"}},
+					},
+				},
+			},
+			ready: true,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.ready, IsIngressReady(test.ingress))
+	}
+}
+
+func Test_IsDaemonSetReady(t *testing.T) {
+	tests := []struct {
+		daemonSet *extv1beta1.DaemonSet
+		ready     bool
+	}{
+		{
+			daemonSet: &extv1beta1.DaemonSet{
+				Status: extv1beta1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+			},
+			ready: true,
+		},
+		{
+			daemonSet: &extv1beta1.DaemonSet{
+				Status: extv1beta1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2},
+			},
+			ready: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.ready, IsDaemonSetReady(test.daemonSet))
+	}
+}
+
+func Test_IsStatefulSetReady(t *testing.T) {
+	replicas := int32(3)
+	partition := int32(2)
+
+	tests := []struct {
+		statefulSet *appsv1.StatefulSet
+		ready       bool
+		hasNote     bool
+	}{
+		{
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.RollingUpdateStatefulSetStrategyType,
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas:   3,
+					UpdatedReplicas: 3,
+					CurrentRevision: "test-revision2",
+					UpdateRevision:  "test-revision2",
+				},
+			},
+			ready: true,
+		},
+		{
+			// old replicas are ready but the new revision has not rolled out yet
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.RollingUpdateStatefulSetStrategyType,
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas:   3,
+					UpdatedReplicas: 0,
+					CurrentRevision: "test-revision1",
+					UpdateRevision:  "test-revision2",
+				},
+			},
+			ready: false,
+		},
+		{
+			// with a partition only the pods above it are expected on the new revision
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+							Partition: &partition,
+						},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas:   3,
+					UpdatedReplicas: 1,
+					CurrentRevision: "test-revision1",
+					UpdateRevision:  "test-revision2",
+				},
+			},
+			ready: true,
+		},
+		{
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.OnDeleteStatefulSetStrategyType,
+					},
+				},
+			},
+			ready:   true,
+			hasNote: true,
+		},
+		{
+			statefulSet: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.RollingUpdateStatefulSetStrategyType,
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas: 2,
+				},
+			},
+			ready: false,
+		},
+	}
+
+	for _, test := range tests {
+		ready, note := IsStatefulSetReady(test.statefulSet)
+		assert.Equal(t, test.ready, ready)
+		assert.Equal(t, test.hasNote, note != "")
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"github.com/hashicorp/golang-lru"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"strings"
+)
+
+// ImpersonatingClientFactory builds kube clients acting as an authenticated
+// caller by setting the impersonation headers on a copy of the base rest
+// config. Clients are cached per caller, so discovery is not rebuilt on every
+// request.
+type ImpersonatingClientFactory struct {
+	baseConfig *rest.Config
+	clients    *lru.Cache
+}
+
+func NewImpersonatingClientFactory(apiserverHost string, kubeConfig string) (*ImpersonatingClientFactory, error) {
+	baseConfig, err := clientcmd.BuildConfigFromFlags(apiserverHost, kubeConfig)
+	if err != nil {
+		logrus.Errorf("failed to build kube rest config : %s", err.Error())
+		return nil, err
+	}
+	clients, _ := lru.New(100)
+	return &ImpersonatingClientFactory{
+		baseConfig: baseConfig,
+		clients:    clients,
+	}, nil
+}
+
+// GetClient returns a kube client whose requests impersonate the user and its
+// groups, so the apiserver applies the user's rbac instead of walm's.
+func (factory *ImpersonatingClientFactory) GetClient(user string, groups []string) (*kubernetes.Clientset, error) {
+	key := user + "\n" + strings.Join(groups, "\n")
+	if client, ok := factory.clients.Get(key); ok {
+		return client.(*kubernetes.Clientset), nil
+	}
+
+	config := rest.CopyConfig(factory.baseConfig)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: user,
+		Groups:   groups,
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		logrus.Errorf("failed to build kube client impersonating %s : %s", user, err.Error())
+		return nil, err
+	}
+	factory.clients.Add(key, client)
+	return client, nil
+}
@@ -9,14 +9,18 @@ import (
 )
 
 func ConvertLimitRangeToK8s(limitRange *k8s.LimitRange) (*v1.LimitRange, error) {
-	resourceList := v1.ResourceList{}
-	for key, value := range limitRange.DefaultLimit {
-		quantity, err := resource.ParseQuantity(value)
-		if err != nil {
-			logrus.Warnf("failed to parse quantity %s : %s", value, err.Error())
-			continue
-		}
-		resourceList[v1.ResourceName(key)] = quantity
+	limitRangeItem := v1.LimitRangeItem{
+		Type:    v1.LimitTypeContainer,
+		Default: buildLimitRangeResourceList(limitRange.DefaultLimit),
+	}
+	if len(limitRange.DefaultRequest) > 0 {
+		limitRangeItem.DefaultRequest = buildLimitRangeResourceList(limitRange.DefaultRequest)
+	}
+	if len(limitRange.Max) > 0 {
+		limitRangeItem.Max = buildLimitRangeResourceList(limitRange.Max)
+	}
+	if len(limitRange.Min) > 0 {
+		limitRangeItem.Min = buildLimitRangeResourceList(limitRange.Min)
 	}
 
 	return &v1.LimitRange{
@@ -26,11 +30,49 @@ func ConvertLimitRangeToK8s(limitRange *k8s.LimitRange) (*v1.LimitRange, error)
 		},
 		Spec: v1.LimitRangeSpec{
 			Limits: []v1.LimitRangeItem{
-				{
-					Type: v1.LimitTypeContainer,
-					Default: resourceList,
-				},
+				limitRangeItem,
 			},
 		},
 	}, nil
 }
+
+func buildLimitRangeResourceList(limits map[k8s.ResourceName]string) v1.ResourceList {
+	resourceList := v1.ResourceList{}
+	for key, value := range limits {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			logrus.Warnf("failed to parse quantity %s : %s", value, err.Error())
+			continue
+		}
+		resourceList[v1.ResourceName(key)] = quantity
+	}
+	return resourceList
+}
+
+func ConvertLimitRangeFromK8s(oriLimitRange *v1.LimitRange) (*k8s.LimitRange, error) {
+	if oriLimitRange == nil {
+		return nil, nil
+	}
+	k8sLimitRange := oriLimitRange.DeepCopy()
+	limitRange := &k8s.LimitRange{
+		Meta: k8s.NewMeta(k8s.LimitRangeKind, k8sLimitRange.Namespace, k8sLimitRange.Name, k8s.NewState("Ready", "", "")),
+	}
+	for _, limit := range k8sLimitRange.Spec.Limits {
+		if limit.Type != v1.LimitTypeContainer {
+			continue
+		}
+		limitRange.DefaultLimit = buildLimitRangeLimits(limit.Default)
+		limitRange.DefaultRequest = buildLimitRangeLimits(limit.DefaultRequest)
+		limitRange.Max = buildLimitRangeLimits(limit.Max)
+		limitRange.Min = buildLimitRangeLimits(limit.Min)
+	}
+	return limitRange, nil
+}
+
+func buildLimitRangeLimits(resourceList v1.ResourceList) map[k8s.ResourceName]string {
+	limits := map[k8s.ResourceName]string{}
+	for key, value := range resourceList {
+		limits[k8s.ResourceName(key)] = value.String()
+	}
+	return limits
+}
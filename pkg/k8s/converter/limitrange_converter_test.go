@@ -76,3 +76,122 @@ func TestConvertLimitRangeToK8s(t *testing.T) {
 		assert.Equal(t, test.limitRange, limitRange)
 	}
 }
+
+func TestConvertLimitRangeToK8sBounds(t *testing.T) {
+	tests := []struct {
+		orilimitRange *k8s.LimitRange
+		limitRange    *corev1.LimitRange
+		err           error
+	}{
+		{
+			orilimitRange: &k8s.LimitRange{
+				Meta: k8s.Meta{
+					Name:      "test-limit-range",
+					Namespace: "test-namespace",
+				},
+				DefaultLimit: map[k8s.ResourceName]string{
+					k8s.ResourceMemory: "256Mi",
+				},
+				DefaultRequest: map[k8s.ResourceName]string{
+					k8s.ResourceMemory: "128Mi",
+				},
+				Max: map[k8s.ResourceName]string{
+					k8s.ResourceMemory: "1Gi",
+				},
+				Min: map[k8s.ResourceName]string{
+					k8s.ResourceMemory: "64Mi",
+				},
+			},
+			limitRange: &corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-limit-range",
+					Namespace: "test-namespace",
+				},
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Default: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+							DefaultRequest: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("128Mi"),
+							},
+							Max: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Min: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("64Mi"),
+							},
+						},
+					},
+				},
+			},
+			err: nil,
+		},
+	}
+
+	for _, test := range tests {
+		limitRange, err := ConvertLimitRangeToK8s(test.orilimitRange)
+		assert.IsType(t, test.err, err)
+		assert.Equal(t, test.limitRange, limitRange)
+	}
+}
+
+func TestConvertLimitRangeFromK8s(t *testing.T) {
+	tests := []struct {
+		oriLimitRange *corev1.LimitRange
+		limitRange    *k8s.LimitRange
+		err           error
+	}{
+		{
+			oriLimitRange: &corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-limit-range",
+					Namespace: "test-namespace",
+				},
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Default: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+							DefaultRequest: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+							Max: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			limitRange: &k8s.LimitRange{
+				Meta: k8s.NewMeta(k8s.LimitRangeKind, "test-namespace", "test-limit-range", k8s.NewState("Ready", "", "")),
+				DefaultLimit: map[k8s.ResourceName]string{
+					k8s.ResourceMemory: "256Mi",
+				},
+				DefaultRequest: map[k8s.ResourceName]string{
+					k8s.ResourceCPU: "100m",
+				},
+				Max: map[k8s.ResourceName]string{
+					k8s.ResourceMemory: "1Gi",
+				},
+				Min: map[k8s.ResourceName]string{},
+			},
+			err: nil,
+		},
+		{
+			oriLimitRange: nil,
+			limitRange:    nil,
+			err:           nil,
+		},
+	}
+
+	for _, test := range tests {
+		limitRange, err := ConvertLimitRangeFromK8s(test.oriLimitRange)
+		assert.IsType(t, test.err, err)
+		assert.Equal(t, test.limitRange, limitRange)
+	}
+}
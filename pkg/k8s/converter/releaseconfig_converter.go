@@ -1,15 +1,46 @@
 package converter
 
 import (
+	"time"
+
 	"WarpCloud/walm/pkg/models/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"transwarp/release-config/pkg/apis/transwarp/v1beta1"
 )
 
+func ConvertReleaseConfigToK8s(releaseConfig *k8s.ReleaseConfig) (*v1beta1.ReleaseConfig, error) {
+	if releaseConfig == nil {
+		return nil, nil
+	}
+	return &v1beta1.ReleaseConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: releaseConfig.Namespace,
+			Name:      releaseConfig.Name,
+			Labels:    releaseConfig.Labels,
+		},
+		Spec: v1beta1.ReleaseConfigSpec{
+			OutputConfig:             releaseConfig.OutputConfig,
+			ChartImage:               releaseConfig.ChartImage,
+			ChartName:                releaseConfig.ChartName,
+			ConfigValues:             releaseConfig.ConfigValues,
+			Dependencies:             releaseConfig.Dependencies,
+			ChartVersion:             releaseConfig.ChartVersion,
+			ChartAppVersion:          releaseConfig.ChartAppVersion,
+			Repo:                     releaseConfig.Repo,
+			DependenciesConfigValues: releaseConfig.DependenciesConfigValues,
+		},
+	}, nil
+}
+
 func ConvertReleaseConfigFromK8s(oriReleaseConfig *v1beta1.ReleaseConfig) (*k8s.ReleaseConfig, error) {
 	if oriReleaseConfig == nil {
 		return nil, nil
 	}
 	releaseConfig := oriReleaseConfig.DeepCopy()
+	creationTimestamp := ""
+	if !releaseConfig.CreationTimestamp.IsZero() {
+		creationTimestamp = releaseConfig.CreationTimestamp.Format(time.RFC3339)
+	}
 	return &k8s.ReleaseConfig{
 		Meta:                     k8s.NewMeta(k8s.ReleaseConfigKind, releaseConfig.Namespace, releaseConfig.Name, k8s.NewState("Ready", "", "")),
 		Labels:                   releaseConfig.Labels,
@@ -22,5 +53,6 @@ func ConvertReleaseConfigFromK8s(oriReleaseConfig *v1beta1.ReleaseConfig) (*k8s.
 		ChartAppVersion:          releaseConfig.Spec.ChartAppVersion,
 		Repo:                     releaseConfig.Spec.Repo,
 		DependenciesConfigValues: releaseConfig.Spec.DependenciesConfigValues,
+		CreationTimestamp:        creationTimestamp,
 	}, nil
 }
@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"WarpCloud/walm/pkg/models/k8s"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ConvertRoleBindingToK8s(roleBinding *k8s.RoleBinding) (*rbacv1.RoleBinding, error) {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: roleBinding.Namespace,
+			Name:      roleBinding.Name,
+			Labels:    roleBinding.Labels,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     roleBinding.SubjectKind,
+				Name:     roleBinding.SubjectName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     roleBinding.ClusterRoleName,
+		},
+	}, nil
+}
+
+func ConvertRoleBindingFromK8s(oriRoleBinding *rbacv1.RoleBinding) (*k8s.RoleBinding, error) {
+	if oriRoleBinding == nil {
+		return nil, nil
+	}
+	k8sRoleBinding := oriRoleBinding.DeepCopy()
+	roleBinding := &k8s.RoleBinding{
+		Meta:            k8s.NewMeta(k8s.RoleBindingKind, k8sRoleBinding.Namespace, k8sRoleBinding.Name, k8s.NewState("Ready", "", "")),
+		Labels:          k8sRoleBinding.Labels,
+		ClusterRoleName: k8sRoleBinding.RoleRef.Name,
+	}
+	for _, subject := range k8sRoleBinding.Subjects {
+		roleBinding.SubjectKind = subject.Kind
+		roleBinding.SubjectName = subject.Name
+		break
+	}
+	return roleBinding, nil
+}
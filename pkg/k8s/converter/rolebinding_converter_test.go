@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"WarpCloud/walm/pkg/models/k8s"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestConvertRoleBindingToK8s(t *testing.T) {
+	tests := []struct {
+		oriRoleBinding *k8s.RoleBinding
+		roleBinding    *rbacv1.RoleBinding
+		err            error
+	}{
+		{
+			oriRoleBinding: &k8s.RoleBinding{
+				Meta: k8s.Meta{
+					Namespace: "test-namespace",
+					Name:      "walm-member-user-test-user",
+				},
+				Labels: map[string]string{
+					"tenant.walm.io/member": "true",
+				},
+				SubjectKind:     "User",
+				SubjectName:     "test-user",
+				ClusterRoleName: "walm-tenant-viewer",
+			},
+			roleBinding: &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-namespace",
+					Name:      "walm-member-user-test-user",
+					Labels: map[string]string{
+						"tenant.walm.io/member": "true",
+					},
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "User",
+						Name:     "test-user",
+					},
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     "walm-tenant-viewer",
+				},
+			},
+			err: nil,
+		},
+	}
+
+	for _, test := range tests {
+		roleBinding, err := ConvertRoleBindingToK8s(test.oriRoleBinding)
+		assert.IsType(t, test.err, err)
+		assert.Equal(t, test.roleBinding, roleBinding)
+	}
+}
+
+func TestConvertRoleBindingFromK8s(t *testing.T) {
+	tests := []struct {
+		oriRoleBinding *rbacv1.RoleBinding
+		roleBinding    *k8s.RoleBinding
+		err            error
+	}{
+		{
+			oriRoleBinding: &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-namespace",
+					Name:      "walm-member-group-test-group",
+					Labels: map[string]string{
+						"tenant.walm.io/member": "true",
+					},
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     "Group",
+						Name:     "test-group",
+					},
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     "walm-tenant-editor",
+				},
+			},
+			roleBinding: &k8s.RoleBinding{
+				Meta: k8s.NewMeta(k8s.RoleBindingKind, "test-namespace", "walm-member-group-test-group", k8s.NewState("Ready", "", "")),
+				Labels: map[string]string{
+					"tenant.walm.io/member": "true",
+				},
+				SubjectKind:     "Group",
+				SubjectName:     "test-group",
+				ClusterRoleName: "walm-tenant-editor",
+			},
+			err: nil,
+		},
+		{
+			oriRoleBinding: nil,
+			roleBinding:    nil,
+			err:            nil,
+		},
+	}
+
+	for _, test := range tests {
+		roleBinding, err := ConvertRoleBindingFromK8s(test.oriRoleBinding)
+		assert.IsType(t, test.err, err)
+		assert.Equal(t, test.roleBinding, roleBinding)
+	}
+}
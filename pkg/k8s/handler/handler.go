@@ -4,23 +4,25 @@ import (
 	"WarpCloud/walm/pkg/k8s/client"
 	"WarpCloud/walm/pkg/k8s/informer"
 	"k8s.io/client-go/kubernetes"
+	"sync"
 	releaseconfigclientset "transwarp/release-config/pkg/client/clientset/versioned"
 )
 
 var handlerSets *HandlerSet
+var handlerSetsOnce sync.Once
 
 func GetDefaultHandlerSet() *HandlerSet {
-	if handlerSets == nil {
+	handlerSetsOnce.Do(func() {
 		handlerSets = &HandlerSet{
 			client: client.GetDefaultClient(),
 			releaseConfigClient: client.GetDefaultReleaseConfigClient(),
 			factory: informer.GetDefaultFactory(),
 		}
-	}
+	})
 	return handlerSets
 }
 
-func NewFakeHandlerSet(client *kubernetes.Clientset, releaseConfigClient *releaseconfigclientset.Clientset, factory *informer.InformerFactory) *HandlerSet{
+func NewFakeHandlerSet(client *kubernetes.Clientset, releaseConfigClient releaseconfigclientset.Interface, factory *informer.InformerFactory) *HandlerSet{
 	return &HandlerSet{
 		client: client,
 		releaseConfigClient: releaseConfigClient,
@@ -8,7 +8,7 @@ import (
 
 type HandlerSet struct {
 	client *kubernetes.Clientset
-	releaseConfigClient *releaseconfigclientset.Clientset
+	releaseConfigClient releaseconfigclientset.Interface
 	factory *informer.InformerFactory
 	configMapHandler *ConfigMapHandler
 	daemonSetHandler *DaemonSetHandler
@@ -13,7 +13,7 @@ import (
 )
 
 type ReleaseConfigHandler struct {
-	client *clientsetex.Clientset
+	client clientsetex.Interface
 	lister listv1beta1.ReleaseConfigLister
 }
 
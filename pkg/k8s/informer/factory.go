@@ -62,7 +62,7 @@ func (factory *InformerFactory) WaitForCacheSync(stopCh <-chan struct{}) {
 	factory.ReleaseConifgFactory.WaitForCacheSync(stopCh)
 }
 
-func newInformerFactory(client *kubernetes.Clientset, releaseConfigClient *releaseconfigclientset.Clientset, resyncPeriod time.Duration) (*InformerFactory) {
+func newInformerFactory(client kubernetes.Interface, releaseConfigClient releaseconfigclientset.Interface, resyncPeriod time.Duration) (*InformerFactory) {
 	factory := &InformerFactory{}
 	factory.Factory = informers.NewSharedInformerFactory(client, resyncPeriod)
 	factory.DeploymentLister = factory.Factory.Extensions().V1beta1().Deployments().Lister()
@@ -88,6 +88,6 @@ func newInformerFactory(client *kubernetes.Clientset, releaseConfigClient *relea
 }
 
 // for test
-func NewFakeInformerFactory(client *kubernetes.Clientset, releaseConfigClient *releaseconfigclientset.Clientset, resyncPeriod time.Duration) (*InformerFactory) {
+func NewFakeInformerFactory(client kubernetes.Interface, releaseConfigClient releaseconfigclientset.Interface, resyncPeriod time.Duration) (*InformerFactory) {
 	return newInformerFactory(client, releaseConfigClient, resyncPeriod)
 }
@@ -17,6 +17,22 @@ func (_m *Cache) AddReleaseConfigHandler(OnAdd func(interface{}), OnUpdate func(
 	_m.Called(OnAdd, OnUpdate, OnDelete)
 }
 
+// GetCacheSyncInfo provides a mock function with given fields:
+func (_m *Cache) GetCacheSyncInfo() *modelsk8s.CacheSyncInfo {
+	ret := _m.Called()
+
+	var r0 *modelsk8s.CacheSyncInfo
+	if rf, ok := ret.Get(0).(func() *modelsk8s.CacheSyncInfo); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelsk8s.CacheSyncInfo)
+		}
+	}
+
+	return r0
+}
+
 // GetNodes provides a mock function with given fields: labelSelector
 func (_m *Cache) GetNodes(labelSelector string) ([]*modelsk8s.Node, error) {
 	ret := _m.Called(labelSelector)
@@ -40,6 +56,29 @@ func (_m *Cache) GetNodes(labelSelector string) ([]*modelsk8s.Node, error) {
 	return r0, r1
 }
 
+// GetPodDetail provides a mock function with given fields: namespace, podName, containerName, previous, tailLines
+func (_m *Cache) GetPodDetail(namespace string, podName string, containerName string, previous bool, tailLines int64) (*modelsk8s.PodDetail, error) {
+	ret := _m.Called(namespace, podName, containerName, previous, tailLines)
+
+	var r0 *modelsk8s.PodDetail
+	if rf, ok := ret.Get(0).(func(string, string, string, bool, int64) *modelsk8s.PodDetail); ok {
+		r0 = rf(namespace, podName, containerName, previous, tailLines)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelsk8s.PodDetail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, bool, int64) error); ok {
+		r1 = rf(namespace, podName, containerName, previous, tailLines)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPodEventList provides a mock function with given fields: namespace, name
 func (_m *Cache) GetPodEventList(namespace string, name string) (*modelsk8s.EventList, error) {
 	ret := _m.Called(namespace, name)
@@ -130,6 +169,29 @@ func (_m *Cache) GetResourceSet(releaseResourceMetas []release.ReleaseResourceMe
 	return r0, r1
 }
 
+// GetResourceSetLive provides a mock function with given fields: releaseResourceMetas
+func (_m *Cache) GetResourceSetLive(releaseResourceMetas []release.ReleaseResourceMeta) (*modelsk8s.ResourceSet, error) {
+	ret := _m.Called(releaseResourceMetas)
+
+	var r0 *modelsk8s.ResourceSet
+	if rf, ok := ret.Get(0).(func([]release.ReleaseResourceMeta) *modelsk8s.ResourceSet); ok {
+		r0 = rf(releaseResourceMetas)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*modelsk8s.ResourceSet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]release.ReleaseResourceMeta) error); ok {
+		r1 = rf(releaseResourceMetas)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTenant provides a mock function with given fields: tenantName
 func (_m *Cache) GetTenant(tenantName string) (*tenant.TenantInfo, error) {
 	ret := _m.Called(tenantName)
@@ -199,6 +261,29 @@ func (_m *Cache) ListReleaseConfigs(namespace string, labelSelectorStr string) (
 	return r0, r1
 }
 
+// ListReleaseConfigsByDependency provides a mock function with given fields: namespace, name
+func (_m *Cache) ListReleaseConfigsByDependency(namespace string, name string) ([]*modelsk8s.ReleaseConfig, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 []*modelsk8s.ReleaseConfig
+	if rf, ok := ret.Get(0).(func(string, string) []*modelsk8s.ReleaseConfig); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*modelsk8s.ReleaseConfig)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListSecrets provides a mock function with given fields: namespace, name
 func (_m *Cache) ListSecrets(namespace string, name string) (*modelsk8s.SecretList, error) {
 	ret := _m.Called(namespace, name)
@@ -268,6 +353,29 @@ func (_m *Cache) ListStorageClasses(namespace string, labelSelectorStr string) (
 	return r0, r1
 }
 
+// ListTenantServices provides a mock function with given fields: tenantName
+func (_m *Cache) ListTenantServices(tenantName string) (*tenant.TenantServiceInfoList, error) {
+	ret := _m.Called(tenantName)
+
+	var r0 *tenant.TenantServiceInfoList
+	if rf, ok := ret.Get(0).(func(string) *tenant.TenantServiceInfoList); ok {
+		r0 = rf(tenantName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tenant.TenantServiceInfoList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListTenants provides a mock function with given fields: labelSelectorStr
 func (_m *Cache) ListTenants(labelSelectorStr string) (*tenant.TenantInfoList, error) {
 	ret := _m.Called(labelSelectorStr)
@@ -290,3 +398,26 @@ func (_m *Cache) ListTenants(labelSelectorStr string) (*tenant.TenantInfoList, e
 
 	return r0, r1
 }
+
+// ListTenantsSummary provides a mock function with given fields: labelSelectorStr
+func (_m *Cache) ListTenantsSummary(labelSelectorStr string) (*tenant.TenantInfoList, error) {
+	ret := _m.Called(labelSelectorStr)
+
+	var r0 *tenant.TenantInfoList
+	if rf, ok := ret.Get(0).(func(string) *tenant.TenantInfoList); ok {
+		r0 = rf(labelSelectorStr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tenant.TenantInfoList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(labelSelectorStr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import mock "github.com/stretchr/testify/mock"
+import k8s "WarpCloud/walm/pkg/k8s"
 import modelsk8s "WarpCloud/walm/pkg/models/k8s"
 import release "WarpCloud/walm/pkg/models/release"
 
@@ -99,6 +100,48 @@ func (_m *Operator) CreateNamespace(namespace *modelsk8s.Namespace) error {
 	return r0
 }
 
+// CreateNamespaceEvent provides a mock function with given fields: namespace, reason, eventType, message
+func (_m *Operator) CreateNamespaceEvent(namespace string, reason string, eventType string, message string) error {
+	ret := _m.Called(namespace, reason, eventType, message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(namespace, reason, eventType, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrUpdateLimitRange provides a mock function with given fields: limitRange
+func (_m *Operator) CreateOrUpdateLimitRange(limitRange *modelsk8s.LimitRange) error {
+	ret := _m.Called(limitRange)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*modelsk8s.LimitRange) error); ok {
+		r0 = rf(limitRange)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrUpdateReleaseConfig provides a mock function with given fields: releaseConfig
+func (_m *Operator) CreateOrUpdateReleaseConfig(releaseConfig *modelsk8s.ReleaseConfig) error {
+	ret := _m.Called(releaseConfig)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*modelsk8s.ReleaseConfig) error); ok {
+		r0 = rf(releaseConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateOrUpdateResourceQuota provides a mock function with given fields: resourceQuota
 func (_m *Operator) CreateOrUpdateResourceQuota(resourceQuota *modelsk8s.ResourceQuota) error {
 	ret := _m.Called(resourceQuota)
@@ -113,6 +156,20 @@ func (_m *Operator) CreateOrUpdateResourceQuota(resourceQuota *modelsk8s.Resourc
 	return r0
 }
 
+// CreateOrUpdateRoleBinding provides a mock function with given fields: roleBinding
+func (_m *Operator) CreateOrUpdateRoleBinding(roleBinding *modelsk8s.RoleBinding) error {
+	ret := _m.Called(roleBinding)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*modelsk8s.RoleBinding) error); ok {
+		r0 = rf(roleBinding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateResourceQuota provides a mock function with given fields: resourceQuota
 func (_m *Operator) CreateResourceQuota(resourceQuota *modelsk8s.ResourceQuota) error {
 	ret := _m.Called(resourceQuota)
@@ -141,6 +198,20 @@ func (_m *Operator) CreateSecret(namespace string, secretRequestBody *modelsk8s.
 	return r0
 }
 
+// DeleteLimitRange provides a mock function with given fields: namespace, name
+func (_m *Operator) DeleteLimitRange(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteNamespace provides a mock function with given fields: name
 func (_m *Operator) DeleteNamespace(name string) error {
 	ret := _m.Called(name)
@@ -197,6 +268,20 @@ func (_m *Operator) DeletePvcs(namespace string, labelSeletorStr string) error {
 	return r0
 }
 
+// DeleteRoleBinding provides a mock function with given fields: namespace, name
+func (_m *Operator) DeleteRoleBinding(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSecret provides a mock function with given fields: namespace, name
 func (_m *Operator) DeleteSecret(namespace string, name string) error {
 	ret := _m.Called(namespace, name)
@@ -239,6 +324,25 @@ func (_m *Operator) LabelNode(name string, labelsToAdd map[string]string, labels
 	return r0
 }
 
+// LabelStatefulSetPvcs provides a mock function with given fields: statefulSets, labelsToAdd
+func (_m *Operator) LabelStatefulSetPvcs(statefulSets []*modelsk8s.StatefulSet, labelsToAdd map[string]string) error {
+	ret := _m.Called(statefulSets, labelsToAdd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*modelsk8s.StatefulSet, map[string]string) error); ok {
+		r0 = rf(statefulSets, labelsToAdd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordReleaseConfigEvent provides a mock function with given fields: namespace, name, eventType, reason, message
+func (_m *Operator) RecordReleaseConfigEvent(namespace string, name string, eventType string, reason string, message string) {
+	_m.Called(namespace, name, eventType, reason, message)
+}
+
 // RestartPod provides a mock function with given fields: namespace, name
 func (_m *Operator) RestartPod(namespace string, name string) error {
 	ret := _m.Called(namespace, name)
@@ -253,6 +357,20 @@ func (_m *Operator) RestartPod(namespace string, name string) error {
 	return r0
 }
 
+// RunJobManifest provides a mock function with given fields: namespace, manifest, timeoutSec
+func (_m *Operator) RunJobManifest(namespace string, manifest string, timeoutSec int64) error {
+	ret := _m.Called(namespace, manifest, timeoutSec)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int64) error); ok {
+		r0 = rf(namespace, manifest, timeoutSec)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateNamespace provides a mock function with given fields: namespace
 func (_m *Operator) UpdateNamespace(namespace *modelsk8s.Namespace) error {
 	ret := _m.Called(namespace)
@@ -267,6 +385,20 @@ func (_m *Operator) UpdateNamespace(namespace *modelsk8s.Namespace) error {
 	return r0
 }
 
+// UpdateReleaseConfigStatus provides a mock function with given fields: namespace, name
+func (_m *Operator) UpdateReleaseConfigStatus(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateSecret provides a mock function with given fields: namespace, secretRequestBody
 func (_m *Operator) UpdateSecret(namespace string, secretRequestBody *modelsk8s.CreateSecretRequestBody) error {
 	ret := _m.Called(namespace, secretRequestBody)
@@ -280,3 +412,26 @@ func (_m *Operator) UpdateSecret(namespace string, secretRequestBody *modelsk8s.
 
 	return r0
 }
+
+// WithCaller provides a mock function with given fields: user, groups
+func (_m *Operator) WithCaller(user string, groups []string) (k8s.Operator, error) {
+	ret := _m.Called(user, groups)
+
+	var r0 k8s.Operator
+	if rf, ok := ret.Get(0).(func(string, []string) k8s.Operator); ok {
+		r0 = rf(user, groups)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(k8s.Operator)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string) error); ok {
+		r1 = rf(user, groups)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
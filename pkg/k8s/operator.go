@@ -6,29 +6,51 @@ import (
 )
 
 type Operator interface {
+	// WithCaller returns an operator whose kube operations impersonate the
+	// caller, so the apiserver enforces the caller's rbac. The operator itself
+	// is returned when impersonation is not configured or the caller is unknown.
+	WithCaller(user string, groups []string) (Operator, error)
+
 	DeletePod(namespace string, name string) error
 	// diff between delete and restart: if pod does not exist, restart return err, but delete not
 	RestartPod(namespace string, name string) error
 
 	BuildManifestObjects(namespace string, manifest string) ([]map[string]interface{}, error)
 	ComputeReleaseResourcesByManifest(namespace string, manifest string) (*release.ReleaseResources, error)
+	RunJobManifest(namespace string, manifest string, timeoutSec int64) error
 
 	CreateNamespace(namespace *k8s.Namespace) error
 	UpdateNamespace(namespace *k8s.Namespace) (error)
 	DeleteNamespace(name string) error
+	CreateNamespaceEvent(namespace string, reason string, eventType string, message string) error
 
 	CreateResourceQuota(resourceQuota *k8s.ResourceQuota) error
 	CreateOrUpdateResourceQuota(resourceQuota *k8s.ResourceQuota) error
 
 	CreateLimitRange(limitRange *k8s.LimitRange) error
+	CreateOrUpdateLimitRange(limitRange *k8s.LimitRange) error
+	DeleteLimitRange(namespace string, name string) error
+
+	CreateOrUpdateRoleBinding(roleBinding *k8s.RoleBinding) error
+	DeleteRoleBinding(namespace string, name string) error
 
 	LabelNode(name string, labelsToAdd map[string]string, labelsToRemove []string) (error)
 	AnnotateNode(name string, annotationsToAdd map[string]string, annotationsToRemove []string) (error)
 
 	DeleteStatefulSetPvcs(statefulSets []*k8s.StatefulSet) error
+	// LabelStatefulSetPvcs labels the pvcs the stateful sets own, used to mark
+	// pvcs with their scheduled deletion time under a delayed pvc policy
+	LabelStatefulSetPvcs(statefulSets []*k8s.StatefulSet, labelsToAdd map[string]string) error
 	DeletePvc(namespace string, name string) error
 	DeletePvcs(namespace string, labelSeletorStr string) error
 
+	CreateOrUpdateReleaseConfig(releaseConfig *k8s.ReleaseConfig) error
+	// UpdateReleaseConfigStatus records that the current spec generation has been processed
+	UpdateReleaseConfigStatus(namespace, name string) error
+	// RecordReleaseConfigEvent emits a kubernetes event on the release config of the
+	// release, best effort : emission failures never fail the caller
+	RecordReleaseConfigEvent(namespace, name, eventType, reason, message string)
+
 	CreateSecret(namespace string, secretRequestBody *k8s.CreateSecretRequestBody) error
 	UpdateSecret(namespace string, secretRequestBody *k8s.CreateSecretRequestBody) error
 	DeleteSecret(namespace, name string) (err error)
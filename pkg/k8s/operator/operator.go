@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"WarpCloud/walm/pkg/k8s/utils"
 	"bytes"
+	"crypto/md5"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/api/extensions/v1beta1"
 	"encoding/json"
@@ -20,9 +21,17 @@ import (
 	"WarpCloud/walm/pkg/k8s/converter"
 	"reflect"
 	"fmt"
+	"time"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	errorUtils "WarpCloud/walm/pkg/util/error"
 	"encoding/base64"
+	k8sclient "WarpCloud/walm/pkg/k8s/client"
 	"WarpCloud/walm/pkg/k8s/client/helm"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	releaseconfigclientset "transwarp/release-config/pkg/client/clientset/versioned"
+	releaseconfigscheme "transwarp/release-config/pkg/client/clientset/versioned/scheme"
 )
 
 const (
@@ -30,9 +39,37 @@ const (
 )
 
 type Operator struct {
-	client      *kubernetes.Clientset
-	k8sCache    k8s.Cache
-	kubeClients *helm.Client
+	client              *kubernetes.Clientset
+	k8sCache            k8s.Cache
+	kubeClients         *helm.Client
+	releaseConfigClient releaseconfigclientset.Interface
+	recorder            record.EventRecorder
+	// optional, kube operations impersonate the caller when it is set
+	impersonatingClients *k8sclient.ImpersonatingClientFactory
+}
+
+// SetImpersonatingClientFactory enables impersonating the authenticated caller
+// on kube operations. Without it WithCaller keeps running as walm itself.
+func (op *Operator) SetImpersonatingClientFactory(factory *k8sclient.ImpersonatingClientFactory) {
+	op.impersonatingClients = factory
+}
+
+// WithCaller returns an operator whose kube client impersonates the caller, so
+// the apiserver enforces the caller's rbac in the tenant namespace. The
+// operator itself is returned when impersonation is not configured or the
+// caller is unknown, e.g. when auth is disabled.
+func (op *Operator) WithCaller(user string, groups []string) (k8s.Operator, error) {
+	if op.impersonatingClients == nil || user == "" {
+		return op, nil
+	}
+	impersonatingClient, err := op.impersonatingClients.GetClient(user, groups)
+	if err != nil {
+		logrus.Errorf("failed to get kube client impersonating %s : %s", user, err.Error())
+		return nil, err
+	}
+	callerOp := *op
+	callerOp.client = impersonatingClient
+	return &callerOp, nil
 }
 
 func (op *Operator) DeleteStatefulSetPvcs(statefulSets []*k8sModel.StatefulSet) error {
@@ -46,6 +83,63 @@ func (op *Operator) DeleteStatefulSetPvcs(statefulSets []*k8sModel.StatefulSet)
 	return nil
 }
 
+// LabelStatefulSetPvcs labels every pvc the stateful sets own, used to mark
+// pvcs with their scheduled deletion time when a release is deleted under a
+// delayed pvc policy.
+func (op *Operator) LabelStatefulSetPvcs(statefulSets []*k8sModel.StatefulSet, labelsToAdd map[string]string) error {
+	for _, statefulSet := range statefulSets {
+		pvcs, err := op.k8sCache.ListPersistentVolumeClaims(statefulSet.Namespace, statefulSet.Selector)
+		if err != nil {
+			logrus.Errorf("failed to list pvcs related to stateful set %s/%s : %s", statefulSet.Namespace, statefulSet.Name, err.Error())
+			return errorUtils.TranslateKubeError(err)
+		}
+		for _, pvc := range pvcs {
+			err = op.labelPvc(pvc.Namespace, pvc.Name, labelsToAdd)
+			if err != nil {
+				return errorUtils.TranslateKubeError(err)
+			}
+		}
+	}
+	return nil
+}
+
+func (op *Operator) labelPvc(namespace string, name string, labelsToAdd map[string]string) (err error) {
+	if len(labelsToAdd) == 0 {
+		return
+	}
+
+	pvc, err := op.client.CoreV1().PersistentVolumeClaims(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			logrus.Warnf("pvc %s/%s is not found ", namespace, name)
+			return nil
+		}
+		logrus.Errorf("failed to get pvc %s/%s : %s", namespace, name, err.Error())
+		return
+	}
+
+	oldLabels, err := json.Marshal(pvc.Labels)
+	if err != nil {
+		return
+	}
+
+	pvc.Labels = utils.MergeLabels(pvc.Labels, labelsToAdd, nil)
+	newLabels, err := json.Marshal(pvc.Labels)
+	if err != nil {
+		return
+	}
+
+	if !reflect.DeepEqual(oldLabels, newLabels) {
+		_, err = op.client.CoreV1().PersistentVolumeClaims(namespace).Update(pvc)
+		if err != nil {
+			logrus.Errorf("failed to update pvc %s/%s : %s", namespace, name, err.Error())
+			return
+		}
+	}
+
+	return
+}
+
 func (op *Operator) DeletePod(namespace string, name string) error {
 	err := op.client.CoreV1().Pods(namespace).Delete(name, &metav1.DeleteOptions{})
 	if err != nil {
@@ -54,7 +148,7 @@ func (op *Operator) DeletePod(namespace string, name string) error {
 			return nil
 		}
 		logrus.Errorf("failed to delete pod %s/%s : %s", namespace, name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -63,7 +157,7 @@ func (op *Operator) RestartPod(namespace string, name string) error {
 	err := op.client.CoreV1().Pods(namespace).Delete(name, &metav1.DeleteOptions{})
 	if err != nil {
 		logrus.Errorf("failed to restart pod %s/%s : %s", namespace, name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -82,6 +176,37 @@ func (op *Operator) BuildManifestObjects(namespace string, manifest string) ([]m
 	return results, nil
 }
 
+// RunJobManifest creates the job defined in the manifest and waits for its pod to
+// complete, returning an error unless the pod phase is succeeded.
+func (op *Operator) RunJobManifest(namespace string, manifest string, timeoutSec int64) error {
+	kubeClient := op.kubeClients.GetKubeClient(namespace)
+	err := kubeClient.Create(namespace, bytes.NewBufferString(manifest), timeoutSec, false)
+	if err != nil {
+		logrus.Errorf("failed to create job : %s", err.Error())
+		return err
+	}
+	podLogs := &bytes.Buffer{}
+	podPhase, err := kubeClient.WaitAndStreamCompletedPodPhase(namespace, bytes.NewBufferString(manifest), time.Duration(timeoutSec)*time.Second, podLogs)
+	if err != nil {
+		logrus.Errorf("failed to wait for job pod completed : %s", err.Error())
+		return err
+	}
+	if podPhase != v1.PodSucceeded {
+		return fmt.Errorf("job pod completed with phase %s, logs:\n%s", podPhase, tailString(podLogs.String(), maxJobLogTailSize))
+	}
+	return nil
+}
+
+// maxJobLogTailSize bounds how much of a failed job pod's output ends up in the error.
+const maxJobLogTailSize = 2048
+
+func tailString(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+	return s[len(s)-size:]
+}
+
 func (op *Operator) ComputeReleaseResourcesByManifest(namespace string, manifest string) (*release.ReleaseResources, error) {
 	resources, err := op.kubeClients.GetKubeClient(namespace).BuildUnstructured(namespace, bytes.NewBufferString(manifest))
 	if err != nil {
@@ -366,7 +491,7 @@ func (op *Operator) CreateNamespace(namespace *k8sModel.Namespace) error {
 	_, err = op.client.CoreV1().Namespaces().Create(k8sNamespace)
 	if err != nil {
 		logrus.Errorf("failed to create namespace %s : %s", k8sNamespace.Name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -380,7 +505,7 @@ func (op *Operator) UpdateNamespace(namespace *k8sModel.Namespace) (error) {
 	_, err = op.client.CoreV1().Namespaces().Update(k8sNamespace)
 	if err != nil {
 		logrus.Errorf("failed to update namespace %s : %s", k8sNamespace.Name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -393,7 +518,37 @@ func (op *Operator) DeleteNamespace(name string) error {
 			return nil
 		}
 		logrus.Errorf("failed to delete namespace %s : %s", name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
+	}
+	return nil
+}
+
+func (op *Operator) CreateNamespaceEvent(namespace string, reason string, eventType string, message string) error {
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    namespace,
+			GenerateName: "walm-",
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Namespace",
+			Namespace: namespace,
+			Name:      namespace,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Source: v1.EventSource{
+			Component: "walm",
+		},
+	}
+	_, err := op.client.CoreV1().Events(namespace).Create(event)
+	if err != nil {
+		logrus.Errorf("failed to create event on namespace %s : %s", namespace, err.Error())
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -407,7 +562,7 @@ func (op *Operator) CreateResourceQuota(resourceQuota *k8sModel.ResourceQuota) e
 	_, err = op.client.CoreV1().ResourceQuotas(k8sQuota.Namespace).Create(k8sQuota)
 	if err != nil {
 		logrus.Errorf("failed to create resource quota %s/%s : %s", k8sQuota.Namespace, k8sQuota.Name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -420,43 +575,219 @@ func (op *Operator) CreateOrUpdateResourceQuota(resourceQuota *k8sModel.Resource
 			update = false
 		} else {
 			logrus.Errorf("failed to get resource quota %s/%s : %s", resourceQuota.Namespace, resourceQuota.Name, err.Error())
-			return err
+			return errorUtils.TranslateKubeError(err)
 		}
 	}
 
 	k8sQuota, err := converter.ConvertResourceQuotaToK8s(resourceQuota)
 	if err != nil {
 		logrus.Errorf("failed to convert resource quota : %s", err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 
 	if update {
 		_, err = op.client.CoreV1().ResourceQuotas(k8sQuota.Namespace).Update(k8sQuota)
 		if err != nil {
 			logrus.Errorf("failed to update resource quota %s/%s : %s", k8sQuota.Namespace, k8sQuota.Name, err.Error())
-			return err
+			return errorUtils.TranslateKubeError(err)
 		}
 	} else {
 		_, err = op.client.CoreV1().ResourceQuotas(k8sQuota.Namespace).Create(k8sQuota)
 		if err != nil {
 			logrus.Errorf("failed to create resource quota %s/%s : %s", k8sQuota.Namespace, k8sQuota.Name, err.Error())
-			return err
+			return errorUtils.TranslateKubeError(err)
+		}
+	}
+	return nil
+}
+
+func (op *Operator) CreateOrUpdateReleaseConfig(releaseConfig *k8sModel.ReleaseConfig) error {
+	k8sReleaseConfig, err := converter.ConvertReleaseConfigToK8s(releaseConfig)
+	if err != nil {
+		logrus.Errorf("failed to convert release config : %s", err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+
+	existedReleaseConfig, err := op.releaseConfigClient.TranswarpV1beta1().ReleaseConfigs(releaseConfig.Namespace).Get(releaseConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			_, err = op.releaseConfigClient.TranswarpV1beta1().ReleaseConfigs(releaseConfig.Namespace).Create(k8sReleaseConfig)
+			if err != nil {
+				logrus.Errorf("failed to create release config %s/%s : %s", releaseConfig.Namespace, releaseConfig.Name, err.Error())
+				return errorUtils.TranslateKubeError(err)
+			}
+			return nil
 		}
+		logrus.Errorf("failed to get release config %s/%s : %s", releaseConfig.Namespace, releaseConfig.Name, err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+
+	existedReleaseConfig = existedReleaseConfig.DeepCopy()
+	existedReleaseConfig.Labels = k8sReleaseConfig.Labels
+	existedReleaseConfig.Spec = k8sReleaseConfig.Spec
+	_, err = op.releaseConfigClient.TranswarpV1beta1().ReleaseConfigs(releaseConfig.Namespace).Update(existedReleaseConfig)
+	if err != nil {
+		logrus.Errorf("failed to update release config %s/%s : %s", releaseConfig.Namespace, releaseConfig.Name, err.Error())
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
 
+// UpdateReleaseConfigStatus records on the release config status that the current
+// spec generation has been processed, so consumers can tell whether a change has
+// been applied yet. It relies on the status subresource of the crd, so the write
+// does not bump the spec generation.
+func (op *Operator) UpdateReleaseConfigStatus(namespace, name string) error {
+	releaseConfig, err := op.releaseConfigClient.TranswarpV1beta1().ReleaseConfigs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		logrus.Errorf("failed to get release config %s/%s : %s", namespace, name, err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+
+	digest, err := buildDependenciesConfigValuesDigest(releaseConfig.Spec.DependenciesConfigValues)
+	if err != nil {
+		logrus.Errorf("failed to build dependencies config values digest of %s/%s : %s", namespace, name, err.Error())
+		return err
+	}
+
+	releaseConfig = releaseConfig.DeepCopy()
+	releaseConfig.Status.ObservedGeneration = releaseConfig.Generation
+	releaseConfig.Status.LastReconcileTime = metav1.Now()
+	releaseConfig.Status.DependenciesDigest = digest
+	_, err = op.releaseConfigClient.TranswarpV1beta1().ReleaseConfigs(namespace).UpdateStatus(releaseConfig)
+	if err != nil {
+		logrus.Errorf("failed to update release config status %s/%s : %s", namespace, name, err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+	return nil
+}
+
+func buildDependenciesConfigValuesDigest(dependenciesConfigValues map[string]interface{}) (string, error) {
+	digestBytes, err := json.Marshal(dependenciesConfigValues)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", md5.Sum(digestBytes)), nil
+}
+
 func (op *Operator) CreateLimitRange(limitRange *k8sModel.LimitRange) error {
 	k8sLimitRange, err := converter.ConvertLimitRangeToK8s(limitRange)
 	if err != nil {
 		logrus.Errorf("failed to convert limit range : %s", err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 
 	_, err = op.client.CoreV1().LimitRanges(k8sLimitRange.Namespace).Create(k8sLimitRange)
 	if err != nil {
 		logrus.Errorf("failed to create limit range %s/%s : %s", k8sLimitRange.Namespace, k8sLimitRange.Name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
+	}
+	return nil
+}
+
+func (op *Operator) CreateOrUpdateLimitRange(limitRange *k8sModel.LimitRange) error {
+	update := true
+	_, err := op.client.CoreV1().LimitRanges(limitRange.Namespace).Get(limitRange.Name, metav1.GetOptions{})
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			update = false
+		} else {
+			logrus.Errorf("failed to get limit range %s/%s : %s", limitRange.Namespace, limitRange.Name, err.Error())
+			return errorUtils.TranslateKubeError(err)
+		}
+	}
+
+	k8sLimitRange, err := converter.ConvertLimitRangeToK8s(limitRange)
+	if err != nil {
+		logrus.Errorf("failed to convert limit range : %s", err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+
+	if update {
+		_, err = op.client.CoreV1().LimitRanges(k8sLimitRange.Namespace).Update(k8sLimitRange)
+		if err != nil {
+			logrus.Errorf("failed to update limit range %s/%s : %s", k8sLimitRange.Namespace, k8sLimitRange.Name, err.Error())
+			return errorUtils.TranslateKubeError(err)
+		}
+	} else {
+		_, err = op.client.CoreV1().LimitRanges(k8sLimitRange.Namespace).Create(k8sLimitRange)
+		if err != nil {
+			logrus.Errorf("failed to create limit range %s/%s : %s", k8sLimitRange.Namespace, k8sLimitRange.Name, err.Error())
+			return errorUtils.TranslateKubeError(err)
+		}
+	}
+	return nil
+}
+
+func (op *Operator) DeleteLimitRange(namespace string, name string) error {
+	err := op.client.CoreV1().LimitRanges(namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			logrus.Warnf("limit range %s/%s is not found ", namespace, name)
+			return nil
+		}
+		logrus.Errorf("failed to delete limit range %s/%s : %s", namespace, name, err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+	return nil
+}
+
+func (op *Operator) CreateOrUpdateRoleBinding(roleBinding *k8sModel.RoleBinding) error {
+	k8sRoleBinding, err := converter.ConvertRoleBindingToK8s(roleBinding)
+	if err != nil {
+		logrus.Errorf("failed to convert role binding : %s", err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+
+	existedRoleBinding, err := op.client.RbacV1().RoleBindings(k8sRoleBinding.Namespace).Get(k8sRoleBinding.Name, metav1.GetOptions{})
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			_, err = op.client.RbacV1().RoleBindings(k8sRoleBinding.Namespace).Create(k8sRoleBinding)
+			if err != nil {
+				logrus.Errorf("failed to create role binding %s/%s : %s", k8sRoleBinding.Namespace, k8sRoleBinding.Name, err.Error())
+				return errorUtils.TranslateKubeError(err)
+			}
+			return nil
+		}
+		logrus.Errorf("failed to get role binding %s/%s : %s", k8sRoleBinding.Namespace, k8sRoleBinding.Name, err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+
+	// role ref is immutable : the binding has to be recreated when the role changes
+	if existedRoleBinding.RoleRef.Name != k8sRoleBinding.RoleRef.Name {
+		err = op.client.RbacV1().RoleBindings(k8sRoleBinding.Namespace).Delete(k8sRoleBinding.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			logrus.Errorf("failed to delete role binding %s/%s : %s", k8sRoleBinding.Namespace, k8sRoleBinding.Name, err.Error())
+			return errorUtils.TranslateKubeError(err)
+		}
+		_, err = op.client.RbacV1().RoleBindings(k8sRoleBinding.Namespace).Create(k8sRoleBinding)
+		if err != nil {
+			logrus.Errorf("failed to create role binding %s/%s : %s", k8sRoleBinding.Namespace, k8sRoleBinding.Name, err.Error())
+			return errorUtils.TranslateKubeError(err)
+		}
+		return nil
+	}
+
+	existedRoleBinding = existedRoleBinding.DeepCopy()
+	existedRoleBinding.Labels = k8sRoleBinding.Labels
+	existedRoleBinding.Subjects = k8sRoleBinding.Subjects
+	_, err = op.client.RbacV1().RoleBindings(k8sRoleBinding.Namespace).Update(existedRoleBinding)
+	if err != nil {
+		logrus.Errorf("failed to update role binding %s/%s : %s", k8sRoleBinding.Namespace, k8sRoleBinding.Name, err.Error())
+		return errorUtils.TranslateKubeError(err)
+	}
+	return nil
+}
+
+func (op *Operator) DeleteRoleBinding(namespace string, name string) error {
+	err := op.client.RbacV1().RoleBindings(namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil {
+		if utils.IsK8sResourceNotFoundErr(err) {
+			logrus.Warnf("role binding %s/%s is not found ", namespace, name)
+			return nil
+		}
+		logrus.Errorf("failed to delete role binding %s/%s : %s", namespace, name, err.Error())
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -533,7 +864,7 @@ func (op *Operator) DeletePvc(namespace string, name string) error {
 			return nil
 		}
 		logrus.Errorf("failed to get pvc %s/%s : %s", namespace, name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 
 	return op.doDeletePvc(resource.(*k8sModel.PersistentVolumeClaim))
@@ -548,13 +879,13 @@ func (op *Operator) doDeletePvc(pvc *k8sModel.PersistentVolumeClaim) error {
 		selectorStr, err := utils.ConvertLabelSelectorToStr(selector)
 		if err != nil {
 			logrus.Errorf("failed to convert label selector: %s", err.Error())
-			return err
+			return errorUtils.TranslateKubeError(err)
 		}
 
 		statefulSets, err := op.k8sCache.ListStatefulSets(pvc.Namespace, selectorStr)
 		if err != nil {
 			logrus.Errorf("failed to list stateful set : %s", err.Error())
-			return err
+			return errorUtils.TranslateKubeError(err)
 		}
 		if len(statefulSets) > 0 {
 			statefulSetNames := make([]string, len(statefulSets))
@@ -562,7 +893,7 @@ func (op *Operator) doDeletePvc(pvc *k8sModel.PersistentVolumeClaim) error {
 				statefulSetNames = append(statefulSetNames, statefulSet.Namespace+"/"+statefulSet.Name)
 			}
 			err = fmt.Errorf("pvc %s/%s can not be deleted, it is still used by statefulsets %v", pvc.Namespace, pvc.Name, statefulSetNames)
-			return err
+			return errorUtils.TranslateKubeError(err)
 		}
 	}
 	err := op.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Delete(pvc.Name, &metav1.DeleteOptions{})
@@ -572,7 +903,7 @@ func (op *Operator) doDeletePvc(pvc *k8sModel.PersistentVolumeClaim) error {
 			return nil
 		}
 		logrus.Errorf("failed to delete pvc %s/%s : %s", pvc.Namespace, pvc.Name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	logrus.Infof("succeed to delete pvc %s/%s", pvc.Namespace, pvc.Name)
 	return nil
@@ -582,12 +913,12 @@ func (op *Operator) DeletePvcs(namespace string, labelSeletorStr string) error {
 	pvcs, err := op.k8sCache.ListPersistentVolumeClaims(namespace, labelSeletorStr)
 	if err != nil {
 		logrus.Errorf("failed to list pvcs : %s", err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	for _, pvc := range pvcs {
 		err := op.doDeletePvc(pvc)
 		if err != nil {
-			return err
+			return errorUtils.TranslateKubeError(err)
 		}
 	}
 	return nil
@@ -596,12 +927,12 @@ func (op *Operator) DeletePvcs(namespace string, labelSeletorStr string) error {
 func (op *Operator) CreateSecret(namespace string, secretRequestBody *k8sModel.CreateSecretRequestBody) error {
 	secret, err := buildSecret(namespace, secretRequestBody)
 	if err != nil {
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	_, err = op.client.CoreV1().Secrets(namespace).Create(secret)
 	if err != nil {
 		logrus.Errorf("failed to create secret %s/%s : %s", namespace, secretRequestBody.Name, err.Error())
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	return nil
 }
@@ -609,7 +940,7 @@ func (op *Operator) CreateSecret(namespace string, secretRequestBody *k8sModel.C
 func (op *Operator) UpdateSecret(namespace string, walmSecret *k8sModel.CreateSecretRequestBody) (err error) {
 	newSecret, err := buildSecret(namespace, walmSecret)
 	if err != nil {
-		return err
+		return errorUtils.TranslateKubeError(err)
 	}
 	_, err = op.client.CoreV1().Secrets(namespace).Update(newSecret)
 	if err != nil {
@@ -655,10 +986,35 @@ func buildSecret(namespace string, walmSecret *k8sModel.CreateSecretRequestBody)
 	return
 }
 
-func NewOperator(client *kubernetes.Clientset, k8sCache k8s.Cache, kubeClients *helm.Client) *Operator {
+func NewOperator(client *kubernetes.Clientset, k8sCache k8s.Cache, kubeClients *helm.Client, releaseConfigClient releaseconfigclientset.Interface) *Operator {
+	// the recorder needs the release config kind registered to build event references
+	releaseconfigscheme.AddToScheme(scheme.Scheme)
+	eventBroadcaster := record.NewBroadcaster()
+	if client != nil {
+		// the broadcaster's correlator aggregates and rate limits event bursts
+		// before they reach the apiserver
+		eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	}
 	return &Operator{
-		client:      client,
-		k8sCache:    k8sCache,
-		kubeClients: kubeClients,
+		client:              client,
+		k8sCache:            k8sCache,
+		kubeClients:         kubeClients,
+		releaseConfigClient: releaseConfigClient,
+		recorder:            eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "walm"}),
+	}
+}
+
+// RecordReleaseConfigEvent emits a kubernetes event on the release config of the release,
+// so lifecycle milestones show up in kubectl describe releaseconfig. Emission is best
+// effort by design : the release config may not exist (pure helm charts do not render
+// one) and failures must never fail the operation the event describes.
+func (op *Operator) RecordReleaseConfigEvent(namespace, name, eventType, reason, message string) {
+	releaseConfig, err := op.releaseConfigClient.TranswarpV1beta1().ReleaseConfigs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !utils.IsK8sResourceNotFoundErr(err) {
+			logrus.Warnf("failed to get release config %s/%s to record event : %s", namespace, name, err.Error())
+		}
+		return
 	}
+	op.recorder.Event(releaseConfig, eventType, reason, message)
 }
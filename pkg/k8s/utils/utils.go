@@ -164,6 +164,10 @@ func ParseK8sResourceStorage(strValue string) (int64) {
 }
 
 func ParseK8sResourcePod(strValue string) (int64) {
+	return ParseK8sResourceCount(strValue)
+}
+
+func ParseK8sResourceCount(strValue string) (int64) {
 	if strValue == "" {
 		return 0
 	}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	walmLog "WarpCloud/walm/pkg/log"
+	"WarpCloud/walm/pkg/models/http"
+	logModel "WarpCloud/walm/pkg/models/log"
+	httpUtils "WarpCloud/walm/pkg/util/http"
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful-openapi"
+)
+
+type LogHandler struct {
+}
+
+func RegisterLogHandler() *restful.WebService {
+	handler := LogHandler{}
+
+	ws := new(restful.WebService)
+
+	ws.Path(http.ApiV1+"/log").
+		Doc("日志级别相关操作").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML)
+
+	tags := []string{"log"}
+
+	ws.Route(ws.GET("/level").To(handler.GetLogLevels).
+		Doc("获取默认日志级别和各模块日志级别").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Writes(logModel.LogLevels{}).
+		Returns(200, "OK", logModel.LogLevels{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.PUT("/level/{module}").To(handler.SetModuleLogLevel).
+		Doc("运行时修改某个模块的日志级别").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("module", "模块名字").DataType("string")).
+		Param(ws.QueryParameter("level", "日志级别(panic/fatal/error/warning/info/debug/trace)").DataType("string").Required(true)).
+		Returns(200, "OK", nil).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	return ws
+}
+
+func (handler *LogHandler) GetLogLevels(request *restful.Request, response *restful.Response) {
+	defaultLevel, moduleLevels := walmLog.Levels()
+	response.WriteEntity(&logModel.LogLevels{
+		Default: defaultLevel,
+		Modules: moduleLevels,
+	})
+}
+
+func (handler *LogHandler) SetModuleLogLevel(request *restful.Request, response *restful.Response) {
+	module := request.PathParameter("module")
+	level := request.QueryParameter("level")
+	err := walmLog.SetLevel(module, level)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to set log level of module %s : %s", module, err.Error()))
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	mutex        sync.RWMutex
+	defaultLevel = logrus.InfoLevel
+	loggers      = map[string]*logrus.Logger{}
+)
+
+// GetLogger returns the leveled logger of a module, created on first use. The
+// module name lands on every line as a logrus field and its level can be
+// changed at runtime, so a single module can be debugged in production
+// without flooding the logs with everything else.
+func GetLogger(module string) *logrus.Entry {
+	return moduleLogger(module).WithField("module", module)
+}
+
+func moduleLogger(module string) *logrus.Logger {
+	mutex.Lock()
+	defer mutex.Unlock()
+	logger, ok := loggers[module]
+	if !ok {
+		logger = logrus.New()
+		logger.SetOutput(logrus.StandardLogger().Out)
+		logger.SetFormatter(logrus.StandardLogger().Formatter)
+		logger.SetLevel(defaultLevel)
+		loggers[module] = logger
+	}
+	return logger
+}
+
+// SetLevel overrides the level of one module at runtime.
+func SetLevel(module, level string) error {
+	parsedLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("log level %s is not valid : %s", level, err.Error())
+	}
+	moduleLogger(module).SetLevel(parsedLevel)
+	return nil
+}
+
+// SetDefaultLevel sets the level modules start with and the global logrus
+// level. Existing module overrides are kept.
+func SetDefaultLevel(level string) error {
+	parsedLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("log level %s is not valid : %s", level, err.Error())
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	defaultLevel = parsedLevel
+	logrus.SetLevel(parsedLevel)
+	return nil
+}
+
+// Levels returns the default level and the current level of every module.
+func Levels() (string, map[string]string) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	moduleLevels := map[string]string{}
+	for module, logger := range loggers {
+		moduleLevels[module] = logger.Level.String()
+	}
+	return defaultLevel.String(), moduleLevels
+}
@@ -0,0 +1,24 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetLevel(t *testing.T) {
+	logger := GetLogger("test-module")
+	assert.Equal(t, "test-module", logger.Data["module"])
+	assert.Equal(t, logrus.InfoLevel, logger.Logger.Level)
+
+	err := SetLevel("test-module", "debug")
+	assert.IsType(t, nil, err)
+	assert.Equal(t, logrus.DebugLevel, GetLogger("test-module").Logger.Level)
+
+	err = SetLevel("test-module", "not-a-level")
+	assert.Error(t, err)
+
+	defaultLevel, moduleLevels := Levels()
+	assert.Equal(t, "info", defaultLevel)
+	assert.Equal(t, "debug", moduleLevels["test-module"])
+}
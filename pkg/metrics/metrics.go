@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	eventkafka "WarpCloud/walm/pkg/event/kafka"
+	eventwebhook "WarpCloud/walm/pkg/event/webhook"
+	"WarpCloud/walm/pkg/ratelimit"
+	"WarpCloud/walm/pkg/redis/impl"
+)
+
+var (
+	releasesTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "walm_releases",
+		Help: "Number of releases walm manages, by namespace.",
+	}, []string{"namespace"})
+
+	projectsTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "walm_projects",
+		Help: "Number of projects walm manages, by namespace.",
+	}, []string{"namespace"})
+
+	projectsReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "walm_projects_ready",
+		Help: "Number of ready projects, by namespace. Divide by walm_projects for the readiness ratio.",
+	}, []string{"namespace"})
+
+	releaseOpsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "walm_release_operations_total",
+		Help: "Total number of finished release tasks, by operation and result.",
+	}, []string{"operation", "result"})
+
+	releaseOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "walm_release_operation_duration_seconds",
+		Help: "Durations of finished release tasks in seconds, by operation.",
+	}, []string{"operation"})
+
+	redisOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "walm_redis_operation_duration_seconds",
+		Help: "Latencies of redis operations in seconds, by operation.",
+	}, []string{"operation"})
+
+	throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "walm_throttled_requests_total",
+		Help: "Total number of requests rejected by the rate limiter, by verb.",
+	}, []string{"verb"})
+
+	isLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "walm_is_leader",
+		Help: "Whether this replica is the elected leader running the singleton background loops.",
+	})
+
+	eventsDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "walm_lifecycle_events_dropped_total",
+		Help: "Total number of lifecycle events dropped because the buffer was full or the kafka send failed, by topic.",
+	}, []string{"topic"})
+
+	webhookEventsDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "walm_webhook_events_dropped_total",
+		Help: "Total number of lifecycle events not delivered to a webhook because the buffer was full or the retries were exhausted, by endpoint.",
+	}, []string{"endpoint"})
+)
+
+// The walm metrics share the default registry, so the kube client and task
+// subsystem metrics land in the same /metrics endpoint.
+func init() {
+	prometheus.MustRegister(
+		releasesTotal,
+		projectsTotal,
+		projectsReady,
+		releaseOpsTotal,
+		releaseOpDuration,
+		redisOpDuration,
+		throttledTotal,
+		isLeader,
+		eventsDroppedTotal,
+		webhookEventsDroppedTotal,
+	)
+	impl.SetRedisOpObserver(func(operation string, duration time.Duration) {
+		redisOpDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	})
+	eventkafka.SetDropObserver(func(topic string) {
+		eventsDroppedTotal.WithLabelValues(topic).Inc()
+	})
+	eventwebhook.SetDropObserver(func(url string) {
+		webhookEventsDroppedTotal.WithLabelValues(url).Inc()
+	})
+	ratelimit.SetThrottleObserver(func(verb string) {
+		throttledTotal.WithLabelValues(verb).Inc()
+	})
+}
+
+// SetIsLeader records whether this replica currently leads, so alerts can
+// verify exactly one replica runs the singleton loops.
+func SetIsLeader(leading bool) {
+	if leading {
+		isLeader.Set(1)
+	} else {
+		isLeader.Set(0)
+	}
+}
+
+// ObserveReleaseOperation records the duration and outcome of a finished
+// release task.
+func ObserveReleaseOperation(operation string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	releaseOpsTotal.WithLabelValues(operation, result).Inc()
+	releaseOpDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
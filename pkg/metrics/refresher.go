@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"WarpCloud/walm/pkg/event"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	projectModel "WarpCloud/walm/pkg/models/project"
+	"WarpCloud/walm/pkg/project"
+	"WarpCloud/walm/pkg/release"
+)
+
+const defaultStateRefreshInterval = time.Second * 30
+
+// StateRefresher periodically feeds the release and project gauges from the
+// caches, so scraping /metrics stays cheap.
+type StateRefresher struct {
+	releaseCache   release.Cache
+	projectUseCase project.UseCase
+	interval       time.Duration
+
+	eventPublisher   event.Publisher
+	releaseUseCase   release.UseCase
+	lastReleaseReady map[string]bool
+	lastProjectReady map[string]bool
+}
+
+func NewStateRefresher(releaseCache release.Cache, projectUseCase project.UseCase, interval time.Duration) *StateRefresher {
+	if interval <= 0 {
+		interval = defaultStateRefreshInterval
+	}
+	return &StateRefresher{
+		releaseCache:   releaseCache,
+		projectUseCase: projectUseCase,
+		interval:       interval,
+	}
+}
+
+// SetEventPublisher enables publishing a lifecycle event whenever the readiness
+// of a release or project flips between two refreshes. The release use case is
+// needed to compute release readiness, which the raw caches do not carry.
+func (refresher *StateRefresher) SetEventPublisher(publisher event.Publisher, releaseUseCase release.UseCase) {
+	refresher.eventPublisher = publisher
+	refresher.releaseUseCase = releaseUseCase
+}
+
+func (refresher *StateRefresher) Start(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(refresher.interval)
+	defer ticker.Stop()
+	refresher.refresh()
+	for {
+		select {
+		case <-ticker.C:
+			refresher.refresh()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (refresher *StateRefresher) refresh() {
+	releaseCaches, err := refresher.releaseCache.GetReleaseCaches("")
+	if err != nil {
+		logrus.Warnf("failed to get release caches : %s", err.Error())
+	} else {
+		releaseCounts := map[string]float64{}
+		for _, releaseCache := range releaseCaches {
+			releaseCounts[releaseCache.Namespace]++
+		}
+		releasesTotal.Reset()
+		for namespace, count := range releaseCounts {
+			releasesTotal.WithLabelValues(namespace).Set(count)
+		}
+	}
+
+	// the ready metric reflects the readiness of the release resources, so the
+	// detailed list is needed here
+	projects, err := refresher.projectUseCase.ListProjects("", true)
+	if err != nil {
+		logrus.Warnf("failed to list projects : %s", err.Error())
+		return
+	}
+	projectCounts := map[string]float64{}
+	readyCounts := map[string]float64{}
+	for _, projectInfo := range projects.Items {
+		projectCounts[projectInfo.Namespace]++
+		if projectInfo.Ready {
+			readyCounts[projectInfo.Namespace]++
+		}
+	}
+	projectsTotal.Reset()
+	projectsReady.Reset()
+	for namespace, count := range projectCounts {
+		projectsTotal.WithLabelValues(namespace).Set(count)
+		projectsReady.WithLabelValues(namespace).Set(readyCounts[namespace])
+	}
+
+	if refresher.eventPublisher != nil {
+		refresher.publishProjectReadinessFlips(projects.Items)
+		refresher.publishReleaseReadinessFlips()
+	}
+}
+
+// publishProjectReadinessFlips compares the project readiness against the last
+// refresh and publishes a ReadinessChanged event for every flip. The first
+// refresh only seeds the state, so restarting walm does not replay an event
+// for every project.
+func (refresher *StateRefresher) publishProjectReadinessFlips(projectInfos []*projectModel.ProjectInfo) {
+	seeded := refresher.lastProjectReady != nil
+	currentReady := map[string]bool{}
+	for _, projectInfo := range projectInfos {
+		key := projectInfo.Namespace + "/" + projectInfo.Name
+		currentReady[key] = projectInfo.Ready
+		if seeded {
+			lastReady, found := refresher.lastProjectReady[key]
+			if found && lastReady != projectInfo.Ready {
+				refresher.eventPublisher.Publish(&eventModel.LifecycleEvent{
+					Kind:      eventModel.ProjectEventKind,
+					Type:      eventModel.ReadinessChangedEventType,
+					Namespace: projectInfo.Namespace,
+					Name:      projectInfo.Name,
+					Project:   projectInfo.Name,
+					Ready:     projectInfo.Ready,
+					Succeeded: true,
+					Timestamp: time.Now(),
+				})
+			}
+		}
+	}
+	refresher.lastProjectReady = currentReady
+}
+
+// publishReleaseReadinessFlips does the same for releases.
+func (refresher *StateRefresher) publishReleaseReadinessFlips() {
+	releaseInfos, err := refresher.releaseUseCase.ListReleases("")
+	if err != nil {
+		logrus.Warnf("failed to list releases : %s", err.Error())
+		return
+	}
+	seeded := refresher.lastReleaseReady != nil
+	currentReady := map[string]bool{}
+	for _, releaseInfo := range releaseInfos {
+		key := releaseInfo.Namespace + "/" + releaseInfo.Name
+		currentReady[key] = releaseInfo.Ready
+		if seeded {
+			lastReady, found := refresher.lastReleaseReady[key]
+			if found && lastReady != releaseInfo.Ready {
+				refresher.eventPublisher.Publish(&eventModel.LifecycleEvent{
+					Kind:         eventModel.ReleaseEventKind,
+					Type:         eventModel.ReadinessChangedEventType,
+					Namespace:    releaseInfo.Namespace,
+					Name:         releaseInfo.Name,
+					ChartName:    releaseInfo.ChartName,
+					ChartVersion: releaseInfo.ChartVersion,
+					Ready:        releaseInfo.Ready,
+					Succeeded:    true,
+					Timestamp:    time.Now(),
+				})
+			}
+		}
+	}
+	refresher.lastReleaseReady = currentReady
+}
@@ -0,0 +1,17 @@
+package audit
+
+import "time"
+
+// Record is one append-only audit entry recording a mutating operation.
+type Record struct {
+	Time      time.Time              `json:"time" description:"when the operation happened"`
+	RequestId string                 `json:"requestId,omitempty" description:"id of the request that triggered the operation"`
+	Principal string                 `json:"principal,omitempty" description:"auth principal or client ip"`
+	Operation string                 `json:"operation" description:"mutating operation name"`
+	Namespace string                 `json:"namespace" description:"target namespace"`
+	Name      string                 `json:"name" description:"target project or release name"`
+	Args      map[string]interface{} `json:"args,omitempty" description:"sanitized operation args"`
+	TaskUUID  string                 `json:"taskUuid,omitempty" description:"uuid of the submitted task"`
+	Succeeded bool                   `json:"succeeded" description:"whether the operation succeeded"`
+	Error     string                 `json:"error,omitempty" description:"error message when the operation failed"`
+}
@@ -0,0 +1,36 @@
+package cluster
+
+import (
+	"time"
+)
+
+// DefaultClusterName names the cluster walm itself runs in. It is implicit :
+// operations without a cluster parameter keep today's in-cluster behavior.
+const DefaultClusterName = "default"
+
+type Cluster struct {
+	Name        string `json:"name" description:"cluster name"`
+	Description string `json:"description" description:"human readable description of the cluster"`
+	// the kubeconfig is encrypted at rest and masked in api responses
+	KubeConfig string    `json:"kubeConfig" description:"kubeconfig used to reach the cluster"`
+	CreateTime time.Time `json:"createTime" description:"time the cluster was registered"`
+}
+
+type ClusterList struct {
+	Num   int        `json:"num" description:"cluster number"`
+	Items []*Cluster `json:"items" description:"cluster list"`
+}
+
+// MaskSensitiveValues replaces the kubeconfig with a masked value, so api
+// responses never leak cluster credentials.
+func (cluster *Cluster) MaskSensitiveValues() {
+	if cluster.KubeConfig != "" {
+		cluster.KubeConfig = "******"
+	}
+}
+
+// IsDefaultCluster reports whether the name selects the implicit default
+// cluster, the empty name included.
+func IsDefaultCluster(name string) bool {
+	return name == "" || name == DefaultClusterName
+}
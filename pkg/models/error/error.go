@@ -1,12 +1,171 @@
 package error
 
-type NotFoundError struct {}
+// error codes carried in the http error body, so api clients can react to an
+// error class without parsing the message
+const (
+	InternalErrorCode          = -1
+	ValidationErrorCode        = -2
+	NotFoundErrorCode          = -3
+	ConflictErrorCode          = -4
+	TimeoutErrorCode           = -5
+	DependencyMissingErrorCode = -6
+	ChartNotFoundErrorCode     = -7
+)
+
+type NotFoundError struct {
+	Message string
+	cause   error
+}
+
+// WrapNotFound returns a NotFoundError keeping err reachable via errors.Unwrap.
+func WrapNotFound(cause error, message string) NotFoundError {
+	return NotFoundError{Message: message, cause: cause}
+}
 
 func (err NotFoundError) Error() string {
+	if err.Message != "" {
+		return err.Message
+	}
 	return "not found error"
 }
 
+func (err NotFoundError) Unwrap() error {
+	return err.cause
+}
+
 func IsNotFoundError(err error) bool {
 	_, ok := err.(NotFoundError)
 	return ok
-}
\ No newline at end of file
+}
+
+type ValidationError struct {
+	Message string
+	cause   error
+}
+
+// WrapValidation returns a ValidationError keeping err reachable via errors.Unwrap.
+func WrapValidation(cause error, message string) ValidationError {
+	return ValidationError{Message: message, cause: cause}
+}
+
+func (err ValidationError) Error() string {
+	return err.Message
+}
+
+func (err ValidationError) Unwrap() error {
+	return err.cause
+}
+
+func IsValidationError(err error) bool {
+	_, ok := err.(ValidationError)
+	return ok
+}
+
+// ConflictError means the resource is busy with another mutation and the
+// caller should retry later.
+type ConflictError struct {
+	Message string
+	cause   error
+}
+
+func NewConflictError(message string) ConflictError {
+	return ConflictError{Message: message}
+}
+
+// WrapConflict returns a ConflictError keeping err reachable via errors.Unwrap.
+func WrapConflict(cause error, message string) ConflictError {
+	return ConflictError{Message: message, cause: cause}
+}
+
+func (err ConflictError) Error() string {
+	return err.Message
+}
+
+func (err ConflictError) Unwrap() error {
+	return err.cause
+}
+
+func IsConflictError(err error) bool {
+	_, ok := err.(ConflictError)
+	return ok
+}
+
+type TimeoutError struct {
+	Message string
+	cause   error
+}
+
+func NewTimeoutError(message string) TimeoutError {
+	return TimeoutError{Message: message}
+}
+
+// WrapTimeout returns a TimeoutError keeping err reachable via errors.Unwrap.
+func WrapTimeout(cause error, message string) TimeoutError {
+	return TimeoutError{Message: message, cause: cause}
+}
+
+func (err TimeoutError) Error() string {
+	return err.Message
+}
+
+func (err TimeoutError) Unwrap() error {
+	return err.cause
+}
+
+func IsTimeoutError(err error) bool {
+	_, ok := err.(TimeoutError)
+	return ok
+}
+
+// DependencyMissingError means a release depends on another release or output
+// config which does not exist yet.
+type DependencyMissingError struct {
+	Message string
+}
+
+func NewDependencyMissingError(message string) DependencyMissingError {
+	return DependencyMissingError{Message: message}
+}
+
+func (err DependencyMissingError) Error() string {
+	return err.Message
+}
+
+func IsDependencyMissingError(err error) bool {
+	_, ok := err.(DependencyMissingError)
+	return ok
+}
+
+type ChartNotFoundError struct {
+	Message string
+}
+
+func NewChartNotFoundError(message string) ChartNotFoundError {
+	return ChartNotFoundError{Message: message}
+}
+
+func (err ChartNotFoundError) Error() string {
+	return err.Message
+}
+
+func IsChartNotFoundError(err error) bool {
+	_, ok := err.(ChartNotFoundError)
+	return ok
+}
+
+type InternalError struct {
+	Message string
+}
+
+func NewInternalError(message string) InternalError {
+	return InternalError{Message: message}
+}
+
+func (err InternalError) Error() string {
+	return err.Message
+}
+
+func IsInternalError(err error) bool {
+	_, ok := err.(InternalError)
+	return ok
+}
@@ -0,0 +1,36 @@
+package error
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFoundError(t *testing.T) {
+	assert.Equal(t, "not found error", NotFoundError{}.Error())
+	assert.Equal(t, "release not found", NotFoundError{Message: "release not found"}.Error())
+	assert.True(t, IsNotFoundError(NotFoundError{}))
+	assert.False(t, IsNotFoundError(errors.New("not found error")))
+}
+
+func TestIsErrorHelpers(t *testing.T) {
+	tests := []struct {
+		err     error
+		matches func(err error) bool
+	}{
+		{NewConflictError("busy"), IsConflictError},
+		{NewTimeoutError("timeout"), IsTimeoutError},
+		{NewDependencyMissingError("dependency missing"), IsDependencyMissingError},
+		{NewChartNotFoundError("chart not found"), IsChartNotFoundError},
+		{NewInternalError("internal"), IsInternalError},
+		{ValidationError{Message: "invalid"}, IsValidationError},
+	}
+
+	for _, test := range tests {
+		assert.True(t, test.matches(test.err))
+		assert.False(t, test.matches(errors.New(test.err.Error())))
+		assert.False(t, test.matches(NotFoundError{}))
+	}
+	assert.Equal(t, "busy", NewConflictError("busy").Error())
+}
@@ -0,0 +1,31 @@
+package event
+
+import "time"
+
+// lifecycle event subject kinds
+const (
+	ReleaseEventKind = "release"
+	ProjectEventKind = "project"
+)
+
+// ReadinessChangedEventType marks events published when the readiness of a
+// release or project flips. The other event types reuse the release event
+// types and the task names of the operation that caused the change.
+const ReadinessChangedEventType = "ReadinessChanged"
+
+// LifecycleEvent is one lifecycle state change published to kafka, so
+// downstream systems can consume walm changes instead of polling the api.
+type LifecycleEvent struct {
+	Kind         string    `json:"kind" description:"kind of the subject : release or project"`
+	Type         string    `json:"type" description:"what happened, e.g. Installed, Delete-Project-Task, ReadinessChanged"`
+	Namespace    string    `json:"namespace" description:"namespace of the subject"`
+	Name         string    `json:"name" description:"name of the subject"`
+	Project      string    `json:"project,omitempty" description:"project the release belongs to"`
+	ChartName    string    `json:"chartName,omitempty" description:"chart name of the release"`
+	ChartVersion string    `json:"chartVersion,omitempty" description:"chart version of the release"`
+	TaskUuid     string    `json:"taskUuid,omitempty" description:"uuid of the task that caused the change"`
+	Ready        bool      `json:"ready" description:"readiness after the change, only meaningful for ReadinessChanged events"`
+	Succeeded    bool      `json:"succeeded" description:"whether the task succeeded, only meaningful for task events"`
+	Error        string    `json:"error,omitempty" description:"error message when the task failed"`
+	Timestamp    time.Time `json:"timestamp" description:"when the change was observed"`
+}
@@ -0,0 +1,12 @@
+package k8s
+
+import (
+	"time"
+)
+
+// CacheSyncInfo reports the freshness of the informer caches the resource
+// statuses are computed from.
+type CacheSyncInfo struct {
+	Synced   bool      `json:"synced" description:"whether the informer caches finished their initial sync"`
+	SyncTime time.Time `json:"syncTime" description:"when the informer caches finished their initial sync"`
+}
@@ -31,6 +31,35 @@ func (resourceSet *ResourceSet) GetPodsNeedRestart() []*Pod {
 	return pods
 }
 
+func (resourceSet *ResourceSet) GetResources() []Resource {
+	resources := []Resource{}
+	for _, secret := range resourceSet.Secrets {
+		resources = append(resources, secret)
+	}
+	for _, job := range resourceSet.Jobs {
+		resources = append(resources, job)
+	}
+	for _, statefulSet := range resourceSet.StatefulSets {
+		resources = append(resources, statefulSet)
+	}
+	for _, service := range resourceSet.Services {
+		resources = append(resources, service)
+	}
+	for _, ingress := range resourceSet.Ingresses {
+		resources = append(resources, ingress)
+	}
+	for _, deployment := range resourceSet.Deployments {
+		resources = append(resources, deployment)
+	}
+	for _, daemonSet := range resourceSet.DaemonSets {
+		resources = append(resources, daemonSet)
+	}
+	for _, configMap := range resourceSet.ConfigMaps {
+		resources = append(resources, configMap)
+	}
+	return resources
+}
+
 func (resourceSet *ResourceSet) IsReady() (bool, Resource) {
 	for _, secret := range resourceSet.Secrets {
 		if secret.State.Status != "Ready" {
@@ -15,6 +15,8 @@ const (
 	PersistentVolumeClaimKind ResourceKind = "PersistentVolumeClaim"
 	StorageClassKind          ResourceKind = "StorageClass"
 	NamespaceKind             ResourceKind = "Namespace"
+	LimitRangeKind            ResourceKind = "LimitRange"
+	RoleBindingKind           ResourceKind = "RoleBinding"
 	ReleaseConfigKind         ResourceKind = "ReleaseConfig"
 )
 
@@ -103,6 +105,16 @@ type EventList struct {
 	Events []Event `json:"events" description:"events"`
 }
 
+type PodContainerLog struct {
+	ContainerName string `json:"containerName" description:"container name"`
+	Log           string `json:"log" description:"tail of the container log"`
+}
+
+type PodDetail struct {
+	Events []Event           `json:"events" description:"recent events of the pod"`
+	Logs   []PodContainerLog `json:"logs" description:"tail of each container log"`
+}
+
 type Deployment struct {
 	Meta
 	Labels            map[string]string `json:"labels" description:"deployment labels"`
@@ -307,17 +319,32 @@ type NodeList struct {
 type ResourceName string
 
 const (
-	ResourcePods            ResourceName = "pods"
-	ResourceLimitsCPU       ResourceName = "limits.cpu"
-	ResourceLimitsMemory    ResourceName = "limits.memory"
-	ResourceRequestsCPU     ResourceName = "requests.cpu"
-	ResourceRequestsMemory  ResourceName = "requests.memory"
-	ResourceRequestsStorage ResourceName = "requests.storage"
+	ResourcePods                     ResourceName = "pods"
+	ResourceLimitsCPU                ResourceName = "limits.cpu"
+	ResourceLimitsMemory             ResourceName = "limits.memory"
+	ResourceRequestsCPU              ResourceName = "requests.cpu"
+	ResourceRequestsMemory           ResourceName = "requests.memory"
+	ResourceRequestsStorage          ResourceName = "requests.storage"
+	ResourceRequestsEphemeralStorage ResourceName = "requests.ephemeral-storage"
+	ResourceServices                 ResourceName = "services"
+	ResourceServicesNodePorts        ResourceName = "services.nodeports"
+	ResourcePersistentVolumeClaims   ResourceName = "persistentvolumeclaims"
+	ResourceCountConfigMaps          ResourceName = "count/configmaps"
 
 	ResourceCPU    ResourceName = "cpu"
 	ResourceMemory ResourceName = "memory"
 )
 
+// labels walm stamps on every resource it creates, for ownership and tenant accounting
+const (
+	WalmReleaseLabelKey   = "walm.release"
+	WalmProjectLabelKey   = "walm.project"
+	WalmNamespaceLabelKey = "walm.namespace"
+	// stamped on pvcs left behind by a release deleted under a delayed pvc
+	// policy, holds the time the pvc reaper will delete them at
+	WalmPvcDeleteAtLabelKey = "walm.pvc-delete-at"
+)
+
 type ResourceQuota struct {
 	Meta
 	ResourceLimits map[ResourceName]string `json:"limits" description:"resource quota hard limits"`
@@ -373,6 +400,7 @@ type ReleaseConfig struct {
 	OutputConfig             map[string]interface{} `json:"outputConfig"`
 	Repo                     string                 `json:"repo" description:"chart repo"`
 	ChartImage               string                 `json:"chartImage" description:"chart image"`
+	CreationTimestamp        string                 `json:"creationTimestamp" description:"creation timestamp"`
 }
 
 func (resource *ReleaseConfig) AddToResourceSet(resourceSet *ResourceSet) {
@@ -389,12 +417,26 @@ func (resource *Namespace) AddToResourceSet(resourceSet *ResourceSet) {
 
 type LimitRange struct {
 	Meta
-	DefaultLimit map[ResourceName]string
+	DefaultLimit   map[ResourceName]string
+	DefaultRequest map[ResourceName]string
+	Max            map[ResourceName]string
+	Min            map[ResourceName]string
 }
 
 func (resource *LimitRange) AddToResourceSet(resourceSet *ResourceSet) {
 }
 
+type RoleBinding struct {
+	Meta
+	Labels          map[string]string
+	SubjectKind     string
+	SubjectName     string
+	ClusterRoleName string
+}
+
+func (resource *RoleBinding) AddToResourceSet(resourceSet *ResourceSet) {
+}
+
 type LabelNodeRequestBody struct {
 	AddLabels    map[string]string `json:"addLabels"`
 	RemoveLabels []string          `json:"removeLabels"`
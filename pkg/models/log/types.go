@@ -0,0 +1,6 @@
+package log
+
+type LogLevels struct {
+	Default string            `json:"default" description:"level modules start with"`
+	Modules map[string]string `json:"modules" description:"current level of every module, by module name"`
+}
@@ -2,11 +2,17 @@ package project
 
 import (
 	"WarpCloud/walm/pkg/models/release"
+	"WarpCloud/walm/pkg/models/schedule"
 	"WarpCloud/walm/pkg/models/task"
 )
 
 const (
 	ProjectNameLabelKey = "Project-Name"
+
+	ProjectEventSnapshot         = "snapshot"
+	ProjectEventTaskStateChanged = "taskStateChanged"
+	ProjectEventReadyChanged     = "readyChanged"
+	ProjectEventDeleted          = "deleted"
 )
 
 type ProjectParams struct {
@@ -15,11 +21,29 @@ type ProjectParams struct {
 }
 
 type ProjectInfo struct {
-	Name      string                   `json:"name" description:"project name"`
-	Namespace string                   `json:"namespace" description:"project namespace"`
-	Releases  []*release.ReleaseInfoV2 `json:"releases" description:"list of release of the project"`
-	Ready     bool                     `json:"ready" description:"whether all the project releases are ready"`
-	Message   string                   `json:"message" description:"why project is not ready"`
+	Name            string                   `json:"name" description:"project name"`
+	Namespace       string                   `json:"namespace" description:"project namespace"`
+	Releases        []*release.ReleaseInfoV2 `json:"releases" description:"list of release of the project"`
+	Ready           bool                     `json:"ready" description:"whether all the project releases are ready"`
+	Message         string                   `json:"message" description:"why project is not ready"`
+	LatestTaskState string                   `json:"latestTaskState,omitempty" description:"state of the latest project task : Running, Succeed or Failed"`
+	// filled only when a schedule store is configured, operations recorded
+	// for later execution which target this project
+	ScheduledOperations []*schedule.ScheduledOperation `json:"scheduledOperations,omitempty" description:"operations scheduled on this project, pending until their scheduled time"`
+}
+
+type ProjectDeletePreview struct {
+	Name      string                          `json:"name" description:"project name"`
+	Namespace string                          `json:"namespace" description:"project namespace"`
+	Releases  []*release.ReleaseDeletePreview `json:"releases" description:"delete previews of the project's releases"`
+}
+
+// MaskSensitiveValues replaces the sensitive config values of the project's
+// releases with masked ones.
+func (projectInfo *ProjectInfo) MaskSensitiveValues() {
+	for _, releaseInfo := range projectInfo.Releases {
+		releaseInfo.MaskSensitiveValues()
+	}
 }
 
 type ProjectInfoList struct {
@@ -27,6 +51,32 @@ type ProjectInfoList struct {
 	Items []*ProjectInfo `json:"items" description:"project info list"`
 }
 
+// ProjectInfoFilteredList carries project infos pruned to the fields the
+// caller asked for, so big listings stay small on the wire.
+type ProjectInfoFilteredList struct {
+	Num   int           `json:"num" description:"project number"`
+	Items []interface{} `json:"items" description:"project infos pruned to the requested fields"`
+}
+
+// ProjectEvent is broadcast on the shared redis channel when a project's
+// latest task changes state or its cache entry is invalidated, so streaming
+// endpoints get notified without polling.
+type ProjectEvent struct {
+	Type      string       `json:"type" description:"event type: snapshot, taskStateChanged, readyChanged, deleted"`
+	RequestId string       `json:"requestId,omitempty" description:"id of the request that triggered the event"`
+	Namespace string       `json:"namespace" description:"project namespace"`
+	Name      string       `json:"name" description:"project name"`
+	Project   *ProjectInfo `json:"project,omitempty" description:"current project info"`
+}
+
+// ProjectWebhook routes the lifecycle events of one project to an external
+// http receiver, overriding the globally configured webhook endpoints.
+type ProjectWebhook struct {
+	Url        string   `json:"url" description:"url lifecycle events are posted to"`
+	Secret     string   `json:"secret" description:"optional secret used to sign the request body with hmac sha256"`
+	EventTypes []string `json:"eventTypes" description:"event types delivered to this webhook, empty means all"`
+}
+
 type ProjectTask struct {
 	Name                string        `json:"name" description:"project name"`
 	Namespace           string        `json:"namespace" description:"project namespace"`
@@ -1,5 +1,7 @@
 package release
 
+import "sort"
+
 type RepoInfo struct {
 	TenantRepoName string `json:"repoName"`
 	TenantRepoURL  string `json:"repoUrl"`
@@ -30,3 +32,109 @@ type ChartDetailInfo struct {
 type ChartInfoList struct {
 	Items []*ChartInfo `json:"items" description:"chart list"`
 }
+
+type ChartBrowseInfo struct {
+	ChartName        string `json:"chartName" description:"chart name"`
+	LatestVersion    string `json:"latestVersion" description:"latest version the repo serves"`
+	VersionCount     int    `json:"versionCount" description:"number of versions the repo serves"`
+	ChartDescription string `json:"chartDescription" description:"description of the latest version"`
+	ChartAppVersion  string `json:"chartAppVersion" description:"app version of the latest version"`
+	IconUrl          string `json:"iconUrl" description:"icon url from the repo index"`
+}
+
+type ChartBrowseList struct {
+	Items    []*ChartBrowseInfo `json:"items" description:"charts of the repo, sorted by chart name"`
+	Continue string             `json:"continue" description:"token to pass to the next list call, empty on the last page"`
+}
+
+type ChartVersionInfo struct {
+	ChartVersion     string `json:"chartVersion" description:"chart version"`
+	ChartAppVersion  string `json:"chartAppVersion" description:"app version"`
+	ChartDescription string `json:"chartDescription" description:"chart description"`
+	Created          string `json:"created" description:"when the version was published to the repo"`
+	Digest           string `json:"digest" description:"digest of the chart tarball"`
+}
+
+type ChartVersionList struct {
+	ChartName string              `json:"chartName" description:"chart name"`
+	Items     []*ChartVersionInfo `json:"items" description:"chart versions, latest first"`
+}
+
+// ChartMetadata is the Chart.yaml metadata of one chart version, served from
+// the repo index, so the ui can render chart cards without downloading charts.
+type ChartMetadata struct {
+	ChartName        string             `json:"chartName" description:"chart name"`
+	ChartVersion     string             `json:"chartVersion" description:"chart version"`
+	ChartAppVersion  string             `json:"chartAppVersion" description:"app version"`
+	ChartDescription string             `json:"chartDescription" description:"chart description"`
+	Keywords         []string           `json:"keywords" description:"chart keywords"`
+	Maintainers      []*ChartMaintainer `json:"maintainers" description:"chart maintainers"`
+	IconUrl          string             `json:"iconUrl" description:"icon url from the repo index"`
+}
+
+type ChartMaintainer struct {
+	Name  string `json:"name" description:"maintainer name"`
+	Email string `json:"email" description:"maintainer email"`
+}
+
+type ChartParamSchema struct {
+	Name         string      `json:"name" description:"parameter name"`
+	MapKey       string      `json:"mapKey" description:"values.yaml key of the parameter"`
+	Type         string      `json:"type" description:"parameter type"`
+	Description  string      `json:"description" description:"parameter description"`
+	Required     bool        `json:"required" description:"required"`
+	Role         string      `json:"role" description:"role the parameter belongs to, empty for common parameters"`
+	DefaultValue interface{} `json:"defaultValue" description:"default value from metainfo or values.yaml"`
+}
+
+type ChartMetainfo struct {
+	ChartName    string              `json:"chartName" description:"chart name"`
+	ChartVersion string              `json:"chartVersion" description:"chart version"`
+	HasMetaInfo  bool                `json:"hasMetaInfo" description:"whether the chart ships transwarp metainfo"`
+	Params       []*ChartParamSchema `json:"params" description:"configurable parameters"`
+}
+
+// BuildParamSchemasFromValues flattens values.yaml into a best effort param schema
+// for charts without transwarp metainfo.
+func BuildParamSchemasFromValues(values map[string]interface{}) []*ChartParamSchema {
+	paramSchemas := []*ChartParamSchema{}
+	flattenValuesToParamSchemas("", values, &paramSchemas)
+	sort.Slice(paramSchemas, func(i, j int) bool {
+		return paramSchemas[i].MapKey < paramSchemas[j].MapKey
+	})
+	return paramSchemas
+}
+
+func flattenValuesToParamSchemas(prefix string, values map[string]interface{}, paramSchemas *[]*ChartParamSchema) {
+	for key, value := range values {
+		mapKey := key
+		if prefix != "" {
+			mapKey = prefix + "." + key
+		}
+		if subValues, ok := value.(map[string]interface{}); ok && len(subValues) > 0 {
+			flattenValuesToParamSchemas(mapKey, subValues, paramSchemas)
+			continue
+		}
+		*paramSchemas = append(*paramSchemas, &ChartParamSchema{
+			MapKey:       mapKey,
+			Type:         buildValueType(value),
+			DefaultValue: value,
+		})
+	}
+}
+
+func buildValueType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, int, int64:
+		return "number"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return ""
+}
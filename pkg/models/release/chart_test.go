@@ -0,0 +1,91 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildParamSchemasFromValues(t *testing.T) {
+	tests := []struct {
+		values               map[string]interface{}
+		expectedParamSchemas []*ChartParamSchema
+	}{
+		{
+			values: map[string]interface{}{
+				"image":    "zookeeper:5.2",
+				"replicas": float64(3),
+				"resources": map[string]interface{}{
+					"memory_request":   float64(1024),
+					"use_host_network": true,
+				},
+				"tolerations": []interface{}{},
+			},
+			expectedParamSchemas: []*ChartParamSchema{
+				{MapKey: "image", Type: "string", DefaultValue: "zookeeper:5.2"},
+				{MapKey: "replicas", Type: "number", DefaultValue: float64(3)},
+				{MapKey: "resources.memory_request", Type: "number", DefaultValue: float64(1024)},
+				{MapKey: "resources.use_host_network", Type: "boolean", DefaultValue: true},
+				{MapKey: "tolerations", Type: "list", DefaultValue: []interface{}{}},
+			},
+		},
+		{
+			values:               map[string]interface{}{},
+			expectedParamSchemas: []*ChartParamSchema{},
+		},
+	}
+
+	for _, test := range tests {
+		paramSchemas := BuildParamSchemasFromValues(test.values)
+		assert.Equal(t, test.expectedParamSchemas, paramSchemas)
+	}
+}
+
+func Test_BuildParamSchemas(t *testing.T) {
+	chartMetaInfo := &ChartMetaInfo{
+		ChartParams: []*MetaCommonConfig{
+			{
+				Name:        "java_opts",
+				MapKey:      "conf.java_opts",
+				Type:        "string",
+				Description: "jvm options",
+				Required:    false,
+			},
+		},
+		ChartRoles: []*MetaRoleConfig{
+			{
+				Name: "zookeeper",
+				RoleBaseConfig: &MetaRoleBaseConfig{
+					Replicas: &MetaIntConfig{
+						IntConfig{
+							MapKey:      "replicas",
+							Description: "zookeeper replicas",
+							Type:        "number",
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	paramSchemas := chartMetaInfo.BuildParamSchemas("{\"conf\": {\"java_opts\": \"-Xmx1g\"}, \"replicas\": 3}")
+	assert.Equal(t, []*ChartParamSchema{
+		{
+			Name:         "java_opts",
+			MapKey:       "conf.java_opts",
+			Type:         "string",
+			Description:  "jvm options",
+			DefaultValue: "\"-Xmx1g\"",
+		},
+		{
+			Name:         "replicas",
+			MapKey:       "replicas",
+			Type:         "number",
+			Description:  "zookeeper replicas",
+			Required:     true,
+			Role:         "zookeeper",
+			DefaultValue: int64(3),
+		},
+	}, paramSchemas)
+}
@@ -0,0 +1,103 @@
+package release
+
+import (
+	"WarpCloud/walm/pkg/util"
+	"encoding/base64"
+	"fmt"
+	"sort"
+)
+
+const (
+	SortReleasesByName         = "name"
+	SortReleasesByCreationTime = "creationTime"
+)
+
+type ReleaseInfoV2Page struct {
+	Num      int           `json:"num" description:"num of releases matching the query"`
+	Continue string        `json:"continue" description:"continue token of the next page, empty for the last page"`
+	Items    []interface{} `json:"items" description:"release infos of the page, masked to the requested fields if fields is given"`
+}
+
+// BuildReleaseInfoV2Page sorts, paginates and masks the given releases. The continue
+// token encodes the key of the last release of the page, so paging is stable across
+// walm replicas.
+func BuildReleaseInfoV2Page(releases []*ReleaseInfoV2, sortBy string, limit int, continueToken string, fields []string) (*ReleaseInfoV2Page, error) {
+	err := sortReleases(releases, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ReleaseInfoV2Page{
+		Num:   len(releases),
+		Items: []interface{}{},
+	}
+
+	if continueToken != "" {
+		releases, err = skipToContinueKey(releases, continueToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limit > 0 && len(releases) > limit {
+		releases = releases[:limit]
+		page.Continue = encodeContinueKey(releases[len(releases)-1])
+	}
+
+	for _, releaseInfo := range releases {
+		if len(fields) > 0 {
+			maskedRelease, err := maskReleaseInfoFields(releaseInfo, fields)
+			if err != nil {
+				return nil, err
+			}
+			page.Items = append(page.Items, maskedRelease)
+		} else {
+			page.Items = append(page.Items, releaseInfo)
+		}
+	}
+	return page, nil
+}
+
+func sortReleases(releases []*ReleaseInfoV2, sortBy string) error {
+	switch sortBy {
+	case "", SortReleasesByName:
+		sort.SliceStable(releases, func(i, j int) bool {
+			return buildReleaseKey(releases[i]) < buildReleaseKey(releases[j])
+		})
+	case SortReleasesByCreationTime:
+		sort.SliceStable(releases, func(i, j int) bool {
+			if releases[i].CreationTimestamp != releases[j].CreationTimestamp {
+				return releases[i].CreationTimestamp < releases[j].CreationTimestamp
+			}
+			return buildReleaseKey(releases[i]) < buildReleaseKey(releases[j])
+		})
+	default:
+		return fmt.Errorf("sort %s is not supported, supported sorts : %s, %s", sortBy, SortReleasesByName, SortReleasesByCreationTime)
+	}
+	return nil
+}
+
+func buildReleaseKey(releaseInfo *ReleaseInfoV2) string {
+	return releaseInfo.Namespace + "/" + releaseInfo.Name
+}
+
+func encodeContinueKey(releaseInfo *ReleaseInfoV2) string {
+	return base64.StdEncoding.EncodeToString([]byte(buildReleaseKey(releaseInfo)))
+}
+
+func skipToContinueKey(releases []*ReleaseInfoV2, continueToken string) ([]*ReleaseInfoV2, error) {
+	lastKey, err := base64.StdEncoding.DecodeString(continueToken)
+	if err != nil {
+		return nil, fmt.Errorf("continue token %s is not valid : %s", continueToken, err.Error())
+	}
+	for index, releaseInfo := range releases {
+		if buildReleaseKey(releaseInfo) == string(lastKey) {
+			return releases[index+1:], nil
+		}
+	}
+	return nil, fmt.Errorf("continue token %s is expired : release %s is not found", continueToken, string(lastKey))
+}
+
+func maskReleaseInfoFields(releaseInfo *ReleaseInfoV2, fields []string) (map[string]interface{}, error) {
+	return util.PruneToFields(releaseInfo, fields)
+}
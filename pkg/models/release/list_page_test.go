@@ -0,0 +1,138 @@
+package release
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildReleaseInfoV2Page(t *testing.T) {
+	buildTestReleases := func() []*ReleaseInfoV2 {
+		return []*ReleaseInfoV2{
+			{
+				ReleaseInfo: ReleaseInfo{
+					ReleaseSpec: ReleaseSpec{
+						Namespace: "test-ns",
+						Name:      "test-name2",
+					},
+				},
+				CreationTimestamp: "2019-01-01T00:00:00Z",
+			},
+			{
+				ReleaseInfo: ReleaseInfo{
+					ReleaseSpec: ReleaseSpec{
+						Namespace: "test-ns",
+						Name:      "test-name1",
+					},
+				},
+				CreationTimestamp: "2019-02-01T00:00:00Z",
+			},
+		}
+	}
+
+	tests := []struct {
+		sortBy        string
+		limit         int
+		continueToken string
+		fields        []string
+		err           error
+		expectedPage  *ReleaseInfoV2Page
+	}{
+		{
+			expectedPage: &ReleaseInfoV2Page{
+				Num:   2,
+				Items: []interface{}{buildTestReleases()[1], buildTestReleases()[0]},
+			},
+		},
+		{
+			sortBy: SortReleasesByCreationTime,
+			expectedPage: &ReleaseInfoV2Page{
+				Num:   2,
+				Items: []interface{}{buildTestReleases()[0], buildTestReleases()[1]},
+			},
+		},
+		{
+			limit: 1,
+			expectedPage: &ReleaseInfoV2Page{
+				Num:      2,
+				Continue: base64.StdEncoding.EncodeToString([]byte("test-ns/test-name1")),
+				Items:    []interface{}{buildTestReleases()[1]},
+			},
+		},
+		{
+			limit:         1,
+			continueToken: base64.StdEncoding.EncodeToString([]byte("test-ns/test-name1")),
+			expectedPage: &ReleaseInfoV2Page{
+				Num:   2,
+				Items: []interface{}{buildTestReleases()[0]},
+			},
+		},
+		{
+			fields: []string{"name", "creationTimestamp", "notExisted"},
+			expectedPage: &ReleaseInfoV2Page{
+				Num: 2,
+				Items: []interface{}{
+					map[string]interface{}{"name": "test-name1", "creationTimestamp": "2019-02-01T00:00:00Z"},
+					map[string]interface{}{"name": "test-name2", "creationTimestamp": "2019-01-01T00:00:00Z"},
+				},
+			},
+		},
+		{
+			sortBy: "not-supported",
+			err:    assert.AnError,
+		},
+		{
+			continueToken: "not-valid-base64!",
+			err:           assert.AnError,
+		},
+		{
+			continueToken: base64.StdEncoding.EncodeToString([]byte("test-ns/not-existed")),
+			err:           assert.AnError,
+		},
+	}
+
+	for _, test := range tests {
+		page, err := BuildReleaseInfoV2Page(buildTestReleases(), test.sortBy, test.limit, test.continueToken, test.fields)
+		if test.err != nil {
+			assert.Error(t, err)
+			continue
+		}
+		assert.IsType(t, nil, err)
+		assert.Equal(t, test.expectedPage, page)
+	}
+}
+
+func Test_BuildReleaseInfoV2PageFieldsSize(t *testing.T) {
+	// a representative big listing: 500 releases with chunky config values
+	releases := []*ReleaseInfoV2{}
+	for i := 0; i < 500; i++ {
+		configValues := map[string]interface{}{}
+		for j := 0; j < 50; j++ {
+			configValues[fmt.Sprintf("key-%d", j)] = "a-fairly-long-config-value-used-to-fill-the-listing"
+		}
+		releases = append(releases, &ReleaseInfoV2{
+			ReleaseInfo: ReleaseInfo{
+				ReleaseSpec: ReleaseSpec{
+					Namespace:    "test-ns",
+					Name:         fmt.Sprintf("test-name-%d", i),
+					ConfigValues: configValues,
+				},
+			},
+		})
+	}
+
+	fullBytes, err := json.Marshal(ReleaseInfoV2List{Num: len(releases), Items: releases})
+	assert.IsType(t, nil, err)
+
+	page, err := BuildReleaseInfoV2Page(releases, "", 0, "", []string{"name", "ready"})
+	assert.IsType(t, nil, err)
+	prunedBytes, err := json.Marshal(page)
+	assert.IsType(t, nil, err)
+
+	// pruning to two fields should shrink the payload by at least an order of magnitude
+	assert.True(t, len(prunedBytes)*10 < len(fullBytes),
+		"pruned size %d should be far smaller than full size %d", len(prunedBytes), len(fullBytes))
+}
@@ -624,3 +624,95 @@ type MetaHealthCheckConfig struct {
 	ReadinessProbe *MetaHealthProbeConfig `json:"readinessProbe"`
 	LivenessProbe  *MetaHealthProbeConfig `json:"livenessProbe"`
 }
+
+// BuildParamSchemas flattens the metainfo configurable params into a schema the ui
+// can build forms from, filling default values from the chart default values json.
+func (chartMetaInfo *ChartMetaInfo) BuildParamSchemas(valuesJsonStr string) []*ChartParamSchema {
+	paramSchemas := []*ChartParamSchema{}
+	for _, param := range chartMetaInfo.ChartParams {
+		if param == nil {
+			continue
+		}
+		paramSchemas = append(paramSchemas, buildCommonConfigSchema(param, "", valuesJsonStr))
+	}
+	for _, role := range chartMetaInfo.ChartRoles {
+		if role == nil || role.RoleBaseConfig == nil {
+			continue
+		}
+		roleBaseConfig := role.RoleBaseConfig
+		if roleBaseConfig.Image != nil {
+			paramSchemas = append(paramSchemas, &ChartParamSchema{
+				Name:         "image",
+				MapKey:       roleBaseConfig.Image.MapKey,
+				Type:         roleBaseConfig.Image.Type,
+				Description:  roleBaseConfig.Image.Description,
+				Required:     roleBaseConfig.Image.Required,
+				Role:         role.Name,
+				DefaultValue: roleBaseConfig.Image.BuildStringConfigValue(valuesJsonStr),
+			})
+		}
+		if roleBaseConfig.Replicas != nil {
+			paramSchemas = append(paramSchemas, &ChartParamSchema{
+				Name:         "replicas",
+				MapKey:       roleBaseConfig.Replicas.MapKey,
+				Type:         roleBaseConfig.Replicas.Type,
+				Description:  roleBaseConfig.Replicas.Description,
+				Required:     roleBaseConfig.Replicas.Required,
+				Role:         role.Name,
+				DefaultValue: roleBaseConfig.Replicas.BuildIntConfigValue(valuesJsonStr),
+			})
+		}
+		if roleBaseConfig.Priority != nil {
+			paramSchemas = append(paramSchemas, &ChartParamSchema{
+				Name:         "priority",
+				MapKey:       roleBaseConfig.Priority.MapKey,
+				Type:         roleBaseConfig.Priority.Type,
+				Description:  roleBaseConfig.Priority.Description,
+				Required:     roleBaseConfig.Priority.Required,
+				Role:         role.Name,
+				DefaultValue: roleBaseConfig.Priority.BuildIntConfigValue(valuesJsonStr),
+			})
+		}
+		if roleBaseConfig.UseHostNetwork != nil {
+			paramSchemas = append(paramSchemas, &ChartParamSchema{
+				Name:         "useHostNetwork",
+				MapKey:       roleBaseConfig.UseHostNetwork.MapKey,
+				Type:         roleBaseConfig.UseHostNetwork.Type,
+				Description:  roleBaseConfig.UseHostNetwork.Description,
+				Required:     roleBaseConfig.UseHostNetwork.Required,
+				Role:         role.Name,
+				DefaultValue: roleBaseConfig.UseHostNetwork.BuildBoolConfigValue(valuesJsonStr),
+			})
+		}
+		if roleBaseConfig.Env != nil {
+			paramSchemas = append(paramSchemas, &ChartParamSchema{
+				Name:         "env",
+				MapKey:       roleBaseConfig.Env.MapKey,
+				Type:         roleBaseConfig.Env.Type,
+				Description:  roleBaseConfig.Env.Description,
+				Required:     roleBaseConfig.Env.Required,
+				Role:         role.Name,
+				DefaultValue: roleBaseConfig.Env.BuildEnvConfigValue(valuesJsonStr),
+			})
+		}
+		for _, other := range roleBaseConfig.Others {
+			if other == nil {
+				continue
+			}
+			paramSchemas = append(paramSchemas, buildCommonConfigSchema(other, role.Name, valuesJsonStr))
+		}
+	}
+	return paramSchemas
+}
+
+func buildCommonConfigSchema(config *MetaCommonConfig, roleName string, valuesJsonStr string) *ChartParamSchema {
+	return &ChartParamSchema{
+		Name:         config.Name,
+		MapKey:       config.MapKey,
+		Type:         config.Type,
+		Description:  config.Description,
+		Required:     config.Required,
+		Role:         roleName,
+		DefaultValue: config.BuildCommonConfigValue(valuesJsonStr).Value,
+	}
+}
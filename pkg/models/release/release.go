@@ -2,6 +2,12 @@ package release
 
 import (
 	"WarpCloud/walm/pkg/models/k8s"
+	"WarpCloud/walm/pkg/models/schedule"
+	"WarpCloud/walm/pkg/util"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ReleaseInfoList struct {
@@ -11,9 +17,47 @@ type ReleaseInfoList struct {
 
 type ReleaseInfo struct {
 	ReleaseSpec
-	Ready   bool             `json:"ready" description:"whether release is ready"`
-	Message string           `json:"message" description:"why release is not ready"`
-	Status  *k8s.ResourceSet `json:"releaseStatus" description:"status of release"`
+	Ready       bool             `json:"ready" description:"whether release is ready"`
+	Message     string           `json:"message" description:"why release is not ready"`
+	ReadyReason string           `json:"readyReason" description:"machine readable reason why release is not ready"`
+	Status      *k8s.ResourceSet `json:"releaseStatus" description:"status of release"`
+}
+
+// machine readable reasons why a release is not ready
+const (
+	ReadyReasonPvcStorageClassMissing  = "PvcStorageClassMissing"
+	ReadyReasonUnschedulable           = "Unschedulable"
+	ReadyReasonCrashLoopBackOff        = "CrashLoopBackOff"
+	ReadyReasonImagePullBackOff        = "ImagePullBackOff"
+	ReadyReasonPvcWaitForFirstConsumer = "PvcWaitForFirstConsumer"
+	ReadyReasonPending                 = "Pending"
+)
+
+// ready reasons ordered from the most severe to the least severe
+var readyReasonSeverities = map[string]int{
+	ReadyReasonPvcStorageClassMissing:  0,
+	ReadyReasonUnschedulable:           1,
+	ReadyReasonCrashLoopBackOff:        2,
+	ReadyReasonImagePullBackOff:        3,
+	ReadyReasonPvcWaitForFirstConsumer: 4,
+	ReadyReasonPending:                 5,
+}
+
+// MoreSevereReadyReason returns the more severe one of the two ready reasons.
+// Unknown reasons are treated as the least severe.
+func MoreSevereReadyReason(reason1, reason2 string) string {
+	severity1, ok := readyReasonSeverities[reason1]
+	if !ok {
+		return reason2
+	}
+	severity2, ok := readyReasonSeverities[reason2]
+	if !ok {
+		return reason1
+	}
+	if severity2 < severity1 {
+		return reason2
+	}
+	return reason1
 }
 
 type ReleaseSpec struct {
@@ -26,6 +70,7 @@ type ReleaseSpec struct {
 	ChartName       string                 `json:"chartName" description:"chart name"`
 	ChartVersion    string                 `json:"chartVersion" description:"chart version"`
 	ChartAppVersion string                 `json:"chartAppVersion" description:"jsonnet app version"`
+	Cluster         string                 `json:"cluster" description:"cluster the release is installed in, empty means the default cluster"`
 }
 
 type ReleaseCache struct {
@@ -34,6 +79,17 @@ type ReleaseCache struct {
 	ComputedValues       map[string]interface{} `json:"computedValues" description:"release computed values"`
 	MetaInfoValues       *MetaInfoParams        `json:"metaInfoValues" description:"meta info values"`
 	Manifest             string                 `json:"manifest" description:"meta info values"`
+	ChartProvenance      *ChartProvenance       `json:"chartProvenance" description:"chart provenance verification result, nil when verification is off or the chart was not loaded from a repo"`
+	// persisted from the release request, consulted when the release is deleted
+	PvcPolicy string `json:"pvcPolicy" description:"policy applied to the release pvcs on delete"`
+}
+
+// ChartProvenance records the outcome of verifying the chart tarball against
+// the .prov file published by its repo.
+type ChartProvenance struct {
+	Verified bool     `json:"verified" description:"whether the chart signature was verified against the configured keyring"`
+	SignedBy []string `json:"signedBy" description:"identities of the key the chart was signed by"`
+	Message  string   `json:"message" description:"reason the chart could not be verified"`
 }
 
 type ReleaseResourceMeta struct {
@@ -48,9 +104,62 @@ type ReleaseRequest struct {
 	ChartName    string                 `json:"chartName" description:"chart name"`
 	ChartVersion string                 `json:"chartVersion" description:"chart repo"`
 	ConfigValues map[string]interface{} `json:"configValues" description:"extra values added to the chart"`
-	Dependencies map[string]string      `json:"dependencies" description:"map of dependency chart name and release"`
+	Dependencies map[string]string      `json:"dependencies" description:"map of dependency chart name and release, a value may be suffixed with #soft to mark the dependency soft"`
 }
 
+// SoftDependencySuffix marks a dependency value as soft: the dependency only
+// provides config values and must not serialize the installation. A value of
+// just the suffix lets the project dep parse resolve the release name.
+const SoftDependencySuffix = "#soft"
+
+// ParseDependencyValue splits a dependency value into the depended release
+// name and whether the dependency was marked soft with SoftDependencySuffix.
+// Values without the suffix stay hard dependencies, so existing requests keep
+// their behavior.
+func ParseDependencyValue(value string) (string, bool) {
+	if strings.HasSuffix(value, SoftDependencySuffix) {
+		return strings.TrimSuffix(value, SoftDependencySuffix), true
+	}
+	return value, false
+}
+
+// pvc policies a release can declare for the pvcs its workloads leave behind
+// when the release is deleted
+const (
+	PvcPolicyRetain = "retain"
+	PvcPolicyDelete = "delete"
+	// PvcPolicyDeleteAfterDaysPrefix prefixes the delayed deletion policy,
+	// e.g. "delete-after-days:7"
+	PvcPolicyDeleteAfterDaysPrefix = "delete-after-days:"
+)
+
+// ParsePvcPolicy parses a pvc policy into whether the pvcs are deleted with
+// the release and, for the delayed policy, after how many days instead. An
+// empty policy means retain, keeping the old behavior.
+func ParsePvcPolicy(pvcPolicy string) (deletePvcs bool, deleteAfterDays int64, err error) {
+	switch {
+	case pvcPolicy == "" || pvcPolicy == PvcPolicyRetain:
+	case pvcPolicy == PvcPolicyDelete:
+		deletePvcs = true
+	case strings.HasPrefix(pvcPolicy, PvcPolicyDeleteAfterDaysPrefix):
+		deleteAfterDays, err = strconv.ParseInt(strings.TrimPrefix(pvcPolicy, PvcPolicyDeleteAfterDaysPrefix), 10, 64)
+		if err != nil || deleteAfterDays <= 0 {
+			err = fmt.Errorf("pvc policy %s is not valid : the number of days should be a positive integer", pvcPolicy)
+			deleteAfterDays = 0
+		}
+	default:
+		err = fmt.Errorf("pvc policy %s is not valid : supported policies are %s, %s and %s<days>",
+			pvcPolicy, PvcPolicyRetain, PvcPolicyDelete, PvcPolicyDeleteAfterDaysPrefix)
+	}
+	return
+}
+
+// ExternalDependenciesConfigKey is the config values key the external
+// dependency declarations are stored under, the way the walm plugins are,
+// so they survive in the helm release and can be rebuilt from the
+// computed values.
+const ExternalDependenciesConfigKey = "WalmExternalDependencies"
+
 type ReleaseConfigDeltaEventType string
 
 const (
@@ -73,6 +182,28 @@ type ReleaseInfoV2 struct {
 	MetaInfoValues           *MetaInfoParams        `json:"metaInfoValues" description:"meta info values"`
 	Paused                   bool                   `json:"paused" description:"whether release is paused"`
 	ChartImage               string                 `json:"chartImage" description:"release chart image"`
+	CreationTimestamp        string                 `json:"creationTimestamp" description:"creation timestamp of the release config"`
+	ChartProvenance          *ChartProvenance       `json:"chartProvenance" description:"chart provenance verification result of the last install or upgrade"`
+	// external dependencies are declared inline on the release request, so
+	// they are listed apart from the release backed dependencies
+	ExternalDependencies map[string]map[string]interface{} `json:"externalDependencies" description:"external dependencies' inline output config values keyed by dependency chart name"`
+	// filled only when a schedule store is configured, operations recorded
+	// for later execution which target this release
+	ScheduledOperations []*schedule.ScheduledOperation `json:"scheduledOperations,omitempty" description:"operations scheduled on this release, pending until their scheduled time"`
+	PvcPolicy           string                         `json:"pvcPolicy" description:"policy applied to the release pvcs on delete"`
+}
+
+// MaskSensitiveValues replaces the sensitive config values of the release info
+// with masked ones, so it is safe to serialize into api responses or logs.
+// The real values the charts are rendered with are not modified.
+func (releaseInfo *ReleaseInfoV2) MaskSensitiveValues() {
+	releaseInfo.ConfigValues = util.MaskSensitiveValues(releaseInfo.ConfigValues)
+	releaseInfo.ComputedValues = util.MaskSensitiveValues(releaseInfo.ComputedValues)
+	releaseInfo.DependenciesConfigValues = util.MaskSensitiveValues(releaseInfo.DependenciesConfigValues)
+	releaseInfo.OutputConfigValues = util.MaskSensitiveValues(releaseInfo.OutputConfigValues)
+	for dependency, outputConfig := range releaseInfo.ExternalDependencies {
+		releaseInfo.ExternalDependencies[dependency] = util.MaskSensitiveValues(outputConfig)
+	}
 }
 
 type ReleasePlugin struct {
@@ -95,6 +226,7 @@ func (releaseInfo *ReleaseInfoV2) BuildReleaseRequestV2() *ReleaseRequestV2 {
 		ReleaseLabels: releaseInfo.ReleaseLabels,
 		Plugins:       releaseInfo.Plugins,
 		ChartImage:    releaseInfo.ChartImage,
+		PvcPolicy:     releaseInfo.PvcPolicy,
 	}
 }
 
@@ -104,11 +236,89 @@ type ReleaseRequestV2 struct {
 	Plugins        []*ReleasePlugin  `json:"plugins" description:"plugins"`
 	MetaInfoParams *MetaInfoParams   `json:"metaInfoParams" description:"meta info parameters"`
 	ChartImage     string            `json:"chartImage" description:"chart image url"`
+	ChartPath      string            `json:"chartPath" description:"local chart directory or archive path, only for development"`
+	Cluster        string            `json:"cluster" description:"target cluster registered in the cluster registry, empty means the default cluster"`
+
+	ListMergeStrategy string `json:"listMergeStrategy" description:"how to merge list config values on upgrade: replace(default) or append"`
+	NullToDelete      bool   `json:"nullToDelete" description:"delete previously set config value keys whose value is null on upgrade"`
+	// off by default for compatibility : installs proceed immediately even
+	// when the declared dependencies are not ready yet
+	WaitForDependencies bool `json:"waitForDependencies" description:"wait, up to the release timeout, for the declared dependencies to be ready before installing"`
+	// external dependencies are not walm releases : the output config values
+	// a walm-managed dependency would have published on its release config
+	// are declared inline instead, keyed by dependency chart name
+	ExternalDependencies map[string]map[string]interface{} `json:"externalDependencies" description:"map of dependency chart name and inline output config values, for dependencies not managed by walm"`
+	// when a dependency's output config keys do not match what the chart
+	// expects, the mapping renames them : the dependency's output value at
+	// the source key is injected at the target config values path
+	DependenciesConfigMapping map[string]map[string]string `json:"dependenciesConfigMapping" description:"per dependency mapping of output config key to the config values path the value is injected at"`
+	// what happens to the pvcs when the release is deleted without an explicit
+	// deletePvcs, empty means retain
+	PvcPolicy string `json:"pvcPolicy" description:"policy applied to the release pvcs on delete : retain(default), delete or delete-after-days:<days>"`
+}
+
+const (
+	ListMergeStrategyReplace = "replace"
+	ListMergeStrategyAppend  = "append"
+)
+
+type ReleaseConfigValues struct {
+	ConfigValues             map[string]interface{}            `json:"configValues" description:"user supplied config values"`
+	DependenciesConfigValues map[string]map[string]interface{} `json:"dependenciesConfigValues" description:"dependencies' output config values keyed by dependency name"`
+	OutputConfigValues       map[string]interface{}            `json:"outputConfigValues" description:"release's output config values published on its release config"`
+}
+
+// release event types recorded by the release tasks
+const (
+	ReleaseEventInstalled            = "Installed"
+	ReleaseEventUpgraded             = "Upgraded"
+	ReleaseEventPaused               = "Paused"
+	ReleaseEventRecovered            = "Recovered"
+	ReleaseEventDeleted              = "Deleted"
+	ReleaseEventInstallUpgradeFailed = "InstallUpgradeFailed"
+	ReleaseEventDeleteFailed         = "DeleteFailed"
+	ReleaseEventChartNotVerified     = "ChartNotVerified"
+	ReleaseEventThrottled            = "Throttled"
+	ReleaseEventWaitingDependency    = "WaitingForDependency"
+)
+
+// DefaultReleaseEventsLimit bounds how many events are kept per release.
+const DefaultReleaseEventsLimit = 50
+
+type ReleaseEvent struct {
+	Type      string    `json:"type" description:"event type"`
+	Message   string    `json:"message" description:"event message"`
+	TaskUuid  string    `json:"taskUuid" description:"uuid of the release task the event was recorded by"`
+	Timestamp time.Time `json:"timestamp" description:"when the event was recorded"`
+}
+
+type ReleaseEventList struct {
+	Num   int             `json:"num" description:"release event num"`
+	Items []*ReleaseEvent `json:"items" description:"release events, latest first"`
+}
+
+type ReleaseDeletePreview struct {
+	Name      string                       `json:"name" description:"release name"`
+	Namespace string                       `json:"namespace" description:"release namespace"`
+	Resources *k8s.ResourceSet             `json:"resources" description:"live resources to be deleted grouped by kind"`
+	Pvcs      []*k8s.PersistentVolumeClaim `json:"pvcs" description:"pvcs to be deleted when deletePvcs is set"`
+}
+
+// MaskSensitiveValues replaces the sensitive config values with masked ones.
+func (releaseConfigValues *ReleaseConfigValues) MaskSensitiveValues() {
+	releaseConfigValues.ConfigValues = util.MaskSensitiveValues(releaseConfigValues.ConfigValues)
+	for dependency, dependencyConfigValues := range releaseConfigValues.DependenciesConfigValues {
+		releaseConfigValues.DependenciesConfigValues[dependency] = util.MaskSensitiveValues(dependencyConfigValues)
+	}
+	releaseConfigValues.OutputConfigValues = util.MaskSensitiveValues(releaseConfigValues.OutputConfigValues)
 }
 
 type ReleaseInfoV2List struct {
 	Num   int              `json:"num" description:"release num"`
 	Items []*ReleaseInfoV2 `json:"items" description:"release infos"`
+	// the statuses are computed from the informer caches, which may still be
+	// syncing at startup
+	SyncInfo *k8s.CacheSyncInfo `json:"syncInfo" description:"freshness of the informer caches the release statuses are computed from"`
 }
 
 type ReleaseResources struct {
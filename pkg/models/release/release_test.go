@@ -0,0 +1,128 @@
+package release
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMoreSevereReadyReason(t *testing.T) {
+	tests := []struct {
+		reason1 string
+		reason2 string
+		result  string
+	}{
+		{
+			reason1: ReadyReasonPending,
+			reason2: ReadyReasonUnschedulable,
+			result:  ReadyReasonUnschedulable,
+		},
+		{
+			reason1: ReadyReasonCrashLoopBackOff,
+			reason2: ReadyReasonImagePullBackOff,
+			result:  ReadyReasonCrashLoopBackOff,
+		},
+		{
+			reason1: ReadyReasonPvcStorageClassMissing,
+			reason2: ReadyReasonUnschedulable,
+			result:  ReadyReasonPvcStorageClassMissing,
+		},
+		{
+			reason1: ReadyReasonPvcWaitForFirstConsumer,
+			reason2: ReadyReasonPending,
+			result:  ReadyReasonPvcWaitForFirstConsumer,
+		},
+		{
+			reason1: "",
+			reason2: ReadyReasonPending,
+			result:  ReadyReasonPending,
+		},
+		{
+			reason1: ReadyReasonPending,
+			reason2: "",
+			result:  ReadyReasonPending,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.result, MoreSevereReadyReason(test.reason1, test.reason2))
+	}
+}
+
+func TestParseDependencyValue(t *testing.T) {
+	tests := []struct {
+		value       string
+		releaseName string
+		soft        bool
+	}{
+		{
+			value:       "release-b",
+			releaseName: "release-b",
+			soft:        false,
+		},
+		{
+			value:       "release-b#soft",
+			releaseName: "release-b",
+			soft:        true,
+		},
+		{
+			value:       "#soft",
+			releaseName: "",
+			soft:        true,
+		},
+		{
+			value:       "",
+			releaseName: "",
+			soft:        false,
+		},
+	}
+
+	for _, test := range tests {
+		releaseName, soft := ParseDependencyValue(test.value)
+		assert.Equal(t, test.releaseName, releaseName)
+		assert.Equal(t, test.soft, soft)
+	}
+}
+
+func TestParsePvcPolicy(t *testing.T) {
+	tests := []struct {
+		pvcPolicy       string
+		deletePvcs      bool
+		deleteAfterDays int64
+		err             error
+	}{
+		{
+			pvcPolicy: "",
+		},
+		{
+			pvcPolicy: PvcPolicyRetain,
+		},
+		{
+			pvcPolicy:  PvcPolicyDelete,
+			deletePvcs: true,
+		},
+		{
+			pvcPolicy:       "delete-after-days:7",
+			deleteAfterDays: 7,
+		},
+		{
+			pvcPolicy: "delete-after-days:notanumber",
+			err:       errors.New(""),
+		},
+		{
+			pvcPolicy: "delete-after-days:-1",
+			err:       errors.New(""),
+		},
+		{
+			pvcPolicy: "notapolicy",
+			err:       errors.New(""),
+		},
+	}
+
+	for _, test := range tests {
+		deletePvcs, deleteAfterDays, err := ParsePvcPolicy(test.pvcPolicy)
+		assert.IsType(t, test.err, err)
+		assert.Equal(t, test.deletePvcs, deletePvcs)
+		assert.Equal(t, test.deleteAfterDays, deleteAfterDays)
+	}
+}
@@ -3,7 +3,15 @@ package release
 import "WarpCloud/walm/pkg/models/task"
 
 type ReleaseTask struct {
-	Name                 string        `json:"name" description:"release name"`
-	Namespace            string        `json:"namespace" description:"release namespace"`
-	LatestReleaseTaskSig *task.TaskSig `json:"latestReleaseTaskSignature" description:"latest release task signature"`
+	Name                 string                 `json:"name" description:"release name"`
+	Namespace            string                 `json:"namespace" description:"release namespace"`
+	LatestReleaseTaskSig *task.TaskSig          `json:"latestReleaseTaskSignature" description:"latest release task signature"`
+	PluginResults        []*ReleasePluginResult `json:"pluginResults" description:"results of release plugin hooks executed by the latest task"`
+}
+
+type ReleasePluginResult struct {
+	Name    string `json:"name" description:"plugin name"`
+	Type    string `json:"type" description:"hook type"`
+	Succeed bool   `json:"succeed" description:"whether the plugin hook succeeded"`
+	Message string `json:"message" description:"plugin hook result message"`
 }
@@ -0,0 +1,50 @@
+package schedule
+
+import (
+	"time"
+)
+
+const (
+	ReleaseKind = "release"
+	ProjectKind = "project"
+
+	// install covers both installing and upgrading, the underlying task is the same
+	InstallOperation = "install"
+	CreateOperation  = "create"
+	DeleteOperation  = "delete"
+)
+
+// ScheduledOperation records a mutation walm will submit at the appointed
+// time instead of right away, for maintenance window deployments. It is
+// stored in redis, so pending operations survive walm restarts and can be
+// cancelled before they fire.
+type ScheduledOperation struct {
+	Uuid      string `json:"uuid" description:"unique id of the scheduled operation, used to cancel it"`
+	Namespace string `json:"namespace" description:"namespace of the release or project the operation targets"`
+	Name      string `json:"name" description:"name of the release or project the operation targets"`
+	Kind      string `json:"kind" description:"kind of the target : release or project"`
+	Operation string `json:"operation" description:"operation to submit : install, create or delete"`
+	// the original mutation request, replayed as is when the operation fires
+	Args        string    `json:"args" description:"json encoded args of the operation"`
+	TimeoutSec  int64     `json:"timeoutSec" description:"timeout of the task submitted when the operation fires"`
+	ScheduledAt time.Time `json:"scheduledAt" description:"time the operation will be submitted at"`
+	CreateTime  time.Time `json:"createTime" description:"time the operation was scheduled"`
+	Principal   string    `json:"principal" description:"identity which scheduled the operation"`
+	RequestId   string    `json:"requestId" description:"id of the request which scheduled the operation"`
+}
+
+type ScheduledOperationList struct {
+	Num   int                   `json:"num" description:"scheduled operation number"`
+	Items []*ScheduledOperation `json:"items" description:"scheduled operation list"`
+}
+
+// ScheduledDeleteReleaseArgs are the args of a scheduled release delete.
+type ScheduledDeleteReleaseArgs struct {
+	DeletePvcs bool `json:"deletePvcs"`
+}
+
+// ScheduledDeleteProjectArgs are the args of a scheduled project delete.
+type ScheduledDeleteProjectArgs struct {
+	DeletePvcs bool `json:"deletePvcs"`
+	Fast       bool `json:"fast"`
+}
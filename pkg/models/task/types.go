@@ -5,4 +5,13 @@ type TaskSig struct {
 	Name        string              `json:"name" description:"task name"`
 	Arg         string              `json:"arg" description:"task arg"`
 	TimeoutSec  int64               `json:"timeout_sec" description:"task timeout(sec)"`
+	RequestId   string              `json:"requestId,omitempty" description:"id of the request that submitted the task"`
+}
+
+// TaskStateInfo is the client facing view of a task state, polled by uuid.
+type TaskStateInfo struct {
+	Finished bool   `json:"finished" description:"whether the task is finished"`
+	Success  bool   `json:"success" description:"whether the task succeeded"`
+	Timeout  bool   `json:"timeout" description:"whether the task timed out"`
+	ErrorMsg string `json:"errorMsg,omitempty" description:"task error message"`
 }
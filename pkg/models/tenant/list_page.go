@@ -0,0 +1,56 @@
+package tenant
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+)
+
+type TenantInfoPage struct {
+	Num      int           `json:"num" description:"num of tenants matching the query"`
+	Continue string        `json:"continue" description:"continue token of the next page, empty for the last page"`
+	Items    []*TenantInfo `json:"items" description:"tenant infos of the page"`
+}
+
+// BuildTenantInfoPage sorts and paginates the given tenants. The continue token
+// encodes the name of the last tenant of the page, so paging is stable across
+// walm replicas.
+func BuildTenantInfoPage(tenantInfos []*TenantInfo, limit int, continueToken string) (*TenantInfoPage, error) {
+	sort.SliceStable(tenantInfos, func(i, j int) bool {
+		return tenantInfos[i].TenantName < tenantInfos[j].TenantName
+	})
+
+	page := &TenantInfoPage{
+		Num:   len(tenantInfos),
+		Items: []*TenantInfo{},
+	}
+
+	if continueToken != "" {
+		var err error
+		tenantInfos, err = skipToContinueTenant(tenantInfos, continueToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limit > 0 && len(tenantInfos) > limit {
+		tenantInfos = tenantInfos[:limit]
+		page.Continue = base64.StdEncoding.EncodeToString([]byte(tenantInfos[len(tenantInfos)-1].TenantName))
+	}
+
+	page.Items = append(page.Items, tenantInfos...)
+	return page, nil
+}
+
+func skipToContinueTenant(tenantInfos []*TenantInfo, continueToken string) ([]*TenantInfo, error) {
+	lastName, err := base64.StdEncoding.DecodeString(continueToken)
+	if err != nil {
+		return nil, fmt.Errorf("continue token %s is not valid : %s", continueToken, err.Error())
+	}
+	for index, tenantInfo := range tenantInfos {
+		if tenantInfo.TenantName == string(lastName) {
+			return tenantInfos[index+1:], nil
+		}
+	}
+	return nil, fmt.Errorf("continue token %s is expired : tenant %s is not found", continueToken, string(lastName))
+}
@@ -0,0 +1,69 @@
+package tenant
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildTenantInfoPage(t *testing.T) {
+	buildTestTenants := func() []*TenantInfo {
+		return []*TenantInfo{
+			{
+				TenantName: "test-tenant2",
+			},
+			{
+				TenantName: "test-tenant1",
+			},
+		}
+	}
+
+	tests := []struct {
+		limit         int
+		continueToken string
+		err           error
+		expectedPage  *TenantInfoPage
+	}{
+		{
+			expectedPage: &TenantInfoPage{
+				Num:   2,
+				Items: []*TenantInfo{buildTestTenants()[1], buildTestTenants()[0]},
+			},
+		},
+		{
+			limit: 1,
+			expectedPage: &TenantInfoPage{
+				Num:      2,
+				Continue: base64.StdEncoding.EncodeToString([]byte("test-tenant1")),
+				Items:    []*TenantInfo{buildTestTenants()[1]},
+			},
+		},
+		{
+			limit:         1,
+			continueToken: base64.StdEncoding.EncodeToString([]byte("test-tenant1")),
+			expectedPage: &TenantInfoPage{
+				Num:   2,
+				Items: []*TenantInfo{buildTestTenants()[0]},
+			},
+		},
+		{
+			continueToken: "not base64 !",
+			err:           assert.AnError,
+		},
+		{
+			continueToken: base64.StdEncoding.EncodeToString([]byte("expired-tenant")),
+			err:           assert.AnError,
+		},
+	}
+
+	for _, test := range tests {
+		page, err := BuildTenantInfoPage(buildTestTenants(), test.limit, test.continueToken)
+		if test.err != nil {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedPage, page)
+	}
+}
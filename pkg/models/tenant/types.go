@@ -1,9 +1,47 @@
 package tenant
 
+import (
+	"WarpCloud/walm/pkg/models/k8s"
+)
+
 const (
 	MultiTenantLabelKey = "multi-tenant"
+
+	TemplateVersionAnnotationKey = "tenant.walm.io/template-version"
+	MemberLabelKey               = "tenant.walm.io/member"
+	ManagedKeysAnnotationKey     = "tenant.walm.io/managed-keys"
+	// comma separated storage class names, read by the admission webhook
+	StorageClassWhitelistAnnotationKey = "tenant.walm.io/storage-class-whitelist"
+	// json encoded DeclaredTenantObjects, read by the readiness computation
+	DeclaredObjectsAnnotationKey = "tenant.walm.io/declared-objects"
+	// max release tasks running at once in the namespace, read by the release
+	// task runners, overrides the global concurrency budget
+	MaxConcurrentReleaseTasksAnnotationKey = "tenant.walm.io/max-concurrent-release-tasks"
+
+	TenantRoleViewer = "viewer"
+	TenantRoleEditor = "editor"
+	TenantRoleAdmin  = "admin"
+
+	MemberKindUser  = "User"
+	MemberKindGroup = "Group"
 )
 
+// TenantRoleClusterRoles maps the fixed tenant member roles to the preinstalled
+// cluster roles backing them.
+var TenantRoleClusterRoles = map[string]string{
+	TenantRoleViewer: "walm-tenant-viewer",
+	TenantRoleEditor: "walm-tenant-editor",
+	TenantRoleAdmin:  "walm-tenant-admin",
+}
+
+// DeclaredTenantObjects records the quota hard limits and limit range names the
+// tenant was declared with, so the readiness computation can compare the cluster
+// state against what was requested.
+type DeclaredTenantObjects struct {
+	ResourceQuotas map[string]map[string]string `json:"resourceQuotas,omitempty"`
+	LimitRanges    []string                     `json:"limitRanges,omitempty"`
+}
+
 type TenantInfoList struct {
 	Items []*TenantInfo `json:"items" description:"tenant list"`
 }
@@ -18,20 +56,103 @@ type TenantInfo struct {
 	TenantQuotas          []*TenantQuota          `json:"tenantQuotas" description:"quotas of the tenant"`
 	MultiTenant           bool                    `json:"multiTenant" description:"multi tenant"`
 	Ready                 bool                    `json:"ready" description:"tenant ready status"`
+	NotReadyMessage       string                  `json:"notReadyMessage,omitempty" description:"names the missing or mismatched object when the tenant is not ready"`
 	UnifyUnitTenantQuotas []*UnifyUnitTenantQuota `json:"unifyUnitTenantQuotas" description:"quotas of the tenant with unified unit"`
+	TenantLimitRanges     []*TenantLimitRange     `json:"tenantLimitRanges" description:"limit ranges of the tenant"`
+	TenantMembers         []*TenantMember         `json:"tenantMembers" description:"members of the tenant derived from walm managed role bindings"`
+	AggregatedTenantQuota *UnifyUnitTenantQuota   `json:"aggregatedTenantQuota,omitempty" description:"quota aggregated across all resource quotas : hard takes the minimum per resource"`
+	QuotaPressures        []*TenantQuotaPressure  `json:"quotaPressures,omitempty" description:"resources whose usage crossed the pressure threshold"`
+	StorageClassWhitelist []string                `json:"storageClassWhitelist,omitempty" description:"storage classes the tenant is allowed to use, empty means no restriction"`
+	ReservedIncomplete    bool                    `json:"reservedIncomplete,omitempty" description:"true when the reserved figures could not be computed for all releases"`
+}
+
+// CreateTenantResult reports what a create call did, or would do in dry run
+// mode : the objects are the ones applied to the cluster.
+type CreateTenantResult struct {
+	Updated        bool                 `json:"updated" description:"the namespace already existed and was reconciled instead of created"`
+	Namespace      *k8s.Namespace       `json:"namespace" description:"namespace applied"`
+	ResourceQuotas []*k8s.ResourceQuota `json:"resourceQuotas" description:"resource quotas applied"`
+	LimitRanges    []*k8s.LimitRange    `json:"limitRanges" description:"limit ranges applied"`
+}
+
+type TenantServiceInfoList struct {
+	Items []*TenantServiceInfo `json:"items" description:"tenant service list"`
+}
+
+// TenantServiceInfo describes how a service in the tenant namespace can be reached.
+type TenantServiceInfo struct {
+	ServiceName string                  `json:"serviceName" description:"service name"`
+	ServiceType string                  `json:"serviceType" description:"service type"`
+	ClusterIp   string                  `json:"clusterIp" description:"service cluster ip"`
+	Ports       []*TenantServicePort    `json:"ports" description:"service ports"`
+	Ingresses   []*TenantServiceIngress `json:"ingresses" description:"ingresses backing the service"`
+	ReleaseName string                  `json:"releaseName" description:"release owning the service"`
+}
+
+type TenantServicePort struct {
+	Name     string `json:"name" description:"service port name"`
+	Protocol string `json:"protocol" description:"service port protocol"`
+	Port     int32  `json:"port" description:"service port"`
+	NodePort int32  `json:"nodePort,omitempty" description:"node port"`
+}
+
+type TenantServiceIngress struct {
+	Host string `json:"host" description:"ingress host"`
+	Path string `json:"path" description:"ingress path"`
+}
+
+type TenantQuotaPressure struct {
+	QuotaName    string  `json:"quotaName" description:"quota name"`
+	ResourceName string  `json:"resourceName" description:"resource under pressure"`
+	Hard         float64 `json:"hard" description:"hard limit with unified unit"`
+	Used         float64 `json:"used" description:"used with unified unit"`
+	Ratio        float64 `json:"ratio" description:"used divided by hard"`
+}
+
+type TenantMember struct {
+	Kind string `json:"kind" description:"member kind : User or Group"`
+	Name string `json:"name" description:"member name"`
+	Role string `json:"role" description:"member role : viewer, editor or admin"`
+}
+
+type TenantLimitRange struct {
+	LimitRangeName string            `json:"limitRangeName" description:"limit range name"`
+	DefaultLimit   map[string]string `json:"defaultLimit" description:"default limit per container"`
+	DefaultRequest map[string]string `json:"defaultRequest" description:"default request per container"`
+	Max            map[string]string `json:"max" description:"max usage per container"`
+	Min            map[string]string `json:"min" description:"min usage per container"`
 }
 
 type UnifyUnitTenantQuota struct {
 	QuotaName string                    `json:"quotaName" description:"quota name"`
 	Hard      *UnifyUnitTenantQuotaInfo `json:"hard" description:"quota hard limit"`
 	Used      *UnifyUnitTenantQuotaInfo `json:"used" description:"quota used"`
+	Reserved  *UnifyUnitTenantQuotaInfo `json:"reserved,omitempty" description:"resources declared by the releases in the namespace, admitted or not"`
 }
 
 //Tenant Params Info
 type TenantParams struct {
-	TenantAnnotations map[string]string    `json:"tenantAnnotations"  description:"annotations of the tenant"`
-	TenantLabels      map[string]string    `json:"tenantLabels"  description:"labels of the tenant"`
-	TenantQuotas      []*TenantQuotaParams `json:"tenantQuotas" description:"quotas of the tenant"`
+	TenantAnnotations         map[string]string         `json:"tenantAnnotations"  description:"annotations of the tenant"`
+	TenantLabels              map[string]string         `json:"tenantLabels"  description:"labels of the tenant"`
+	TenantQuotas              []*TenantQuotaParams      `json:"tenantQuotas" description:"quotas of the tenant"`
+	TenantLimitRanges         []*TenantLimitRangeParams `json:"tenantLimitRanges" description:"limit ranges of the tenant"`
+	TenantMembers             []*TenantMemberParams     `json:"tenantMembers" description:"members of the tenant"`
+	StorageClassWhitelist     []string                  `json:"storageClassWhitelist" description:"storage classes the tenant is allowed to use, empty means no restriction"`
+	MaxConcurrentReleaseTasks int64                     `json:"maxConcurrentReleaseTasks" description:"max release tasks running at once in the tenant namespace, zero keeps the global budget"`
+}
+
+type TenantMemberParams struct {
+	Kind string `json:"kind" description:"member kind : User or Group"`
+	Name string `json:"name" description:"member name"`
+	Role string `json:"role" description:"member role : viewer, editor or admin"`
+}
+
+type TenantLimitRangeParams struct {
+	LimitRangeName string            `json:"limitRangeName" description:"limit range name"`
+	DefaultLimit   map[string]string `json:"defaultLimit" description:"default limit per container"`
+	DefaultRequest map[string]string `json:"defaultRequest" description:"default request per container"`
+	Max            map[string]string `json:"max" description:"max usage per container"`
+	Min            map[string]string `json:"min" description:"min usage per container"`
 }
 
 type TenantQuotaParams struct {
@@ -43,23 +164,36 @@ type TenantQuota struct {
 	QuotaName string           `json:"quotaName" description:"quota name"`
 	Hard      *TenantQuotaInfo `json:"hard" description:"quota hard limit"`
 	Used      *TenantQuotaInfo `json:"used" description:"quota used"`
+	Reserved  *TenantQuotaInfo `json:"reserved,omitempty" description:"resources declared by the releases in the namespace, admitted or not"`
 }
 
 //Quota Info
 type TenantQuotaInfo struct {
-	LimitCpu        string `json:"limitCpu"  description:"requests of the CPU"`
-	LimitMemory     string `json:"limitMemory"  description:"limit of the memory"`
-	RequestsCPU     string `json:"requestsCpu"  description:"requests of the CPU"`
-	RequestsMemory  string `json:"requestsMemory"  description:"requests of the memory"`
-	RequestsStorage string `json:"requestsStorage"  description:"requests of the storage"`
-	Pods            string `json:"pods" description:"num of the pods"`
+	LimitCpu                 string            `json:"limitCpu"  description:"requests of the CPU"`
+	LimitMemory              string            `json:"limitMemory"  description:"limit of the memory"`
+	RequestsCPU              string            `json:"requestsCpu"  description:"requests of the CPU"`
+	RequestsMemory           string            `json:"requestsMemory"  description:"requests of the memory"`
+	RequestsStorage          string            `json:"requestsStorage"  description:"requests of the storage"`
+	RequestsEphemeralStorage string            `json:"requestsEphemeralStorage,omitempty" description:"requests of the ephemeral storage"`
+	Pods                     string            `json:"pods" description:"num of the pods"`
+	Services                 string            `json:"services,omitempty" description:"num of the services"`
+	ServicesNodePorts        string            `json:"servicesNodePorts,omitempty" description:"num of the service node ports"`
+	PersistentVolumeClaims   string            `json:"persistentVolumeClaims,omitempty" description:"num of the persistent volume claims"`
+	ConfigMaps               string            `json:"configMaps,omitempty" description:"num of the config maps"`
+	ExtendedResources        map[string]string `json:"extendedResources,omitempty" description:"extended resources, e.g. requests.nvidia.com/gpu"`
 }
 
 type UnifyUnitTenantQuotaInfo struct {
-	LimitCpu        float64 `json:"limitCpu"  description:"requests of the CPU"`
-	LimitMemory     int64   `json:"limitMemory"  description:"limit of the memory"`
-	RequestsCPU     float64 `json:"requestsCpu"  description:"requests of the CPU"`
-	RequestsMemory  int64   `json:"requestsMemory"  description:"requests of the memory"`
-	RequestsStorage int64   `json:"requestsStorage"  description:"requests of the storage"`
-	Pods            int64   `json:"pods" description:"num of the pods"`
+	LimitCpu                 float64           `json:"limitCpu"  description:"requests of the CPU"`
+	LimitMemory              int64             `json:"limitMemory"  description:"limit of the memory"`
+	RequestsCPU              float64           `json:"requestsCpu"  description:"requests of the CPU"`
+	RequestsMemory           int64             `json:"requestsMemory"  description:"requests of the memory"`
+	RequestsStorage          int64             `json:"requestsStorage"  description:"requests of the storage"`
+	RequestsEphemeralStorage int64             `json:"requestsEphemeralStorage,omitempty" description:"requests of the ephemeral storage"`
+	Pods                     int64             `json:"pods" description:"num of the pods"`
+	Services                 int64             `json:"services,omitempty" description:"num of the services"`
+	ServicesNodePorts        int64             `json:"servicesNodePorts,omitempty" description:"num of the service node ports"`
+	PersistentVolumeClaims   int64             `json:"persistentVolumeClaims,omitempty" description:"num of the persistent volume claims"`
+	ConfigMaps               int64             `json:"configMaps,omitempty" description:"num of the config maps"`
+	ExtendedResources        map[string]string `json:"extendedResources,omitempty" description:"extended resources with raw quantities"`
 }
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"WarpCloud/walm/pkg/auth"
 	"WarpCloud/walm/pkg/k8s"
 	"github.com/emicklei/go-restful"
 	"WarpCloud/walm/pkg/models/http"
@@ -9,6 +10,7 @@ import (
 	"strconv"
 	"github.com/sirupsen/logrus"
 	httpUtils "WarpCloud/walm/pkg/util/http"
+	errorModel "WarpCloud/walm/pkg/models/error"
 	"fmt"
 )
 
@@ -51,6 +53,19 @@ func RegisterPodHandler(k8sCache k8s.Cache, k8sOperator k8s.Operator) *restful.W
 		Returns(200, "OK", "").
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{namespace}/name/{pod}/detail").To(handler.GetPodDetail).
+		Doc("获取Pod详情(事件和容器日志)").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("pod", "pod名字").DataType("string")).
+		Param(ws.QueryParameter("container", "container名字").DataType("string")).
+		Param(ws.QueryParameter("previous", "是否返回上一个container实例的日志").DataType("boolean")).
+		Param(ws.QueryParameter("tail", "最后几行").DataType("integer")).
+		Writes(k8sModel.PodDetail{}).
+		Returns(200, "OK", k8sModel.PodDetail{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.POST("/{namespace}/name/{pod}/restart").To(handler.RestartPod).
 		Doc("重启Pod").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -65,7 +80,12 @@ func RegisterPodHandler(k8sCache k8s.Cache, k8sOperator k8s.Operator) *restful.W
 func (handler *PodHandler)RestartPod(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("pod")
-	err := handler.k8sOperator.RestartPod(namespace, name)
+	k8sOperator, err := handler.k8sOperator.WithCaller(auth.GetRequestPrincipal(request), auth.GetRequestPrincipalGroups(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build kube client impersonating the caller : %s", err.Error()))
+		return
+	}
+	err = k8sOperator.RestartPod(namespace, name)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to restart pod %s: %s", name, err.Error()))
 		return
@@ -101,6 +121,45 @@ func (handler *PodHandler)GetPodLogs(request *restful.Request, response *restful
 	response.WriteEntity(logs)
 }
 
+func (handler *PodHandler)GetPodDetail(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("pod")
+	containerName := request.QueryParameter("container")
+	previous, err := getPreviousQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param previous is not valid : %s", err.Error()))
+		return
+	}
+	tailLines, err := getTailLinesQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param tail is not valid : %s", err.Error()))
+		return
+	}
+
+	podDetail, err := handler.k8sCache.GetPodDetail(namespace, name, containerName, previous, tailLines)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("pod %s/%s is not found", namespace, name))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get pod detail %s: %s", name, err.Error()))
+		return
+	}
+	response.WriteEntity(podDetail)
+}
+
+func getPreviousQueryParam(request *restful.Request) (previous bool, err error) {
+	previousStr := request.QueryParameter("previous")
+	if len(previousStr) > 0 {
+		previous, err = strconv.ParseBool(previousStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter previous %s : %s", previousStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
 func getTailLinesQueryParam(request *restful.Request) (tailLines int64, err error) {
 	tailLinesStr := request.QueryParameter("tail")
 	if len(tailLinesStr) > 0 {
@@ -7,4 +7,10 @@ type Cache interface {
 	GetProjectTasks(namespace string) ([]*project.ProjectTask, error)
 	CreateOrUpdateProjectTask(projectTask *project.ProjectTask) error
 	DeleteProjectTask(namespace, name string) (error)
+	// GetProjectWebhooks returns the webhooks configured for the project,
+	// an empty list when none are configured.
+	GetProjectWebhooks(namespace, name string) ([]*project.ProjectWebhook, error)
+	// SetProjectWebhooks replaces the webhooks of the project, an empty list
+	// removes them.
+	SetProjectWebhooks(namespace, name string, webhooks []*project.ProjectWebhook) error
 }
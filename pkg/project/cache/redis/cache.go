@@ -3,6 +3,7 @@ package redis
 import (
 	"WarpCloud/walm/pkg/redis"
 	"github.com/sirupsen/logrus"
+	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/models/project"
 	"encoding/json"
 )
@@ -71,6 +72,41 @@ func (cache *Cache) DeleteProjectTask(namespace, name string) error {
 	return nil
 }
 
+func (cache *Cache) GetProjectWebhooks(namespace, name string) ([]*project.ProjectWebhook, error) {
+	webhooksStr, err := cache.redis.GetFieldValue(redis.WalmProjectWebhooksKey, namespace, name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			return []*project.ProjectWebhook{}, nil
+		}
+		return nil, err
+	}
+
+	webhooks := []*project.ProjectWebhook{}
+	err = json.Unmarshal([]byte(webhooksStr), &webhooks)
+	if err != nil {
+		logrus.Errorf("failed to unmarshal project webhooks %s : %s", webhooksStr, err.Error())
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (cache *Cache) SetProjectWebhooks(namespace, name string, webhooks []*project.ProjectWebhook) error {
+	if len(webhooks) == 0 {
+		err := cache.redis.DeleteField(redis.WalmProjectWebhooksKey, namespace, name)
+		if err != nil && !errorModel.IsNotFoundError(err) {
+			return err
+		}
+		return nil
+	}
+
+	err := cache.redis.SetFieldValues(redis.WalmProjectWebhooksKey, map[string]interface{}{redis.BuildFieldName(namespace, name): webhooks})
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("succeed to set project webhooks of %s/%s to redis", namespace, name)
+	return nil
+}
+
 func NewProjectCache(redis redis.Redis) *Cache {
 	return &Cache{
 		redis: redis,
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"encoding/json"
 	"github.com/stretchr/testify/mock"
+	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/models/project"
 )
 
@@ -186,3 +187,109 @@ func TestCache_DeleteProjectTask(t *testing.T) {
 	}
 }
 
+
+func TestCache_GetProjectWebhooks(t *testing.T) {
+	var mockRedis *mocks.Redis
+	var mockCache *Cache
+
+	refreshMocks := func() {
+		mockRedis = &mocks.Redis{}
+		mockCache = NewProjectCache(mockRedis)
+	}
+
+	tests := []struct {
+		initMock func()
+		webhooks []*project.ProjectWebhook
+		err      error
+	}{
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("GetFieldValue", redis.WalmProjectWebhooksKey, "testns", "testnm").Return("", errors.New(""))
+			},
+			err: errors.New(""),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("GetFieldValue", redis.WalmProjectWebhooksKey, "testns", "testnm").Return("", errorModel.NotFoundError{})
+			},
+			webhooks: []*project.ProjectWebhook{},
+			err:      nil,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("GetFieldValue", redis.WalmProjectWebhooksKey, "testns", "testnm").Return("notvalid", nil)
+			},
+			err: &json.SyntaxError{},
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("GetFieldValue", redis.WalmProjectWebhooksKey, "testns", "testnm").Return("[{\"url\":\"http://test\"}]", nil)
+			},
+			webhooks: []*project.ProjectWebhook{{Url: "http://test"}},
+			err:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		test.initMock()
+		webhooks, err := mockCache.GetProjectWebhooks("testns", "testnm")
+		assert.IsType(t, test.err, err)
+		if test.err == nil {
+			assert.Equal(t, test.webhooks, webhooks)
+		}
+
+		mockRedis.AssertExpectations(t)
+	}
+}
+
+func TestCache_SetProjectWebhooks(t *testing.T) {
+	var mockRedis *mocks.Redis
+	var mockCache *Cache
+
+	refreshMocks := func() {
+		mockRedis = &mocks.Redis{}
+		mockCache = NewProjectCache(mockRedis)
+	}
+
+	tests := []struct {
+		initMock func()
+		webhooks []*project.ProjectWebhook
+		err      error
+	}{
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("DeleteField", redis.WalmProjectWebhooksKey, "testns", "testnm").Return(errorModel.NotFoundError{})
+			},
+			err: nil,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("SetFieldValues", redis.WalmProjectWebhooksKey, mock.Anything).Return(errors.New(""))
+			},
+			webhooks: []*project.ProjectWebhook{{Url: "http://test"}},
+			err:      errors.New(""),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("SetFieldValues", redis.WalmProjectWebhooksKey, mock.Anything).Return(nil)
+			},
+			webhooks: []*project.ProjectWebhook{{Url: "http://test"}},
+			err:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		test.initMock()
+		err := mockCache.SetProjectWebhooks("testns", "testnm", test.webhooks)
+		assert.IsType(t, test.err, err)
+
+		mockRedis.AssertExpectations(t)
+	}
+}
@@ -1,15 +1,26 @@
 package http
 
 import (
+	"WarpCloud/walm/pkg/auth"
 	"WarpCloud/walm/pkg/project"
 	"github.com/emicklei/go-restful"
 	"WarpCloud/walm/pkg/models/http"
 	"github.com/emicklei/go-restful-openapi"
 	projectModel "WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/release"
+	taskModel "WarpCloud/walm/pkg/models/task"
+	walmRedis "WarpCloud/walm/pkg/redis"
+	"WarpCloud/walm/pkg/requestid"
+	"WarpCloud/walm/pkg/schedule"
+	"WarpCloud/walm/pkg/util"
 	httpUtils "WarpCloud/walm/pkg/util/http"
 	"fmt"
+	"strings"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"time"
 )
 
 const (
@@ -18,10 +29,55 @@ const (
 
 type ProjectHandler struct {
 	usecase project.UseCase
+	redis   walmRedis.Redis
+	// optional, mutation requests with a scheduledAt query param are recorded
+	// for later execution when it is set
+	scheduleStore schedule.Store
 }
 
-func NewProjectHandler(usecase project.UseCase) *ProjectHandler {
-	return &ProjectHandler{usecase: usecase}
+func NewProjectHandler(usecase project.UseCase, redis walmRedis.Redis) *ProjectHandler {
+	return &ProjectHandler{usecase: usecase, redis: redis}
+}
+
+// SetScheduleStore enables scheduling the mutating operations for later
+// execution through the scheduledAt query param. The store stays optional,
+// without it scheduled requests are rejected.
+func (handler *ProjectHandler) SetScheduleStore(scheduleStore schedule.Store) {
+	handler.scheduleStore = scheduleStore
+}
+
+// scheduleOperation records the operation in the schedule store instead of
+// submitting it now, the stored args being the original mutation request,
+// replayed as is when the operation fires. The created operation is written
+// to the response, its uuid is what cancelling it takes.
+func (handler *ProjectHandler) scheduleOperation(request *restful.Request, response *restful.Response,
+	namespace, name, operation string, args interface{}, timeoutSec int64, scheduledAt time.Time) {
+	if handler.scheduleStore == nil {
+		httpUtils.WriteErrorResponse(response, -1, "scheduled operations are not enabled")
+		return
+	}
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to marshal scheduled operation args : %s", err.Error()))
+		return
+	}
+	scheduledOperation := &scheduleModel.ScheduledOperation{
+		Namespace:   namespace,
+		Name:        name,
+		Kind:        scheduleModel.ProjectKind,
+		Operation:   operation,
+		Args:        string(argsBytes),
+		TimeoutSec:  timeoutSec,
+		ScheduledAt: scheduledAt,
+		Principal:   auth.GetRequestPrincipal(request),
+		RequestId:   requestid.GetRequestId(request),
+	}
+	err = handler.scheduleStore.CreateScheduledOperation(scheduledOperation)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to create scheduled operation : %s", err.Error()))
+		return
+	}
+	response.WriteEntity(scheduledOperation)
 }
 
 func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
@@ -36,6 +92,9 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 	ws.Route(ws.GET("/").To(handler.ListProject).
 		Doc("获取所有Project列表").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.QueryParameter("detail", "是否返回完整的项目信息(默认只返回摘要, release只含名字和chart信息)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("fields", "返回字段过滤, 逗号分隔, 例如fields=name,ready").DataType("string").Required(false)).
 		Writes(projectModel.ProjectInfoList{}).
 		Returns(200, "OK", projectModel.ProjectInfoList{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -44,6 +103,9 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Doc("获取对应租户的Project列表").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.QueryParameter("detail", "是否返回完整的项目信息(默认只返回摘要, release只含名字和chart信息)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("fields", "返回字段过滤, 逗号分隔, 例如fields=name,ready").DataType("string").Required(false)).
 		Writes(projectModel.ProjectInfoList{}).
 		Returns(200, "OK", projectModel.ProjectInfoList{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -53,10 +115,48 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
 		Param(ws.PathParameter("project", "Project名字").DataType("string")).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
 		Returns(200, "OK", projectModel.ProjectInfo{}).
 		Returns(404, "Not Found", http.ErrorMessageResponse{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{namespace}/name/{project}/events").To(handler.StreamProjectEvents).
+		Doc("订阅Project状态变化事件(SSE), 先返回快照, 之后推送增量事件").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("project", "Project名字").DataType("string")).
+		Produces("text/event-stream").
+		Returns(200, "OK", projectModel.ProjectEvent{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{namespace}/name/{project}/depgraph").To(handler.GetProjectDepGraph).
+		Doc("获取Project的Release依赖关系图(graphviz dot格式), 用于调试").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("project", "Project名字").DataType("string")).
+		Produces("text/vnd.graphviz").
+		Returns(200, "OK", "").
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{namespace}/name/{project}/webhooks").To(handler.GetProjectWebhooks).
+		Doc("获取Project的事件Webhook列表").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("project", "Project名字").DataType("string")).
+		Returns(200, "OK", []projectModel.ProjectWebhook{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.PUT("/{namespace}/name/{project}/webhooks").To(handler.SetProjectWebhooks).
+		Doc("设置Project的事件Webhook列表, 覆盖全局配置的Webhook, 空列表表示恢复全局配置").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("project", "Project名字").DataType("string")).
+		Reads([]projectModel.ProjectWebhook{}).
+		Returns(200, "OK", nil).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.POST("/{namespace}/name/{project}").To(handler.CreateProject).
 		Doc("创建一个Project").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -64,8 +164,9 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Param(ws.PathParameter("project", "Project名字").DataType("string")).
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
+		Param(ws.QueryParameter("scheduledAt", "定时执行时间(RFC3339格式), 设置后该操作将被记录, 到时间后才执行").DataType("string").Required(false)).
 		Reads(projectModel.ProjectParams{}).
-		Returns(200, "OK", nil).
+		Returns(200, "OK", taskModel.TaskSig{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.DELETE("/{namespace}/name/{project}").To(handler.DeleteProject).
@@ -76,7 +177,10 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
 		Param(ws.QueryParameter("deletePvcs", "是否删除Project Releases管理的statefulSet关联的所有pvc").DataType("boolean").Required(false)).
-		Returns(200, "OK", nil).
+		Param(ws.QueryParameter("fast", "是否快速删除, 不按照依赖关系顺序删除Release").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("dryRun", "是否只预览将被删除的资源, 不真正删除").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("scheduledAt", "定时执行时间(RFC3339格式), 设置后该操作将被记录, 到时间后才执行").DataType("string").Required(false)).
+		Returns(200, "OK", taskModel.TaskSig{}).
 		Returns(500, "Server Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.POST("/{namespace}/name/{project}/instance").To(handler.AddReleaseInProject).
@@ -87,7 +191,7 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
 		Reads(release.ReleaseRequestV2{}).
-		Returns(200, "OK", nil).
+		Returns(200, "OK", taskModel.TaskSig{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.PUT("/{namespace}/name/{project}/instance").To(handler.UpgradeReleaseInProject).
@@ -98,7 +202,7 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
 		Reads(release.ReleaseRequestV2{}).
-		Returns(200, "OK", nil).
+		Returns(200, "OK", taskModel.TaskSig{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.POST("/{namespace}/name/{project}/project").To(handler.AddReleasesInProject).
@@ -109,7 +213,7 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
 		Reads(projectModel.ProjectParams{}).
-		Returns(200, "OK", nil).
+		Returns(200, "OK", taskModel.TaskSig{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.DELETE("/{namespace}/name/{project}/instance/{release}").To(handler.DeleteReleaseInProject).
@@ -121,7 +225,7 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
 		Param(ws.QueryParameter("deletePvcs", "是否删除release管理的statefulSet关联的所有pvc").DataType("boolean").Required(false)).
-		Returns(200, "OK", nil).
+		Returns(200, "OK", taskModel.TaskSig{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	return ws
@@ -129,24 +233,92 @@ func RegisterProjectHandler(handler *ProjectHandler) *restful.WebService {
 }
 
 func (handler *ProjectHandler) ListProject(request *restful.Request, response *restful.Response) {
-	projectList, err := handler.usecase.ListProjects("")
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
+	detail, err := httpUtils.GetDetailQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param detail value is not valid : %s", err.Error()))
+		return
+	}
+	projectList, err := handler.usecase.ListProjects("", detail)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response,-1, fmt.Sprintf("failed to list all projects : %s", err.Error()))
 		return
 	}
+	if !reveal {
+		for _, projectInfo := range projectList.Items {
+			projectInfo.MaskSensitiveValues()
+		}
+	}
+	if writeProjectListPrunedToFields(request, response, projectList) {
+		return
+	}
 	response.WriteEntity(projectList)
 }
 
 func (handler *ProjectHandler)ListProjectByNamespace(request *restful.Request, response *restful.Response) {
 	tenantName := request.PathParameter("namespace")
-	projectList, err := handler.usecase.ListProjects(tenantName)
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
+	detail, err := httpUtils.GetDetailQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param detail value is not valid : %s", err.Error()))
+		return
+	}
+	projectList, err := handler.usecase.ListProjects(tenantName, detail)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response,-1, fmt.Sprintf("failed to list projects in tenant %s : %s", tenantName, err.Error()))
 		return
 	}
+	if !reveal {
+		for _, projectInfo := range projectList.Items {
+			projectInfo.MaskSensitiveValues()
+		}
+	}
+	if writeProjectListPrunedToFields(request, response, projectList) {
+		return
+	}
 	response.WriteEntity(projectList)
 }
 
+// writeProjectListPrunedToFields writes the list pruned to the fields query
+// param and reports whether it handled the response.
+func writeProjectListPrunedToFields(request *restful.Request, response *restful.Response, projectList *projectModel.ProjectInfoList) bool {
+	fieldsStr := request.QueryParameter("fields")
+	if fieldsStr == "" {
+		return false
+	}
+	fields := strings.Split(fieldsStr, ",")
+	filteredList := &projectModel.ProjectInfoFilteredList{
+		Num:   projectList.Num,
+		Items: []interface{}{},
+	}
+	for _, projectInfo := range projectList.Items {
+		prunedProject, err := util.PruneToFields(projectInfo, fields)
+		if err != nil {
+			httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to prune project info to fields : %s", err.Error()))
+			return true
+		}
+		filteredList.Items = append(filteredList.Items, prunedProject)
+	}
+	response.WriteEntity(filteredList)
+	return true
+}
+
 func (handler *ProjectHandler) CreateProject(request *restful.Request, response *restful.Response) {
 	projectParams := new(projectModel.ProjectParams)
 	tenantName := request.PathParameter("namespace")
@@ -185,16 +357,37 @@ func (handler *ProjectHandler) CreateProject(request *restful.Request, response
 		}
 	}
 
-	err = handler.usecase.CreateProject(tenantName, projectName, projectParams, async, timeoutSec)
+	scheduledAt, err := httpUtils.GetScheduledAtQueryParam(request)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to create project : %s", err.Error()))
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param scheduledAt value is not valid : %s", err.Error()))
 		return
 	}
+	if !scheduledAt.IsZero() {
+		handler.scheduleOperation(request, response, tenantName, projectName, scheduleModel.CreateOperation,
+			projectParams, timeoutSec, scheduledAt)
+		return
+	}
+
+	taskSig, err := handler.usecase.CreateProject(request.Request.Context(), tenantName, projectName, projectParams, async, timeoutSec, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
+	if err != nil {
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to create project : %s", err.Error()))
+		return
+	}
+	writeTaskSig(response, taskSig)
 }
 
 func (handler *ProjectHandler)GetProjectInfo(request *restful.Request, response *restful.Response) {
 	tenantName := request.PathParameter("namespace")
 	projectName := request.PathParameter("project")
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
 	projectInfo, err := handler.usecase.GetProjectInfo(tenantName, projectName)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
@@ -204,9 +397,28 @@ func (handler *ProjectHandler)GetProjectInfo(request *restful.Request, response
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get project info : %s", err.Error()))
 		return
 	}
+	if !reveal {
+		projectInfo.MaskSensitiveValues()
+	}
 	response.WriteEntity(projectInfo)
 }
 
+func (handler *ProjectHandler) GetProjectDepGraph(request *restful.Request, response *restful.Response) {
+	tenantName := request.PathParameter("namespace")
+	projectName := request.PathParameter("project")
+	dot, err := handler.usecase.GetProjectDepGraph(tenantName, projectName)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("project %s/%s is not found", tenantName, projectName))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get project dep graph : %s", err.Error()))
+		return
+	}
+	response.AddHeader("Content-Type", "text/vnd.graphviz")
+	response.Write([]byte(dot))
+}
+
 func (handler *ProjectHandler)DeleteProject(request *restful.Request, response *restful.Response) {
 	tenantName := request.PathParameter("namespace")
 	projectName := request.PathParameter("project")
@@ -225,11 +437,48 @@ func (handler *ProjectHandler)DeleteProject(request *restful.Request, response *
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param deletePvcs value is not valid : %s", err.Error()))
 		return
 	}
-	err = handler.usecase.DeleteProject(tenantName, projectName, async, timeoutSec, deletePvcs)
+	fast, err := httpUtils.GetFastQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param fast value is not valid : %s", err.Error()))
+		return
+	}
+	dryRun, err := httpUtils.GetDryRunQueryParam(request)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete project : %s", err.Error()))
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param dryRun value is not valid : %s", err.Error()))
+		return
+	}
+
+	if dryRun {
+		preview, err := handler.usecase.DryRunDeleteProject(tenantName, projectName, deletePvcs)
+		if err != nil {
+			if errorModel.IsNotFoundError(err) {
+				httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("project %s/%s is not found", tenantName, projectName))
+				return
+			}
+			httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to dry run delete project : %s", err.Error()))
+			return
+		}
+		response.WriteEntity(preview)
 		return
 	}
+
+	scheduledAt, err := httpUtils.GetScheduledAtQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param scheduledAt value is not valid : %s", err.Error()))
+		return
+	}
+	if !scheduledAt.IsZero() {
+		handler.scheduleOperation(request, response, tenantName, projectName, scheduleModel.DeleteOperation,
+			&scheduleModel.ScheduledDeleteProjectArgs{DeletePvcs: deletePvcs, Fast: fast}, timeoutSec, scheduledAt)
+		return
+	}
+
+	taskSig, err := handler.usecase.DeleteProject(request.Request.Context(), tenantName, projectName, async, timeoutSec, deletePvcs, fast, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
+	if err != nil {
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to delete project : %s", err.Error()))
+		return
+	}
+	writeTaskSig(response, taskSig)
 }
 
 func (handler *ProjectHandler) AddReleaseInProject(request *restful.Request, response *restful.Response) {
@@ -251,11 +500,12 @@ func (handler *ProjectHandler) AddReleaseInProject(request *restful.Request, res
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body: %s", err.Error()))
 		return
 	}
-	err = handler.usecase.AddReleasesInProject(tenantName, projectName, &projectModel.ProjectParams{Releases: []*release.ReleaseRequestV2{releaseRequest}}, async, timeoutSec)
+	taskSig, err := handler.usecase.AddReleasesInProject(request.Request.Context(), tenantName, projectName, &projectModel.ProjectParams{Releases: []*release.ReleaseRequestV2{releaseRequest}}, async, timeoutSec, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to add release in project : %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to add release in project : %s", err.Error()))
 		return
 	}
+	writeTaskSig(response, taskSig)
 }
 
 func (handler *ProjectHandler) UpgradeReleaseInProject(request *restful.Request, response *restful.Response) {
@@ -277,11 +527,12 @@ func (handler *ProjectHandler) UpgradeReleaseInProject(request *restful.Request,
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body: %s", err.Error()))
 		return
 	}
-	err = handler.usecase.UpgradeReleaseInProject(tenantName, projectName, releaseRequest, async, timeoutSec)
+	taskSig, err := handler.usecase.UpgradeReleaseInProject(request.Request.Context(), tenantName, projectName, releaseRequest, async, timeoutSec, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to upgrade release in project : %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to upgrade release in project : %s", err.Error()))
 		return
 	}
+	writeTaskSig(response, taskSig)
 }
 
 func (handler *ProjectHandler) AddReleasesInProject(request *restful.Request, response *restful.Response) {
@@ -303,11 +554,12 @@ func (handler *ProjectHandler) AddReleasesInProject(request *restful.Request, re
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body: %s", err.Error()))
 		return
 	}
-	err = handler.usecase.AddReleasesInProject(tenantName, projectName, projectParams, async, timeoutSec)
+	taskSig, err := handler.usecase.AddReleasesInProject(request.Request.Context(), tenantName, projectName, projectParams, async, timeoutSec, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to add releases in project : %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to add releases in project : %s", err.Error()))
 		return
 	}
+	writeTaskSig(response, taskSig)
 }
 
 func (handler *ProjectHandler) DeleteReleaseInProject(request *restful.Request, response *restful.Response) {
@@ -329,9 +581,142 @@ func (handler *ProjectHandler) DeleteReleaseInProject(request *restful.Request,
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param deletePvcs value is not valid : %s", err.Error()))
 		return
 	}
-	err = handler.usecase.RemoveReleaseInProject(tenantName, projectName, releaseName, async, timeoutSec, deletePvcs)
+	taskSig, err := handler.usecase.RemoveReleaseInProject(request.Request.Context(), tenantName, projectName, releaseName, async, timeoutSec, deletePvcs, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
+	if err != nil {
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to delete release in project : %s", err.Error()))
+		return
+	}
+	writeTaskSig(response, taskSig)
+}
+
+func (handler *ProjectHandler) GetProjectWebhooks(request *restful.Request, response *restful.Response) {
+	tenantName := request.PathParameter("namespace")
+	projectName := request.PathParameter("project")
+	webhooks, err := handler.usecase.GetProjectWebhooks(tenantName, projectName)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get project webhooks : %s", err.Error()))
+		return
+	}
+	response.WriteEntity(webhooks)
+}
+
+func (handler *ProjectHandler) SetProjectWebhooks(request *restful.Request, response *restful.Response) {
+	tenantName := request.PathParameter("namespace")
+	projectName := request.PathParameter("project")
+	webhooks := []*projectModel.ProjectWebhook{}
+	err := request.ReadEntity(&webhooks)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body : %s", err.Error()))
+		return
+	}
+	err = handler.usecase.SetProjectWebhooks(tenantName, projectName, webhooks, auth.GetRequestPrincipal(request), requestid.GetRequestId(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to set project webhooks : %s", err.Error()))
+		return
+	}
+}
+
+// StreamProjectEvents pushes the project state to the client over SSE : an
+// initial snapshot followed by deltas fed by the project events channel, so
+// the client does not have to poll GetProjectInfo.
+func (handler *ProjectHandler) StreamProjectEvents(request *restful.Request, response *restful.Response) {
+	tenantName := request.PathParameter("namespace")
+	projectName := request.PathParameter("project")
+
+	// subscribe before taking the snapshot, so changes in between are not lost
+	msgChan, closeSub, err := handler.redis.Subscribe(walmRedis.WalmProjectEventsChannel)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to subscribe project events : %s", err.Error()))
+		return
+	}
+	defer closeSub()
+
+	projectInfo, err := handler.usecase.GetProjectInfo(tenantName, projectName)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete release in project : %s", err.Error()))
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("project %s/%s is not found", tenantName, projectName))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get project info : %s", err.Error()))
+		return
+	}
+
+	response.AddHeader("Content-Type", "text/event-stream")
+	response.AddHeader("Cache-Control", "no-cache")
+	response.AddHeader("Connection", "keep-alive")
+
+	lastReady := projectInfo.Ready
+	writeProjectEvent(response, &projectModel.ProjectEvent{
+		Type:      projectModel.ProjectEventSnapshot,
+		Namespace: tenantName,
+		Name:      projectName,
+		Project:   projectInfo,
+	})
+
+	for {
+		select {
+		case <-request.Request.Context().Done():
+			return
+		case message, ok := <-msgChan:
+			if !ok {
+				return
+			}
+			event := &projectModel.ProjectEvent{}
+			err = json.Unmarshal([]byte(message), event)
+			if err != nil {
+				logrus.Warnf("failed to unmarshal project event %s : %s", message, err.Error())
+				continue
+			}
+			if event.Namespace != tenantName || event.Name != projectName {
+				continue
+			}
+			projectInfo, err = handler.usecase.GetProjectInfo(tenantName, projectName)
+			if err != nil {
+				if errorModel.IsNotFoundError(err) {
+					writeProjectEvent(response, &projectModel.ProjectEvent{
+						Type:      projectModel.ProjectEventDeleted,
+						Namespace: tenantName,
+						Name:      projectName,
+					})
+					return
+				}
+				logrus.Errorf("failed to get project info of %s/%s : %s", tenantName, projectName, err.Error())
+				continue
+			}
+			event.Project = projectInfo
+			writeProjectEvent(response, event)
+			if projectInfo.Ready != lastReady {
+				lastReady = projectInfo.Ready
+				writeProjectEvent(response, &projectModel.ProjectEvent{
+					Type:      projectModel.ProjectEventReadyChanged,
+					Namespace: tenantName,
+					Name:      projectName,
+					Project:   projectInfo,
+				})
+			}
+		}
+	}
+}
+
+func writeProjectEvent(response *restful.Response, event *projectModel.ProjectEvent) {
+	eventStr, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("failed to marshal project event : %s", err.Error())
+		return
+	}
+	fmt.Fprintf(response, "data: %s\n\n", eventStr)
+	response.Flush()
+}
+
+// writeTaskSig echoes the submitted task identity back to the client. The arg
+// is stripped as it carries the whole serialized request.
+func writeTaskSig(response *restful.Response, taskSig *taskModel.TaskSig) {
+	if taskSig == nil {
 		return
 	}
+	response.WriteEntity(&taskModel.TaskSig{
+		Name:       taskSig.Name,
+		UUID:       taskSig.UUID,
+		TimeoutSec: taskSig.TimeoutSec,
+	})
 }
@@ -13,6 +13,8 @@ import (
 	"bytes"
 	"WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/release"
+	walmRedis "WarpCloud/walm/pkg/redis"
+	redisMocks "WarpCloud/walm/pkg/redis/mocks"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -34,14 +36,14 @@ func TestProjectHandler_ListProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("ListProjects", "").Return(nil, nil)
+				mockUseCase.On("ListProjects", "", false).Return(nil, nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("ListProjects", "").Return(nil, errors.New(""))
+				mockUseCase.On("ListProjects", "", false).Return(nil, errors.New(""))
 			},
 			statusCode: 500,
 		},
@@ -58,6 +60,36 @@ func TestProjectHandler_ListProject(t *testing.T) {
 	}
 }
 
+func TestProjectHandler_ListProjectFields(t *testing.T) {
+	var mockUseCase *mocks.UseCase
+	var mockProjectHandler ProjectHandler
+
+	container := restful.NewContainer()
+	container.Add(RegisterProjectHandler(&mockProjectHandler))
+
+	mockUseCase = &mocks.UseCase{}
+	mockProjectHandler.usecase = mockUseCase
+	mockUseCase.On("ListProjects", "", false).Return(&project.ProjectInfoList{
+		Num: 1,
+		Items: []*project.ProjectInfo{
+			{
+				Name:      "test-nm",
+				Namespace: "test-ns",
+			},
+		},
+	}, nil)
+
+	httpRequest, _ := http.NewRequest("GET", projectRootPath+"/?fields=name", nil)
+	httpRequest.Header.Set("Accept", "application/json")
+	httpWriter := httptest.NewRecorder()
+	container.ServeHTTP(httpWriter, httpRequest)
+
+	assert.Equal(t, 200, httpWriter.Code)
+	body := httpWriter.Body.String()
+	assert.Contains(t, body, "test-nm")
+	assert.NotContains(t, body, "test-ns")
+}
+
 func TestProjectHandler_ListProjectByNamespace(t *testing.T) {
 	var mockUseCase *mocks.UseCase
 	var mockProjectHandler ProjectHandler
@@ -76,14 +108,14 @@ func TestProjectHandler_ListProjectByNamespace(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("ListProjects", "testns").Return(nil, nil)
+				mockUseCase.On("ListProjects", "testns", false).Return(nil, nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("ListProjects", "testns").Return(nil, errors.New(""))
+				mockUseCase.On("ListProjects", "testns", false).Return(nil, errors.New(""))
 			},
 			statusCode: 500,
 		},
@@ -183,7 +215,7 @@ func TestProjectHandler_CreateProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("CreateProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+				mockUseCase.On("CreateProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			body: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{
@@ -197,7 +229,7 @@ func TestProjectHandler_CreateProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("CreateProject", "testns", "testnm", mock.Anything, false, int64(0)).Return(nil)
+				mockUseCase.On("CreateProject", mock.Anything, "testns", "testnm", mock.Anything, false, int64(0), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{
@@ -211,7 +243,7 @@ func TestProjectHandler_CreateProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("CreateProject", "testns", "testnm", mock.Anything, true, int64(60)).Return(nil)
+				mockUseCase.On("CreateProject", mock.Anything, "testns", "testnm", mock.Anything, true, int64(60), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{
@@ -287,25 +319,40 @@ func TestProjectHandler_DeleteProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("DeleteProject", "testns", "testname", false, int64(0), false).Return(nil)
+				mockUseCase.On("DeleteProject", mock.Anything, "testns", "testname", false, int64(0), false, false, mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("DeleteProject", "testns", "testname", false, int64(0), false).Return(errors.New(""))
+				mockUseCase.On("DeleteProject", mock.Anything, "testns", "testname", false, int64(0), false, false, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			statusCode: 500,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("DeleteProject", "testns", "testname", true, int64(60), true).Return(nil)
+				mockUseCase.On("DeleteProject", mock.Anything, "testns", "testname", true, int64(60), true, false, mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			queryUrl:   "?deletePvcs=true&async=true&timeoutSec=60",
 			statusCode: 200,
 		},
+		{
+			initMock: func() {
+				refreshMockUseCase()
+				mockUseCase.On("DeleteProject", mock.Anything, "testns", "testname", true, int64(60), true, true, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			queryUrl:   "?deletePvcs=true&async=true&timeoutSec=60&fast=true",
+			statusCode: 200,
+		},
+		{
+			initMock: func() {
+				refreshMockUseCase()
+			},
+			queryUrl:   "?deletePvcs=true&async=true&timeoutSec=60&fast=notvalid",
+			statusCode: 500,
+		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
@@ -367,7 +414,7 @@ func TestProjectHandler_AddReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("AddReleasesInProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+				mockUseCase.On("AddReleasesInProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			body:       &release.ReleaseRequestV2{},
 			statusCode: 500,
@@ -375,7 +422,7 @@ func TestProjectHandler_AddReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("AddReleasesInProject", "testns", "testnm", mock.Anything, false, int64(0)).Return(nil)
+				mockUseCase.On("AddReleasesInProject", mock.Anything, "testns", "testnm", mock.Anything, false, int64(0), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body:       &release.ReleaseRequestV2{},
 			statusCode: 200,
@@ -383,7 +430,7 @@ func TestProjectHandler_AddReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("AddReleasesInProject", "testns", "testnm", mock.Anything, true, int64(60)).Return(nil)
+				mockUseCase.On("AddReleasesInProject", mock.Anything, "testns", "testnm", mock.Anything, true, int64(60), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body:       &release.ReleaseRequestV2{},
 			queryUrl:   "?async=true&timeoutSec=60",
@@ -449,7 +496,7 @@ func TestProjectHandler_UpgradeReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("UpgradeReleaseInProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+				mockUseCase.On("UpgradeReleaseInProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			body:       &release.ReleaseRequestV2{},
 			statusCode: 500,
@@ -457,7 +504,7 @@ func TestProjectHandler_UpgradeReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("UpgradeReleaseInProject", "testns", "testnm", mock.Anything, false, int64(0)).Return(nil)
+				mockUseCase.On("UpgradeReleaseInProject", mock.Anything, "testns", "testnm", mock.Anything, false, int64(0), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body:       &release.ReleaseRequestV2{},
 			statusCode: 200,
@@ -465,7 +512,7 @@ func TestProjectHandler_UpgradeReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("UpgradeReleaseInProject", "testns", "testnm", mock.Anything, true, int64(60)).Return(nil)
+				mockUseCase.On("UpgradeReleaseInProject", mock.Anything, "testns", "testnm", mock.Anything, true, int64(60), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body:       &release.ReleaseRequestV2{},
 			queryUrl:   "?async=true&timeoutSec=60",
@@ -531,7 +578,7 @@ func TestProjectHandler_AddReleasesInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("AddReleasesInProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+				mockUseCase.On("AddReleasesInProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			body:       &project.ProjectParams{},
 			statusCode: 500,
@@ -539,7 +586,7 @@ func TestProjectHandler_AddReleasesInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("AddReleasesInProject", "testns", "testnm", mock.Anything, false, int64(0)).Return(nil)
+				mockUseCase.On("AddReleasesInProject", mock.Anything, "testns", "testnm", mock.Anything, false, int64(0), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body:       &project.ProjectParams{},
 			statusCode: 200,
@@ -547,7 +594,7 @@ func TestProjectHandler_AddReleasesInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("AddReleasesInProject", "testns", "testnm", mock.Anything, true, int64(60)).Return(nil)
+				mockUseCase.On("AddReleasesInProject", mock.Anything, "testns", "testnm", mock.Anything, true, int64(60), mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			body:       &project.ProjectParams{},
 			queryUrl:   "?async=true&timeoutSec=60",
@@ -605,21 +652,21 @@ func TestProjectHandler_DeleteReleaseInProject(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("RemoveReleaseInProject", "testns", "testname", "testrls", false, int64(0), false).Return(nil)
+				mockUseCase.On("RemoveReleaseInProject", mock.Anything, "testns", "testname", "testrls", false, int64(0), false, mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("RemoveReleaseInProject", "testns", "testname", "testrls", false, int64(0), false).Return(errors.New(""))
+				mockUseCase.On("RemoveReleaseInProject", mock.Anything, "testns", "testname", "testrls", false, int64(0), false, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			statusCode: 500,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("RemoveReleaseInProject", "testns", "testname", "testrls", true, int64(60), true).Return(nil)
+				mockUseCase.On("RemoveReleaseInProject", mock.Anything, "testns", "testname", "testrls", true, int64(60), true, mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			queryUrl:   "?deletePvcs=true&async=true&timeoutSec=60",
 			statusCode: 200,
@@ -657,3 +704,78 @@ func TestProjectHandler_DeleteReleaseInProject(t *testing.T) {
 		assert.Equal(t, httpWriter.Code, test.statusCode)
 	}
 }
+
+func TestProjectHandler_StreamProjectEvents(t *testing.T) {
+	var mockUseCase *mocks.UseCase
+	var mockRedis *redisMocks.Redis
+	var mockProjectHandler ProjectHandler
+
+	container := restful.NewContainer()
+	container.Add(RegisterProjectHandler(&mockProjectHandler))
+
+	refreshMocks := func() {
+		mockUseCase = &mocks.UseCase{}
+		mockRedis = &redisMocks.Redis{}
+		mockProjectHandler.usecase = mockUseCase
+		mockProjectHandler.redis = mockRedis
+	}
+	closeSub := func() error { return nil }
+
+	tests := []struct {
+		initMock     func()
+		statusCode   int
+		bodyContains string
+	}{
+		{
+			initMock: func() {
+				refreshMocks()
+				mockRedis.On("Subscribe", walmRedis.WalmProjectEventsChannel).Return(nil, nil, errors.New(""))
+			},
+			statusCode: 500,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				msgChan := make(chan string)
+				close(msgChan)
+				mockRedis.On("Subscribe", walmRedis.WalmProjectEventsChannel).Return((<-chan string)(msgChan), closeSub, nil)
+				mockUseCase.On("GetProjectInfo", "testns", "testnm").Return(nil, errorModel.NotFoundError{})
+			},
+			statusCode: 404,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				msgChan := make(chan string, 1)
+				eventStr, _ := json.Marshal(&project.ProjectEvent{
+					Type:      project.ProjectEventTaskStateChanged,
+					Namespace: "testns",
+					Name:      "testnm",
+				})
+				msgChan <- string(eventStr)
+				close(msgChan)
+				mockRedis.On("Subscribe", walmRedis.WalmProjectEventsChannel).Return((<-chan string)(msgChan), closeSub, nil)
+				mockUseCase.On("GetProjectInfo", "testns", "testnm").Return(&project.ProjectInfo{
+					Namespace: "testns",
+					Name:      "testnm",
+				}, nil)
+			},
+			statusCode:   200,
+			bodyContains: project.ProjectEventSnapshot,
+		},
+	}
+
+	for _, test := range tests {
+		test.initMock()
+		url := projectRootPath + "/testns/name/testnm/events"
+
+		httpRequest, _ := http.NewRequest("GET", url, nil)
+		httpWriter := httptest.NewRecorder()
+		container.ServeHTTP(httpWriter, httpRequest)
+		assert.Equal(t, httpWriter.Code, test.statusCode)
+		if test.bodyContains != "" {
+			assert.Contains(t, httpWriter.Body.String(), test.bodyContains)
+		}
+		mockRedis.AssertExpectations(t)
+	}
+}
@@ -84,3 +84,40 @@ func (_m *Cache) GetProjectTasks(namespace string) ([]*project.ProjectTask, erro
 
 	return r0, r1
 }
+
+// GetProjectWebhooks provides a mock function with given fields: namespace, name
+func (_m *Cache) GetProjectWebhooks(namespace string, name string) ([]*project.ProjectWebhook, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 []*project.ProjectWebhook
+	if rf, ok := ret.Get(0).(func(string, string) []*project.ProjectWebhook); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*project.ProjectWebhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetProjectWebhooks provides a mock function with given fields: namespace, name, webhooks
+func (_m *Cache) SetProjectWebhooks(namespace string, name string, webhooks []*project.ProjectWebhook) error {
+	ret := _m.Called(namespace, name, webhooks)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, []*project.ProjectWebhook) error); ok {
+		r0 = rf(namespace, name, webhooks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
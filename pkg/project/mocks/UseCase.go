@@ -2,56 +2,129 @@
 
 package mocks
 
+import context "context"
 import mock "github.com/stretchr/testify/mock"
 
 import project "WarpCloud/walm/pkg/models/project"
 import release "WarpCloud/walm/pkg/models/release"
+import task "WarpCloud/walm/pkg/models/task"
 
 // UseCase is an autogenerated mock type for the UseCase type
 type UseCase struct {
 	mock.Mock
 }
 
-// AddReleasesInProject provides a mock function with given fields: namespace, projectName, projectParams, async, timeoutSec
-func (_m *UseCase) AddReleasesInProject(namespace string, projectName string, projectParams *project.ProjectParams, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, projectName, projectParams, async, timeoutSec)
+// AddReleasesInProject provides a mock function with given fields: ctx, namespace, projectName, projectParams, async, timeoutSec, principal, requestId
+func (_m *UseCase) AddReleasesInProject(ctx context.Context, namespace string, projectName string, projectParams *project.ProjectParams, async bool, timeoutSec int64, principal string, requestId string) (*task.TaskSig, error) {
+	ret := _m.Called(ctx, namespace, projectName, projectParams, async, timeoutSec, principal, requestId)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *project.ProjectParams, bool, int64) error); ok {
-		r0 = rf(namespace, projectName, projectParams, async, timeoutSec)
+	var r0 *task.TaskSig
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *project.ProjectParams, bool, int64, string, string) *task.TaskSig); ok {
+		r0 = rf(ctx, namespace, projectName, projectParams, async, timeoutSec, principal, requestId)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*task.TaskSig)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *project.ProjectParams, bool, int64, string, string) error); ok {
+		r1 = rf(ctx, namespace, projectName, projectParams, async, timeoutSec, principal, requestId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// CreateProject provides a mock function with given fields: namespace, _a1, projectParams, async, timeoutSec
-func (_m *UseCase) CreateProject(namespace string, _a1 string, projectParams *project.ProjectParams, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, _a1, projectParams, async, timeoutSec)
+// CreateProject provides a mock function with given fields: ctx, namespace, _a1, projectParams, async, timeoutSec, principal, requestId
+func (_m *UseCase) CreateProject(ctx context.Context, namespace string, _a1 string, projectParams *project.ProjectParams, async bool, timeoutSec int64, principal string, requestId string) (*task.TaskSig, error) {
+	ret := _m.Called(ctx, namespace, _a1, projectParams, async, timeoutSec, principal, requestId)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *project.ProjectParams, bool, int64) error); ok {
-		r0 = rf(namespace, _a1, projectParams, async, timeoutSec)
+	var r0 *task.TaskSig
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *project.ProjectParams, bool, int64, string, string) *task.TaskSig); ok {
+		r0 = rf(ctx, namespace, _a1, projectParams, async, timeoutSec, principal, requestId)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*task.TaskSig)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *project.ProjectParams, bool, int64, string, string) error); ok {
+		r1 = rf(ctx, namespace, _a1, projectParams, async, timeoutSec, principal, requestId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// DeleteProject provides a mock function with given fields: namespace, _a1, async, timeoutSec, deletePvcs
-func (_m *UseCase) DeleteProject(namespace string, _a1 string, async bool, timeoutSec int64, deletePvcs bool) error {
-	ret := _m.Called(namespace, _a1, async, timeoutSec, deletePvcs)
+// DeleteProject provides a mock function with given fields: ctx, namespace, _a1, async, timeoutSec, deletePvcs, fast, principal, requestId
+func (_m *UseCase) DeleteProject(ctx context.Context, namespace string, _a1 string, async bool, timeoutSec int64, deletePvcs bool, fast bool, principal string, requestId string) (*task.TaskSig, error) {
+	ret := _m.Called(ctx, namespace, _a1, async, timeoutSec, deletePvcs, fast, principal, requestId)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, bool, int64, bool) error); ok {
-		r0 = rf(namespace, _a1, async, timeoutSec, deletePvcs)
+	var r0 *task.TaskSig
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, int64, bool, bool, string, string) *task.TaskSig); ok {
+		r0 = rf(ctx, namespace, _a1, async, timeoutSec, deletePvcs, fast, principal, requestId)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*task.TaskSig)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool, int64, bool, bool, string, string) error); ok {
+		r1 = rf(ctx, namespace, _a1, async, timeoutSec, deletePvcs, fast, principal, requestId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DryRunDeleteProject provides a mock function with given fields: namespace, _a1, deletePvcs
+func (_m *UseCase) DryRunDeleteProject(namespace string, _a1 string, deletePvcs bool) (*project.ProjectDeletePreview, error) {
+	ret := _m.Called(namespace, _a1, deletePvcs)
+
+	var r0 *project.ProjectDeletePreview
+	if rf, ok := ret.Get(0).(func(string, string, bool) *project.ProjectDeletePreview); ok {
+		r0 = rf(namespace, _a1, deletePvcs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*project.ProjectDeletePreview)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+		r1 = rf(namespace, _a1, deletePvcs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProjectDepGraph provides a mock function with given fields: namespace, projectName
+func (_m *UseCase) GetProjectDepGraph(namespace string, projectName string) (string, error) {
+	ret := _m.Called(namespace, projectName)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(namespace, projectName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, projectName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetProjectInfo provides a mock function with given fields: namespace, projectName
@@ -77,13 +150,36 @@ func (_m *UseCase) GetProjectInfo(namespace string, projectName string) (*projec
 	return r0, r1
 }
 
-// ListProjects provides a mock function with given fields: namespace
-func (_m *UseCase) ListProjects(namespace string) (*project.ProjectInfoList, error) {
-	ret := _m.Called(namespace)
+// GetProjectWebhooks provides a mock function with given fields: namespace, projectName
+func (_m *UseCase) GetProjectWebhooks(namespace string, projectName string) ([]*project.ProjectWebhook, error) {
+	ret := _m.Called(namespace, projectName)
+
+	var r0 []*project.ProjectWebhook
+	if rf, ok := ret.Get(0).(func(string, string) []*project.ProjectWebhook); ok {
+		r0 = rf(namespace, projectName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*project.ProjectWebhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, projectName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProjects provides a mock function with given fields: namespace, detail
+func (_m *UseCase) ListProjects(namespace string, detail bool) (*project.ProjectInfoList, error) {
+	ret := _m.Called(namespace, detail)
 
 	var r0 *project.ProjectInfoList
-	if rf, ok := ret.Get(0).(func(string) *project.ProjectInfoList); ok {
-		r0 = rf(namespace)
+	if rf, ok := ret.Get(0).(func(string, bool) *project.ProjectInfoList); ok {
+		r0 = rf(namespace, detail)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*project.ProjectInfoList)
@@ -91,8 +187,8 @@ func (_m *UseCase) ListProjects(namespace string) (*project.ProjectInfoList, err
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(namespace)
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(namespace, detail)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,30 +196,62 @@ func (_m *UseCase) ListProjects(namespace string) (*project.ProjectInfoList, err
 	return r0, r1
 }
 
-// RemoveReleaseInProject provides a mock function with given fields: namespace, projectName, releaseName, async, timeoutSec, deletePvcs
-func (_m *UseCase) RemoveReleaseInProject(namespace string, projectName string, releaseName string, async bool, timeoutSec int64, deletePvcs bool) error {
-	ret := _m.Called(namespace, projectName, releaseName, async, timeoutSec, deletePvcs)
+// RemoveReleaseInProject provides a mock function with given fields: ctx, namespace, projectName, releaseName, async, timeoutSec, deletePvcs, principal, requestId
+func (_m *UseCase) RemoveReleaseInProject(ctx context.Context, namespace string, projectName string, releaseName string, async bool, timeoutSec int64, deletePvcs bool, principal string, requestId string) (*task.TaskSig, error) {
+	ret := _m.Called(ctx, namespace, projectName, releaseName, async, timeoutSec, deletePvcs, principal, requestId)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, bool, int64, bool) error); ok {
-		r0 = rf(namespace, projectName, releaseName, async, timeoutSec, deletePvcs)
+	var r0 *task.TaskSig
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool, int64, bool, string, string) *task.TaskSig); ok {
+		r0 = rf(ctx, namespace, projectName, releaseName, async, timeoutSec, deletePvcs, principal, requestId)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*task.TaskSig)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, bool, int64, bool, string, string) error); ok {
+		r1 = rf(ctx, namespace, projectName, releaseName, async, timeoutSec, deletePvcs, principal, requestId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UpgradeReleaseInProject provides a mock function with given fields: namespace, projectName, releaseParams, async, timeoutSec
-func (_m *UseCase) UpgradeReleaseInProject(namespace string, projectName string, releaseParams *release.ReleaseRequestV2, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, projectName, releaseParams, async, timeoutSec)
+// SetProjectWebhooks provides a mock function with given fields: namespace, projectName, webhooks, principal, requestId
+func (_m *UseCase) SetProjectWebhooks(namespace string, projectName string, webhooks []*project.ProjectWebhook, principal string, requestId string) error {
+	ret := _m.Called(namespace, projectName, webhooks, principal, requestId)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *release.ReleaseRequestV2, bool, int64) error); ok {
-		r0 = rf(namespace, projectName, releaseParams, async, timeoutSec)
+	if rf, ok := ret.Get(0).(func(string, string, []*project.ProjectWebhook, string, string) error); ok {
+		r0 = rf(namespace, projectName, webhooks, principal, requestId)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// UpgradeReleaseInProject provides a mock function with given fields: ctx, namespace, projectName, releaseParams, async, timeoutSec, principal, requestId
+func (_m *UseCase) UpgradeReleaseInProject(ctx context.Context, namespace string, projectName string, releaseParams *release.ReleaseRequestV2, async bool, timeoutSec int64, principal string, requestId string) (*task.TaskSig, error) {
+	ret := _m.Called(ctx, namespace, projectName, releaseParams, async, timeoutSec, principal, requestId)
+
+	var r0 *task.TaskSig
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *release.ReleaseRequestV2, bool, int64, string, string) *task.TaskSig); ok {
+		r0 = rf(ctx, namespace, projectName, releaseParams, async, timeoutSec, principal, requestId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*task.TaskSig)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *release.ReleaseRequestV2, bool, int64, string, string) error); ok {
+		r1 = rf(ctx, namespace, projectName, releaseParams, async, timeoutSec, principal, requestId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
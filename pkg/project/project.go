@@ -3,6 +3,7 @@ package project
 import (
 	"sync"
 	"errors"
+	"strings"
 	"github.com/sirupsen/logrus"
 
 	"WarpCloud/walm/pkg/redis"
@@ -28,14 +29,19 @@ type ProjectManager struct {
 }
 
 var projectManager *ProjectManager
+var projectManagerOnce sync.Once
 
-func GetDefaultProjectManager() *ProjectManager {
-	if projectManager == nil {
-		projectManager = &ProjectManager{
-			helmClient:  helm.GetDefaultHelmClient(),
-			redisClient: redis.GetDefaultRedisClient(),
-		}
+func NewProjectManager(helmClient *helm.HelmClient, redisClient *redis.RedisClient) *ProjectManager {
+	return &ProjectManager{
+		helmClient:  helmClient,
+		redisClient: redisClient,
 	}
+}
+
+func GetDefaultProjectManager() *ProjectManager {
+	projectManagerOnce.Do(func() {
+		projectManager = NewProjectManager(helm.GetDefaultHelmClient(), redis.GetDefaultRedisClient())
+	})
 	return projectManager
 }
 
@@ -476,8 +482,8 @@ func (manager *ProjectManager) brainFuckRuntimeDepParse(projectInfo *ProjectInfo
 				}
 			}
 		}
-		logrus.Infof("add %s Modify UpperStream Release %+v deps %s\n", releaseParams.Name, g.UpEdges(releaseParams.Name).List(), releaseParams.Name)
-		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).List() {
+		logrus.Infof("add %s Modify UpperStream Release %+v deps %s\n", releaseParams.Name, g.UpEdges(releaseParams.Name).SortedList(nil), releaseParams.Name)
+		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).SortedList(nil) {
 			upperRelease := buildReleaseRequest(projectInfo, upperReleaseName.(string))
 			if upperRelease == nil {
 				continue
@@ -492,8 +498,8 @@ func (manager *ProjectManager) brainFuckRuntimeDepParse(projectInfo *ProjectInfo
 			affectReleases = append(affectReleases, upperRelease)
 		}
 		logrus.Infof("add %s release add more %+v deps. current %+v\n",
-			releaseParams.Name, g.DownEdges(releaseParams.Name).List(), releaseParams.Dependencies)
-		for _, downReleaseName := range g.DownEdges(releaseParams.Name).List() {
+			releaseParams.Name, g.DownEdges(releaseParams.Name).SortedList(nil), releaseParams.Dependencies)
+		for _, downReleaseName := range g.DownEdges(releaseParams.Name).SortedList(nil) {
 			downRelease := buildReleaseRequest(projectInfo, downReleaseName.(string))
 			if downRelease == nil {
 				continue
@@ -508,8 +514,8 @@ func (manager *ProjectManager) brainFuckRuntimeDepParse(projectInfo *ProjectInfo
 			}
 		}
 	} else {
-		logrus.Infof("%+v are depending on %s", g.UpEdges(releaseParams.Name).List(), releaseParams.Name)
-		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).List() {
+		logrus.Infof("%+v are depending on %s", g.UpEdges(releaseParams.Name).SortedList(nil), releaseParams.Name)
+		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).SortedList(nil) {
 			upperRelease := buildReleaseRequest(projectInfo, upperReleaseName.(string))
 			if upperRelease == nil {
 				continue
@@ -553,25 +559,40 @@ func (manager *ProjectManager) brainFuckChartDepParse(projectParams *ProjectPara
 		}
 	}
 
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		cycleStrs := make([]string, len(cycles))
+		for i, cycle := range cycles {
+			chartNames := make([]string, 0, len(cycle)+1)
+			for _, vertex := range cycle {
+				chartNames = append(chartNames, vertex.(*release.ReleaseRequestV2).ChartName)
+			}
+			// close the loop so the path reads as a cycle
+			chartNames = append(chartNames, chartNames[0])
+			cycleStrs[i] = strings.Join(chartNames, " -> ")
+		}
+		return nil, fmt.Errorf("the release charts depend on each other in a cycle : %s", strings.Join(cycleStrs, "; "))
+	}
+
 	_, err := g.Root()
 	if err != nil {
 		return nil, err
 	}
 
-	var lock sync.Mutex
-	err = g.Walk(func(v dag.Vertex) error {
-		lock.Lock()
-		defer lock.Unlock()
+	// the install order is computed deterministically, so identical project
+	// params always yield the identical task plan
+	order, err := g.TopologicalOrder(func(v dag.Vertex) string {
+		return v.(*release.ReleaseRequestV2).ChartName
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range order {
 		releaseRequest := v.(*release.ReleaseRequestV2)
 		for _, dv := range g.DownEdges(releaseRequest).List() {
 			releaseInfo := dv.(*release.ReleaseRequestV2)
 			releaseRequest.Dependencies[releaseInfo.ChartName] = releaseInfo.Name
 		}
 		releaseParsed = append(releaseParsed, releaseRequest)
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	return releaseParsed, nil
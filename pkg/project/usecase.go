@@ -1,16 +1,41 @@
 package project
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/release"
+	"WarpCloud/walm/pkg/models/task"
 )
 
+// The mutation methods return the signature of the submitted task, so async
+// callers get a handle to poll instead of re-deriving state from the project
+// info. The signature is nil when nothing was submitted. The principal is the
+// identity the auth layer resolved and the requestId correlates the task, the
+// audit record and the events with the http request, both recorded as is.
+// The ctx carries the trace of the http request, propagated through the task
+// queue so the worker side spans join it.
 type UseCase interface {
-	ListProjects(namespace string) (*project.ProjectInfoList, error)
+	// ListProjects lists the projects of the namespace, all of them when the
+	// namespace is empty. Without detail the infos are served from the caches
+	// alone : the releases only carry their name and chart and Ready reflects
+	// the latest task states instead of the kube resources, which keeps large
+	// lists fast.
+	ListProjects(namespace string, detail bool) (*project.ProjectInfoList, error)
 	GetProjectInfo(namespace, projectName string) (*project.ProjectInfo, error)
-	CreateProject(namespace string, project string, projectParams *project.ProjectParams, async bool, timeoutSec int64) error
-	DeleteProject(namespace string, project string, async bool, timeoutSec int64, deletePvcs bool) error
-	AddReleasesInProject(namespace string, projectName string, projectParams *project.ProjectParams, async bool, timeoutSec int64) error
-	UpgradeReleaseInProject(namespace string, projectName string, releaseParams *release.ReleaseRequestV2, async bool, timeoutSec int64) error
-	RemoveReleaseInProject(namespace, projectName, releaseName string, async bool, timeoutSec int64, deletePvcs bool) error
+	// GetProjectDepGraph returns the release dependency graph of the project
+	// in graphviz dot format, for debugging.
+	GetProjectDepGraph(namespace, projectName string) (string, error)
+	CreateProject(ctx context.Context, namespace string, project string, projectParams *project.ProjectParams, async bool, timeoutSec int64, principal string, requestId string) (*task.TaskSig, error)
+	DeleteProject(ctx context.Context, namespace string, project string, async bool, timeoutSec int64, deletePvcs bool, fast bool, principal string, requestId string) (*task.TaskSig, error)
+	DryRunDeleteProject(namespace string, project string, deletePvcs bool) (*project.ProjectDeletePreview, error)
+	AddReleasesInProject(ctx context.Context, namespace string, projectName string, projectParams *project.ProjectParams, async bool, timeoutSec int64, principal string, requestId string) (*task.TaskSig, error)
+	UpgradeReleaseInProject(ctx context.Context, namespace string, projectName string, releaseParams *release.ReleaseRequestV2, async bool, timeoutSec int64, principal string, requestId string) (*task.TaskSig, error)
+	RemoveReleaseInProject(ctx context.Context, namespace, projectName, releaseName string, async bool, timeoutSec int64, deletePvcs bool, principal string, requestId string) (*task.TaskSig, error)
+	// GetProjectWebhooks returns the webhooks routing the lifecycle events of
+	// the project, an empty list when none are configured.
+	GetProjectWebhooks(namespace, projectName string) ([]*project.ProjectWebhook, error)
+	// SetProjectWebhooks replaces the webhooks of the project, an empty list
+	// falls back to the globally configured endpoints.
+	SetProjectWebhooks(namespace, projectName string, webhooks []*project.ProjectWebhook, principal string, requestId string) error
 }
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
 	"WarpCloud/walm/pkg/models/project"
@@ -16,23 +18,33 @@ type AddReleaseTaskArgs struct {
 	Namespace     string
 	Name          string
 	ProjectParams *project.ProjectParams
+	RequestId     string
 }
 
 func (projectImpl *Project) registerAddReleaseTask() error{
 	return projectImpl.task.RegisterTask(addReleaseTaskName, projectImpl.AddReleaseTask)
 }
 
-func (projectImpl *Project)AddReleaseTask(addReleaseTaskArgsStr string) error {
+func (projectImpl *Project)AddReleaseTask(ctx context.Context, addReleaseTaskArgsStr string) error {
 	addReleaseTaskArgs := &AddReleaseTaskArgs{}
 	err := json.Unmarshal([]byte(addReleaseTaskArgsStr), addReleaseTaskArgs)
 	if err != nil {
 		logrus.Errorf("add release task arg is not valid : %s", err.Error())
 		return err
 	}
-	return projectImpl.doAddRelease(addReleaseTaskArgs.Namespace, addReleaseTaskArgs.Name, addReleaseTaskArgs.ProjectParams)
+	defer projectImpl.publishProjectEvent(addReleaseTaskArgs.Namespace, addReleaseTaskArgs.Name, project.ProjectEventTaskStateChanged, addReleaseTaskArgs.RequestId)
+	defer func() {
+		projectImpl.publishProjectTaskEvent(addReleaseTaskName, addReleaseTaskArgs.Namespace, addReleaseTaskArgs.Name, err)
+	}()
+	err = projectImpl.doAddRelease(ctx, addReleaseTaskArgs.Namespace, addReleaseTaskArgs.Name, addReleaseTaskArgs.ProjectParams)
+	if err != nil {
+		logrus.WithField("requestId", addReleaseTaskArgs.RequestId).Errorf("failed to add releases in project %s/%s : %s", addReleaseTaskArgs.Namespace, addReleaseTaskArgs.Name, err.Error())
+		return err
+	}
+	return nil
 }
 
-func (projectImpl *Project) doAddRelease(namespace, name string, projectParams *project.ProjectParams) error {
+func (projectImpl *Project) doAddRelease(ctx context.Context, namespace, name string, projectParams *project.ProjectParams) error {
 	projectInfo, err := projectImpl.GetProjectInfo(namespace, name)
 	projectExists := true
 	if err != nil {
@@ -64,21 +76,21 @@ func (projectImpl *Project) doAddRelease(namespace, name string, projectParams *
 				logrus.Errorf("RuntimeDepParse install release %s error %v\n", releaseParams.Name, err)
 				return err2
 			}
-			err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(namespace, releaseParams,  nil, false, 0, nil)
+			err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(ctx, namespace, releaseParams,  nil, false, 0, nil)
 			if err != nil {
 				logrus.Errorf("AddReleaseInProject install release %s error %v\n", releaseParams.Name, err)
 				return err
 			}
 			for _, affectReleaseParams := range affectReleaseRequest {
 				logrus.Infof("Update BecauseOf Dependency Modified: %v", *affectReleaseParams)
-				err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(namespace, affectReleaseParams,  nil, false, 0, nil)
+				err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(ctx, namespace, affectReleaseParams,  nil, false, 0, nil)
 				if err != nil {
 					logrus.Errorf("AddReleaseInProject Other Affected Release install release %s error %v\n", releaseParams.Name, err)
 					return err
 				}
 			}
 		} else {
-			err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(namespace, releaseParams,  nil, false, 0, nil)
+			err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(ctx, namespace, releaseParams,  nil, false, 0, nil)
 			if err != nil {
 				logrus.Errorf("AddReleaseInProject install release %s error %v\n", releaseParams.Name, err)
 				return err
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"testing"
 	"github.com/stretchr/testify/assert"
 	"WarpCloud/walm/pkg/project/mocks"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"errors"
 	releaseMocks "WarpCloud/walm/pkg/release/mocks"
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
 	"WarpCloud/walm/pkg/models/project"
 	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/models/release"
@@ -21,6 +23,7 @@ func TestProject_doAddRelease(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
 
 	var mockProjectManager *Project
 
@@ -31,13 +34,14 @@ func TestProject_doAddRelease(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache)
 		assert.IsType(t, err, nil)
 	}
 
@@ -87,7 +91,7 @@ func TestProject_doAddRelease(t *testing.T) {
 					}
 					return
 				}, nil)
-				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
 			},
 			projectParams: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{
@@ -117,7 +121,7 @@ func TestProject_doAddRelease(t *testing.T) {
 					}
 					return
 				}, nil)
-				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			projectParams: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{
@@ -169,7 +173,7 @@ func TestProject_doAddRelease(t *testing.T) {
 					}
 					return
 				}, nil)
-				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			projectParams: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{
@@ -186,7 +190,7 @@ func TestProject_doAddRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.doAddRelease("test-ns", "test-name", test.projectParams)
+		err := mockProjectManager.doAddRelease(context.Background(), "test-ns", "test-name", test.projectParams)
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
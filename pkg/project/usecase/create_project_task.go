@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
 	"WarpCloud/walm/pkg/models/project"
@@ -15,28 +17,33 @@ type CreateProjectTaskArgs struct {
 	Namespace     string
 	Name          string
 	ProjectParams *project.ProjectParams
+	RequestId     string
 }
 
 func (projectImpl *Project) registerCreateProjectTask() error{
 	return projectImpl.task.RegisterTask(createProjectTaskName, projectImpl.CreateProjectTask)
 }
 
-func (projectImpl *Project)CreateProjectTask(createProjectTaskArgsStr string) error {
+func (projectImpl *Project)CreateProjectTask(ctx context.Context, createProjectTaskArgsStr string) error {
 	createProjectTaskArgs := &CreateProjectTaskArgs{}
 	err := json.Unmarshal([]byte(createProjectTaskArgsStr), createProjectTaskArgs)
 	if err != nil {
 		logrus.Errorf("create project task arg is not valid : %s", err.Error())
 		return err
 	}
-	err = projectImpl.doCreateProject(createProjectTaskArgs.Namespace, createProjectTaskArgs.Name, createProjectTaskArgs.ProjectParams)
+	defer projectImpl.publishProjectEvent(createProjectTaskArgs.Namespace, createProjectTaskArgs.Name, project.ProjectEventTaskStateChanged, createProjectTaskArgs.RequestId)
+	defer func() {
+		projectImpl.publishProjectTaskEvent(createProjectTaskName, createProjectTaskArgs.Namespace, createProjectTaskArgs.Name, err)
+	}()
+	err = projectImpl.doCreateProject(ctx, createProjectTaskArgs.Namespace, createProjectTaskArgs.Name, createProjectTaskArgs.ProjectParams)
 	if err != nil {
-		logrus.Errorf("failed to create project %s/%s : %s", createProjectTaskArgs.Namespace, createProjectTaskArgs.Name, err.Error())
+		logrus.WithField("requestId", createProjectTaskArgs.RequestId).Errorf("failed to create project %s/%s : %s", createProjectTaskArgs.Namespace, createProjectTaskArgs.Name, err.Error())
 		return err
 	}
 	return nil
 }
 
-func (projectImpl *Project) doCreateProject(namespace string, name string, projectParams *project.ProjectParams) error {
+func (projectImpl *Project) doCreateProject(ctx context.Context, namespace string, name string, projectParams *project.ProjectParams) error {
 	rawValsBase := map[string]interface{}{}
 	rawValsBase = util.MergeValues(rawValsBase, projectParams.CommonValues, false)
 
@@ -54,12 +61,24 @@ func (projectImpl *Project) doCreateProject(namespace string, name string, proje
 		return err
 	}
 	for _, releaseParams := range releaseList {
-		err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(namespace, releaseParams,  nil, false, 0, nil)
+		err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(ctx, namespace, releaseParams,  nil, false, 0, nil)
 		if err != nil {
 			logrus.Errorf("failed to create project release %s/%s : %s", namespace, releaseParams.Name, err)
 			return err
 		}
 		logrus.Debugf("succeed to create project release %s/%s", namespace, releaseParams.Name)
 	}
+
+	// the create task only succeeds once every release of the project is ready,
+	// so waiters on the task get a project which is actually up
+	releaseNames := []string{}
+	for _, releaseParams := range releaseList {
+		releaseNames = append(releaseNames, releaseParams.Name)
+	}
+	err = projectImpl.releaseUseCase.WatchReleasesReady(ctx, namespace, releaseNames, 0)
+	if err != nil {
+		logrus.Errorf("failed to wait for the releases of project %s/%s to be ready : %s", namespace, name, err.Error())
+		return err
+	}
 	return nil
 }
\ No newline at end of file
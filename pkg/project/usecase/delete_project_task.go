@@ -1,9 +1,18 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	projectModel "WarpCloud/walm/pkg/models/project"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"WarpCloud/walm/pkg/util/dag"
+	"fmt"
+	"sync"
+	"time"
 )
 
 const (
@@ -14,28 +23,36 @@ type DeleteProjectTaskArgs struct {
 	Namespace     string
 	Name          string
 	DeletePvcs    bool
+	Fast          bool
+	TimeoutSec    int64
+	RequestId     string
 }
 
 func (projectImpl *Project)registerDeleteProjectTask() error{
 	return projectImpl.task.RegisterTask(deleteProjectTaskName, projectImpl.DeleteProjectTask)
 }
 
-func (projectImpl *Project)DeleteProjectTask(deleteProjectTaskArgsStr string) error {
+func (projectImpl *Project)DeleteProjectTask(ctx context.Context, deleteProjectTaskArgsStr string) error {
 	deleteProjectTaskArgs := &DeleteProjectTaskArgs{}
 	err := json.Unmarshal([]byte(deleteProjectTaskArgsStr), deleteProjectTaskArgs)
 	if err != nil {
 		logrus.Errorf("delete project task arg is not valid : %s", err.Error())
 		return err
 	}
-	err = projectImpl.doDeleteProject(deleteProjectTaskArgs.Namespace, deleteProjectTaskArgs.Name, deleteProjectTaskArgs.DeletePvcs)
+	defer projectImpl.publishProjectEvent(deleteProjectTaskArgs.Namespace, deleteProjectTaskArgs.Name, projectModel.ProjectEventTaskStateChanged, deleteProjectTaskArgs.RequestId)
+	defer func() {
+		projectImpl.publishProjectTaskEvent(deleteProjectTaskName, deleteProjectTaskArgs.Namespace, deleteProjectTaskArgs.Name, err)
+	}()
+	err = projectImpl.doDeleteProject(ctx, deleteProjectTaskArgs.Namespace, deleteProjectTaskArgs.Name,
+		deleteProjectTaskArgs.DeletePvcs, deleteProjectTaskArgs.Fast, deleteProjectTaskArgs.TimeoutSec)
 	if err != nil {
-		logrus.Errorf("failed to delete project %s/%s : %s", deleteProjectTaskArgs.Namespace, deleteProjectTaskArgs.Name, err.Error())
+		logrus.WithField("requestId", deleteProjectTaskArgs.RequestId).Errorf("failed to delete project %s/%s : %s", deleteProjectTaskArgs.Namespace, deleteProjectTaskArgs.Name, err.Error())
 		return err
 	}
 	return nil
 }
 
-func (projectImpl *Project) doDeleteProject(namespace, name string, deletePvcs bool) error {
+func (projectImpl *Project) doDeleteProject(ctx context.Context, namespace, name string, deletePvcs, fast bool, timeoutSec int64) error {
 	projectInfo, err := projectImpl.GetProjectInfo(namespace, name)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
@@ -46,12 +63,39 @@ func (projectImpl *Project) doDeleteProject(namespace, name string, deletePvcs b
 		return err
 	}
 
-	for _, releaseInfo := range projectInfo.Releases {
-		err = projectImpl.releaseUseCase.DeleteReleaseWithRetry(namespace, releaseInfo.Name,  deletePvcs, false, 0)
+	releasesToDelete := projectInfo.Releases
+	if !fast {
+		releasesToDelete, err = buildReleaseDeletionOrder(projectInfo.Releases)
+		if err != nil {
+			logrus.Warnf("failed to build release deletion order of project %s/%s : %s, fall back to the original order", namespace, name, err.Error())
+			releasesToDelete = projectInfo.Releases
+		} else {
+			releaseNames := make([]string, 0, len(releasesToDelete))
+			for _, releaseInfo := range releasesToDelete {
+				releaseNames = append(releaseNames, releaseInfo.Name)
+			}
+			logrus.Infof("deleting releases of project %s/%s in order %v", namespace, name, releaseNames)
+		}
+	}
+
+	if timeoutSec == 0 {
+		timeoutSec = defaultTimeoutSec
+	}
+	deadline := time.Now().Add(time.Duration(timeoutSec) * time.Second)
+
+	for _, releaseInfo := range releasesToDelete {
+		err = projectImpl.releaseUseCase.DeleteReleaseWithRetry(ctx, namespace, releaseInfo.Name,  deletePvcs, false, 0)
 		if err != nil {
 			logrus.Errorf("failed to delete release %s/%s : %s", namespace, releaseInfo.Name, err.Error())
 			return err
 		}
+		if !fast {
+			err = projectImpl.waitReleaseResourcesTerminated(releaseInfo, deadline)
+			if err != nil {
+				logrus.Errorf("failed to wait resources of release %s/%s to be terminated : %s", namespace, releaseInfo.Name, err.Error())
+				return err
+			}
+		}
 	}
 
 	err = projectImpl.cache.DeleteProjectTask(namespace, name)
@@ -61,3 +105,79 @@ func (projectImpl *Project) doDeleteProject(namespace, name string, deletePvcs b
 
 	return nil
 }
+
+// buildReleaseDeletionOrder builds the same dependency dag as project creation
+// and returns the releases in reverse topological order, so a release is always
+// deleted before the releases it depends on.
+func buildReleaseDeletionOrder(releases []*releaseModel.ReleaseInfoV2) ([]*releaseModel.ReleaseInfoV2, error) {
+	releasesMap := map[string]*releaseModel.ReleaseInfoV2{}
+	var g dag.AcyclicGraph
+
+	for _, releaseInfo := range releases {
+		releasesMap[releaseInfo.Name] = releaseInfo
+		g.Add(releaseInfo)
+	}
+
+	for _, releaseInfo := range releases {
+		for _, dependedRelease := range releaseInfo.Dependencies {
+			if dependency, ok := releasesMap[dependedRelease]; ok && dependency != releaseInfo {
+				g.Connect(dag.BasicEdge(releaseInfo, dependency))
+			}
+		}
+	}
+
+	_, err := g.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	orderedReleases := make([]*releaseModel.ReleaseInfoV2, 0, len(releases))
+	var lock sync.Mutex
+	err = g.Walk(func(v dag.Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+		orderedReleases = append(orderedReleases, v.(*releaseModel.ReleaseInfoV2))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the walk visits dependencies first : reverse it to delete dependents first
+	for i, j := 0, len(orderedReleases)-1; i < j; i, j = i+1, j-1 {
+		orderedReleases[i], orderedReleases[j] = orderedReleases[j], orderedReleases[i]
+	}
+	return orderedReleases, nil
+}
+
+// waitReleaseResourcesTerminated polls the resources a release managed until they
+// are all terminated or the deadline is reached.
+func (projectImpl *Project) waitReleaseResourcesTerminated(releaseInfo *releaseModel.ReleaseInfoV2, deadline time.Time) error {
+	if releaseInfo.Status == nil {
+		return nil
+	}
+	resources := releaseInfo.Status.GetResources()
+	for {
+		remainingResources := []k8sModel.Resource{}
+		for _, resource := range resources {
+			_, err := projectImpl.k8sCache.GetResource(resource.GetKind(), resource.GetNamespace(), resource.GetName())
+			if err != nil {
+				if errorModel.IsNotFoundError(err) {
+					continue
+				}
+				logrus.Errorf("failed to get resource %s %s/%s : %s", resource.GetKind(), resource.GetNamespace(), resource.GetName(), err.Error())
+				return err
+			}
+			remainingResources = append(remainingResources, resource)
+		}
+		if len(remainingResources) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for %d resources of release %s/%s to be terminated",
+				len(remainingResources), releaseInfo.Namespace, releaseInfo.Name)
+		}
+		resources = remainingResources
+		time.Sleep(time.Duration(defaultSleepTimeSecond) * time.Second)
+	}
+}
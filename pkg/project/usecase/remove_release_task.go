@@ -1,8 +1,11 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
+	"WarpCloud/walm/pkg/models/project"
 	"fmt"
 )
 
@@ -15,23 +18,33 @@ type RemoveReleaseTaskArgs struct {
 	Name        string
 	ReleaseName string
 	DeletePvcs  bool
+	RequestId   string
 }
 
 func (projectImpl *Project) registerRemoveReleaseTask() error{
 	return projectImpl.task.RegisterTask(removeReleaseTaskName, projectImpl.RemoveReleaseTask)
 }
 
-func (projectImpl *Project) RemoveReleaseTask(removeReleaseTaskArgsStr string) error {
+func (projectImpl *Project) RemoveReleaseTask(ctx context.Context, removeReleaseTaskArgsStr string) error {
 	removeReleaseTaskArgs := &RemoveReleaseTaskArgs{}
 	err := json.Unmarshal([]byte(removeReleaseTaskArgsStr), removeReleaseTaskArgs)
 	if err != nil {
 		logrus.Errorf("remove release task arg is not valid : %s", err.Error())
 		return err
 	}
-	return projectImpl.doRemoveRelease(removeReleaseTaskArgs.Namespace, removeReleaseTaskArgs.Name, removeReleaseTaskArgs.ReleaseName, removeReleaseTaskArgs.DeletePvcs)
+	defer projectImpl.publishProjectEvent(removeReleaseTaskArgs.Namespace, removeReleaseTaskArgs.Name, project.ProjectEventTaskStateChanged, removeReleaseTaskArgs.RequestId)
+	defer func() {
+		projectImpl.publishProjectTaskEvent(removeReleaseTaskName, removeReleaseTaskArgs.Namespace, removeReleaseTaskArgs.Name, err)
+	}()
+	err = projectImpl.doRemoveRelease(ctx, removeReleaseTaskArgs.Namespace, removeReleaseTaskArgs.Name, removeReleaseTaskArgs.ReleaseName, removeReleaseTaskArgs.DeletePvcs)
+	if err != nil {
+		logrus.WithField("requestId", removeReleaseTaskArgs.RequestId).Errorf("failed to remove release %s in project %s/%s : %s", removeReleaseTaskArgs.ReleaseName, removeReleaseTaskArgs.Namespace, removeReleaseTaskArgs.Name, err.Error())
+		return err
+	}
+	return nil
 }
 
-func (projectImpl *Project) doRemoveRelease(namespace, name, releaseName string, deletePvcs bool) error {
+func (projectImpl *Project) doRemoveRelease(ctx context.Context, namespace, name, releaseName string, deletePvcs bool) error {
 	projectInfo, err := projectImpl.GetProjectInfo(namespace, name)
 	if err != nil {
 		logrus.Errorf("failed to get project info : %s", err.Error())
@@ -50,7 +63,7 @@ func (projectImpl *Project) doRemoveRelease(namespace, name, releaseName string,
 		}
 		for _, affectReleaseParams := range affectReleaseRequest {
 			logrus.Infof("Update BecauseOf Dependency Modified: %v", *affectReleaseParams)
-			err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(namespace, affectReleaseParams,  nil, false, 0, nil)
+			err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(ctx, namespace, affectReleaseParams,  nil, false, 0, nil)
 			if err != nil {
 				logrus.Errorf("RemoveReleaseInProject Other Affected Release install release %s error %v\n", releaseParams.Name, err)
 				return err
@@ -58,7 +71,7 @@ func (projectImpl *Project) doRemoveRelease(namespace, name, releaseName string,
 		}
 	}
 
-	err = projectImpl.releaseUseCase.DeleteReleaseWithRetry(namespace, releaseName,  deletePvcs, false, 0)
+	err = projectImpl.releaseUseCase.DeleteReleaseWithRetry(ctx, namespace, releaseName,  deletePvcs, false, 0)
 	if err != nil {
 		logrus.Errorf("failed to remove release %s/%s in project : %s", releaseName, name, err.Error())
 		return err
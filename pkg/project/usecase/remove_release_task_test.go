@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"testing"
 	"github.com/stretchr/testify/assert"
 	"WarpCloud/walm/pkg/project/mocks"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"errors"
 	releaseMocks "WarpCloud/walm/pkg/release/mocks"
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
 	"WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/task"
 	"WarpCloud/walm/pkg/models/release"
@@ -20,6 +22,7 @@ func TestProject_doRemoveRelease(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
 
 	var mockProjectManager *Project
 
@@ -30,13 +33,14 @@ func TestProject_doRemoveRelease(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache)
 		assert.IsType(t, err, nil)
 	}
 
@@ -115,8 +119,8 @@ func TestProject_doRemoveRelease(t *testing.T) {
 				}).Return(mockTaskState, nil)
 				mockTaskState.On("IsFinished").Return(true)
 				mockTaskState.On("IsSuccess").Return(true)
-				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			err: nil,
 		},
@@ -124,7 +128,7 @@ func TestProject_doRemoveRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.doRemoveRelease("test-ns", "test-name", "B", false)
+		err := mockProjectManager.doRemoveRelease(context.Background(), "test-ns", "test-name", "B", false)
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
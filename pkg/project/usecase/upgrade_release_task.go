@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
 	"WarpCloud/walm/pkg/models/release"
@@ -15,29 +17,39 @@ type UpgradeReleaseTaskArgs struct {
 	Namespace     string
 	ProjectName   string
 	ReleaseParams *release.ReleaseRequestV2
+	RequestId     string
 }
 
 func (projectImpl *Project) registerUpgradeReleaseTask() error{
 	return projectImpl.task.RegisterTask(upgradeReleaseTaskName, projectImpl.UpgradeReleaseTask)
 }
 
-func (projectImpl *Project) UpgradeReleaseTask(upgradeReleaseTaskArgsStr string) error {
+func (projectImpl *Project) UpgradeReleaseTask(ctx context.Context, upgradeReleaseTaskArgsStr string) error {
 	upgradeReleaseTaskArgs := &UpgradeReleaseTaskArgs{}
 	err := json.Unmarshal([]byte(upgradeReleaseTaskArgsStr), upgradeReleaseTaskArgs)
 	if err != nil {
 		logrus.Errorf("upgrade release task arg is not valid : %s", err.Error())
 		return err
 	}
-	return projectImpl.upgradeRelease(upgradeReleaseTaskArgs.Namespace, upgradeReleaseTaskArgs.ProjectName, upgradeReleaseTaskArgs.ReleaseParams)
+	defer projectImpl.publishProjectEvent(upgradeReleaseTaskArgs.Namespace, upgradeReleaseTaskArgs.ProjectName, project.ProjectEventTaskStateChanged, upgradeReleaseTaskArgs.RequestId)
+	defer func() {
+		projectImpl.publishProjectTaskEvent(upgradeReleaseTaskName, upgradeReleaseTaskArgs.Namespace, upgradeReleaseTaskArgs.ProjectName, err)
+	}()
+	err = projectImpl.upgradeRelease(ctx, upgradeReleaseTaskArgs.Namespace, upgradeReleaseTaskArgs.ProjectName, upgradeReleaseTaskArgs.ReleaseParams)
+	if err != nil {
+		logrus.WithField("requestId", upgradeReleaseTaskArgs.RequestId).Errorf("failed to upgrade release %s in project %s/%s : %s", upgradeReleaseTaskArgs.ReleaseParams.Name, upgradeReleaseTaskArgs.Namespace, upgradeReleaseTaskArgs.ProjectName, err.Error())
+		return err
+	}
+	return nil
 }
 
-func (projectImpl *Project) upgradeRelease(namespace, projectName string, releaseParams *release.ReleaseRequestV2) (err error) {
+func (projectImpl *Project) upgradeRelease(ctx context.Context, namespace, projectName string, releaseParams *release.ReleaseRequestV2) (err error) {
 	if releaseParams.ReleaseLabels == nil {
 		releaseParams.ReleaseLabels = map[string]string{}
 	}
 	releaseParams.ReleaseLabels[project.ProjectNameLabelKey] = projectName
 
-	err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(namespace, releaseParams,  nil, false, 0, nil)
+	err = projectImpl.releaseUseCase.InstallUpgradeReleaseWithRetry(ctx, namespace, releaseParams,  nil, false, 0, nil)
 	if err != nil {
 		logrus.Errorf("failed to upgrade release %s in project %s/%s : %s", releaseParams.Name, namespace, projectName, err.Error())
 		return
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"testing"
 	"github.com/stretchr/testify/assert"
 	"WarpCloud/walm/pkg/project/mocks"
@@ -8,6 +9,7 @@ import (
 	taskMocks "WarpCloud/walm/pkg/task/mocks"
 	"github.com/stretchr/testify/mock"
 	releaseMocks "WarpCloud/walm/pkg/release/mocks"
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
 	"WarpCloud/walm/pkg/models/release"
 	"github.com/pkg/errors"
 )
@@ -18,6 +20,7 @@ func TestProject_doUpgradeRelease(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
 
 	var mockProjectManager *Project
 
@@ -28,13 +31,14 @@ func TestProject_doUpgradeRelease(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache)
 		assert.IsType(t, err, nil)
 	}
 
@@ -45,14 +49,14 @@ func TestProject_doUpgradeRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			err: nil,
 		},
 		{
 			initMock: func() {
 				refreshMocks()
-				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+				mockReleaseUseCase.On("InstallUpgradeReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
 			},
 			err: errors.New(""),
 		},
@@ -60,7 +64,7 @@ func TestProject_doUpgradeRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.upgradeRelease("test-ns", "test-name", &release.ReleaseRequestV2{})
+		err := mockProjectManager.upgradeRelease(context.Background(), "test-ns", "test-name", &release.ReleaseRequestV2{})
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -1,8 +1,12 @@
 package usecase
 
 import (
+	"context"
+
 	projectModel "WarpCloud/walm/pkg/models/project"
 	releaseModel "WarpCloud/walm/pkg/models/release"
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	taskModel "WarpCloud/walm/pkg/models/task"
 	"github.com/sirupsen/logrus"
 	"WarpCloud/walm/pkg/project"
 	"WarpCloud/walm/pkg/task"
@@ -12,8 +16,18 @@ import (
 	"sync"
 	"errors"
 	"encoding/json"
+	"strings"
 	"WarpCloud/walm/pkg/util/dag"
 	"WarpCloud/walm/pkg/helm"
+	"WarpCloud/walm/pkg/k8s"
+	"WarpCloud/walm/pkg/redis"
+	"WarpCloud/walm/pkg/schedule"
+	"WarpCloud/walm/pkg/audit"
+	auditModel "WarpCloud/walm/pkg/models/audit"
+	"WarpCloud/walm/pkg/event"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	"WarpCloud/walm/pkg/util"
+	"time"
 )
 
 const (
@@ -28,9 +42,73 @@ type Project struct {
 	task           task.Task
 	releaseUseCase release.UseCase
 	helm           helm.Helm
+	k8sCache       k8s.Cache
+	redis          redis.Redis
+	auditWriter    audit.Writer
+	eventPublisher event.Publisher
+	scheduleStore  schedule.Store
+}
+
+// SetAuditWriter enables audit logging of the mutating operations. The writer
+// stays optional, so callers not using audit keep the old behavior.
+func (projectImpl *Project) SetAuditWriter(writer audit.Writer) {
+	projectImpl.auditWriter = writer
+}
+
+// SetEventPublisher enables publishing project lifecycle events to an external
+// system. The publisher stays optional, like the audit writer.
+func (projectImpl *Project) SetEventPublisher(publisher event.Publisher) {
+	projectImpl.eventPublisher = publisher
+}
+
+// SetScheduleStore enables showing the operations scheduled on a project in
+// its project info. The store stays optional, like the audit writer.
+func (projectImpl *Project) SetScheduleStore(scheduleStore schedule.Store) {
+	projectImpl.scheduleStore = scheduleStore
+}
+
+// publishProjectTaskEvent publishes that a project task reached a terminal
+// state, when an external publisher is configured. The publisher never blocks,
+// so this is safe to call from the task runners.
+func (projectImpl *Project) publishProjectTaskEvent(taskName, namespace, name string, taskErr error) {
+	if projectImpl.eventPublisher == nil {
+		return
+	}
+	lifecycleEvent := &eventModel.LifecycleEvent{
+		Kind:      eventModel.ProjectEventKind,
+		Type:      taskName,
+		Namespace: namespace,
+		Name:      name,
+		Project:   name,
+		TaskUuid:  projectImpl.latestProjectTaskUuid(namespace, name),
+		Succeeded: taskErr == nil,
+		Timestamp: time.Now(),
+	}
+	if taskErr != nil {
+		lifecycleEvent.Error = taskErr.Error()
+	}
+	projectImpl.eventPublisher.Publish(lifecycleEvent)
+}
+
+func (projectImpl *Project) latestProjectTaskUuid(namespace, name string) string {
+	projectTask, err := projectImpl.cache.GetProjectTask(namespace, name)
+	if err != nil {
+		if !errorModel.IsNotFoundError(err) {
+			logrus.Warnf("failed to get project task of %s/%s : %s", namespace, name, err.Error())
+		}
+		return ""
+	}
+	if projectTask.LatestTaskSignature == nil {
+		return ""
+	}
+	return projectTask.LatestTaskSignature.UUID
 }
 
-func (projectImpl *Project) ListProjects(namespace string) (*projectModel.ProjectInfoList, error) {
+// ListProjects lists the projects of the namespace, all of them when the
+// namespace is empty. Without detail the infos are built from the caches
+// alone, so listing many projects does not fan out into the release
+// readiness computation.
+func (projectImpl *Project) ListProjects(namespace string, detail bool) (*projectModel.ProjectInfoList, error) {
 	projectTasks, err := projectImpl.cache.GetProjectTasks(namespace)
 	if err != nil {
 		logrus.Errorf("failed to get project tasks in namespace %s : %s", namespace, err.Error())
@@ -41,13 +119,53 @@ func (projectImpl *Project) ListProjects(namespace string) (*projectModel.Projec
 		Items: []*projectModel.ProjectInfo{},
 	}
 
+	if !detail {
+		for _, projectTask := range projectTasks {
+			projectInfo, err := projectImpl.buildProjectInfoSummary(projectTask)
+			if err != nil {
+				logrus.Errorf("failed to build project info summary of %s/%s : %s", projectTask.Namespace, projectTask.Name, err.Error())
+				return nil, err
+			}
+			projectInfoList.Items = append(projectInfoList.Items, projectInfo)
+		}
+		projectInfoList.Num = len(projectInfoList.Items)
+		return projectInfoList, nil
+	}
+
+	// one pass over the namespace's releases replaces the per project label
+	// query : the releases are grouped by their project label in memory
+	releases, err := projectImpl.releaseUseCase.ListReleases(namespace)
+	if err != nil {
+		logrus.Errorf("failed to list releases in namespace %s : %s", namespace, err.Error())
+		return nil, err
+	}
+	releasesByProject := map[string][]*releaseModel.ReleaseInfoV2{}
+	for _, releaseInfo := range releases {
+		projectName, ok := releaseInfo.ReleaseLabels[projectModel.ProjectNameLabelKey]
+		if !ok || projectName == "" {
+			continue
+		}
+		projectKey := releaseInfo.Namespace + "/" + projectName
+		releasesByProject[projectKey] = append(releasesByProject[projectKey], releaseInfo)
+	}
+
 	mux := &sync.Mutex{}
 	var wg sync.WaitGroup
 	for _, projectTask := range projectTasks {
 		wg.Add(1)
 		go func(projectTask *projectModel.ProjectTask) {
 			defer wg.Done()
-			projectInfo, err1 := projectImpl.buildProjectInfo(projectTask)
+			var projectInfo *projectModel.ProjectInfo
+			var err1 error
+			projectReleases := releasesByProject[projectTask.Namespace+"/"+projectTask.Name]
+			if len(projectReleases) > 0 {
+				projectInfo, err1 = projectImpl.buildProjectInfoFromReleases(projectTask, projectReleases)
+			} else {
+				// releases installed before the project label was guaranteed
+				// on their release configs are not in the groups, the label
+				// query backfills them
+				projectInfo, err1 = projectImpl.buildProjectInfo(projectTask)
+			}
 			if err1 != nil {
 				logrus.Errorf("failed to build project info from project cache of %s/%s : %s", projectTask.Namespace, projectTask.Name, err1.Error())
 				err = errors.New(err1.Error())
@@ -76,12 +194,41 @@ func (projectImpl *Project) GetProjectInfo(namespace, projectName string) (*proj
 		return nil, err
 	}
 
-	return projectImpl.buildProjectInfo(projectTask)
+	projectInfo, err := projectImpl.buildProjectInfo(projectTask)
+	if err != nil {
+		return nil, err
+	}
+	projectInfo.ScheduledOperations = projectImpl.getScheduledOperations(namespace, projectName)
+	return projectInfo, nil
 }
 
-func (projectImpl *Project) CreateProject(namespace string, project string, projectParams *projectModel.ProjectParams, async bool, timeoutSec int64) error {
+// getScheduledOperations lists the operations scheduled on the project, so
+// they show up in the project info as pending. Failing to list them does not
+// fail getting the project, it is only logged.
+func (projectImpl *Project) getScheduledOperations(namespace, projectName string) []*scheduleModel.ScheduledOperation {
+	if projectImpl.scheduleStore == nil {
+		return nil
+	}
+	operationList, err := projectImpl.scheduleStore.ListScheduledOperations(namespace)
+	if err != nil {
+		logrus.Warnf("failed to list scheduled operations of namespace %s : %s", namespace, err.Error())
+		return nil
+	}
+	scheduledOperations := []*scheduleModel.ScheduledOperation{}
+	for _, operation := range operationList.Items {
+		if operation.Kind == scheduleModel.ProjectKind && operation.Name == projectName {
+			scheduledOperations = append(scheduledOperations, operation)
+		}
+	}
+	return scheduledOperations
+}
+
+func (projectImpl *Project) CreateProject(ctx context.Context, namespace string, project string, projectParams *projectModel.ProjectParams, async bool, timeoutSec int64, principal string, requestId string) (taskSig *taskModel.TaskSig, err error) {
+	defer func() {
+		projectImpl.writeAuditRecord("createProject", principal, requestId, namespace, project, buildProjectParamsAuditArgs(projectParams), taskSig, err)
+	}()
 	if len(projectParams.Releases) == 0 {
-		return errors.New("project releases can not be empty")
+		return nil, errorModel.ValidationError{Message: "project releases can not be empty"}
 	}
 
 	if timeoutSec == 0 {
@@ -91,21 +238,22 @@ func (projectImpl *Project) CreateProject(namespace string, project string, proj
 	oldProjectTask, err := projectImpl.validateProjectTask(namespace, project, true)
 	if err != nil {
 		logrus.Errorf("failed to validate project task : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	createProjectTaskArgs := &CreateProjectTaskArgs{
 		Name:          project,
 		Namespace:     namespace,
 		ProjectParams: projectParams,
+		RequestId:     requestId,
 	}
-	err = projectImpl.sendProjectTask(namespace, project, createProjectTaskName, createProjectTaskArgs, oldProjectTask, timeoutSec, async)
+	taskSig, err = projectImpl.sendProjectTask(ctx, namespace, project, createProjectTaskName, createProjectTaskArgs, oldProjectTask, timeoutSec, async, requestId)
 	if err != nil {
 		logrus.Errorf("failed to send project task %s of %s/%s : %s", createProjectTaskName, namespace, project, err.Error())
-		return err
+		return nil, err
 	}
 	logrus.Infof("succeed to create project %s/%s", namespace, project)
-	return nil
+	return taskSig, nil
 }
 
 func (projectImpl *Project) validateProjectTask(namespace, name string, allowProjectNotExist bool) (projectTask *projectModel.ProjectTask, err error) {
@@ -132,7 +280,7 @@ func (projectImpl *Project) validateProjectTask(namespace, name string, allowPro
 		}
 
 		if !(taskState.IsFinished() || taskState.IsTimeout()) {
-			err = fmt.Errorf("please wait for the last project task %s-%s finished or timeout", projectTask.LatestTaskSignature.Name, projectTask.LatestTaskSignature.UUID)
+			err = errorModel.NewConflictError(fmt.Sprintf("please wait for the last project task %s-%s finished or timeout", projectTask.LatestTaskSignature.Name, projectTask.LatestTaskSignature.UUID))
 			logrus.Warn(err.Error())
 			return projectTask, err
 		}
@@ -140,15 +288,18 @@ func (projectImpl *Project) validateProjectTask(namespace, name string, allowPro
 	return
 }
 
-func (projectImpl *Project) DeleteProject(namespace string, project string, async bool, timeoutSec int64, deletePvcs bool) error {
+func (projectImpl *Project) DeleteProject(ctx context.Context, namespace string, project string, async bool, timeoutSec int64, deletePvcs bool, fast bool, principal string, requestId string) (taskSig *taskModel.TaskSig, err error) {
+	defer func() {
+		projectImpl.writeAuditRecord("deleteProject", principal, requestId, namespace, project, map[string]interface{}{"deletePvcs": deletePvcs, "fast": fast}, taskSig, err)
+	}()
 	oldProjectTask, err := projectImpl.validateProjectTask(namespace, project, false)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
 			logrus.Warnf("project %s/%s is not found", namespace, project)
-			return nil
+			return nil, nil
 		}
 		logrus.Errorf("failed to validate project job : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	if timeoutSec == 0 {
@@ -159,28 +310,59 @@ func (projectImpl *Project) DeleteProject(namespace string, project string, asyn
 		Name:       project,
 		Namespace:  namespace,
 		DeletePvcs: deletePvcs,
+		Fast:       fast,
+		TimeoutSec: timeoutSec,
+		RequestId:  requestId,
 	}
 
-	err = projectImpl.sendProjectTask(namespace, project, deleteProjectTaskName, deleteProjectTaskArgs, oldProjectTask, timeoutSec, async)
+	taskSig, err = projectImpl.sendProjectTask(ctx, namespace, project, deleteProjectTaskName, deleteProjectTaskArgs, oldProjectTask, timeoutSec, async, requestId)
 	if err != nil {
 		logrus.Errorf("failed to send project task %s of %s/%s : %s", deleteProjectTaskName, namespace, project, err.Error())
-		return err
+		return nil, err
 	}
 	logrus.Infof("succeed to delete project %s/%s", namespace, project)
 
-	return nil
+	return taskSig, nil
+}
+// DryRunDeleteProject previews the live resources deleting the project would remove,
+// release by release, without mutating anything.
+func (projectImpl *Project) DryRunDeleteProject(namespace string, project string, deletePvcs bool) (*projectModel.ProjectDeletePreview, error) {
+	projectInfo, err := projectImpl.GetProjectInfo(namespace, project)
+	if err != nil {
+		logrus.Errorf("failed to get project info %s/%s : %s", namespace, project, err.Error())
+		return nil, err
+	}
+
+	preview := &projectModel.ProjectDeletePreview{
+		Name:      project,
+		Namespace: namespace,
+		Releases:  []*releaseModel.ReleaseDeletePreview{},
+	}
+	for _, releaseInfo := range projectInfo.Releases {
+		releasePreview, err := projectImpl.releaseUseCase.DryRunDeleteRelease(namespace, releaseInfo.Name, deletePvcs)
+		if err != nil {
+			logrus.Errorf("failed to dry run delete release %s/%s : %s", namespace, releaseInfo.Name, err.Error())
+			return nil, err
+		}
+		preview.Releases = append(preview.Releases, releasePreview)
+	}
+	return preview, nil
 }
-func (projectImpl *Project) AddReleasesInProject(namespace string, projectName string,
-	projectParams *projectModel.ProjectParams, async bool, timeoutSec int64) error {
+
+func (projectImpl *Project) AddReleasesInProject(ctx context.Context, namespace string, projectName string,
+	projectParams *projectModel.ProjectParams, async bool, timeoutSec int64, principal string, requestId string) (taskSig *taskModel.TaskSig, err error) {
+	defer func() {
+		projectImpl.writeAuditRecord("addReleasesInProject", principal, requestId, namespace, projectName, buildProjectParamsAuditArgs(projectParams), taskSig, err)
+	}()
 
 	if len(projectParams.Releases) == 0 {
-		return errors.New("project releases can not be empty")
+		return nil, errorModel.ValidationError{Message: "project releases can not be empty"}
 	}
 
 	oldProjectTask, err := projectImpl.validateProjectTask(namespace, projectName, true)
 	if err != nil {
 		logrus.Errorf("failed to validate project job : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	if timeoutSec == 0 {
@@ -191,34 +373,39 @@ func (projectImpl *Project) AddReleasesInProject(namespace string, projectName s
 		Name:          projectName,
 		Namespace:     namespace,
 		ProjectParams: projectParams,
+		RequestId:     requestId,
 	}
 
-	err = projectImpl.sendProjectTask(namespace, projectName, addReleaseTaskName, taskArgs, oldProjectTask, timeoutSec, async)
+	taskSig, err = projectImpl.sendProjectTask(ctx, namespace, projectName, addReleaseTaskName, taskArgs, oldProjectTask, timeoutSec, async, requestId)
 	if err != nil {
 		logrus.Errorf("failed to send project task %s of %s/%s : %s", addReleaseTaskName, namespace, projectName, err.Error())
-		return err
+		return nil, err
 	}
 	logrus.Infof("succeed to add releases in project %s/%s", namespace, projectName)
 
-	return nil
+	return taskSig, nil
 }
 
-func (projectImpl *Project) UpgradeReleaseInProject(namespace string, projectName string,
-	releaseParams *releaseModel.ReleaseRequestV2, async bool, timeoutSec int64) error {
+func (projectImpl *Project) UpgradeReleaseInProject(ctx context.Context, namespace string, projectName string,
+	releaseParams *releaseModel.ReleaseRequestV2, async bool, timeoutSec int64, principal string, requestId string) (taskSig *taskModel.TaskSig, err error) {
+	defer func() {
+		projectImpl.writeAuditRecord("upgradeReleaseInProject", principal, requestId, namespace, projectName,
+			map[string]interface{}{"release": releaseParams.Name, "configValues": util.MaskSensitiveValues(releaseParams.ConfigValues)}, taskSig, err)
+	}()
 	oldProjectTask, err := projectImpl.validateProjectTask(namespace, projectName, false)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
 			logrus.Warnf("project %s/%s is not found", namespace, projectName)
-			return nil
+			return nil, nil
 		}
 		logrus.Errorf("failed to validate project job : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	projectInfo, err := projectImpl.buildProjectInfo(oldProjectTask)
 	if err != nil {
 		logrus.Errorf("failed to build project info : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	releaseExistsInProject := false
@@ -232,7 +419,7 @@ func (projectImpl *Project) UpgradeReleaseInProject(namespace string, projectNam
 	if !releaseExistsInProject {
 		err = fmt.Errorf("release %s is not found in project %s", releaseParams.Name, projectName)
 		logrus.Error(err.Error())
-		return err
+		return nil, err
 	}
 
 	if timeoutSec == 0 {
@@ -243,34 +430,39 @@ func (projectImpl *Project) UpgradeReleaseInProject(namespace string, projectNam
 		ProjectName:   projectName,
 		Namespace:     namespace,
 		ReleaseParams: releaseParams,
+		RequestId:     requestId,
 	}
 
-	err = projectImpl.sendProjectTask(namespace, projectName, upgradeReleaseTaskName, taskArgs, oldProjectTask, timeoutSec, async)
+	taskSig, err = projectImpl.sendProjectTask(ctx, namespace, projectName, upgradeReleaseTaskName, taskArgs, oldProjectTask, timeoutSec, async, requestId)
 	if err != nil {
 		logrus.Errorf("failed to send project task %s of %s/%s : %s", upgradeReleaseTaskName, namespace, projectName, err.Error())
-		return err
+		return nil, err
 	}
 	logrus.Infof("succeed to upgrade release %s in project %s/%s", releaseParams.Name, namespace, projectName)
 
-	return nil
+	return taskSig, nil
 }
 
-func (projectImpl *Project) RemoveReleaseInProject(namespace, projectName,
-releaseName string, async bool, timeoutSec int64, deletePvcs bool) error {
+func (projectImpl *Project) RemoveReleaseInProject(ctx context.Context, namespace, projectName,
+releaseName string, async bool, timeoutSec int64, deletePvcs bool, principal string, requestId string) (taskSig *taskModel.TaskSig, err error) {
+	defer func() {
+		projectImpl.writeAuditRecord("removeReleaseInProject", principal, requestId, namespace, projectName,
+			map[string]interface{}{"release": releaseName, "deletePvcs": deletePvcs}, taskSig, err)
+	}()
 	oldProjectTask, err := projectImpl.validateProjectTask(namespace, projectName, false)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
 			logrus.Warnf("project %s/%s is not found", namespace, projectName)
-			return nil
+			return nil, nil
 		}
 		logrus.Errorf("failed to validate project job : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	projectInfo, err := projectImpl.buildProjectInfo(oldProjectTask)
 	if err != nil {
 		logrus.Errorf("failed to build project info : %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	releaseExistsInProject := false
@@ -283,7 +475,17 @@ releaseName string, async bool, timeoutSec int64, deletePvcs bool) error {
 
 	if !releaseExistsInProject {
 		logrus.Warnf("release %s is not found in project %s", releaseName, projectName)
-		return nil
+		return nil, nil
+	}
+
+	dependents, err := projectImpl.releaseUseCase.GetReleaseDependents(namespace, releaseName)
+	if err != nil {
+		logrus.Warnf("failed to get dependents of release %s/%s : %s", namespace, releaseName, err.Error())
+	} else {
+		for _, dependent := range dependents {
+			logrus.Warnf("release %s/%s to be removed from project %s is still depended on by release %s/%s",
+				namespace, releaseName, projectName, dependent.Namespace, dependent.Name)
+		}
 	}
 
 	if timeoutSec == 0 {
@@ -295,28 +497,34 @@ releaseName string, async bool, timeoutSec int64, deletePvcs bool) error {
 		Namespace:   namespace,
 		ReleaseName: releaseName,
 		DeletePvcs:  deletePvcs,
+		RequestId:   requestId,
 	}
 
-	err = projectImpl.sendProjectTask(namespace, projectName, removeReleaseTaskName, taskArgs, oldProjectTask, timeoutSec, async)
+	taskSig, err = projectImpl.sendProjectTask(ctx, namespace, projectName, removeReleaseTaskName, taskArgs, oldProjectTask, timeoutSec, async, requestId)
 	if err != nil {
 		logrus.Errorf("failed to send project task %s of %s/%s : %s", removeReleaseTaskName, namespace, projectName, err.Error())
-		return err
+		return nil, err
 	}
 	logrus.Infof("succeed to remove release %s in project %s/%s", releaseName, namespace, projectName)
 
-	return nil
+	return taskSig, nil
 }
 
 func (projectImpl *Project) buildProjectInfo(task *projectModel.ProjectTask) (projectInfo *projectModel.ProjectInfo, err error) {
+	releases, err := projectImpl.releaseUseCase.ListReleasesByLabels(task.Namespace, projectModel.ProjectNameLabelKey+"="+task.Name)
+	if err != nil {
+		return nil, err
+	}
+	return projectImpl.buildProjectInfoFromReleases(task, releases)
+}
+
+// buildProjectInfoFromReleases is buildProjectInfo with the releases already
+// fetched, so batch callers do not repeat the label query per project.
+func (projectImpl *Project) buildProjectInfoFromReleases(task *projectModel.ProjectTask, releases []*releaseModel.ReleaseInfoV2) (projectInfo *projectModel.ProjectInfo, err error) {
 	projectInfo = &projectModel.ProjectInfo{
 		Namespace: task.Namespace,
 		Name:      task.Name,
-		Releases:  []*releaseModel.ReleaseInfoV2{},
-	}
-
-	projectInfo.Releases, err = projectImpl.releaseUseCase.ListReleasesByLabels(task.Namespace, projectModel.ProjectNameLabelKey+"="+task.Name)
-	if err != nil {
-		return nil, err
+		Releases:  releases,
 	}
 
 	taskState, err := projectImpl.task.GetTaskState(task.LatestTaskSignature)
@@ -330,6 +538,7 @@ func (projectImpl *Project) buildProjectInfo(task *projectModel.ProjectTask) (pr
 		return nil, err
 	}
 
+	projectInfo.LatestTaskState = projectTaskStateName(taskState)
 	if taskState.IsFinished() {
 		if taskState.IsSuccess() {
 			projectInfo.Ready, projectInfo.Message = isProjectReadyByReleases(projectInfo.Releases)
@@ -343,14 +552,87 @@ func (projectImpl *Project) buildProjectInfo(task *projectModel.ProjectTask) (pr
 	return
 }
 
+// buildProjectInfoSummary is buildProjectInfo without the release readiness
+// computation : the release configs from the informer cache name the releases
+// and their charts, and Ready only reflects the latest task state, so the
+// list page does not pay for the full status of every release.
+func (projectImpl *Project) buildProjectInfoSummary(projectTask *projectModel.ProjectTask) (projectInfo *projectModel.ProjectInfo, err error) {
+	projectInfo = &projectModel.ProjectInfo{
+		Namespace: projectTask.Namespace,
+		Name:      projectTask.Name,
+		Releases:  []*releaseModel.ReleaseInfoV2{},
+	}
+
+	releaseConfigs, err := projectImpl.k8sCache.ListReleaseConfigs(projectTask.Namespace, projectModel.ProjectNameLabelKey+"="+projectTask.Name)
+	if err != nil {
+		logrus.Errorf("failed to list release configs of project %s/%s : %s", projectTask.Namespace, projectTask.Name, err.Error())
+		return nil, err
+	}
+	for _, releaseConfig := range releaseConfigs {
+		releaseInfo := &releaseModel.ReleaseInfoV2{}
+		releaseInfo.Namespace = releaseConfig.Namespace
+		releaseInfo.Name = releaseConfig.Name
+		releaseInfo.ChartName = releaseConfig.ChartName
+		releaseInfo.ChartVersion = releaseConfig.ChartVersion
+		releaseInfo.ChartAppVersion = releaseConfig.ChartAppVersion
+		projectInfo.Releases = append(projectInfo.Releases, releaseInfo)
+	}
+
+	taskState, err := projectImpl.task.GetTaskState(projectTask.LatestTaskSignature)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			err = nil
+			projectInfo.Ready = len(projectInfo.Releases) > 0
+			return
+		}
+		logrus.Errorf("failed to get task state : %s", err.Error())
+		return nil, err
+	}
+
+	projectInfo.LatestTaskState = projectTaskStateName(taskState)
+	if taskState.IsFinished() {
+		if taskState.IsSuccess() {
+			projectInfo.Ready = len(projectInfo.Releases) > 0
+		} else {
+			projectInfo.Message = fmt.Sprintf("the project latest task %s-%s failed : %s", projectTask.LatestTaskSignature.Name, projectTask.LatestTaskSignature.UUID, taskState.GetErrorMsg())
+		}
+	} else {
+		projectInfo.Message = fmt.Sprintf("please wait for the project latest task %s-%s finished", projectTask.LatestTaskSignature.Name, projectTask.LatestTaskSignature.UUID)
+	}
+
+	return
+}
+
+// projectTaskStateName flattens the task state to the word the list page shows.
+func projectTaskStateName(taskState task.TaskState) string {
+	if !taskState.IsFinished() {
+		return "Running"
+	}
+	if taskState.IsSuccess() {
+		return "Succeed"
+	}
+	return "Failed"
+}
+
 func isProjectReadyByReleases(releases []*releaseModel.ReleaseInfoV2) (ready bool, message string) {
 	if len(releases) > 0 {
 		ready = true
+		// propagate the most severe not-ready reason to the project message
+		var notReadyRelease *releaseModel.ReleaseInfoV2
 		for _, releaseInfo := range releases {
 			if !releaseInfo.Ready {
 				ready = false
-				message = releaseInfo.Message
-				break
+				if notReadyRelease == nil ||
+					(releaseInfo.ReadyReason != notReadyRelease.ReadyReason &&
+						releaseModel.MoreSevereReadyReason(notReadyRelease.ReadyReason, releaseInfo.ReadyReason) == releaseInfo.ReadyReason) {
+					notReadyRelease = releaseInfo
+				}
+			}
+		}
+		if notReadyRelease != nil {
+			message = notReadyRelease.Message
+			if notReadyRelease.ReadyReason != "" {
+				message = notReadyRelease.ReadyReason + ": " + message
 			}
 		}
 	} else {
@@ -359,20 +641,21 @@ func isProjectReadyByReleases(releases []*releaseModel.ReleaseInfoV2) (ready boo
 	return
 }
 
-func (projectImpl *Project) sendProjectTask(namespace, projectName, taskName string, taskArgs interface{},
-	oldProjectTask *projectModel.ProjectTask, timeoutSec int64, async bool) (error) {
+func (projectImpl *Project) sendProjectTask(ctx context.Context, namespace, projectName, taskName string, taskArgs interface{},
+	oldProjectTask *projectModel.ProjectTask, timeoutSec int64, async bool, requestId string) (*taskModel.TaskSig, error) {
 
 	taskArgsStr, err := json.Marshal(taskArgs)
 	if err != nil {
 		logrus.Errorf("failed to marshal task args : %s", err.Error())
-		return err
+		return nil, err
 	}
 
-	taskSig, err := projectImpl.task.SendTask(taskName, string(taskArgsStr), timeoutSec)
+	taskSig, err := projectImpl.task.SendTask(ctx, taskName, string(taskArgsStr), timeoutSec)
 	if err != nil {
 		logrus.Errorf("failed to send %s : %s", taskName, err.Error())
-		return err
+		return nil, err
 	}
+	taskSig.RequestId = requestId
 
 	projectTask := &projectModel.ProjectTask{
 		Namespace:           namespace,
@@ -383,8 +666,9 @@ func (projectImpl *Project) sendProjectTask(namespace, projectName, taskName str
 	err = projectImpl.cache.CreateOrUpdateProjectTask(projectTask)
 	if err != nil {
 		logrus.Errorf("failed to set project task of %s/%s to redis: %s", namespace, projectName, err.Error())
-		return err
+		return nil, err
 	}
+	projectImpl.publishProjectEvent(namespace, projectName, projectModel.ProjectEventTaskStateChanged, requestId)
 
 	if oldProjectTask != nil && oldProjectTask.LatestTaskSignature != nil {
 		_ = projectImpl.task.PurgeTaskState(oldProjectTask.LatestTaskSignature)
@@ -394,16 +678,117 @@ func (projectImpl *Project) sendProjectTask(namespace, projectName, taskName str
 		err = projectImpl.task.TouchTask(taskSig, defaultSleepTimeSecond)
 		if err != nil {
 			logrus.Errorf("project task %s of %s/%s is failed or timeout: %s", taskName, namespace, projectName, err.Error())
-			return err
+			return nil, err
 		}
 	}
 
+	return taskSig, nil
+}
+
+// publishProjectEvent broadcasts a project event on the shared redis channel,
+// so streaming endpoints get notified without polling. The events are
+// advisory, so publish failures are only logged.
+func (projectImpl *Project) publishProjectEvent(namespace, projectName, eventType, requestId string) {
+	if projectImpl.redis == nil {
+		return
+	}
+	event := &projectModel.ProjectEvent{
+		Type:      eventType,
+		RequestId: requestId,
+		Namespace: namespace,
+		Name:      projectName,
+	}
+	eventStr, err := json.Marshal(event)
+	if err != nil {
+		logrus.Warnf("failed to marshal project event of %s/%s : %s", namespace, projectName, err.Error())
+		return
+	}
+	err = projectImpl.redis.Publish(redis.WalmProjectEventsChannel, string(eventStr))
+	if err != nil {
+		logrus.Warnf("failed to publish project event of %s/%s : %s", namespace, projectName, err.Error())
+	}
+}
+
+// GetProjectWebhooks returns the webhooks routing the lifecycle events of the
+// project, an empty list when none are configured.
+func (projectImpl *Project) GetProjectWebhooks(namespace, projectName string) ([]*projectModel.ProjectWebhook, error) {
+	webhooks, err := projectImpl.cache.GetProjectWebhooks(namespace, projectName)
+	if err != nil {
+		logrus.Errorf("failed to get project webhooks of %s/%s : %s", namespace, projectName, err.Error())
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// SetProjectWebhooks replaces the webhooks of the project, an empty list falls
+// back to the globally configured endpoints.
+func (projectImpl *Project) SetProjectWebhooks(namespace, projectName string, webhooks []*projectModel.ProjectWebhook, principal string, requestId string) error {
+	err := projectImpl.cache.SetProjectWebhooks(namespace, projectName, webhooks)
+	urls := []string{}
+	for _, webhook := range webhooks {
+		if webhook != nil {
+			urls = append(urls, webhook.Url)
+		}
+	}
+	projectImpl.writeAuditRecord("setProjectWebhooks", principal, requestId, namespace, projectName,
+		map[string]interface{}{"webhooks": urls}, nil, err)
+	if err != nil {
+		logrus.Errorf("failed to set project webhooks of %s/%s : %s", namespace, projectName, err.Error())
+		return err
+	}
 	return nil
 }
 
-func (projectImpl *Project) autoCreateReleaseDependencies(projectParams *projectModel.ProjectParams) ([]*releaseModel.ReleaseRequestV2, error) {
+// writeAuditRecord appends the outcome of a mutating operation to the audit
+// log. The records are advisory, so write failures are only logged.
+func (projectImpl *Project) writeAuditRecord(operation, principal, requestId, namespace, name string, args map[string]interface{}, taskSig *taskModel.TaskSig, opErr error) {
+	if projectImpl.auditWriter == nil {
+		return
+	}
+	record := &auditModel.Record{
+		Time:      time.Now(),
+		RequestId: requestId,
+		Principal: principal,
+		Operation: operation,
+		Namespace: namespace,
+		Name:      name,
+		Args:      args,
+		Succeeded: opErr == nil,
+	}
+	if taskSig != nil {
+		record.TaskUUID = taskSig.UUID
+	}
+	if opErr != nil {
+		record.Error = opErr.Error()
+	}
+	err := projectImpl.auditWriter.Write(record)
+	if err != nil {
+		logrus.Warnf("failed to write audit record of %s %s/%s : %s", operation, namespace, name, err.Error())
+	}
+}
+
+// buildProjectParamsAuditArgs keeps the audited args small and masks the
+// sensitive config values with the same rules as the api responses.
+func buildProjectParamsAuditArgs(projectParams *projectModel.ProjectParams) map[string]interface{} {
+	if projectParams == nil {
+		return nil
+	}
+	releaseNames := []string{}
+	for _, releaseParams := range projectParams.Releases {
+		if releaseParams != nil {
+			releaseNames = append(releaseNames, releaseParams.Name)
+		}
+	}
+	return map[string]interface{}{
+		"releases":     releaseNames,
+		"commonValues": util.MaskSensitiveValues(projectParams.CommonValues),
+	}
+}
+
+// buildReleaseDepGraph builds the dependency graph of the project releases,
+// with an edge from each release to the releases it depends on.
+func (projectImpl *Project) buildReleaseDepGraph(projectParams *projectModel.ProjectParams) (*dag.AcyclicGraph, error) {
 	projectParamsMap := make(map[string]*releaseModel.ReleaseRequestV2)
-	releaseParsed := make([]*releaseModel.ReleaseRequestV2, 0)
 	var g dag.AcyclicGraph
 
 	for _, releaseInfo := range projectParams.Releases {
@@ -417,29 +802,107 @@ func (projectImpl *Project) autoCreateReleaseDependencies(projectParams *project
 
 	// init edge
 	for _, helmRelease := range projectParams.Releases {
-		subCharts, err := projectImpl.helm.GetChartAutoDependencies(helmRelease.RepoName, helmRelease.ChartName, helmRelease.ChartVersion)
+		subCharts, err := projectImpl.getReleaseAutoDependencies(helmRelease)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, subChartName := range subCharts {
+			if _, external := helmRelease.ExternalDependencies[subChartName]; external {
+				// the dependency's output values are declared inline, no
+				// project release provides them
+				continue
+			}
 			_, ok := projectParamsMap[subChartName]
 			_, ok2 := helmRelease.Dependencies[subChartName]
 			if ok && !ok2 {
 				g.Connect(dag.BasicEdge(helmRelease, projectParamsMap[subChartName]))
 			}
 		}
+
+		// dependencies declared soft keep their metadata in the graph
+		// without constraining the install order
+		for subChartName, dependencyValue := range helmRelease.Dependencies {
+			if _, soft := releaseModel.ParseDependencyValue(dependencyValue); !soft {
+				continue
+			}
+			if _, ok := projectParamsMap[subChartName]; ok {
+				g.Connect(dag.AnnotatedEdge(helmRelease, projectParamsMap[subChartName], dag.EdgeAnnotation{Soft: true}))
+			}
+		}
+	}
+
+	return &g, nil
+}
+
+// GetProjectDepGraph renders the dependency graph of the project releases in
+// graphviz dot format, as the dep parse helpers compute it, so gnarly
+// dependency situations can be debugged by rendering the graph.
+func (projectImpl *Project) GetProjectDepGraph(namespace, projectName string) (string, error) {
+	projectInfo, err := projectImpl.GetProjectInfo(namespace, projectName)
+	if err != nil {
+		logrus.Errorf("failed to get project info : %s", err.Error())
+		return "", err
+	}
+
+	projectParams := &projectModel.ProjectParams{
+		Releases: make([]*releaseModel.ReleaseRequestV2, 0, len(projectInfo.Releases)),
+	}
+	for _, releaseInfo := range projectInfo.Releases {
+		projectParams.Releases = append(projectParams.Releases, buildReleaseRequest(projectInfo, releaseInfo.Name))
+	}
+
+	g, err := projectImpl.buildReleaseDepGraph(projectParams)
+	if err != nil {
+		logrus.Errorf("failed to build release dep graph : %s", err.Error())
+		return "", err
+	}
+
+	dot := g.MarshalDOT(func(v dag.Vertex) string {
+		releaseRequest := v.(*releaseModel.ReleaseRequestV2)
+		if releaseRequest.Name != "" {
+			return releaseRequest.Name
+		}
+		return releaseRequest.ChartName
+	})
+	return string(dot), nil
+}
+
+func (projectImpl *Project) autoCreateReleaseDependencies(projectParams *projectModel.ProjectParams) ([]*releaseModel.ReleaseRequestV2, error) {
+	releaseParsed := make([]*releaseModel.ReleaseRequestV2, 0)
+	g, err := projectImpl.buildReleaseDepGraph(projectParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		cycleStrs := make([]string, len(cycles))
+		for i, cycle := range cycles {
+			chartNames := make([]string, 0, len(cycle)+1)
+			for _, vertex := range cycle {
+				chartNames = append(chartNames, vertex.(*releaseModel.ReleaseRequestV2).ChartName)
+			}
+			// close the loop so the path reads as a cycle
+			chartNames = append(chartNames, chartNames[0])
+			cycleStrs[i] = strings.Join(chartNames, " -> ")
+		}
+		return nil, errorModel.ValidationError{Message: fmt.Sprintf("the release charts depend on each other in a cycle : %s", strings.Join(cycleStrs, "; "))}
 	}
 
-	_, err := g.Root()
+	_, err = g.Root()
 	if err != nil {
 		return nil, err
 	}
 
-	var lock sync.Mutex
-	err = g.Walk(func(v dag.Vertex) error {
-		lock.Lock()
-		defer lock.Unlock()
+	// the install order is computed deterministically, so identical project
+	// params always yield the identical task plan
+	order, err := g.TopologicalOrder(func(v dag.Vertex) string {
+		return v.(*releaseModel.ReleaseRequestV2).ChartName
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range order {
 		releaseRequest := v.(*releaseModel.ReleaseRequestV2)
 		for _, dv := range g.DownEdges(releaseRequest).List() {
 			releaseInfo := dv.(*releaseModel.ReleaseRequestV2)
@@ -448,16 +911,31 @@ func (projectImpl *Project) autoCreateReleaseDependencies(projectParams *project
 			}
 			releaseRequest.Dependencies[releaseInfo.ChartName] = releaseInfo.Name
 		}
+		// the soft markers served their purpose building the graph, so
+		// strip them before the values are used as release names
+		for dependencyChart, dependencyValue := range releaseRequest.Dependencies {
+			if dependedRelease, soft := releaseModel.ParseDependencyValue(dependencyValue); soft {
+				releaseRequest.Dependencies[dependencyChart] = dependedRelease
+			} else {
+				// the dag already encodes that the release needs this
+				// dependency, so its install gates on the dependency
+				// being ready
+				releaseRequest.WaitForDependencies = true
+			}
+		}
 		releaseParsed = append(releaseParsed, releaseRequest)
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	return releaseParsed, nil
 }
 
+func (projectImpl *Project) getReleaseAutoDependencies(releaseParams *releaseModel.ReleaseRequestV2) ([]string, error) {
+	if releaseParams.ChartPath != "" {
+		return projectImpl.helm.GetChartAutoDependenciesFromPath(releaseParams.ChartPath)
+	}
+	return projectImpl.helm.GetChartAutoDependencies(releaseParams.RepoName, releaseParams.ChartName, releaseParams.ChartVersion)
+}
+
 func (projectImpl *Project) autoUpdateReleaseDependencies(projectInfo *projectModel.ProjectInfo, releaseParams *releaseModel.ReleaseRequestV2, isRemove bool) ([]*releaseModel.ReleaseRequestV2, error) {
 	var g dag.AcyclicGraph
 	affectReleases := make([]*releaseModel.ReleaseRequestV2, 0)
@@ -482,17 +960,24 @@ func (projectImpl *Project) autoUpdateReleaseDependencies(projectInfo *projectMo
 				return nil, err
 			}
 			for _, subChartName := range subCharts {
+				// dependencies declared external never get graph edges
+				if _, external := helmRelease.ExternalDependencies[subChartName]; external {
+					continue
+				}
 				_, ok := helmRelease.Dependencies[subChartName]
 				if subChartName == releaseParams.ChartName && !ok {
 					g.Connect(dag.BasicEdge(helmRelease.Name, releaseParams.Name))
 				}
 			}
 		}
-		releaseSubCharts, err := projectImpl.helm.GetChartAutoDependencies(releaseParams.RepoName, releaseParams.ChartName, releaseParams.ChartVersion)
+		releaseSubCharts, err := projectImpl.getReleaseAutoDependencies(releaseParams)
 		if err != nil {
 			return nil, err
 		}
 		for _, releaseSubChart := range releaseSubCharts {
+			if _, external := releaseParams.ExternalDependencies[releaseSubChart]; external {
+				continue
+			}
 			_, ok := releaseParams.Dependencies[releaseSubChart]
 			if ok {
 				continue
@@ -503,8 +988,8 @@ func (projectImpl *Project) autoUpdateReleaseDependencies(projectInfo *projectMo
 				}
 			}
 		}
-		logrus.Infof("add %s Modify UpperStream Release %+v deps %s\n", releaseParams.Name, g.UpEdges(releaseParams.Name).List(), releaseParams.Name)
-		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).List() {
+		logrus.Infof("add %s Modify UpperStream Release %+v deps %s\n", releaseParams.Name, g.UpEdges(releaseParams.Name).SortedList(nil), releaseParams.Name)
+		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).SortedList(nil) {
 			upperRelease := buildReleaseRequest(projectInfo, upperReleaseName.(string))
 			if upperRelease == nil {
 				continue
@@ -519,8 +1004,8 @@ func (projectImpl *Project) autoUpdateReleaseDependencies(projectInfo *projectMo
 			affectReleases = append(affectReleases, upperRelease)
 		}
 		logrus.Infof("add %s release add more %+v deps. current %+v\n",
-			releaseParams.Name, g.DownEdges(releaseParams.Name).List(), releaseParams.Dependencies)
-		for _, downReleaseName := range g.DownEdges(releaseParams.Name).List() {
+			releaseParams.Name, g.DownEdges(releaseParams.Name).SortedList(nil), releaseParams.Dependencies)
+		for _, downReleaseName := range g.DownEdges(releaseParams.Name).SortedList(nil) {
 			downRelease := buildReleaseRequest(projectInfo, downReleaseName.(string))
 			if downRelease == nil {
 				continue
@@ -535,8 +1020,8 @@ func (projectImpl *Project) autoUpdateReleaseDependencies(projectInfo *projectMo
 			}
 		}
 	} else {
-		logrus.Infof("%+v are depending on %s", g.UpEdges(releaseParams.Name).List(), releaseParams.Name)
-		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).List() {
+		logrus.Infof("%+v are depending on %s", g.UpEdges(releaseParams.Name).SortedList(nil), releaseParams.Name)
+		for _, upperReleaseName := range g.UpEdges(releaseParams.Name).SortedList(nil) {
 			upperRelease := buildReleaseRequest(projectInfo, upperReleaseName.(string))
 			if upperRelease == nil {
 				continue
@@ -567,12 +1052,14 @@ func deleteReleaseDependency(dependencies map[string]string, dependencyKey strin
 	}
 }
 
-func NewProject(cache project.Cache, task task.Task, releaseUseCase release.UseCase, helm helm.Helm) (*Project, error) {
+func NewProject(cache project.Cache, task task.Task, releaseUseCase release.UseCase, helm helm.Helm, k8sCache k8s.Cache, redis redis.Redis) (*Project, error) {
 	p := &Project{
 		cache:          cache,
 		task:           task,
 		releaseUseCase: releaseUseCase,
 		helm:           helm,
+		k8sCache:       k8sCache,
+		redis:          redis,
 	}
 	err := p.registerAddReleaseTask()
 	if err != nil {
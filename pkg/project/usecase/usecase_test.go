@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"testing"
 	"github.com/stretchr/testify/assert"
 	"WarpCloud/walm/pkg/project/mocks"
@@ -8,10 +9,14 @@ import (
 	taskMocks "WarpCloud/walm/pkg/task/mocks"
 	"github.com/stretchr/testify/mock"
 	"errors"
+	"strings"
 	releaseMocks "WarpCloud/walm/pkg/release/mocks"
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
+	redisMocks "WarpCloud/walm/pkg/redis/mocks"
 	"WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/task"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
 	"WarpCloud/walm/pkg/models/release"
 )
 
@@ -21,6 +26,8 @@ func TestProject_ListProjects(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -31,13 +38,16 @@ func TestProject_ListProjects(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -64,6 +74,7 @@ func TestProject_ListProjects(t *testing.T) {
 						Name: "test-name",
 						UUID: "test-uuid",
 					}}}, nil)
+				mockReleaseUseCase.On("ListReleases", "test-ns").Return(nil, nil)
 				mockReleaseUseCase.On("ListReleasesByLabels", "test-ns", project.ProjectNameLabelKey+"=test-name").Return(nil, nil)
 				mockTask.On("GetTaskState", &task.TaskSig{
 					Name: "test-name",
@@ -75,9 +86,10 @@ func TestProject_ListProjects(t *testing.T) {
 			projectInfoList: &project.ProjectInfoList{
 				Items: []*project.ProjectInfo{
 					{
-						Namespace: "test-ns",
-						Name:      "test-name",
-						Message:   noReleaseFoundMsg,
+						Namespace:       "test-ns",
+						Name:            "test-name",
+						Message:         noReleaseFoundMsg,
+						LatestTaskState: "Succeed",
 					},
 				},
 				Num: 1,
@@ -94,6 +106,7 @@ func TestProject_ListProjects(t *testing.T) {
 						Name: "test-name",
 						UUID: "test-uuid",
 					}}}, nil)
+				mockReleaseUseCase.On("ListReleases", "test-ns").Return(nil, nil)
 				mockReleaseUseCase.On("ListReleasesByLabels", "test-ns", project.ProjectNameLabelKey+"=test-name").Return(nil, errors.New(""))
 			},
 			projectInfoList: nil,
@@ -103,7 +116,7 @@ func TestProject_ListProjects(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		projectInfoList, err := mockProjectManager.ListProjects("test-ns")
+		projectInfoList, err := mockProjectManager.ListProjects("test-ns", true)
 		assert.IsType(t, test.err, err)
 		assert.Equal(t, test.projectInfoList, projectInfoList)
 
@@ -116,12 +129,96 @@ func TestProject_ListProjects(t *testing.T) {
 	}
 }
 
+func TestProject_ListProjects_Batch(t *testing.T) {
+	mockProjectCache := &mocks.Cache{}
+	mockTask := &taskMocks.Task{}
+	mockReleaseUseCase := &releaseMocks.UseCase{}
+	mockTaskState := &taskMocks.TaskState{}
+	mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
+
+	mockProjectManager, err := NewProject(mockProjectCache, mockTask, mockReleaseUseCase, &helmMocks.Helm{}, &k8sMocks.Cache{}, &redisMocks.Redis{})
+	assert.IsType(t, nil, err)
+
+	mockProjectCache.On("GetProjectTasks", "test-ns").Return([]*project.ProjectTask{{
+		Namespace: "test-ns",
+		Name:      "test-name",
+		LatestTaskSignature: &task.TaskSig{
+			Name: "test-name",
+			UUID: "test-uuid",
+		}}}, nil)
+	mockReleaseUseCase.On("ListReleases", "test-ns").Return([]*release.ReleaseInfoV2{
+		{
+			ReleaseInfo: release.ReleaseInfo{
+				ReleaseSpec: release.ReleaseSpec{Namespace: "test-ns", Name: "test-release"},
+				Ready:       true,
+			},
+			ReleaseLabels: map[string]string{project.ProjectNameLabelKey: "test-name"},
+		},
+	}, nil)
+	mockTask.On("GetTaskState", mock.Anything).Return(mockTaskState, nil)
+	mockTaskState.On("IsFinished").Return(true)
+	mockTaskState.On("IsSuccess").Return(true)
+
+	projectInfoList, err := mockProjectManager.ListProjects("test-ns", true)
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 1, projectInfoList.Num)
+	assert.Equal(t, true, projectInfoList.Items[0].Ready)
+	assert.Equal(t, 1, len(projectInfoList.Items[0].Releases))
+	// the releases came from the single batch query grouped by project label
+	mockReleaseUseCase.AssertNotCalled(t, "ListReleasesByLabels", mock.Anything, mock.Anything)
+}
+
+func TestProject_ListProjects_Summary(t *testing.T) {
+	mockProjectCache := &mocks.Cache{}
+	mockTask := &taskMocks.Task{}
+	mockReleaseUseCase := &releaseMocks.UseCase{}
+	mockK8sCache := &k8sMocks.Cache{}
+	mockRedis := &redisMocks.Redis{}
+	mockTaskState := &taskMocks.TaskState{}
+	mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
+
+	mockProjectManager, err := NewProject(mockProjectCache, mockTask, mockReleaseUseCase, &helmMocks.Helm{}, mockK8sCache, mockRedis)
+	assert.IsType(t, nil, err)
+
+	mockProjectCache.On("GetProjectTasks", "test-ns").Return([]*project.ProjectTask{{
+		Namespace: "test-ns",
+		Name:      "test-name",
+		LatestTaskSignature: &task.TaskSig{
+			Name: "test-name",
+			UUID: "test-uuid",
+		}}}, nil)
+	mockK8sCache.On("ListReleaseConfigs", "test-ns", project.ProjectNameLabelKey+"=test-name").Return([]*k8sModel.ReleaseConfig{
+		{
+			Meta:         k8sModel.Meta{Namespace: "test-ns", Name: "test-release"},
+			ChartName:    "test-chart",
+			ChartVersion: "1.0.0",
+		},
+	}, nil)
+	mockTask.On("GetTaskState", mock.Anything).Return(mockTaskState, nil)
+	mockTaskState.On("IsFinished").Return(true)
+	mockTaskState.On("IsSuccess").Return(true)
+
+	projectInfoList, err := mockProjectManager.ListProjects("test-ns", false)
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 1, projectInfoList.Num)
+	projectInfo := projectInfoList.Items[0]
+	assert.Equal(t, true, projectInfo.Ready)
+	assert.Equal(t, "Succeed", projectInfo.LatestTaskState)
+	assert.Equal(t, 1, len(projectInfo.Releases))
+	assert.Equal(t, "test-release", projectInfo.Releases[0].Name)
+	assert.Equal(t, "test-chart", projectInfo.Releases[0].ChartName)
+	// the heavy release readiness computation is skipped in summary mode
+	mockReleaseUseCase.AssertNotCalled(t, "ListReleasesByLabels", mock.Anything, mock.Anything)
+}
+
 func TestProject_GetProjectInfo(t *testing.T) {
 
 	var mockProjectCache *mocks.Cache
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -132,13 +229,16 @@ func TestProject_GetProjectInfo(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -197,12 +297,94 @@ func TestProject_GetProjectInfo(t *testing.T) {
 	}
 }
 
+func TestProject_GetProjectDepGraph(t *testing.T) {
+
+	var mockProjectCache *mocks.Cache
+	var mockHelm *helmMocks.Helm
+	var mockTask *taskMocks.Task
+	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
+
+	var mockProjectManager *Project
+
+	var mockTaskState *taskMocks.TaskState
+
+	mockProjectCache = &mocks.Cache{}
+	mockHelm = &helmMocks.Helm{}
+	mockTask = &taskMocks.Task{}
+	mockReleaseUseCase = &releaseMocks.UseCase{}
+	mockK8sCache = &k8sMocks.Cache{}
+	mockRedis = &redisMocks.Redis{}
+
+	mockTaskState = &taskMocks.TaskState{}
+
+	mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
+
+	var err error
+	mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
+	assert.IsType(t, err, nil)
+
+	mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(&project.ProjectTask{
+		Namespace: "test-ns",
+		Name:      "test-name",
+		LatestTaskSignature: &task.TaskSig{
+			Name: "test-name",
+			UUID: "test-uuid",
+		}}, nil)
+	mockReleaseUseCase.On("ListReleasesByLabels", "test-ns", project.ProjectNameLabelKey+"=test-name").Return([]*release.ReleaseInfoV2{
+		{
+			ReleaseInfo: release.ReleaseInfo{
+				ReleaseSpec: release.ReleaseSpec{
+					Name:      "A",
+					ChartName: "chartA",
+				},
+			},
+		},
+		{
+			ReleaseInfo: release.ReleaseInfo{
+				ReleaseSpec: release.ReleaseSpec{
+					Name:      "B",
+					ChartName: "chartB",
+				},
+			},
+		},
+	}, nil)
+	mockTask.On("GetTaskState", &task.TaskSig{
+		Name: "test-name",
+		UUID: "test-uuid",
+	}).Return(mockTaskState, nil)
+	mockTaskState.On("IsFinished").Return(true)
+	mockTaskState.On("IsSuccess").Return(true)
+	mockHelm.On("GetChartAutoDependencies", mock.Anything, "chartA", mock.Anything).Return([]string{"chartB"}, nil)
+	mockHelm.On("GetChartAutoDependencies", mock.Anything, "chartB", mock.Anything).Return(nil, nil)
+
+	dot, err := mockProjectManager.GetProjectDepGraph("test-ns", "test-name")
+	assert.IsType(t, nil, err)
+	expectedDot := `digraph {
+	"A"
+	"B"
+	"A" -> "B"
+}
+`
+	assert.Equal(t, expectedDot, dot)
+
+	mockProjectCache.AssertExpectations(t)
+	mockHelm.AssertExpectations(t)
+	mockTask.AssertExpectations(t)
+	mockReleaseUseCase.AssertExpectations(t)
+
+	mockTaskState.AssertExpectations(t)
+}
+
 func TestProject_buildProjectInfo(t *testing.T) {
 
 	var mockProjectCache *mocks.Cache
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -213,13 +395,16 @@ func TestProject_buildProjectInfo(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -434,6 +619,8 @@ func TestProject_validateProjectTask(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -444,13 +631,16 @@ func TestProject_validateProjectTask(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -509,7 +699,7 @@ func TestProject_validateProjectTask(t *testing.T) {
 				mockTaskState.On("IsFinished").Return(false)
 				mockTaskState.On("IsTimeout").Return(false)
 			},
-			err: errors.New("please wait for the last project task test-name-test-uuid finished or timeout"),
+			err: errorModel.ConflictError{},
 		},
 		{
 			initMock: func() {
@@ -573,6 +763,8 @@ func TestProject_sendProjectTask(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -583,13 +775,16 @@ func TestProject_sendProjectTask(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -602,14 +797,14 @@ func TestProject_sendProjectTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			err: errors.New("failed"),
 		},
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(errors.New(""))
 			},
 			err: errors.New("failed"),
@@ -617,7 +812,7 @@ func TestProject_sendProjectTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 			},
 			async: true,
@@ -626,7 +821,7 @@ func TestProject_sendProjectTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(nil)
 			},
@@ -637,7 +832,7 @@ func TestProject_sendProjectTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(errors.New(""))
 			},
@@ -648,7 +843,7 @@ func TestProject_sendProjectTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(errors.New(""))
 			},
@@ -657,7 +852,7 @@ func TestProject_sendProjectTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -667,7 +862,7 @@ func TestProject_sendProjectTask(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.sendProjectTask("test-ns", "test", "test", nil, test.oldTask, 0, test.async)
+		err := mockProjectManager.sendProjectTask(context.Background(), "test-ns", "test", "test", nil, test.oldTask, 0, test.async)
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -685,6 +880,8 @@ func TestProject_CreateProject(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -695,13 +892,16 @@ func TestProject_CreateProject(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -737,7 +937,7 @@ func TestProject_CreateProject(t *testing.T) {
 			func() {
 				refreshMocks()
 				mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			projectParams: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{{
@@ -753,7 +953,7 @@ func TestProject_CreateProject(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -771,7 +971,7 @@ func TestProject_CreateProject(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.CreateProject("test-ns", "test-nm", test.projectParams, false, 0)
+		_, err := mockProjectManager.CreateProject(context.Background(), "test-ns", "test-nm", test.projectParams, false, 0, "", "")
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -789,6 +989,8 @@ func TestProject_DeleteProject(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -799,13 +1001,16 @@ func TestProject_DeleteProject(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -832,7 +1037,7 @@ func TestProject_DeleteProject(t *testing.T) {
 				refreshMocks()
 				mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(&project.ProjectTask{}, nil)
 				mockTask.On("GetTaskState", mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -843,7 +1048,7 @@ func TestProject_DeleteProject(t *testing.T) {
 				refreshMocks()
 				mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(&project.ProjectTask{}, nil)
 				mockTask.On("GetTaskState", mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			err: errors.New(""),
 		},
@@ -851,7 +1056,7 @@ func TestProject_DeleteProject(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.DeleteProject("test-ns", "test-nm", false, 0, false)
+		_, err := mockProjectManager.DeleteProject(context.Background(), "test-ns", "test-nm", false, 0, false, false, "", "")
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -869,6 +1074,8 @@ func TestProject_AddReleasesInProject(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -879,13 +1086,16 @@ func TestProject_AddReleasesInProject(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -921,7 +1131,7 @@ func TestProject_AddReleasesInProject(t *testing.T) {
 			func() {
 				refreshMocks()
 				mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			projectParams: &project.ProjectParams{
 				Releases: []*release.ReleaseRequestV2{{
@@ -937,7 +1147,7 @@ func TestProject_AddReleasesInProject(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockProjectCache.On("GetProjectTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -955,7 +1165,7 @@ func TestProject_AddReleasesInProject(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.AddReleasesInProject("test-ns", "test-nm", test.projectParams, false, 0)
+		_, err := mockProjectManager.AddReleasesInProject(context.Background(), "test-ns", "test-nm", test.projectParams, false, 0, "", "")
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -973,6 +1183,8 @@ func TestProject_UpgradeReleaseInProject(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -983,13 +1195,16 @@ func TestProject_UpgradeReleaseInProject(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -1044,7 +1259,7 @@ func TestProject_UpgradeReleaseInProject(t *testing.T) {
 						},
 					}}, nil)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -1062,7 +1277,7 @@ func TestProject_UpgradeReleaseInProject(t *testing.T) {
 					},
 				}}, nil)
 				mockTask.On("GetTaskState", mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			err: errors.New(""),
 		},
@@ -1072,7 +1287,7 @@ func TestProject_UpgradeReleaseInProject(t *testing.T) {
 		test.initMock()
 		releaseRequest := &release.ReleaseRequestV2{}
 		releaseRequest.Name = "test-name"
-		err := mockProjectManager.UpgradeReleaseInProject("test-ns", "test-nm", releaseRequest, false, 0)
+		_, err := mockProjectManager.UpgradeReleaseInProject(context.Background(), "test-ns", "test-nm", releaseRequest, false, 0, "", "")
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -1090,6 +1305,8 @@ func TestProject_RemoveReleaseInProject(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -1100,13 +1317,16 @@ func TestProject_RemoveReleaseInProject(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -1160,8 +1380,9 @@ func TestProject_RemoveReleaseInProject(t *testing.T) {
 							},
 						},
 					}}, nil)
+				mockReleaseUseCase.On("GetReleaseDependents", mock.Anything, mock.Anything).Return(nil, nil)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockProjectCache.On("CreateOrUpdateProjectTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -1178,8 +1399,9 @@ func TestProject_RemoveReleaseInProject(t *testing.T) {
 						},
 					},
 				}}, nil)
+				mockReleaseUseCase.On("GetReleaseDependents", mock.Anything, mock.Anything).Return(nil, nil)
 				mockTask.On("GetTaskState", mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			err: errors.New(""),
 		},
@@ -1187,7 +1409,7 @@ func TestProject_RemoveReleaseInProject(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockProjectManager.RemoveReleaseInProject("test-ns", "test-nm", "test-name", false, 0, false)
+		_, err := mockProjectManager.RemoveReleaseInProject(context.Background(), "test-ns", "test-nm", "test-name", false, 0, false, "", "")
 		assert.IsType(t, test.err, err)
 
 		mockProjectCache.AssertExpectations(t)
@@ -1205,6 +1427,8 @@ func TestProject_autoCreateReleaseDependencies(t *testing.T) {
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -1215,13 +1439,16 @@ func TestProject_autoCreateReleaseDependencies(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
@@ -1287,6 +1514,7 @@ func TestProject_autoCreateReleaseDependencies(t *testing.T) {
 						ChartName:    "chartA",
 						Dependencies: map[string]string{"chartB": "B"},
 					},
+					WaitForDependencies: true,
 				},
 				{
 					ReleaseRequest: release.ReleaseRequest{
@@ -1330,6 +1558,97 @@ func TestProject_autoCreateReleaseDependencies(t *testing.T) {
 						ChartName:    "chartA",
 						Dependencies: map[string]string{"chartB": "BB"},
 					},
+					WaitForDependencies: true,
+				},
+				{
+					ReleaseRequest: release.ReleaseRequest{
+						Name:      "B",
+						ChartName: "chartB",
+					},
+				},
+			},
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockHelm.On("GetChartAutoDependencies", mock.Anything, mock.Anything, mock.Anything).Return(func(repo, chart, version string) (result []string) {
+					if chart == "chartA" {
+						result = append(result, "chartB")
+					}
+					return
+				}, nil)
+			},
+			projectParams: &project.ProjectParams{
+				Releases: []*release.ReleaseRequestV2{
+					{
+						ReleaseRequest: release.ReleaseRequest{
+							Name:         "A",
+							ChartName:    "chartA",
+							Dependencies: map[string]string{"chartB": release.SoftDependencySuffix},
+						},
+					},
+					{
+						ReleaseRequest: release.ReleaseRequest{
+							Name:      "B",
+							ChartName: "chartB",
+						},
+					},
+				},
+			},
+			releaseRequests: []*release.ReleaseRequestV2{
+				{
+					ReleaseRequest: release.ReleaseRequest{
+						Name:         "A",
+						ChartName:    "chartA",
+						Dependencies: map[string]string{"chartB": "B"},
+					},
+				},
+				{
+					ReleaseRequest: release.ReleaseRequest{
+						Name:      "B",
+						ChartName: "chartB",
+					},
+				},
+			},
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockHelm.On("GetChartAutoDependencies", mock.Anything, mock.Anything, mock.Anything).Return(func(repo, chart, version string) (result []string) {
+					if chart == "chartA" {
+						result = append(result, "chartB")
+					}
+					return
+				}, nil)
+			},
+			projectParams: &project.ProjectParams{
+				Releases: []*release.ReleaseRequestV2{
+					{
+						ReleaseRequest: release.ReleaseRequest{
+							Name:      "A",
+							ChartName: "chartA",
+						},
+						ExternalDependencies: map[string]map[string]interface{}{
+							"chartB": {"zookeeper_addresses": "external-zk:2181"},
+						},
+					},
+					{
+						ReleaseRequest: release.ReleaseRequest{
+							Name:      "B",
+							ChartName: "chartB",
+						},
+					},
+				},
+			},
+			releaseRequests: []*release.ReleaseRequestV2{
+				{
+					ReleaseRequest: release.ReleaseRequest{
+						Name:      "A",
+						ChartName: "chartA",
+					},
+					ExternalDependencies: map[string]map[string]interface{}{
+						"chartB": {"zookeeper_addresses": "external-zk:2181"},
+					},
 				},
 				{
 					ReleaseRequest: release.ReleaseRequest{
@@ -1339,6 +1658,37 @@ func TestProject_autoCreateReleaseDependencies(t *testing.T) {
 				},
 			},
 		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockHelm.On("GetChartAutoDependencies", mock.Anything, mock.Anything, mock.Anything).Return(func(repo, chart, version string) (result []string) {
+					if chart == "chartA" {
+						result = append(result, "chartB")
+					}
+					if chart == "chartB" {
+						result = append(result, "chartA")
+					}
+					return
+				}, nil)
+			},
+			projectParams: &project.ProjectParams{
+				Releases: []*release.ReleaseRequestV2{
+					{
+						ReleaseRequest: release.ReleaseRequest{
+							Name:      "A",
+							ChartName: "chartA",
+						},
+					},
+					{
+						ReleaseRequest: release.ReleaseRequest{
+							Name:      "B",
+							ChartName: "chartB",
+						},
+					},
+				},
+			},
+			err: errorModel.ValidationError{},
+		},
 	}
 
 	for _, test := range tests {
@@ -1357,11 +1707,67 @@ func TestProject_autoCreateReleaseDependencies(t *testing.T) {
 
 }
 
+func TestProject_autoCreateReleaseDependenciesOrder(t *testing.T) {
+	mockProjectCache := &mocks.Cache{}
+	mockHelm := &helmMocks.Helm{}
+	mockTask := &taskMocks.Task{}
+	mockReleaseUseCase := &releaseMocks.UseCase{}
+	mockK8sCache := &k8sMocks.Cache{}
+	mockRedis := &redisMocks.Redis{}
+	mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
+	mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
+
+	mockProjectManager, err := NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
+	assert.IsType(t, err, nil)
+
+	mockHelm.On("GetChartAutoDependencies", mock.Anything, mock.Anything, mock.Anything).Return(func(repo, chart, version string) (result []string) {
+		switch chart {
+		case "chartA":
+			result = append(result, "chartB", "chartC")
+		case "chartB", "chartC":
+			result = append(result, "chartD")
+		}
+		return
+	}, nil)
+
+	buildProjectParams := func(chartNames []string) *project.ProjectParams {
+		projectParams := &project.ProjectParams{}
+		for _, chartName := range chartNames {
+			projectParams.Releases = append(projectParams.Releases, &release.ReleaseRequestV2{
+				ReleaseRequest: release.ReleaseRequest{
+					Name:      strings.TrimPrefix(chartName, "chart"),
+					ChartName: chartName,
+				},
+			})
+		}
+		return projectParams
+	}
+
+	// identical project params always yield the identical install order, no
+	// matter how the releases were listed in the request
+	expectedOrder := []string{"chartD", "chartB", "chartC", "chartA"}
+	for _, chartNames := range [][]string{
+		{"chartA", "chartB", "chartC", "chartD"},
+		{"chartD", "chartC", "chartB", "chartA"},
+		{"chartB", "chartD", "chartA", "chartC"},
+	} {
+		releaseRequests, err := mockProjectManager.autoCreateReleaseDependencies(buildProjectParams(chartNames))
+		assert.IsType(t, nil, err)
+		parsedOrder := make([]string, 0, len(releaseRequests))
+		for _, releaseRequest := range releaseRequests {
+			parsedOrder = append(parsedOrder, releaseRequest.ChartName)
+		}
+		assert.Equal(t, expectedOrder, parsedOrder)
+	}
+}
+
 func TestProject_autoUpdateReleaseDependencies(t *testing.T) {
 	var mockProjectCache *mocks.Cache
 	var mockHelm *helmMocks.Helm
 	var mockTask *taskMocks.Task
 	var mockReleaseUseCase *releaseMocks.UseCase
+	var mockK8sCache *k8sMocks.Cache
+	var mockRedis *redisMocks.Redis
 
 	var mockProjectManager *Project
 
@@ -1372,13 +1778,16 @@ func TestProject_autoUpdateReleaseDependencies(t *testing.T) {
 		mockHelm = &helmMocks.Helm{}
 		mockTask = &taskMocks.Task{}
 		mockReleaseUseCase = &releaseMocks.UseCase{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockRedis = &redisMocks.Redis{}
+		mockRedis.On("Publish", mock.Anything, mock.Anything).Return(nil)
 
 		mockTaskState = &taskMocks.TaskState{}
 
 		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
 
 		var err error
-		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm)
+		mockProjectManager, err = NewProject(mockProjectCache, mockTask, mockReleaseUseCase, mockHelm, mockK8sCache, mockRedis)
 		assert.IsType(t, err, nil)
 	}
 
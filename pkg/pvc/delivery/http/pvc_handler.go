@@ -1,6 +1,7 @@
 package http
 
 import (
+	"WarpCloud/walm/pkg/auth"
 	"WarpCloud/walm/pkg/k8s"
 	"github.com/emicklei/go-restful"
 	"WarpCloud/walm/pkg/models/http"
@@ -99,7 +100,12 @@ func (handler *PvcHandler) GetPvc(request *restful.Request, response *restful.Re
 func (handler *PvcHandler) DeletePvc(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	pvcName := request.PathParameter("pvcname")
-	err := handler.k8sOperator.DeletePvc(namespace, pvcName)
+	k8sOperator, err := handler.k8sOperator.WithCaller(auth.GetRequestPrincipal(request), auth.GetRequestPrincipalGroups(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build kube client impersonating the caller : %s", err.Error()))
+		return
+	}
+	err = k8sOperator.DeletePvc(namespace, pvcName)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete pvc : %s", err.Error()))
 		return
@@ -110,7 +116,12 @@ func (handler *PvcHandler) DeletePvcs(request *restful.Request, response *restfu
 	namespace := request.PathParameter("namespace")
 	labelSelectorStr := request.QueryParameter("labelselector")
 
-	err := handler.k8sOperator.DeletePvcs(namespace, labelSelectorStr)
+	k8sOperator, err := handler.k8sOperator.WithCaller(auth.GetRequestPrincipal(request), auth.GetRequestPrincipalGroups(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build kube client impersonating the caller : %s", err.Error()))
+		return
+	}
+	err = k8sOperator.DeletePvcs(namespace, labelSelectorStr)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete pvcs: %s",  err.Error()))
 		return
@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"WarpCloud/walm/pkg/auth"
+	httpModel "WarpCloud/walm/pkg/models/http"
+	"WarpCloud/walm/pkg/setting"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultReadQPS     float64 = 50
+	defaultReadBurst           = 100
+	defaultMutateQPS   float64 = 10
+	defaultMutateBurst         = 20
+
+	// clients idle longer than this are pruned, so the limiter state stays bounded
+	staleClientTTL = 10 * time.Minute
+)
+
+var throttleObserver func(verb string)
+
+// SetThrottleObserver registers a callback invoked every time a request is
+// rejected by the rate limiter. It keeps this package free of a direct
+// dependency on the metrics implementation.
+func SetThrottleObserver(observer func(verb string)) {
+	throttleObserver = observer
+}
+
+type clientBuckets struct {
+	read     *rate.Limiter
+	mutate   *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter throttles the api routes with a token bucket per client, budgeting
+// read and mutate requests separately. The state is local to the replica, so
+// no shared store is needed. It is mounted as a global go-restful filter
+// behind the auth filter.
+type Limiter struct {
+	enable      bool
+	readQPS     float64
+	readBurst   int
+	mutateQPS   float64
+	mutateBurst int
+	exempt      map[string]bool
+
+	mutex   sync.Mutex
+	clients map[string]*clientBuckets
+}
+
+func (limiter *Limiter) Filter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	// only the api routes are throttled, like the auth filter
+	if !limiter.enable || !strings.HasPrefix(request.Request.URL.Path, httpModel.ApiV1) {
+		chain.ProcessFilter(request, response)
+		return
+	}
+
+	// walm components calling each other use exempted principals to bypass the limiter
+	client := clientIdentity(request)
+	if limiter.exempt[client] {
+		chain.ProcessFilter(request, response)
+		return
+	}
+
+	verb := auth.RequestVerb(request)
+	reservation := limiter.getBucket(client, verb).Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		if throttleObserver != nil {
+			throttleObserver(verb)
+		}
+		retryAfter := int(math.Ceil(delay.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		logrus.Warnf("client %s exceeded the %s budget, retry after %d seconds", client, verb, retryAfter)
+		response.AddHeader("Retry-After", strconv.Itoa(retryAfter))
+		response.WriteHeaderAndEntity(http.StatusTooManyRequests, httpModel.ErrorMessageResponse{ErrCode: -1,
+			ErrMessage: "too many " + verb + " requests, retry after " + strconv.Itoa(retryAfter) + " seconds"})
+		return
+	}
+
+	chain.ProcessFilter(request, response)
+}
+
+func (limiter *Limiter) getBucket(client, verb string) *rate.Limiter {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	buckets, ok := limiter.clients[client]
+	if !ok {
+		limiter.pruneStaleClients()
+		buckets = &clientBuckets{
+			read:   rate.NewLimiter(rate.Limit(limiter.readQPS), limiter.readBurst),
+			mutate: rate.NewLimiter(rate.Limit(limiter.mutateQPS), limiter.mutateBurst),
+		}
+		limiter.clients[client] = buckets
+	}
+	buckets.lastSeen = time.Now()
+	if verb == auth.VerbMutate {
+		return buckets.mutate
+	}
+	return buckets.read
+}
+
+// pruneStaleClients is called with the mutex held when a new client shows up.
+func (limiter *Limiter) pruneStaleClients() {
+	deadline := time.Now().Add(-staleClientTTL)
+	for client, buckets := range limiter.clients {
+		if buckets.lastSeen.Before(deadline) {
+			delete(limiter.clients, client)
+		}
+	}
+}
+
+// clientIdentity keys the buckets by the authenticated principal, falling back
+// to the remote ip when auth is disabled.
+func clientIdentity(request *restful.Request) string {
+	if principal := auth.GetRequestPrincipal(request); principal != "" {
+		return principal
+	}
+	host, _, err := net.SplitHostPort(request.Request.RemoteAddr)
+	if err != nil {
+		return request.Request.RemoteAddr
+	}
+	return host
+}
+
+func NewLimiter(config *setting.RateLimitConfig) *Limiter {
+	limiter := &Limiter{
+		clients: map[string]*clientBuckets{},
+	}
+	limiter.apply(config)
+	return limiter
+}
+
+// Reload applies a changed rate limit config at runtime. The client buckets
+// are dropped so they are rebuilt with the new rates.
+func (limiter *Limiter) Reload(config *setting.RateLimitConfig) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.clients = map[string]*clientBuckets{}
+	limiter.apply(config)
+}
+
+// apply resets the limiter to the defaults overridden by the config. The
+// caller must hold the mutex when the limiter is already serving.
+func (limiter *Limiter) apply(config *setting.RateLimitConfig) {
+	limiter.enable = false
+	limiter.readQPS = defaultReadQPS
+	limiter.readBurst = defaultReadBurst
+	limiter.mutateQPS = defaultMutateQPS
+	limiter.mutateBurst = defaultMutateBurst
+	limiter.exempt = map[string]bool{}
+	if config == nil || !config.Enable {
+		return
+	}
+	limiter.enable = true
+	if config.ReadQPS > 0 {
+		limiter.readQPS = config.ReadQPS
+	}
+	if config.ReadBurst > 0 {
+		limiter.readBurst = config.ReadBurst
+	}
+	if config.MutateQPS > 0 {
+		limiter.mutateQPS = config.MutateQPS
+	}
+	if config.MutateBurst > 0 {
+		limiter.mutateBurst = config.MutateBurst
+	}
+	for _, principal := range config.ExemptPrincipals {
+		limiter.exempt[principal] = true
+	}
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"WarpCloud/walm/pkg/setting"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLimiter_Filter(t *testing.T) {
+	config := &setting.RateLimitConfig{
+		Enable:           true,
+		ReadQPS:          0.001,
+		ReadBurst:        2,
+		MutateQPS:        0.001,
+		MutateBurst:      1,
+		ExemptPrincipals: []string{"walm-sync"},
+	}
+
+	tests := []struct {
+		config      *setting.RateLimitConfig
+		method      string
+		requests    int
+		statusCodes []int
+		retryAfter  bool
+	}{
+		{
+			config:      nil,
+			method:      "GET",
+			requests:    3,
+			statusCodes: []int{200, 200, 200},
+		},
+		{
+			// the read burst fits two requests, the third is throttled
+			config:      config,
+			method:      "GET",
+			requests:    3,
+			statusCodes: []int{200, 200, 429},
+			retryAfter:  true,
+		},
+		{
+			// the mutate budget is separate and smaller
+			config:      config,
+			method:      "POST",
+			requests:    2,
+			statusCodes: []int{200, 429},
+			retryAfter:  true,
+		},
+	}
+
+	for _, test := range tests {
+		limiter := NewLimiter(test.config)
+
+		container := restful.NewContainer()
+		container.Filter(limiter.Filter)
+		ws := new(restful.WebService)
+		ws.Produces(restful.MIME_JSON)
+		handler := func(request *restful.Request, response *restful.Response) {
+			response.WriteHeader(200)
+		}
+		ws.Route(ws.GET("/api/v1/project").To(handler))
+		ws.Route(ws.POST("/api/v1/project/{namespace}/name/{project}").To(handler))
+		container.Add(ws)
+
+		path := "/api/v1/project"
+		if test.method == "POST" {
+			path = "/api/v1/project/testns/name/testnm"
+		}
+		for i := 0; i < test.requests; i++ {
+			httpRequest, _ := http.NewRequest(test.method, path, nil)
+			httpRequest.RemoteAddr = "127.0.0.1:12345"
+			httpWriter := httptest.NewRecorder()
+			container.ServeHTTP(httpWriter, httpRequest)
+
+			assert.Equal(t, test.statusCodes[i], httpWriter.Code)
+			if httpWriter.Code == 429 {
+				assert.Equal(t, test.retryAfter, httpWriter.Header().Get("Retry-After") != "")
+			}
+		}
+	}
+}
+
+func TestLimiter_FilterExempt(t *testing.T) {
+	limiter := NewLimiter(&setting.RateLimitConfig{
+		Enable:           true,
+		ReadQPS:          0.001,
+		ReadBurst:        1,
+		ExemptPrincipals: []string{"walm-sync"},
+	})
+
+	container := restful.NewContainer()
+	// the auth filter resolves the principal before the limiter sees the request
+	container.Filter(func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		request.SetAttribute("walm-principal", "walm-sync")
+		chain.ProcessFilter(request, response)
+	})
+	container.Filter(limiter.Filter)
+	ws := new(restful.WebService)
+	ws.Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/api/v1/project").To(func(request *restful.Request, response *restful.Response) {
+		response.WriteHeader(200)
+	}))
+	container.Add(ws)
+
+	// exempted principals are never throttled, even past the burst
+	for i := 0; i < 3; i++ {
+		httpRequest, _ := http.NewRequest("GET", "/api/v1/project", nil)
+		httpRequest.RemoteAddr = "127.0.0.1:12345"
+		httpWriter := httptest.NewRecorder()
+		container.ServeHTTP(httpWriter, httpRequest)
+		assert.Equal(t, 200, httpWriter.Code)
+	}
+}
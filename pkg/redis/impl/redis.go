@@ -14,7 +14,22 @@ type Redis struct {
 	client *redis.Client
 }
 
+var redisOpObserver func(operation string, duration time.Duration)
+
+// SetRedisOpObserver registers the observer the operation latencies are fed
+// to. The indirection keeps this package free of the metrics dependencies.
+func SetRedisOpObserver(observer func(operation string, duration time.Duration)) {
+	redisOpObserver = observer
+}
+
+func observeRedisOp(operation string, startTime time.Time) {
+	if redisOpObserver != nil {
+		redisOpObserver(operation, time.Since(startTime))
+	}
+}
+
 func (redis *Redis) GetFieldValue(key, namespace, name string) (value string, err error) {
+	defer observeRedisOp("getFieldValue", time.Now())
 	value, err = redis.client.HGet(key, walmRedis.BuildFieldName(namespace, name)).Result()
 	if err != nil {
 		if isKeyNotFoundError(err) {
@@ -29,6 +44,7 @@ func (redis *Redis) GetFieldValue(key, namespace, name string) (value string, er
 }
 
 func (redis *Redis) GetFieldValues(key, namespace string) (values []string, err error) {
+	defer observeRedisOp("getFieldValues", time.Now())
 	values = []string{}
 	if namespace == "" {
 		releaseCacheMap, err := redis.client.HGetAll(key).Result()
@@ -56,6 +72,7 @@ func (redis *Redis) GetFieldValues(key, namespace string) (values []string, err
 }
 
 func (redis *Redis) GetFieldValuesByNames(key string, fieldNames ... string) (values []string, err error) {
+	defer observeRedisOp("getFieldValuesByNames", time.Now())
 	objects, err := redis.client.HMGet(key, fieldNames...).Result()
 	if err != nil {
 		logrus.Errorf("failed to get fields %v of key %s from redis : %s", fieldNames, key, err.Error())
@@ -72,6 +89,7 @@ func (redis *Redis) SetFieldValues(key string, fieldValues map[string]interface{
 	if len(fieldValues) == 0 {
 		return nil
 	}
+	defer observeRedisOp("setFieldValues", time.Now())
 	marshaledFieldValues := map[string]interface{}{}
 	for k, value := range fieldValues {
 		valueStr, err := json.Marshal(value)
@@ -90,6 +108,7 @@ func (redis *Redis) SetFieldValues(key string, fieldValues map[string]interface{
 }
 
 func (redis *Redis) DeleteField(key, namespace, name string) error {
+	defer observeRedisOp("deleteField", time.Now())
 	_, err := redis.client.HDel(key, walmRedis.BuildFieldName(namespace, name)).Result()
 	if err != nil {
 		logrus.Errorf("failed to delete filed %s/%s of key %s from redis: %s", namespace, name, key, err.Error())
@@ -98,6 +117,52 @@ func (redis *Redis) DeleteField(key, namespace, name string) error {
 	return nil
 }
 
+func (redis *Redis) IncrValue(key string, delta int64, expiration time.Duration) (int64, error) {
+	defer observeRedisOp("incrValue", time.Now())
+	value, err := redis.client.IncrBy(key, delta).Result()
+	if err != nil {
+		logrus.Errorf("failed to incr key %s by %d in redis : %s", key, delta, err.Error())
+		return 0, err
+	}
+	if expiration > 0 {
+		err = redis.client.Expire(key, expiration).Err()
+		if err != nil {
+			logrus.Warnf("failed to set expiration of key %s in redis : %s", key, err.Error())
+		}
+	}
+	return value, nil
+}
+
+func (redis *Redis) Publish(channel, message string) error {
+	defer observeRedisOp("publish", time.Now())
+	_, err := redis.client.Publish(channel, message).Result()
+	if err != nil {
+		logrus.Errorf("failed to publish message to channel %s : %s", channel, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (redis *Redis) Subscribe(channel string) (<-chan string, func() error, error) {
+	pubSub := redis.client.Subscribe(channel)
+	// make sure the subscription is established before telling the caller
+	// it would not miss messages published from now on
+	_, err := pubSub.Receive()
+	if err != nil {
+		logrus.Errorf("failed to subscribe channel %s : %s", channel, err.Error())
+		pubSub.Close()
+		return nil, nil, err
+	}
+	msgChan := make(chan string)
+	go func() {
+		defer close(msgChan)
+		for message := range pubSub.Channel() {
+			msgChan <- message.Payload
+		}
+	}()
+	return msgChan, pubSub.Close, nil
+}
+
 func NewRedisClient(redisConfig *setting.RedisConfig) *redis.Client {
 	return redis.NewClient(&redis.Options{
 		Addr:         redisConfig.Addr,
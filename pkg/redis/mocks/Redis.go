@@ -3,6 +3,7 @@
 package mocks
 
 import mock "github.com/stretchr/testify/mock"
+import time "time"
 
 // Redis is an autogenerated mock type for the Redis type
 type Redis struct {
@@ -97,6 +98,41 @@ func (_m *Redis) GetFieldValuesByNames(key string, filedNames ...string) ([]stri
 	return r0, r1
 }
 
+// IncrValue provides a mock function with given fields: key, delta, expiration
+func (_m *Redis) IncrValue(key string, delta int64, expiration time.Duration) (int64, error) {
+	ret := _m.Called(key, delta, expiration)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(string, int64, time.Duration) int64); ok {
+		r0 = rf(key, delta, expiration)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int64, time.Duration) error); ok {
+		r1 = rf(key, delta, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: channel, message
+func (_m *Redis) Publish(channel string, message string) error {
+	ret := _m.Called(channel, message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(channel, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetFieldValues provides a mock function with given fields: key, fieldValues
 func (_m *Redis) SetFieldValues(key string, fieldValues map[string]interface{}) error {
 	ret := _m.Called(key, fieldValues)
@@ -110,3 +146,35 @@ func (_m *Redis) SetFieldValues(key string, fieldValues map[string]interface{})
 
 	return r0
 }
+
+// Subscribe provides a mock function with given fields: channel
+func (_m *Redis) Subscribe(channel string) (<-chan string, func() error, error) {
+	ret := _m.Called(channel)
+
+	var r0 <-chan string
+	if rf, ok := ret.Get(0).(func(string) <-chan string); ok {
+		r0 = rf(channel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan string)
+		}
+	}
+
+	var r1 func() error
+	if rf, ok := ret.Get(1).(func(string) func() error); ok {
+		r1 = rf(channel)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func() error)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(channel)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
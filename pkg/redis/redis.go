@@ -1,9 +1,23 @@
 package redis
 
+import "time"
+
 const (
 	WalmReleasesKey   = "walm-releases"
 	WalmProjectsKey   = "walm-project-tasks"
 	WalmReleaseTasksKey   = "walm-release-tasks"
+	WalmReleaseEventsKey   = "walm-release-events"
+	WalmProjectWebhooksKey = "walm-project-webhooks"
+	WalmClustersKey        = "walm-clusters"
+	// operations scheduled for later execution, pending until the leader
+	// submits them at the appointed time
+	WalmScheduledOperationsKey = "walm-scheduled-operations"
+
+	WalmProjectEventsChannel = "walm-project-events"
+
+	// per namespace semaphores bounding concurrent heavy release tasks,
+	// the namespace is appended to the prefix
+	WalmNamespaceReleaseTasksKeyPrefix = "walm-namespace-release-tasks-"
 )
 
 type Redis interface {
@@ -12,6 +26,14 @@ type Redis interface {
 	GetFieldValuesByNames(key string, filedNames... string) ([]string, error)
 	SetFieldValues(key string, fieldValues map[string]interface{}) error
 	DeleteField(key, namespace, name string) error
+	// IncrValue atomically adds delta to the integer value of key and refreshes
+	// its expiration, returning the new value. The expiration reclaims the
+	// counters of crashed workers.
+	IncrValue(key string, delta int64, expiration time.Duration) (int64, error)
+	Publish(channel, message string) error
+	// Subscribe returns a channel delivering the messages published on the
+	// channel and a function releasing the subscription.
+	Subscribe(channel string) (<-chan string, func() error, error)
 }
 
 func BuildFieldName(namespace, name string) string {
@@ -12,6 +12,9 @@ type Cache interface {
 	CreateOrUpdateReleaseCache(releaseCache *release.ReleaseCache) error
 	DeleteReleaseCache(namespace string, name string) error
 
+	GetReleaseEvents(namespace, name string, limit int) ([]*release.ReleaseEvent, error)
+	AddReleaseEvent(namespace, name string, event *release.ReleaseEvent) error
+
 	GetReleaseTask(namespace, name string) (*release.ReleaseTask, error)
 	GetReleaseTasks(namespace string)([]*release.ReleaseTask, error)
 	GetReleaseTasksByReleaseConfigs(releaseConfigs []*k8s.ReleaseConfig) ([]*release.ReleaseTask, error)
@@ -3,6 +3,7 @@ package redis
 import (
 	"WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/models/k8s"
+	errorModel "WarpCloud/walm/pkg/models/error"
 	"github.com/sirupsen/logrus"
 	"encoding/json"
 	"WarpCloud/walm/pkg/redis"
@@ -105,6 +106,50 @@ func (cache *Cache) DeleteReleaseCache(namespace string, name string) error {
 	return nil
 }
 
+// GetReleaseEvents returns the latest events of a release, latest first.
+// A non positive limit returns all the events kept.
+func (cache *Cache) GetReleaseEvents(namespace, name string, limit int) (releaseEvents []*release.ReleaseEvent, err error) {
+	releaseEvents = []*release.ReleaseEvent{}
+	releaseEventsStr, err := cache.redis.GetFieldValue(redis.WalmReleaseEventsKey, namespace, name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			return releaseEvents, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(releaseEventsStr), &releaseEvents)
+	if err != nil {
+		logrus.Errorf("failed to unmarshal release events of %s/%s : %s", namespace, name, err.Error())
+		return nil, err
+	}
+	if limit > 0 && len(releaseEvents) > limit {
+		releaseEvents = releaseEvents[:limit]
+	}
+	return
+}
+
+// AddReleaseEvent prepends an event to the release's event log, keeping only
+// the latest events.
+func (cache *Cache) AddReleaseEvent(namespace, name string, event *release.ReleaseEvent) error {
+	releaseEvents, err := cache.GetReleaseEvents(namespace, name, 0)
+	if err != nil {
+		return err
+	}
+
+	releaseEvents = append([]*release.ReleaseEvent{event}, releaseEvents...)
+	if len(releaseEvents) > release.DefaultReleaseEventsLimit {
+		releaseEvents = releaseEvents[:release.DefaultReleaseEventsLimit]
+	}
+
+	err = cache.redis.SetFieldValues(redis.WalmReleaseEventsKey, map[string]interface{}{redis.BuildFieldName(namespace, name): releaseEvents})
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("succeed to add release event of %s/%s to redis", namespace, name)
+	return nil
+}
+
 func (cache *Cache) GetReleaseTask(namespace, name string) (releaseTask *release.ReleaseTask, err error) {
 	releaseTaskStr, err := cache.redis.GetFieldValue(redis.WalmReleaseTasksKey, namespace, name)
 	if err != nil {
@@ -7,7 +7,6 @@ import (
 	"time"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"transwarp/release-config/pkg/apis/transwarp/v1beta1"
-	"strings"
 	"reflect"
 	releaseModel "WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/kafka"
@@ -234,7 +233,7 @@ func (controller *ReleaseConfigController) reloadDependingReleaseWorker() {
 			defer controller.reloadDependingReleaseWorkingQueue.Done(key)
 			err := controller.reloadDependingRelease(key.(string))
 			if err != nil {
-				if strings.Contains(err.Error(), release.WaitReleaseTaskMsgPrefix) {
+				if errorModel.IsConflictError(err) {
 					logrus.Warnf("depending release %s would be reloaded after %d second", key.(string), controller.retryReloadDelayTimeSecond)
 					controller.reloadDependingReleaseWorkingQueue.AddAfter(key, time.Second* time.Duration(controller.retryReloadDelayTimeSecond))
 				} else {
@@ -246,6 +245,11 @@ func (controller *ReleaseConfigController) reloadDependingReleaseWorker() {
 }
 
 func needsEnqueueUpdatedReleaseConfig(old *v1beta1.ReleaseConfig, cur *v1beta1.ReleaseConfig) bool {
+	// a status write only moves observedGeneration forward, reacting to it would
+	// make the controller reload depending releases for walm's own bookkeeping
+	if old.Generation == cur.Generation && old.Status.ObservedGeneration != cur.Status.ObservedGeneration {
+		return false
+	}
 	if utils.ConfigValuesDiff(old.Spec.OutputConfig, cur.Spec.OutputConfig) {
 		return true
 	}
@@ -274,25 +278,16 @@ func (controller *ReleaseConfigController) syncReleaseConfig(releaseConfigKey st
 		return err
 	}
 
-	releaseConfigs, err := controller.k8sCache.ListReleaseConfigs("", "")
+	releaseConfigs, err := controller.k8sCache.ListReleaseConfigsByDependency(namespace, name)
 	if err != nil {
-		logrus.Errorf("failed to list all release configs : %s", err.Error())
+		logrus.Errorf("failed to list release configs depending on %s/%s : %s", namespace, name, err.Error())
 		return err
 	}
 	for _, releaseConfig := range releaseConfigs {
-		for _, dependedRelease := range releaseConfig.Dependencies {
-			dependedReleaseNamespace, dependedReleaseName, err := utils.ParseDependedRelease(releaseConfig.Namespace, dependedRelease)
-			if err != nil {
-				continue
-			}
-			if dependedReleaseNamespace == namespace && dependedReleaseName == name {
-				rc := &v1beta1.ReleaseConfig{}
-				rc.Namespace = releaseConfig.Namespace
-				rc.Name = releaseConfig.Name
-				controller.enqueueDependingRelease(rc)
-				break
-			}
-		}
+		rc := &v1beta1.ReleaseConfig{}
+		rc.Namespace = releaseConfig.Namespace
+		rc.Name = releaseConfig.Name
+		controller.enqueueDependingRelease(rc)
 	}
 
 	return nil
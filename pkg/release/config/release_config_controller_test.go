@@ -80,7 +80,7 @@ func TestReleaseConfigController_enqueueReleaseConfig(t *testing.T) {
 				mockReleaseUseCase.On("ReloadRelease", "testns", "testnm1").Return(func(string, string) error {
 					if retryTimes == 0 {
 						retryTimes ++
-						return errors.New(release.WaitReleaseTaskMsgPrefix)
+						return errorModel.NewConflictError(release.WaitReleaseTaskMsgPrefix)
 					} else {
 						return nil
 					}
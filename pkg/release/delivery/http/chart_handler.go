@@ -8,15 +8,40 @@ import (
 	"WarpCloud/walm/pkg/models/http"
 	httpUtils "WarpCloud/walm/pkg/util/http"
 	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"time"
 	"WarpCloud/walm/pkg/helm"
+	"github.com/hashicorp/golang-lru"
+	"github.com/sirupsen/logrus"
 )
 
+const (
+	// icons bigger than the cap are refused, the proxy is meant for small images
+	chartIconMaxSizeBytes = 1024 * 1024
+	chartIconCacheSize    = 100
+)
+
+type chartIcon struct {
+	contentType string
+	data        []byte
+}
+
 type ChartHandler struct {
 	helm helm.Helm
+	// fetched icons cached by their url, so hotlinking uis do not hammer the repo
+	iconCache      *lru.Cache
+	iconHttpClient *nethttp.Client
 }
 
 func RegisterChartHandler(helm helm.Helm) *restful.WebService {
-	handler := &ChartHandler{helm: helm}
+	iconCache, _ := lru.New(chartIconCacheSize)
+	handler := &ChartHandler{
+		helm:           helm,
+		iconCache:      iconCache,
+		iconHttpClient: &nethttp.Client{Timeout: 10 * time.Second},
+	}
 
 	ws := new(restful.WebService)
 
@@ -38,6 +63,28 @@ func RegisterChartHandler(helm helm.Helm) *restful.WebService {
 		Returns(200, "OK", release.ChartInfoList{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{repo-name}/charts").To(handler.ListCharts).
+		Doc("分页获取chart浏览列表").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("repo-name", "Repo名字").DataType("string")).
+		Param(ws.QueryParameter("limit", "每页chart个数，0表示不分页").DataType("integer").Required(false)).
+		Param(ws.QueryParameter("continue", "上一页返回的continue标记").DataType("string").Required(false)).
+		Param(ws.QueryParameter("refresh", "是否先强制刷新repo索引缓存").DataType("boolean").Required(false)).
+		Writes(release.ChartBrowseList{}).
+		Returns(200, "OK", release.ChartBrowseList{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{repo-name}/chart/{chart-name}/versions").To(handler.ListChartVersions).
+		Doc("获取chart版本列表").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("repo-name", "Repo名字").DataType("string")).
+		Param(ws.PathParameter("chart-name", "Chart名字").DataType("string")).
+		Param(ws.QueryParameter("refresh", "是否先强制刷新repo索引缓存").DataType("boolean").Required(false)).
+		Writes(release.ChartVersionList{}).
+		Returns(200, "OK", release.ChartVersionList{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.GET("/{repo-name}/chart/{chart-name}").To(handler.GetChartInfo).
 		Doc("获取chart详细信息").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -70,6 +117,29 @@ func RegisterChartHandler(helm helm.Helm) *restful.WebService {
 		Returns(404, "Not Found", http.ErrorMessageResponse{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{repo-name}/chart/{chart-name}/metadata").To(handler.GetChartMetadata).
+		Doc("获取chart的Chart.yaml元数据").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("repo-name", "Repo名字").DataType("string")).
+		Param(ws.PathParameter("chart-name", "Chart名字").DataType("string")).
+		Param(ws.QueryParameter("chart-version", "chart版本, 空表示最新版本").DataType("string").DefaultValue("")).
+		Writes(release.ChartMetadata{}).
+		Returns(200, "OK", release.ChartMetadata{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/icon").To(handler.ProxyChartIcon).
+		Doc("代理获取chart图标字节流, 前端可直接引用").
+		Produces("image/*").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.QueryParameter("repo-name", "Repo名字").DataType("string").Required(false)).
+		Param(ws.QueryParameter("chart-name", "Chart名字").DataType("string").Required(true)).
+		Param(ws.QueryParameter("chart-version", "chart版本, 空表示最新版本").DataType("string").DefaultValue("")).
+		Writes("").
+		Returns(200, "OK", "").
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.GET("/{repo-name}/chart/{chart-name}/advantage").To(handler.GetChartAdvantage).
 		Doc("获取chart产品优势信息").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -81,6 +151,17 @@ func RegisterChartHandler(helm helm.Helm) *restful.WebService {
 		Returns(404, "Not Found", http.ErrorMessageResponse{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{repo-name}/chart/{chart-name}/metainfo").To(handler.GetChartMetainfo).
+		Doc("获取chart可配置参数信息").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("repo-name", "Repo名字").DataType("string")).
+		Param(ws.PathParameter("chart-name", "Chart名字").DataType("string")).
+		Param(ws.QueryParameter("chart-version", "chart版本").DataType("string").DefaultValue("")).
+		Writes(release.ChartMetainfo{}).
+		Returns(200, "OK", release.ChartMetainfo{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.GET("/{repo-name}/chart/{chart-name}/architecture").To(handler.GetChartArchitecture).
 		Doc("获取chart架构信息").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -110,6 +191,49 @@ func (handler *ChartHandler) GetChartList(request *restful.Request, response *re
 	response.WriteEntity(chartList)
 }
 
+func (handler *ChartHandler) ListCharts(request *restful.Request, response *restful.Response) {
+	repoName := request.PathParameter("repo-name")
+	continueToken := request.QueryParameter("continue")
+	limit, err := httpUtils.GetLimitQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param limit value is not valid : %s", err.Error()))
+		return
+	}
+	refresh, err := httpUtils.GetRefreshQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param refresh value is not valid : %s", err.Error()))
+		return
+	}
+
+	chartBrowseList, err := handler.helm.ListCharts(repoName, limit, continueToken, refresh)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to list charts: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(chartBrowseList)
+}
+
+func (handler *ChartHandler) ListChartVersions(request *restful.Request, response *restful.Response) {
+	repoName := request.PathParameter("repo-name")
+	chartName := request.PathParameter("chart-name")
+	refresh, err := httpUtils.GetRefreshQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param refresh value is not valid : %s", err.Error()))
+		return
+	}
+
+	chartVersionList, err := handler.helm.ListChartVersions(repoName, chartName, refresh)
+	if err != nil {
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s is not found in repo %s", chartName, repoName))
+			return
+		}
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to list chart versions: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(chartVersionList)
+}
+
 func (handler *ChartHandler) GetChartInfo(request *restful.Request, response *restful.Response) {
 	repoName := request.PathParameter("repo-name")
 	chartName := request.PathParameter("chart-name")
@@ -117,11 +241,11 @@ func (handler *ChartHandler) GetChartInfo(request *restful.Request, response *re
 
 	chartDetailInfo, err := handler.helm.GetChartDetailInfo(repoName, chartName, chartVersion)
 	if err != nil {
-		if errorModel.IsNotFoundError(err) {
-			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
 			return
 		}
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get chart: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart: %s", err.Error()))
 		return
 	}
 	response.WriteEntity(chartDetailInfo.ChartInfo)
@@ -131,11 +255,11 @@ func (handler *ChartHandler) GetChartInfoByImage(request *restful.Request, respo
 	chartImage := request.QueryParameter("chart-image")
 	chartDetailInfo, err := handler.helm.GetDetailChartInfoByImage(chartImage)
 	if err != nil {
-		if errorModel.IsNotFoundError(err) {
-			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("Chart %s is not found", chartImage))
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s is not found", chartImage))
 			return
 		}
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get chart: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart: %s", err.Error()))
 		return
 	}
 	response.WriteEntity(chartDetailInfo.ChartInfo)
@@ -148,11 +272,11 @@ func (handler *ChartHandler) GetChartIcon(request *restful.Request, response *re
 
 	chartDetailInfo, err := handler.helm.GetChartDetailInfo(repoName, chartName, chartVersion)
 	if err != nil {
-		if errorModel.IsNotFoundError(err) {
-			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
 			return
 		}
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get chart: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart: %s", err.Error()))
 		return
 	}
 	response.WriteEntity(chartDetailInfo.Icon)
@@ -165,11 +289,11 @@ func (handler *ChartHandler) GetChartAdvantage(request *restful.Request, respons
 
 	chartDetailInfo, err := handler.helm.GetChartDetailInfo(repoName, chartName, chartVersion)
 	if err != nil {
-		if errorModel.IsNotFoundError(err) {
-			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
 			return
 		}
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get chart: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart: %s", err.Error()))
 		return
 	}
 	response.WriteEntity(chartDetailInfo.Advantage)
@@ -182,12 +306,117 @@ func (handler *ChartHandler) GetChartArchitecture(request *restful.Request, resp
 
 	chartDetailInfo, err := handler.helm.GetChartDetailInfo(repoName, chartName, chartVersion)
 	if err != nil {
-		if errorModel.IsNotFoundError(err) {
-			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
 			return
 		}
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get chart: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart: %s", err.Error()))
 		return
 	}
 	response.WriteEntity(chartDetailInfo.Architecture)
 }
+
+func (handler *ChartHandler) GetChartMetadata(request *restful.Request, response *restful.Response) {
+	repoName := request.PathParameter("repo-name")
+	chartName := request.PathParameter("chart-name")
+	chartVersion := request.QueryParameter("chart-version")
+
+	chartMetadata, err := handler.helm.GetChartMetadata(repoName, chartName, chartVersion)
+	if err != nil {
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+			return
+		}
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart metadata: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(chartMetadata)
+}
+
+// ProxyChartIcon streams the icon of the chart, fetched from the url the repo
+// index declares, so browsers which can not reach the repo can hotlink it.
+func (handler *ChartHandler) ProxyChartIcon(request *restful.Request, response *restful.Response) {
+	repoName := request.QueryParameter("repo-name")
+	chartName := request.QueryParameter("chart-name")
+	chartVersion := request.QueryParameter("chart-version")
+
+	chartMetadata, err := handler.helm.GetChartMetadata(repoName, chartName, chartVersion)
+	if err != nil {
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+			return
+		}
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart metadata: %s", err.Error()))
+		return
+	}
+	if chartMetadata.IconUrl == "" {
+		httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("chart %s-%s has no icon", chartName, chartMetadata.ChartVersion))
+		return
+	}
+
+	icon, err := handler.getChartIcon(chartMetadata.IconUrl)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("icon of chart %s-%s is not found", chartName, chartMetadata.ChartVersion))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get chart icon: %s", err.Error()))
+		return
+	}
+	response.AddHeader("Content-Type", icon.contentType)
+	response.Write(icon.data)
+}
+
+// getChartIcon fetches the icon bytes from the url, serving repeated requests
+// from the icon cache. Icons bigger than chartIconMaxSizeBytes are refused.
+func (handler *ChartHandler) getChartIcon(iconUrl string) (*chartIcon, error) {
+	if cachedIcon, ok := handler.iconCache.Get(iconUrl); ok {
+		return cachedIcon.(*chartIcon), nil
+	}
+
+	resp, err := handler.iconHttpClient.Get(iconUrl)
+	if err != nil {
+		logrus.Errorf("failed to fetch chart icon %s : %s", iconUrl, err.Error())
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == nethttp.StatusNotFound {
+		return nil, errorModel.NotFoundError{Message: fmt.Sprintf("icon %s is not found", iconUrl)}
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		return nil, fmt.Errorf("fetching icon %s returned status %s", iconUrl, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, chartIconMaxSizeBytes+1))
+	if err != nil {
+		logrus.Errorf("failed to read chart icon %s : %s", iconUrl, err.Error())
+		return nil, err
+	}
+	if len(data) > chartIconMaxSizeBytes {
+		return nil, fmt.Errorf("icon %s is bigger than the %d bytes cap", iconUrl, chartIconMaxSizeBytes)
+	}
+
+	icon := &chartIcon{
+		contentType: resp.Header.Get("Content-Type"),
+		data:        data,
+	}
+	handler.iconCache.Add(iconUrl, icon)
+	return icon, nil
+}
+
+func (handler *ChartHandler) GetChartMetainfo(request *restful.Request, response *restful.Response) {
+	repoName := request.PathParameter("repo-name")
+	chartName := request.PathParameter("chart-name")
+	chartVersion := request.QueryParameter("chart-version")
+
+	chartMetainfo, err := handler.helm.GetChartMetainfo(repoName, chartName, chartVersion)
+	if err != nil {
+		if errorModel.IsChartNotFoundError(err) {
+			httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("Chart %s-%s is not found in repo %s", chartName, chartVersion, repoName))
+			return
+		}
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to get chart metainfo: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(chartMetainfo)
+}
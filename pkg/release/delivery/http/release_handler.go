@@ -1,16 +1,25 @@
 package http
 
 import (
+	"WarpCloud/walm/pkg/auth"
+	"WarpCloud/walm/pkg/k8s"
 	"WarpCloud/walm/pkg/release"
+	"WarpCloud/walm/pkg/requestid"
+	"WarpCloud/walm/pkg/schedule"
 	"github.com/emicklei/go-restful"
 	"github.com/emicklei/go-restful-openapi"
+	clusterModel "WarpCloud/walm/pkg/models/cluster"
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
 	releaseModel "WarpCloud/walm/pkg/models/release"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
 	"WarpCloud/walm/pkg/models/http"
 	httpUtils "WarpCloud/walm/pkg/util/http"
 	"fmt"
 	"WarpCloud/walm/pkg/models/common"
 	"encoding/json"
+	"strings"
+	"time"
 )
 
 const (
@@ -19,10 +28,64 @@ const (
 
 type ReleaseHandler struct {
 	usecase release.UseCase
+	// optional, release list responses report the freshness of the informer
+	// caches the statuses are computed from when it is set
+	k8sCache k8s.Cache
+	// optional, mutation requests with a scheduledAt query param are recorded
+	// for later execution when it is set
+	scheduleStore schedule.Store
 }
 
-func NewReleaseHandler(usecase release.UseCase) *ReleaseHandler {
-	return &ReleaseHandler{usecase: usecase}
+func NewReleaseHandler(usecase release.UseCase, k8sCache k8s.Cache) *ReleaseHandler {
+	return &ReleaseHandler{usecase: usecase, k8sCache: k8sCache}
+}
+
+// SetScheduleStore enables scheduling the mutating operations for later
+// execution through the scheduledAt query param. The store stays optional,
+// without it scheduled requests are rejected.
+func (handler *ReleaseHandler) SetScheduleStore(scheduleStore schedule.Store) {
+	handler.scheduleStore = scheduleStore
+}
+
+// scheduleOperation records the operation in the schedule store instead of
+// submitting it now, the stored args being the original mutation request,
+// replayed as is when the operation fires. The created operation is written
+// to the response, its uuid is what cancelling it takes.
+func (handler *ReleaseHandler) scheduleOperation(request *restful.Request, response *restful.Response,
+	namespace, name, operation string, args interface{}, timeoutSec int64, scheduledAt time.Time) {
+	if handler.scheduleStore == nil {
+		httpUtils.WriteErrorResponse(response, -1, "scheduled operations are not enabled")
+		return
+	}
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to marshal scheduled operation args: %s", err.Error()))
+		return
+	}
+	scheduledOperation := &scheduleModel.ScheduledOperation{
+		Namespace:   namespace,
+		Name:        name,
+		Kind:        scheduleModel.ReleaseKind,
+		Operation:   operation,
+		Args:        string(argsBytes),
+		TimeoutSec:  timeoutSec,
+		ScheduledAt: scheduledAt,
+		Principal:   auth.GetRequestPrincipal(request),
+		RequestId:   requestid.GetRequestId(request),
+	}
+	err = handler.scheduleStore.CreateScheduledOperation(scheduledOperation)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to create scheduled operation: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(scheduledOperation)
+}
+
+func (handler *ReleaseHandler) getCacheSyncInfo() *k8sModel.CacheSyncInfo {
+	if handler.k8sCache == nil {
+		return nil
+	}
+	return handler.k8sCache.GetCacheSyncInfo()
 }
 
 func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService {
@@ -38,6 +101,9 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 		Doc("获取所有Release列表").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.QueryParameter("labelselector", "标签过滤").DataType("string")).
+		Param(ws.QueryParameter("cluster", "集群过滤, default表示默认集群").DataType("string").Required(false)).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("fields", "返回字段过滤, 逗号分隔, 例如fields=name,chartVersion,ready").DataType("string").Required(false)).
 		Writes(releaseModel.ReleaseInfoV2List{}).
 		Returns(200, "OK", releaseModel.ReleaseInfoV2List{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -47,6 +113,12 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
 		Param(ws.QueryParameter("labelselector", "标签过滤").DataType("string")).
+		Param(ws.QueryParameter("cluster", "集群过滤, default表示默认集群").DataType("string").Required(false)).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("limit", "分页大小, 0表示不分页").DataType("integer").Required(false)).
+		Param(ws.QueryParameter("continue", "上一页返回的continue token").DataType("string").Required(false)).
+		Param(ws.QueryParameter("sort", "排序方式: name(默认)或creationTime").DataType("string").Required(false)).
+		Param(ws.QueryParameter("fields", "返回字段过滤, 逗号分隔, 例如fields=name,chartVersion,ready").DataType("string").Required(false)).
 		Writes(releaseModel.ReleaseInfoV2List{}).
 		Returns(200, "OK", releaseModel.ReleaseInfoV2List{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -56,17 +128,50 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
 		Param(ws.PathParameter("release", "Release名字").DataType("string")).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("forceRefresh", "是否从apiserver读取最新状态(默认从informer缓存读取)").DataType("boolean").Required(false)).
 		Writes(releaseModel.ReleaseInfoV2{}).
 		Returns(200, "OK", releaseModel.ReleaseInfoV2{}).
 		Returns(404, "Not Found", http.ErrorMessageResponse{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{namespace}/name/{release}/configvalues").To(releaseHandler.GetReleaseConfigValues).
+		Doc("获取Release的用户配置,依赖注入配置和输出配置").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("release", "Release名字").DataType("string")).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Writes(releaseModel.ReleaseConfigValues{}).
+		Returns(200, "OK", releaseModel.ReleaseConfigValues{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{namespace}/name/{release}/events").To(releaseHandler.GetReleaseEvents).
+		Doc("获取Release的生命周期事件列表").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("release", "Release名字").DataType("string")).
+		Param(ws.QueryParameter("limit", "事件数量上限").DataType("integer").Required(false)).
+		Writes(releaseModel.ReleaseEventList{}).
+		Returns(200, "OK", releaseModel.ReleaseEventList{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{namespace}/name/{release}/dependents").To(releaseHandler.GetReleaseDependents).
+		Doc("获取依赖该Release的Release列表").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("release", "Release名字").DataType("string")).
+		Writes(releaseModel.ReleaseInfoV2List{}).
+		Returns(200, "OK", releaseModel.ReleaseInfoV2List{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.PUT("/{namespace}").To(releaseHandler.UpgradeRelease).
 		Doc("升级一个Release").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
+		Param(ws.QueryParameter("scheduledAt", "定时执行时间(RFC3339格式), 设置后该操作将被记录, 到时间后才执行").DataType("string").Required(false)).
 		Reads(releaseModel.ReleaseRequestV2{}).
 		Returns(200, "OK", nil).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -90,6 +195,9 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
 		Param(ws.QueryParameter("deletePvcs", "是否删除release管理的statefulSet关联的所有pvc").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("dryRun", "是否只预览将被删除的资源, 不真正删除").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("force", "是否强制删除存在依赖方的Release").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("scheduledAt", "定时执行时间(RFC3339格式), 设置后该操作将被记录, 到时间后才执行").DataType("string").Required(false)).
 		Returns(200, "OK", nil).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
@@ -99,6 +207,7 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
 		Param(ws.QueryParameter("async", "异步与否").DataType("boolean").Required(false)).
 		Param(ws.QueryParameter("timeoutSec", "超时时间").DataType("integer").Required(false)).
+		Param(ws.QueryParameter("scheduledAt", "定时执行时间(RFC3339格式), 设置后该操作将被记录, 到时间后才执行").DataType("string").Required(false)).
 		Reads(releaseModel.ReleaseRequestV2{}).
 		Returns(200, "OK", nil).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -122,6 +231,14 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 		Returns(200, "OK", []map[string]interface{}{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.POST("/{namespace}/dryrun/configvalues").To(releaseHandler.DryRunReleaseConfigValues).
+		Doc("模拟安装或升级一个Release, 返回合并后的配置值").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Reads(releaseModel.ReleaseRequestV2{}).
+		Returns(200, "OK", map[string]interface{}{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.POST("/{namespace}/dryrun/withchart").Consumes().To(releaseHandler.DryRunReleaseWithChart).
 		Consumes("multipart/form-data").
 		Doc("模拟用本地chart安装一个Release").
@@ -161,6 +278,27 @@ func RegisterReleaseHandler(releaseHandler *ReleaseHandler) *restful.WebService
 	//	Returns(200, "OK", nil).
 	//	Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.POST("/{namespace}/name/{release}/sync").To(releaseHandler.SyncReleaseCache).
+		Doc("强制同步一个Release的缓存").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("release", "Release名字").DataType("string")).
+		Param(ws.QueryParameter("reveal", "是否显示敏感配置值(默认用******屏蔽, 仅特权principal可用)").DataType("boolean").Required(false)).
+		Writes(releaseModel.ReleaseInfoV2{}).
+		Returns(200, "OK", releaseModel.ReleaseInfoV2{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.POST("/{namespace}/name/{release}/adopt").To(releaseHandler.AdoptRelease).
+		Doc("接管一个直接通过helm安装的Release").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("release", "Release名字").DataType("string")).
+		Param(ws.QueryParameter("project", "项目名字").DataType("string").Required(false)).
+		Returns(200, "OK", nil).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.POST("/{namespace}/name/{release}/restart").To(releaseHandler.RestartRelease).
 		Doc("Restart　Release关联的所有pod").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -212,9 +350,61 @@ func (handler *ReleaseHandler) DeleteRelease(request *restful.Request, response
 		return
 	}
 
-	err = handler.usecase.DeleteRelease(namespace, name, deletePvcs, async, timeoutSec)
+	dryRun, err := httpUtils.GetDryRunQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param dryRun value is not valid : %s", err.Error()))
+		return
+	}
+
+	if dryRun {
+		preview, err := handler.usecase.DryRunDeleteRelease(namespace, name, deletePvcs)
+		if err != nil {
+			if errorModel.IsNotFoundError(err) {
+				httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("release %s is not found", name))
+				return
+			}
+			httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to dry run delete release: %s", err.Error()))
+			return
+		}
+		response.WriteEntity(preview)
+		return
+	}
+
+	force, err := httpUtils.GetForceQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param force value is not valid : %s", err.Error()))
+		return
+	}
+	if !force {
+		dependents, err := handler.usecase.GetReleaseDependents(namespace, name)
+		if err != nil {
+			httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get dependents of release %s: %s", name, err.Error()))
+			return
+		}
+		if len(dependents) > 0 {
+			dependentNames := []string{}
+			for _, dependent := range dependents {
+				dependentNames = append(dependentNames, dependent.Namespace+"/"+dependent.Name)
+			}
+			httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("release %s is still depended on by releases %v, deleting it would break them, add query param force=true to delete it anyway", name, dependentNames))
+			return
+		}
+	}
+
+	scheduledAt, err := httpUtils.GetScheduledAtQueryParam(request)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete release: %s", err.Error()))
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param scheduledAt value is not valid : %s", err.Error()))
+		return
+	}
+	if !scheduledAt.IsZero() {
+		handler.scheduleOperation(request, response, namespace, name, scheduleModel.DeleteOperation,
+			&scheduleModel.ScheduledDeleteReleaseArgs{DeletePvcs: deletePvcs}, timeoutSec, scheduledAt)
+		return
+	}
+
+	err = handler.usecase.DeleteRelease(request.Request.Context(), namespace, name, deletePvcs, async, timeoutSec)
+	if err != nil {
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to delete release: %s", err.Error()))
 		return
 	}
 }
@@ -238,9 +428,19 @@ func (handler *ReleaseHandler) InstallRelease(request *restful.Request, response
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body: %s", err.Error()))
 		return
 	}
-	err = handler.usecase.InstallUpgradeRelease(namespace, releaseRequest, nil, async, timeoutSec, nil)
+	scheduledAt, err := httpUtils.GetScheduledAtQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param scheduledAt value is not valid : %s", err.Error()))
+		return
+	}
+	if !scheduledAt.IsZero() {
+		handler.scheduleOperation(request, response, namespace, releaseRequest.Name, scheduleModel.InstallOperation,
+			releaseRequest, timeoutSec, scheduledAt)
+		return
+	}
+	err = handler.usecase.InstallUpgradeRelease(request.Request.Context(), namespace, releaseRequest, nil, async, timeoutSec, nil)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to install release: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to install release: %s", err.Error()))
 	}
 }
 
@@ -269,9 +469,9 @@ func (handler *ReleaseHandler) InstallReleaseWithChart(request *restful.Request,
 	}
 	releaseRequest.Name = releaseName
 
-	err = handler.usecase.InstallUpgradeRelease(namespace, releaseRequest, chartFiles, false, 0, nil)
+	err = handler.usecase.InstallUpgradeRelease(request.Request.Context(), namespace, releaseRequest, chartFiles, false, 0, nil)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to install release: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to install release: %s", err.Error()))
 	}
 }
 
@@ -291,6 +491,22 @@ func (handler *ReleaseHandler) DryRunRelease(request *restful.Request, response
 	response.WriteEntity(manifest)
 }
 
+func (handler *ReleaseHandler) DryRunReleaseConfigValues(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	releaseRequest := &releaseModel.ReleaseRequestV2{}
+	err := request.ReadEntity(releaseRequest)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body: %s", err.Error()))
+		return
+	}
+	configValues, err := handler.usecase.DryRunReleaseConfigValues(namespace, releaseRequest, nil)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to dry run release config values: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(configValues)
+}
+
 func (handler *ReleaseHandler) ComputeResourcesByDryRunRelease(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	releaseRequest := &releaseModel.ReleaseRequestV2{}
@@ -393,9 +609,19 @@ func (handler *ReleaseHandler) UpgradeRelease(request *restful.Request, response
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read request body: %s", err.Error()))
 		return
 	}
-	err = handler.usecase.InstallUpgradeRelease(namespace, releaseRequest, nil, async, timeoutSec, nil)
+	scheduledAt, err := httpUtils.GetScheduledAtQueryParam(request)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to upgrade release: %s", err.Error()))
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param scheduledAt value is not valid : %s", err.Error()))
+		return
+	}
+	if !scheduledAt.IsZero() {
+		handler.scheduleOperation(request, response, namespace, releaseRequest.Name, scheduleModel.InstallOperation,
+			releaseRequest, timeoutSec, scheduledAt)
+		return
+	}
+	err = handler.usecase.InstallUpgradeRelease(request.Request.Context(), namespace, releaseRequest, nil, async, timeoutSec, nil)
+	if err != nil {
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to upgrade release: %s", err.Error()))
 	}
 }
 
@@ -427,17 +653,34 @@ func (handler *ReleaseHandler) UpgradeReleaseWithChart(request *restful.Request,
 
 	releaseRequest.Name = releaseName
 
-	err = handler.usecase.InstallUpgradeRelease(namespace, releaseRequest, chartFiles, false, 0, nil)
+	err = handler.usecase.InstallUpgradeRelease(request.Request.Context(), namespace, releaseRequest, chartFiles, false, 0, nil)
 	if err != nil {
-		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to upgrade release: %s", err.Error()))
+		httpUtils.WriteTypedErrorResponse(response, err, fmt.Sprintf("failed to upgrade release: %s", err.Error()))
 	}
 }
 
 func (handler *ReleaseHandler) ListReleaseByNamespace(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	labelSelectorStr := request.QueryParameter("labelselector")
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
+	limit, err := httpUtils.GetLimitQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param limit value is not valid : %s", err.Error()))
+		return
+	}
+	continueToken := request.QueryParameter("continue")
+	sortBy := request.QueryParameter("sort")
+	fieldsStr := request.QueryParameter("fields")
+
 	var infos []*releaseModel.ReleaseInfoV2
-	var err error
 	if labelSelectorStr == "" {
 		infos, err = handler.usecase.ListReleases(namespace)
 		if err != nil {
@@ -451,14 +694,60 @@ func (handler *ReleaseHandler) ListReleaseByNamespace(request *restful.Request,
 			return
 		}
 	}
+	if clusterStr := request.QueryParameter("cluster"); clusterStr != "" {
+		infos = filterReleasesByCluster(infos, clusterStr)
+	}
+
+	if !reveal {
+		for _, info := range infos {
+			info.MaskSensitiveValues()
+		}
+	}
+
+	if limit == 0 && continueToken == "" && sortBy == "" && fieldsStr == "" {
+		response.WriteEntity(releaseModel.ReleaseInfoV2List{Num: len(infos), Items: infos, SyncInfo: handler.getCacheSyncInfo()})
+		return
+	}
+	var fields []string
+	if fieldsStr != "" {
+		fields = strings.Split(fieldsStr, ",")
+	}
+	page, err := releaseModel.BuildReleaseInfoV2Page(infos, sortBy, limit, continueToken, fields)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build release page: %s", err.Error()))
+		return
+	}
+	response.WriteEntity(page)
+}
 
-	response.WriteEntity(releaseModel.ReleaseInfoV2List{len(infos), infos})
+// filterReleasesByCluster keeps the releases installed in the cluster, the
+// default cluster matching releases without a recorded cluster.
+func filterReleasesByCluster(infos []*releaseModel.ReleaseInfoV2, clusterName string) []*releaseModel.ReleaseInfoV2 {
+	filtered := make([]*releaseModel.ReleaseInfoV2, 0, len(infos))
+	for _, info := range infos {
+		if clusterModel.IsDefaultCluster(clusterName) {
+			if clusterModel.IsDefaultCluster(info.Cluster) {
+				filtered = append(filtered, info)
+			}
+		} else if info.Cluster == clusterName {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
 }
 
 func (handler *ReleaseHandler) ListRelease(request *restful.Request, response *restful.Response) {
 	labelSelectorStr := request.QueryParameter("labelselector")
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
 	var infos []*releaseModel.ReleaseInfoV2
-	var err error
 	if labelSelectorStr == "" {
 		infos, err = handler.usecase.ListReleases("")
 		if err != nil {
@@ -472,14 +761,51 @@ func (handler *ReleaseHandler) ListRelease(request *restful.Request, response *r
 			return
 		}
 	}
+	if clusterStr := request.QueryParameter("cluster"); clusterStr != "" {
+		infos = filterReleasesByCluster(infos, clusterStr)
+	}
 
-	response.WriteEntity(releaseModel.ReleaseInfoV2List{len(infos), infos})
+	if !reveal {
+		for _, info := range infos {
+			info.MaskSensitiveValues()
+		}
+	}
+	fieldsStr := request.QueryParameter("fields")
+	if fieldsStr != "" {
+		page, err := releaseModel.BuildReleaseInfoV2Page(infos, "", 0, "", strings.Split(fieldsStr, ","))
+		if err != nil {
+			httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build release page: %s", err.Error()))
+			return
+		}
+		response.WriteEntity(page)
+		return
+	}
+	response.WriteEntity(releaseModel.ReleaseInfoV2List{Num: len(infos), Items: infos, SyncInfo: handler.getCacheSyncInfo()})
 }
 
 func (handler *ReleaseHandler) GetRelease(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("release")
-	info, err := handler.usecase.GetRelease(namespace, name)
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
+	forceRefresh, err := httpUtils.GetForceRefreshQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param forceRefresh value is not valid : %s", err.Error()))
+		return
+	}
+	var info *releaseModel.ReleaseInfoV2
+	if forceRefresh {
+		info, err = handler.usecase.GetReleaseWithLiveStatus(namespace, name)
+	} else {
+		info, err = handler.usecase.GetRelease(namespace, name)
+	}
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
 			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("release %s is not found", name))
@@ -488,9 +814,66 @@ func (handler *ReleaseHandler) GetRelease(request *restful.Request, response *re
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get release %s: %s", name, err.Error()))
 		return
 	}
+	if !reveal {
+		info.MaskSensitiveValues()
+	}
 	response.WriteEntity(info)
 }
 
+func (handler *ReleaseHandler) GetReleaseConfigValues(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("release")
+	reveal, err := httpUtils.GetRevealQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param reveal value is not valid : %s", err.Error()))
+		return
+	}
+	if reveal && !auth.IsRequestPrivileged(request) {
+		httpUtils.WriteForbiddenResponse(response, -1, "query param reveal is only allowed for privileged principals")
+		return
+	}
+	configValues, err := handler.usecase.GetReleaseConfigValues(namespace, name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("release %s is not found", name))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get config values of release %s: %s", name, err.Error()))
+		return
+	}
+	if !reveal {
+		configValues.MaskSensitiveValues()
+	}
+	response.WriteEntity(configValues)
+}
+
+func (handler *ReleaseHandler) GetReleaseEvents(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("release")
+	limit, err := httpUtils.GetLimitQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param limit value is not valid : %s", err.Error()))
+		return
+	}
+	events, err := handler.usecase.GetReleaseEvents(namespace, name, limit)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get events of release %s: %s", name, err.Error()))
+		return
+	}
+	response.WriteEntity(releaseModel.ReleaseEventList{len(events), events})
+}
+
+func (handler *ReleaseHandler) GetReleaseDependents(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("release")
+	dependents, err := handler.usecase.GetReleaseDependents(namespace, name)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get dependents of release %s: %s", name, err.Error()))
+		return
+	}
+	response.WriteEntity(releaseModel.ReleaseInfoV2List{Num: len(dependents), Items: dependents, SyncInfo: handler.getCacheSyncInfo()})
+}
+
 func (handler *ReleaseHandler) RestartRelease(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("release")
@@ -501,6 +884,36 @@ func (handler *ReleaseHandler) RestartRelease(request *restful.Request, response
 	}
 }
 
+func (handler *ReleaseHandler) SyncReleaseCache(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("release")
+	info, err := handler.usecase.SyncReleaseCache(namespace, name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("release %s is not found", name))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to sync release cache of %s: %s", name, err.Error()))
+		return
+	}
+	response.WriteEntity(info)
+}
+
+func (handler *ReleaseHandler) AdoptRelease(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("release")
+	projectName := request.QueryParameter("project")
+	err := handler.usecase.AdoptRelease(namespace, name, projectName)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("release %s is not found in helm storage", name))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to adopt release %s: %s", name, err.Error()))
+		return
+	}
+}
+
 func (handler *ReleaseHandler) PauseRelease(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("release")
@@ -515,7 +928,7 @@ func (handler *ReleaseHandler) PauseRelease(request *restful.Request, response *
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param timeoutSec value is not valid : %s", err.Error()))
 		return
 	}
-	err = handler.usecase.PauseRelease(namespace, name, async, timeoutSec)
+	err = handler.usecase.PauseRelease(request.Request.Context(), namespace, name, async, timeoutSec)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to pause release %s: %s", name, err.Error()))
 		return
@@ -536,7 +949,7 @@ func (handler *ReleaseHandler) RecoverRelease(request *restful.Request, response
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param timeoutSec value is not valid : %s", err.Error()))
 		return
 	}
-	err = handler.usecase.RecoverRelease(namespace, name, async, timeoutSec)
+	err = handler.usecase.RecoverRelease(request.Request.Context(), namespace, name, async, timeoutSec)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to recover release %s: %s", name, err.Error()))
 		return
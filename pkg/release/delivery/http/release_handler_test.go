@@ -42,25 +42,51 @@ func TestReleaseHandler_DeleteRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("DeleteRelease", "testns", "testname", false, false, int64(0)).Return(nil)
+				mockUseCase.On("GetReleaseDependents", "testns", "testname").Return(nil, nil)
+				mockUseCase.On("DeleteRelease", mock.Anything, "testns", "testname", false, false, int64(0)).Return(nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("DeleteRelease", "testns", "testname", false, false, int64(0)).Return(errors.New(""))
+				mockUseCase.On("GetReleaseDependents", "testns", "testname").Return(nil, nil)
+				mockUseCase.On("DeleteRelease", mock.Anything, "testns", "testname", false, false, int64(0)).Return(errors.New(""))
 			},
 			statusCode: 500,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("DeleteRelease", "testns", "testname", true, true, int64(60)).Return(nil)
+				mockUseCase.On("GetReleaseDependents", "testns", "testname").Return(nil, nil)
+				mockUseCase.On("DeleteRelease", mock.Anything, "testns", "testname", true, true, int64(60)).Return(nil)
 			},
 			queryUrl:   "?deletePvcs=true&async=true&timeoutSec=60",
 			statusCode: 200,
 		},
+		{
+			initMock: func() {
+				refreshMockUseCase()
+				mockUseCase.On("GetReleaseDependents", "testns", "testname").Return([]*release.ReleaseInfoV2{
+					{
+						ReleaseInfo: release.ReleaseInfo{
+							ReleaseSpec: release.ReleaseSpec{
+								Namespace: "testns",
+								Name:      "dependent-name",
+							},
+						},
+					}}, nil)
+			},
+			statusCode: 500,
+		},
+		{
+			initMock: func() {
+				refreshMockUseCase()
+				mockUseCase.On("DeleteRelease", mock.Anything, "testns", "testname", false, false, int64(0)).Return(nil)
+			},
+			queryUrl:   "?force=true",
+			statusCode: 200,
+		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
@@ -123,7 +149,7 @@ func TestReleaseHandler_InstallRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(nil)
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(nil)
 			},
 			body:       release.ReleaseRequestV2{},
 			statusCode: 200,
@@ -131,7 +157,7 @@ func TestReleaseHandler_InstallRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(errors.New(""))
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(errors.New(""))
 			},
 			body:       release.ReleaseRequestV2{},
 			statusCode: 500,
@@ -139,7 +165,7 @@ func TestReleaseHandler_InstallRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), true, int64(60), (*bool)(nil)).Return(nil)
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), true, int64(60), (*bool)(nil)).Return(nil)
 			},
 			queryUrl:   "?async=true&timeoutSec=60",
 			body:       release.ReleaseRequestV2{},
@@ -226,7 +252,7 @@ func TestReleaseHandler_InstallReleaseWithChart(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns",
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns",
 					&release.ReleaseRequestV2{ReleaseRequest: release.ReleaseRequest{Name: "testname"}},
 					mock.Anything, false, int64(0), (*bool)(nil)).Return(nil)
 			},
@@ -238,7 +264,7 @@ func TestReleaseHandler_InstallReleaseWithChart(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns",
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns",
 					&release.ReleaseRequestV2{ReleaseRequest: release.ReleaseRequest{Name: "testname"}},
 					mock.Anything, false, int64(0), (*bool)(nil)).Return(errors.New(""))
 			},
@@ -337,7 +363,7 @@ func TestReleaseHandler_UpgradeRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(nil)
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(nil)
 			},
 			body:       release.ReleaseRequestV2{},
 			statusCode: 200,
@@ -345,7 +371,7 @@ func TestReleaseHandler_UpgradeRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(errors.New(""))
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), false, int64(0), (*bool)(nil)).Return(errors.New(""))
 			},
 			body:       release.ReleaseRequestV2{},
 			statusCode: 500,
@@ -353,7 +379,7 @@ func TestReleaseHandler_UpgradeRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), true, int64(60), (*bool)(nil)).Return(nil)
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns", &release.ReleaseRequestV2{}, ([]*common.BufferedFile)(nil), true, int64(60), (*bool)(nil)).Return(nil)
 			},
 			queryUrl:   "?async=true&timeoutSec=60",
 			body:       release.ReleaseRequestV2{},
@@ -440,7 +466,7 @@ func TestReleaseHandler_UpgradeReleaseWithChart(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns",
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns",
 					&release.ReleaseRequestV2{ReleaseRequest: release.ReleaseRequest{Name: "testname"}},
 					mock.Anything, false, int64(0), (*bool)(nil)).Return(nil)
 			},
@@ -452,7 +478,7 @@ func TestReleaseHandler_UpgradeReleaseWithChart(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("InstallUpgradeRelease", "testns",
+				mockUseCase.On("InstallUpgradeRelease", mock.Anything, "testns",
 					&release.ReleaseRequestV2{ReleaseRequest: release.ReleaseRequest{Name: "testname"}},
 					mock.Anything, false, int64(0), (*bool)(nil)).Return(errors.New(""))
 			},
@@ -1089,21 +1115,21 @@ func TestReleaseHandler_PauseRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("PauseRelease", "testns", "testname", false, int64(0)).Return(nil)
+				mockUseCase.On("PauseRelease", mock.Anything, "testns", "testname", false, int64(0)).Return(nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("PauseRelease", "testns", "testname", false, int64(0)).Return(errors.New(""))
+				mockUseCase.On("PauseRelease", mock.Anything, "testns", "testname", false, int64(0)).Return(errors.New(""))
 			},
 			statusCode: 500,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("PauseRelease", "testns", "testname", true, int64(60)).Return(nil)
+				mockUseCase.On("PauseRelease", mock.Anything, "testns", "testname", true, int64(60)).Return(nil)
 			},
 			queryUrl:   "?async=true&timeoutSec=60",
 			statusCode: 200,
@@ -1156,21 +1182,21 @@ func TestReleaseHandler_RecoverRelease(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("RecoverRelease", "testns", "testname", false, int64(0)).Return(nil)
+				mockUseCase.On("RecoverRelease", mock.Anything, "testns", "testname", false, int64(0)).Return(nil)
 			},
 			statusCode: 200,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("RecoverRelease", "testns", "testname", false, int64(0)).Return(errors.New(""))
+				mockUseCase.On("RecoverRelease", mock.Anything, "testns", "testname", false, int64(0)).Return(errors.New(""))
 			},
 			statusCode: 500,
 		},
 		{
 			initMock: func() {
 				refreshMockUseCase()
-				mockUseCase.On("RecoverRelease", "testns", "testname", true, int64(60)).Return(nil)
+				mockUseCase.On("RecoverRelease", mock.Anything, "testns", "testname", true, int64(60)).Return(nil)
 			},
 			queryUrl:   "?async=true&timeoutSec=60",
 			statusCode: 200,
@@ -0,0 +1,87 @@
+// Package hooks provides walm level pre/post actions executed around release
+// installs and upgrades, so standardized actions do not have to be added to
+// every chart.
+package hooks
+
+import (
+	"WarpCloud/walm/pkg/k8s"
+	"WarpCloud/walm/pkg/models/release"
+	"fmt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PreInstallHookType  = "pre-install"
+	PostInstallHookType = "post-install"
+	PreUpgradeHookType  = "pre-upgrade"
+)
+
+type Context struct {
+	Namespace      string
+	ReleaseRequest *release.ReleaseRequestV2
+	K8sOperator    k8s.Operator
+}
+
+type Hook interface {
+	Name() string
+	// Required reports whether a failure of this hook must fail the release
+	Required() bool
+	// Types reports the hook types the plugin runs at, given its args
+	Types(args string) []string
+	Run(context *Context, args string) error
+}
+
+var hookRegistry = map[string]Hook{}
+
+func RegisterHook(hook Hook) error {
+	if _, ok := hookRegistry[hook.Name()]; ok {
+		return fmt.Errorf("hook %s has already been registered", hook.Name())
+	}
+	hookRegistry[hook.Name()] = hook
+	return nil
+}
+
+// ExecuteHooks runs the enabled plugins of the release request registered for the
+// given hook type, reporting one result per executed plugin. Plugins which are not
+// registered hooks are ignored, as they are handled by the chart render plugin
+// system. A failed required hook stops the execution and fails the release.
+func ExecuteHooks(hookType string, context *Context) ([]*release.ReleasePluginResult, error) {
+	results := []*release.ReleasePluginResult{}
+	for _, plugin := range context.ReleaseRequest.Plugins {
+		if plugin.Disable {
+			continue
+		}
+		hook, ok := hookRegistry[plugin.Name]
+		if !ok || !hasHookType(hook.Types(plugin.Args), hookType) {
+			continue
+		}
+		result := &release.ReleasePluginResult{
+			Name:    plugin.Name,
+			Type:    hookType,
+			Succeed: true,
+		}
+		err := hook.Run(context, plugin.Args)
+		if err != nil {
+			result.Succeed = false
+			result.Message = err.Error()
+			results = append(results, result)
+			if hook.Required() {
+				logrus.Errorf("required hook %s failed : %s", plugin.Name, err.Error())
+				return results, err
+			}
+			logrus.Warnf("optional hook %s failed : %s", plugin.Name, err.Error())
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func hasHookType(hookTypes []string, hookType string) bool {
+	for _, t := range hookTypes {
+		if t == hookType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package hooks
+
+import (
+	"errors"
+	"testing"
+
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
+	"WarpCloud/walm/pkg/models/release"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RegisterHook(t *testing.T) {
+	err := RegisterHook(&labelInjectorHook{})
+	assert.NotNil(t, err)
+}
+
+func Test_ExecuteHooks_LabelInjector(t *testing.T) {
+	context := &Context{
+		Namespace: "test-ns",
+		ReleaseRequest: &release.ReleaseRequestV2{
+			Plugins: []*release.ReleasePlugin{
+				{
+					Name: LabelInjectorHookName,
+					Args: "{\"labels\": {\"test-key\": \"test-value\"}}",
+				},
+			},
+		},
+	}
+
+	results, err := ExecuteHooks(PreInstallHookType, context)
+	assert.IsType(t, nil, err)
+	assert.Equal(t, []*release.ReleasePluginResult{
+		{
+			Name:    LabelInjectorHookName,
+			Type:    PreInstallHookType,
+			Succeed: true,
+		},
+	}, results)
+	assert.Equal(t, map[string]string{"test-key": "test-value"}, context.ReleaseRequest.ReleaseLabels)
+}
+
+func Test_ExecuteHooks_DisabledPlugin(t *testing.T) {
+	context := &Context{
+		Namespace: "test-ns",
+		ReleaseRequest: &release.ReleaseRequestV2{
+			Plugins: []*release.ReleasePlugin{
+				{
+					Name:    LabelInjectorHookName,
+					Args:    "{\"labels\": {\"test-key\": \"test-value\"}}",
+					Disable: true,
+				},
+			},
+		},
+	}
+
+	results, err := ExecuteHooks(PreInstallHookType, context)
+	assert.IsType(t, nil, err)
+	assert.Equal(t, 0, len(results))
+	assert.Nil(t, context.ReleaseRequest.ReleaseLabels)
+}
+
+func Test_ExecuteHooks_JobRunner(t *testing.T) {
+	tests := []struct {
+		runJobErr      error
+		err            error
+		expectedResult *release.ReleasePluginResult
+	}{
+		{
+			runJobErr: nil,
+			err:       nil,
+			expectedResult: &release.ReleasePluginResult{
+				Name:    JobRunnerHookName,
+				Type:    PreUpgradeHookType,
+				Succeed: true,
+			},
+		},
+		{
+			runJobErr: errors.New("job pod completed with phase Failed"),
+			err:       errors.New("job pod completed with phase Failed"),
+			expectedResult: &release.ReleasePluginResult{
+				Name:    JobRunnerHookName,
+				Type:    PreUpgradeHookType,
+				Succeed: false,
+				Message: "job pod completed with phase Failed",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		mockK8sOperator := &k8sMocks.Operator{}
+		mockK8sOperator.On("RunJobManifest", "test-ns", "test-manifest", int64(60)).Return(test.runJobErr)
+		context := &Context{
+			Namespace: "test-ns",
+			ReleaseRequest: &release.ReleaseRequestV2{
+				Plugins: []*release.ReleasePlugin{
+					{
+						Name: JobRunnerHookName,
+						Args: "{\"hookType\": \"pre-upgrade\", \"jobManifest\": \"test-manifest\", \"timeoutSec\": 60}",
+					},
+				},
+			},
+			K8sOperator: mockK8sOperator,
+		}
+
+		results, err := ExecuteHooks(PreUpgradeHookType, context)
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, []*release.ReleasePluginResult{test.expectedResult}, results)
+		mockK8sOperator.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+)
+
+const JobRunnerHookName = "job-runner"
+
+const defaultJobTimeoutSec int64 = 300
+
+type JobRunnerArgs struct {
+	HookType    string `json:"hookType" description:"when to run the job: pre-install(default), post-install or pre-upgrade"`
+	JobManifest string `json:"jobManifest" description:"manifest of the job to run"`
+	TimeoutSec  int64  `json:"timeoutSec" description:"timeout waiting for the job to complete"`
+}
+
+// jobRunnerHook runs the job given in its args and waits for it to complete, e.g. a
+// schema migration job before an upgrade. The release fails if the job does not succeed.
+type jobRunnerHook struct{}
+
+func (hook *jobRunnerHook) Name() string {
+	return JobRunnerHookName
+}
+
+func (hook *jobRunnerHook) Required() bool {
+	return true
+}
+
+func (hook *jobRunnerHook) Types(args string) []string {
+	hookArgs, err := parseJobRunnerArgs(args)
+	if err != nil {
+		return []string{PreInstallHookType}
+	}
+	return []string{hookArgs.HookType}
+}
+
+func (hook *jobRunnerHook) Run(context *Context, args string) error {
+	hookArgs, err := parseJobRunnerArgs(args)
+	if err != nil {
+		return err
+	}
+	if hookArgs.JobManifest == "" {
+		return fmt.Errorf("%s args must contain jobManifest", JobRunnerHookName)
+	}
+	return context.K8sOperator.RunJobManifest(context.Namespace, hookArgs.JobManifest, hookArgs.TimeoutSec)
+}
+
+func parseJobRunnerArgs(args string) (*JobRunnerArgs, error) {
+	hookArgs := &JobRunnerArgs{}
+	if args != "" {
+		err := json.Unmarshal([]byte(args), hookArgs)
+		if err != nil {
+			logrus.Errorf("%s args is not valid : %s", JobRunnerHookName, err.Error())
+			return nil, err
+		}
+	}
+	if hookArgs.HookType == "" {
+		hookArgs.HookType = PreInstallHookType
+	}
+	if hookArgs.TimeoutSec <= 0 {
+		hookArgs.TimeoutSec = defaultJobTimeoutSec
+	}
+	return hookArgs, nil
+}
+
+func init() {
+	RegisterHook(&jobRunnerHook{})
+}
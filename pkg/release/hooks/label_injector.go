@@ -0,0 +1,53 @@
+package hooks
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+)
+
+const LabelInjectorHookName = "label-injector"
+
+type LabelInjectorArgs struct {
+	Labels map[string]string `json:"labels" description:"labels to inject into the release"`
+}
+
+// labelInjectorHook injects the labels given in its args into the release labels
+// before the release is installed or upgraded.
+type labelInjectorHook struct{}
+
+func (hook *labelInjectorHook) Name() string {
+	return LabelInjectorHookName
+}
+
+func (hook *labelInjectorHook) Required() bool {
+	return false
+}
+
+func (hook *labelInjectorHook) Types(args string) []string {
+	return []string{PreInstallHookType, PreUpgradeHookType}
+}
+
+func (hook *labelInjectorHook) Run(context *Context, args string) error {
+	hookArgs := &LabelInjectorArgs{}
+	if args != "" {
+		err := json.Unmarshal([]byte(args), hookArgs)
+		if err != nil {
+			logrus.Errorf("%s args is not valid : %s", LabelInjectorHookName, err.Error())
+			return err
+		}
+	}
+	if len(hookArgs.Labels) == 0 {
+		return nil
+	}
+	if context.ReleaseRequest.ReleaseLabels == nil {
+		context.ReleaseRequest.ReleaseLabels = map[string]string{}
+	}
+	for key, value := range hookArgs.Labels {
+		context.ReleaseRequest.ReleaseLabels[key] = value
+	}
+	return nil
+}
+
+func init() {
+	RegisterHook(&labelInjectorHook{})
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/tls"
 	"github.com/containerd/containerd/remotes/docker"
 	"os"
+	"sync"
 	"WarpCloud/walm/pkg/setting"
 )
 
@@ -16,9 +17,16 @@ type ChartImageClient struct {
 }
 
 var chartImageClient *ChartImageClient
+var chartImageClientOnce sync.Once
+
+func NewChartImageClient(registryClient *registry.Client) *ChartImageClient {
+	return &ChartImageClient{
+		registryClient: registryClient,
+	}
+}
 
 func GetDefaultChartImageClient() *ChartImageClient {
-	if chartImageClient == nil {
+	chartImageClientOnce.Do(func() {
 		tr := &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		}
@@ -38,10 +46,8 @@ func GetDefaultChartImageClient() *ChartImageClient {
 		}
 		registryClient := registry.NewClient(option)
 
-		chartImageClient = &ChartImageClient{
-			registryClient: registryClient,
-		}
-	}
+		chartImageClient = NewChartImageClient(registryClient)
+	})
 	return chartImageClient
 }
 
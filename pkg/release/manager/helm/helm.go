@@ -3,6 +3,7 @@ package helm
 import (
 	"fmt"
 	"io/ioutil"
+	"sync"
 	"time"
 	"github.com/hashicorp/golang-lru"
 	"github.com/sirupsen/logrus"
@@ -44,9 +45,27 @@ type HelmClient struct {
 }
 
 var helmClient *HelmClient
+var helmClientOnce sync.Once
+
+// NewHelmClient builds a helm client on injected dependencies, so tests and
+// multi-instance scenarios do not go through the default singleton.
+func NewHelmClient(chartRepoMap map[string]*ChartRepository, helmCache *cache.HelmCache, releaseConfigHandler *handler.ReleaseConfigHandler) *HelmClient {
+	helmClients, _ := lru.New(100)
+
+	return &HelmClient{
+		chartRepoMap:            chartRepoMap,
+		helmCache:               helmCache,
+		helmCacheResyncInterval: helmCacheDefaultResyncInterval,
+		releaseConfigHandler:    releaseConfigHandler,
+		helmClients:             helmClients,
+	}
+}
 
+// GetDefaultHelmClient lazily builds the default helm client, the sync.Once
+// keeps concurrent first calls from constructing two clients. The other
+// GetDefault* accessors follow the same pattern.
 func GetDefaultHelmClient() *HelmClient {
-	if helmClient == nil {
+	helmClientOnce.Do(func() {
 		chartRepoMap := make(map[string]*ChartRepository)
 
 		for _, chartRepo := range setting.Config.RepoList {
@@ -61,16 +80,8 @@ func GetDefaultHelmClient() *HelmClient {
 
 		helmCache := cache.NewHelmCache(redis.GetDefaultRedisClient())
 
-		helmClients, _ := lru.New(100)
-
-		helmClient = &HelmClient{
-			chartRepoMap:            chartRepoMap,
-			helmCache:               helmCache,
-			helmCacheResyncInterval: helmCacheDefaultResyncInterval,
-			releaseConfigHandler:    handler.GetDefaultHandlerSet().GetReleaseConfigHandler(),
-			helmClients:             helmClients,
-		}
-	}
+		helmClient = NewHelmClient(chartRepoMap, helmCache, handler.GetDefaultHandlerSet().GetReleaseConfigHandler())
+	})
 	return helmClient
 }
 
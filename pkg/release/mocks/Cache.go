@@ -12,6 +12,20 @@ type Cache struct {
 	mock.Mock
 }
 
+// AddReleaseEvent provides a mock function with given fields: namespace, name, event
+func (_m *Cache) AddReleaseEvent(namespace string, name string, event *release.ReleaseEvent) error {
+	ret := _m.Called(namespace, name, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *release.ReleaseEvent) error); ok {
+		r0 = rf(namespace, name, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateOrUpdateReleaseCache provides a mock function with given fields: releaseCache
 func (_m *Cache) CreateOrUpdateReleaseCache(releaseCache *release.ReleaseCache) error {
 	ret := _m.Called(releaseCache)
@@ -137,6 +151,29 @@ func (_m *Cache) GetReleaseCachesByReleaseConfigs(releaseConfigs []*k8s.ReleaseC
 	return r0, r1
 }
 
+// GetReleaseEvents provides a mock function with given fields: namespace, name, limit
+func (_m *Cache) GetReleaseEvents(namespace string, name string, limit int) ([]*release.ReleaseEvent, error) {
+	ret := _m.Called(namespace, name, limit)
+
+	var r0 []*release.ReleaseEvent
+	if rf, ok := ret.Get(0).(func(string, string, int) []*release.ReleaseEvent); ok {
+		r0 = rf(namespace, name, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*release.ReleaseEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(namespace, name, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetReleaseTask provides a mock function with given fields: namespace, name
 func (_m *Cache) GetReleaseTask(namespace string, name string) (*release.ReleaseTask, error) {
 	ret := _m.Called(namespace, name)
@@ -3,6 +3,7 @@
 package mocks
 
 import common "WarpCloud/walm/pkg/models/common"
+import context "context"
 import mock "github.com/stretchr/testify/mock"
 
 import release "WarpCloud/walm/pkg/models/release"
@@ -12,6 +13,52 @@ type UseCase struct {
 	mock.Mock
 }
 
+// AdoptRelease provides a mock function with given fields: namespace, releaseName, projectName
+func (_m *UseCase) AdoptRelease(namespace string, releaseName string, projectName string) error {
+	ret := _m.Called(namespace, releaseName, projectName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(namespace, releaseName, projectName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ComputeReleasesResources provides a mock function with given fields: namespace
+func (_m *UseCase) ComputeReleasesResources(namespace string) ([]*release.ReleaseResources, []string, error) {
+	ret := _m.Called(namespace)
+
+	var r0 []*release.ReleaseResources
+	if rf, ok := ret.Get(0).(func(string) []*release.ReleaseResources); ok {
+		r0 = rf(namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*release.ReleaseResources)
+		}
+	}
+
+	var r1 []string
+	if rf, ok := ret.Get(1).(func(string) []string); ok {
+		r1 = rf(namespace)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(namespace)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ComputeResourcesByDryRunRelease provides a mock function with given fields: namespace, releaseRequest, chartFiles
 func (_m *UseCase) ComputeResourcesByDryRunRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) (*release.ReleaseResources, error) {
 	ret := _m.Called(namespace, releaseRequest, chartFiles)
@@ -35,13 +82,13 @@ func (_m *UseCase) ComputeResourcesByDryRunRelease(namespace string, releaseRequ
 	return r0, r1
 }
 
-// DeleteRelease provides a mock function with given fields: namespace, releaseName, deletePvcs, async, timeoutSec
-func (_m *UseCase) DeleteRelease(namespace string, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, releaseName, deletePvcs, async, timeoutSec)
+// DeleteRelease provides a mock function with given fields: ctx, namespace, releaseName, deletePvcs, async, timeoutSec
+func (_m *UseCase) DeleteRelease(ctx context.Context, namespace string, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
+	ret := _m.Called(ctx, namespace, releaseName, deletePvcs, async, timeoutSec)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, bool, bool, int64) error); ok {
-		r0 = rf(namespace, releaseName, deletePvcs, async, timeoutSec)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, bool, int64) error); ok {
+		r0 = rf(ctx, namespace, releaseName, deletePvcs, async, timeoutSec)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,13 +96,13 @@ func (_m *UseCase) DeleteRelease(namespace string, releaseName string, deletePvc
 	return r0
 }
 
-// DeleteReleaseWithRetry provides a mock function with given fields: namespace, releaseName, deletePvcs, async, timeoutSec
-func (_m *UseCase) DeleteReleaseWithRetry(namespace string, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, releaseName, deletePvcs, async, timeoutSec)
+// DeleteReleaseWithRetry provides a mock function with given fields: ctx, namespace, releaseName, deletePvcs, async, timeoutSec
+func (_m *UseCase) DeleteReleaseWithRetry(ctx context.Context, namespace string, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
+	ret := _m.Called(ctx, namespace, releaseName, deletePvcs, async, timeoutSec)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, bool, bool, int64) error); ok {
-		r0 = rf(namespace, releaseName, deletePvcs, async, timeoutSec)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, bool, int64) error); ok {
+		r0 = rf(ctx, namespace, releaseName, deletePvcs, async, timeoutSec)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -63,6 +110,29 @@ func (_m *UseCase) DeleteReleaseWithRetry(namespace string, releaseName string,
 	return r0
 }
 
+// DryRunDeleteRelease provides a mock function with given fields: namespace, releaseName, deletePvcs
+func (_m *UseCase) DryRunDeleteRelease(namespace string, releaseName string, deletePvcs bool) (*release.ReleaseDeletePreview, error) {
+	ret := _m.Called(namespace, releaseName, deletePvcs)
+
+	var r0 *release.ReleaseDeletePreview
+	if rf, ok := ret.Get(0).(func(string, string, bool) *release.ReleaseDeletePreview); ok {
+		r0 = rf(namespace, releaseName, deletePvcs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ReleaseDeletePreview)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+		r1 = rf(namespace, releaseName, deletePvcs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DryRunRelease provides a mock function with given fields: namespace, releaseRequest, chartFiles
 func (_m *UseCase) DryRunRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) ([]map[string]interface{}, error) {
 	ret := _m.Called(namespace, releaseRequest, chartFiles)
@@ -86,6 +156,29 @@ func (_m *UseCase) DryRunRelease(namespace string, releaseRequest *release.Relea
 	return r0, r1
 }
 
+// DryRunReleaseConfigValues provides a mock function with given fields: namespace, releaseRequest, chartFiles
+func (_m *UseCase) DryRunReleaseConfigValues(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) (map[string]interface{}, error) {
+	ret := _m.Called(namespace, releaseRequest, chartFiles)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile) map[string]interface{}); ok {
+		r0 = rf(namespace, releaseRequest, chartFiles)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile) error); ok {
+		r1 = rf(namespace, releaseRequest, chartFiles)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRelease provides a mock function with given fields: namespace, name
 func (_m *UseCase) GetRelease(namespace string, name string) (*release.ReleaseInfoV2, error) {
 	ret := _m.Called(namespace, name)
@@ -109,13 +202,105 @@ func (_m *UseCase) GetRelease(namespace string, name string) (*release.ReleaseIn
 	return r0, r1
 }
 
-// InstallUpgradeRelease provides a mock function with given fields: namespace, releaseRequest, chartFiles, async, timeoutSec, paused
-func (_m *UseCase) InstallUpgradeRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
-	ret := _m.Called(namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
+// GetReleaseConfigValues provides a mock function with given fields: namespace, name
+func (_m *UseCase) GetReleaseConfigValues(namespace string, name string) (*release.ReleaseConfigValues, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *release.ReleaseConfigValues
+	if rf, ok := ret.Get(0).(func(string, string) *release.ReleaseConfigValues); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ReleaseConfigValues)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReleaseDependents provides a mock function with given fields: namespace, releaseName
+func (_m *UseCase) GetReleaseDependents(namespace string, releaseName string) ([]*release.ReleaseInfoV2, error) {
+	ret := _m.Called(namespace, releaseName)
+
+	var r0 []*release.ReleaseInfoV2
+	if rf, ok := ret.Get(0).(func(string, string) []*release.ReleaseInfoV2); ok {
+		r0 = rf(namespace, releaseName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*release.ReleaseInfoV2)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, releaseName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReleaseEvents provides a mock function with given fields: namespace, releaseName, limit
+func (_m *UseCase) GetReleaseEvents(namespace string, releaseName string, limit int) ([]*release.ReleaseEvent, error) {
+	ret := _m.Called(namespace, releaseName, limit)
+
+	var r0 []*release.ReleaseEvent
+	if rf, ok := ret.Get(0).(func(string, string, int) []*release.ReleaseEvent); ok {
+		r0 = rf(namespace, releaseName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*release.ReleaseEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(namespace, releaseName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReleaseWithLiveStatus provides a mock function with given fields: namespace, name
+func (_m *UseCase) GetReleaseWithLiveStatus(namespace string, name string) (*release.ReleaseInfoV2, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *release.ReleaseInfoV2
+	if rf, ok := ret.Get(0).(func(string, string) *release.ReleaseInfoV2); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ReleaseInfoV2)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InstallUpgradeRelease provides a mock function with given fields: ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused
+func (_m *UseCase) InstallUpgradeRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
+	ret := _m.Called(ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, int64, *bool) error); ok {
-		r0 = rf(namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, int64, *bool) error); ok {
+		r0 = rf(ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -123,13 +308,13 @@ func (_m *UseCase) InstallUpgradeRelease(namespace string, releaseRequest *relea
 	return r0
 }
 
-// InstallUpgradeReleaseWithRetry provides a mock function with given fields: namespace, releaseRequest, chartFiles, async, timeoutSec, paused
-func (_m *UseCase) InstallUpgradeReleaseWithRetry(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
-	ret := _m.Called(namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
+// InstallUpgradeReleaseWithRetry provides a mock function with given fields: ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused
+func (_m *UseCase) InstallUpgradeReleaseWithRetry(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
+	ret := _m.Called(ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, int64, *bool) error); ok {
-		r0 = rf(namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *release.ReleaseRequestV2, []*common.BufferedFile, bool, int64, *bool) error); ok {
+		r0 = rf(ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -183,13 +368,13 @@ func (_m *UseCase) ListReleasesByLabels(namespace string, labelSelectorStr strin
 	return r0, r1
 }
 
-// PauseRelease provides a mock function with given fields: namespace, releaseName, async, timeoutSec
-func (_m *UseCase) PauseRelease(namespace string, releaseName string, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, releaseName, async, timeoutSec)
+// PauseRelease provides a mock function with given fields: ctx, namespace, releaseName, async, timeoutSec
+func (_m *UseCase) PauseRelease(ctx context.Context, namespace string, releaseName string, async bool, timeoutSec int64) error {
+	ret := _m.Called(ctx, namespace, releaseName, async, timeoutSec)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, bool, int64) error); ok {
-		r0 = rf(namespace, releaseName, async, timeoutSec)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, int64) error); ok {
+		r0 = rf(ctx, namespace, releaseName, async, timeoutSec)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -197,13 +382,13 @@ func (_m *UseCase) PauseRelease(namespace string, releaseName string, async bool
 	return r0
 }
 
-// RecoverRelease provides a mock function with given fields: namespace, releaseName, async, timeoutSec
-func (_m *UseCase) RecoverRelease(namespace string, releaseName string, async bool, timeoutSec int64) error {
-	ret := _m.Called(namespace, releaseName, async, timeoutSec)
+// RecoverRelease provides a mock function with given fields: ctx, namespace, releaseName, async, timeoutSec
+func (_m *UseCase) RecoverRelease(ctx context.Context, namespace string, releaseName string, async bool, timeoutSec int64) error {
+	ret := _m.Called(ctx, namespace, releaseName, async, timeoutSec)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, bool, int64) error); ok {
-		r0 = rf(namespace, releaseName, async, timeoutSec)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, int64) error); ok {
+		r0 = rf(ctx, namespace, releaseName, async, timeoutSec)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -238,3 +423,54 @@ func (_m *UseCase) RestartRelease(namespace string, releaseName string) error {
 
 	return r0
 }
+
+// SyncReleaseCache provides a mock function with given fields: namespace, name
+func (_m *UseCase) SyncReleaseCache(namespace string, name string) (*release.ReleaseInfoV2, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *release.ReleaseInfoV2
+	if rf, ok := ret.Get(0).(func(string, string) *release.ReleaseInfoV2); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*release.ReleaseInfoV2)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchReleaseReady provides a mock function with given fields: ctx, namespace, name, timeoutSec
+func (_m *UseCase) WatchReleaseReady(ctx context.Context, namespace string, name string, timeoutSec int64) error {
+	ret := _m.Called(ctx, namespace, name, timeoutSec)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) error); ok {
+		r0 = rf(ctx, namespace, name, timeoutSec)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchReleasesReady provides a mock function with given fields: ctx, namespace, names, timeoutSec
+func (_m *UseCase) WatchReleasesReady(ctx context.Context, namespace string, names []string, timeoutSec int64) error {
+	ret := _m.Called(ctx, namespace, names, timeoutSec)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int64) error); ok {
+		r0 = rf(ctx, namespace, names, timeoutSec)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -1,6 +1,8 @@
 package release
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/models/common"
 )
@@ -11,21 +13,42 @@ const (
 
 type UseCase interface {
 	GetRelease(namespace, name string) (releaseV2 *release.ReleaseInfoV2, err error)
+	// GetReleaseWithLiveStatus is GetRelease with the workload statuses read from the
+	// apiserver instead of the informer caches, for callers needing strict consistency
+	GetReleaseWithLiveStatus(namespace, name string) (releaseV2 *release.ReleaseInfoV2, err error)
+	GetReleaseConfigValues(namespace, name string) (*release.ReleaseConfigValues, error)
+	GetReleaseEvents(namespace, releaseName string, limit int) ([]*release.ReleaseEvent, error)
 	ListReleases(namespace string) ([]*release.ReleaseInfoV2, error)
 	ListReleasesByLabels(namespace string, labelSelectorStr string) ([]*release.ReleaseInfoV2, error)
+	GetReleaseDependents(namespace, releaseName string) ([]*release.ReleaseInfoV2, error)
 	DryRunRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) ([]map[string]interface{}, error)
+	DryRunReleaseConfigValues(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) (map[string]interface{}, error)
 	ComputeResourcesByDryRunRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) (*release.ReleaseResources, error)
-	DeleteReleaseWithRetry(namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error
-	DeleteRelease(namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error
+	// the second result names the releases whose resources could not be computed
+	ComputeReleasesResources(namespace string) ([]*release.ReleaseResources, []string, error)
+	// mutating methods take a ctx carrying the trace of the api call, it is
+	// propagated through the task queue to the task worker
+	DeleteReleaseWithRetry(ctx context.Context, namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error
+	DeleteRelease(ctx context.Context, namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error
+	DryRunDeleteRelease(namespace, releaseName string, deletePvcs bool) (*release.ReleaseDeletePreview, error)
 	// paused :
 	// 1. nil: maintain pause state
 	// 2. true: make release paused
 	// 3. false: make release recovered
-	InstallUpgradeReleaseWithRetry(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error
-	InstallUpgradeRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error
+	InstallUpgradeReleaseWithRetry(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error
+	InstallUpgradeRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error
 	ReloadRelease(namespace, name string) error
+	AdoptRelease(namespace, releaseName, projectName string) error
+	SyncReleaseCache(namespace, name string) (*release.ReleaseInfoV2, error)
 	RestartRelease(namespace, releaseName string) error
-	RecoverRelease(namespace, releaseName string, async bool, timeoutSec int64) error
-	PauseRelease(namespace, releaseName string, async bool, timeoutSec int64) error
+	RecoverRelease(ctx context.Context, namespace, releaseName string, async bool, timeoutSec int64) error
+	PauseRelease(ctx context.Context, namespace, releaseName string, async bool, timeoutSec int64) error
+	// WatchReleaseReady blocks until the release is ready, its latest task
+	// failed, the timeout passed, or the ctx is canceled, zero timeoutSec
+	// means the default
+	WatchReleaseReady(ctx context.Context, namespace, name string, timeoutSec int64) error
+	// WatchReleasesReady is WatchReleaseReady over a set of releases, failing
+	// fast on the first release failing
+	WatchReleasesReady(ctx context.Context, namespace string, names []string, timeoutSec int64) error
 
 }
@@ -0,0 +1,110 @@
+package helm
+
+import (
+	errorModel "WarpCloud/walm/pkg/models/error"
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	"WarpCloud/walm/pkg/models/project"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"reflect"
+)
+
+// AdoptRelease takes a release installed directly with helm under walm's management :
+// it rebuilds the walm release cache from helm storage, publishes the release config
+// and optionally labels the release into a project. If a walm release cache already
+// exists with different content, the conflict is reported instead of overwritten.
+func (helm *Helm) AdoptRelease(namespace, releaseName, projectName string) error {
+	adoptedCache, err := helm.helm.GetReleaseCache(namespace, releaseName)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			logrus.Warnf("release %s/%s is not found in helm storage", namespace, releaseName)
+		} else {
+			logrus.Errorf("failed to get release %s/%s from helm storage : %s", namespace, releaseName, err.Error())
+		}
+		return err
+	}
+
+	existedCache, err := helm.releaseCache.GetReleaseCache(namespace, releaseName)
+	if err != nil && !errorModel.IsNotFoundError(err) {
+		logrus.Errorf("failed to get release cache of %s/%s : %s", namespace, releaseName, err.Error())
+		return err
+	}
+	if existedCache != nil && !reflect.DeepEqual(existedCache.ReleaseSpec, adoptedCache.ReleaseSpec) {
+		return fmt.Errorf("release %s/%s already has a walm release cache with different content, refuse to adopt it", namespace, releaseName)
+	}
+
+	err = helm.releaseCache.CreateOrUpdateReleaseCache(adoptedCache)
+	if err != nil {
+		logrus.Errorf("failed to create or update release cache of %s/%s : %s", namespace, releaseName, err.Error())
+		return err
+	}
+
+	_, err = helm.releaseCache.GetReleaseTask(namespace, releaseName)
+	if err != nil {
+		if !errorModel.IsNotFoundError(err) {
+			logrus.Errorf("failed to get release task of %s/%s : %s", namespace, releaseName, err.Error())
+			return err
+		}
+		err = helm.releaseCache.CreateOrUpdateReleaseTask(&releaseModel.ReleaseTask{
+			Namespace: namespace,
+			Name:      releaseName,
+		})
+		if err != nil {
+			logrus.Errorf("failed to create release task of %s/%s : %s", namespace, releaseName, err.Error())
+			return err
+		}
+	}
+
+	releaseLabels := map[string]string{}
+	if projectName != "" {
+		releaseLabels[project.ProjectNameLabelKey] = projectName
+	}
+	err = helm.publishReleaseConfig(adoptedCache, releaseLabels)
+	if err != nil {
+		logrus.Errorf("failed to create or update release config of %s/%s : %s", namespace, releaseName, err.Error())
+		return err
+	}
+
+	logrus.Infof("succeed to adopt release %s/%s", namespace, releaseName)
+	return nil
+}
+
+// publishReleaseConfig creates or updates the release config of a release from its
+// release cache, keeping the output config and labels the release already published.
+func (helm *Helm) publishReleaseConfig(releaseCache *releaseModel.ReleaseCache, releaseLabels map[string]string) error {
+	if releaseLabels == nil {
+		releaseLabels = map[string]string{}
+	}
+	releaseConfig := &k8sModel.ReleaseConfig{
+		Meta:                     k8sModel.NewMeta(k8sModel.ReleaseConfigKind, releaseCache.Namespace, releaseCache.Name, k8sModel.NewState("Ready", "", "")),
+		Labels:                   releaseLabels,
+		ConfigValues:             releaseCache.ConfigValues,
+		DependenciesConfigValues: map[string]interface{}{},
+		Dependencies:             releaseCache.Dependencies,
+		ChartName:                releaseCache.ChartName,
+		ChartVersion:             releaseCache.ChartVersion,
+		ChartAppVersion:          releaseCache.ChartAppVersion,
+		OutputConfig:             map[string]interface{}{},
+		Repo:                     releaseCache.RepoName,
+	}
+
+	releaseConfigResource, err := helm.k8sCache.GetResource(k8sModel.ReleaseConfigKind, releaseCache.Namespace, releaseCache.Name)
+	if err != nil {
+		if !errorModel.IsNotFoundError(err) {
+			logrus.Errorf("failed to get release config of %s/%s : %s", releaseCache.Namespace, releaseCache.Name, err.Error())
+			return err
+		}
+	} else {
+		existedReleaseConfig := releaseConfigResource.(*k8sModel.ReleaseConfig)
+		releaseConfig.OutputConfig = existedReleaseConfig.OutputConfig
+		releaseConfig.DependenciesConfigValues = existedReleaseConfig.DependenciesConfigValues
+		for key, value := range existedReleaseConfig.Labels {
+			if _, ok := releaseConfig.Labels[key]; !ok {
+				releaseConfig.Labels[key] = value
+			}
+		}
+	}
+
+	return helm.k8sOperator.CreateOrUpdateReleaseConfig(releaseConfig)
+}
@@ -0,0 +1,50 @@
+package helm
+
+import (
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"github.com/sirupsen/logrus"
+)
+
+// GetReleaseConfigValues separates the values a release is rendered with :
+// the user supplied config values, the config values injected by each dependency
+// and the output config values the release publishes on its release config.
+func (helm *Helm) GetReleaseConfigValues(namespace, name string) (*releaseModel.ReleaseConfigValues, error) {
+	releaseInfo, err := helm.GetRelease(namespace, name)
+	if err != nil {
+		logrus.Errorf("failed to get release %s/%s : %s", namespace, name, err.Error())
+		return nil, err
+	}
+
+	releaseConfigValues := &releaseModel.ReleaseConfigValues{
+		ConfigValues:             releaseInfo.ConfigValues,
+		DependenciesConfigValues: map[string]map[string]interface{}{},
+		OutputConfigValues:       releaseInfo.OutputConfigValues,
+	}
+
+	// external dependencies already carry their output config values inline
+	for dependencyName, outputConfig := range releaseInfo.ExternalDependencies {
+		releaseConfigValues.DependenciesConfigValues[dependencyName] = outputConfig
+	}
+
+	if len(releaseInfo.Dependencies) == 0 {
+		return releaseConfigValues, nil
+	}
+
+	chartInfo, err := helm.helm.GetChartDetailInfo(releaseInfo.RepoName, releaseInfo.ChartName, releaseInfo.ChartVersion)
+	if err != nil {
+		logrus.Errorf("failed to get chart info : %s", err.Error())
+		return nil, err
+	}
+
+	for dependencyName, dependedRelease := range releaseInfo.Dependencies {
+		dependencyConfigs, err := helm.helm.GetDependencyOutputConfigs(namespace,
+			map[string]string{dependencyName: dependedRelease}, chartInfo, false)
+		if err != nil {
+			logrus.Errorf("failed to get output configs of dependency %s of %s/%s : %s", dependencyName, namespace, name, err.Error())
+			return nil, err
+		}
+		releaseConfigValues.DependenciesConfigValues[dependencyName] = dependencyConfigs
+	}
+
+	return releaseConfigValues, nil
+}
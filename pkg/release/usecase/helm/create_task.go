@@ -1,10 +1,20 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
+	"fmt"
+	"time"
+	"WarpCloud/walm/pkg/metrics"
 	"WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/models/common"
+	errorModel "WarpCloud/walm/pkg/models/error"
+	"WarpCloud/walm/pkg/release/utils"
+	"WarpCloud/walm/pkg/util"
+
+	"github.com/opentracing/opentracing-go"
 )
 
 const (
@@ -16,24 +26,105 @@ type CreateReleaseTaskArgs struct {
 	ReleaseRequest *release.ReleaseRequestV2
 	ChartFiles     []*common.BufferedFile
 	Paused         *bool
+	// the release timeout bounding the dependency wait, zero means the default
+	TimeoutSec int64
 }
 
 func (helm *Helm) registerCreateReleaseTask() error{
 	return helm.task.RegisterTask(createReleaseTaskName, helm.createReleaseTask)
 }
 
-func (helm *Helm) createReleaseTask(releaseTaskArgsStr string) error {
+// the ctx carries the trace of the api call which sent the task, resumed by
+// the task worker
+func (helm *Helm) createReleaseTask(ctx context.Context, releaseTaskArgsStr string) error {
 	releaseTaskArgs := &CreateReleaseTaskArgs{}
 	err := json.Unmarshal([]byte(releaseTaskArgsStr), releaseTaskArgs)
 	if err != nil {
 		logrus.Errorf("%s args is not valid : %s", createReleaseTaskName, err.Error())
 		return err
 	}
-	_, err = helm.doInstallUpgradeRelease(releaseTaskArgs.Namespace,
+	releaseSlot, err := helm.acquireNamespaceReleaseTaskSlot(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name)
+	if err != nil {
+		logrus.Errorf("failed to acquire a concurrency slot for release %s/%s : %s",
+			releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name, err.Error())
+		return err
+	}
+	defer releaseSlot()
+	taskUuid := helm.latestTaskUuid(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name)
+	helm.k8sOperator.RecordReleaseConfigEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name,
+		"Normal", "InstallUpgradeStarted", fmt.Sprintf("started install or upgrade task %s", taskUuid))
+
+	if releaseTaskArgs.ReleaseRequest.WaitForDependencies {
+		waitSpan, _ := opentracing.StartSpanFromContext(ctx, "wait-for-dependencies")
+		err = helm.waitForDependenciesReady(ctx, releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest, releaseTaskArgs.TimeoutSec)
+		waitSpan.Finish()
+		if err != nil {
+			logrus.Errorf("failed to wait for the dependencies of release %s/%s : %s", releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name, err.Error())
+			helm.recordReleaseEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name, release.ReleaseEventInstallUpgradeFailed, err.Error())
+			helm.k8sOperator.RecordReleaseConfigEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name,
+				"Warning", release.ReleaseEventInstallUpgradeFailed, fmt.Sprintf("install or upgrade task %s failed : %s", taskUuid, err.Error()))
+			return err
+		}
+	}
+
+	startTime := time.Now()
+	_, err = helm.doInstallUpgradeRelease(ctx, releaseTaskArgs.Namespace,
 		releaseTaskArgs.ReleaseRequest, releaseTaskArgs.ChartFiles, false, releaseTaskArgs.Paused)
+	metrics.ObserveReleaseOperation("install_upgrade", time.Since(startTime), err)
+	helm.writeAuditRecord("installUpgradeRelease", releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name,
+		map[string]interface{}{"configValues": util.MaskSensitiveValues(releaseTaskArgs.ReleaseRequest.ConfigValues)}, err)
 	if err != nil {
 		logrus.Errorf("failed to install or update release %s/%s : %s", releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name, err.Error())
+		helm.recordReleaseEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name, release.ReleaseEventInstallUpgradeFailed, err.Error())
+		helm.k8sOperator.RecordReleaseConfigEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseRequest.Name,
+			"Warning", release.ReleaseEventInstallUpgradeFailed, fmt.Sprintf("install or upgrade task %s failed : %s", taskUuid, err.Error()))
 		return err
 	}
 	return nil
 }
+
+// waitForDependenciesReady blocks until every declared hard dependency of the
+// release is ready, up to the release timeout. Waiting is recorded as a
+// release event per dependency, so it shows up in the task progress. Soft
+// dependencies only provide config values and never gate the installation.
+func (helm *Helm) waitForDependenciesReady(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, timeoutSec int64) error {
+	if timeoutSec == 0 {
+		timeoutSec = defaultTimeoutSec
+	}
+	deadline := time.Now().Add(time.Duration(timeoutSec) * time.Second)
+
+	for dependencyChart, dependencyValue := range releaseRequest.Dependencies {
+		dependedRelease, soft := release.ParseDependencyValue(dependencyValue)
+		if soft {
+			continue
+		}
+		dependencyNamespace, dependencyName, err := utils.ParseDependedRelease(namespace, dependedRelease)
+		if err != nil {
+			return err
+		}
+		dependencyInfo, err := helm.GetRelease(dependencyNamespace, dependencyName)
+		if err != nil && !errorModel.IsNotFoundError(err) {
+			logrus.Errorf("failed to get dependency release %s/%s : %s", dependencyNamespace, dependencyName, err.Error())
+			return err
+		}
+		if err == nil && dependencyInfo.Ready {
+			continue
+		}
+		logrus.Infof("release %s/%s is waiting for dependency %s (release %s/%s) to be ready",
+			namespace, releaseRequest.Name, dependencyChart, dependencyNamespace, dependencyName)
+		helm.recordReleaseEvent(namespace, releaseRequest.Name, release.ReleaseEventWaitingDependency,
+			fmt.Sprintf("waiting for dependency %s (release %s/%s) to be ready", dependencyChart, dependencyNamespace, dependencyName))
+		remainingSec := int64(time.Until(deadline) / time.Second)
+		if remainingSec <= 0 {
+			return errorModel.NewTimeoutError(fmt.Sprintf("timeout waiting for dependency %s (release %s/%s) to be ready", dependencyChart, dependencyNamespace, dependencyName))
+		}
+		err = helm.WatchReleaseReady(ctx, dependencyNamespace, dependencyName, remainingSec)
+		if err != nil {
+			if errorModel.IsTimeoutError(err) {
+				return errorModel.NewTimeoutError(fmt.Sprintf("timeout waiting for dependency %s (release %s/%s) to be ready", dependencyChart, dependencyNamespace, dependencyName))
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"errors"
 	"github.com/stretchr/testify/assert"
@@ -55,7 +56,10 @@ func TestHelm_createReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sOperator.On("RecordReleaseConfigEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockReleaseCache.On("AddReleaseEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			releaseTaskArgsStr: "{\"ReleaseRequest\":{}}",
 			err: errors.New("failed"),
@@ -63,9 +67,14 @@ func TestHelm_createReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sOperator.On("RecordReleaseConfigEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseCache", mock.Anything).Return(nil)
+				mockReleaseCache.On("AddReleaseEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				mockK8sOperator.On("UpdateReleaseConfigStatus", mock.Anything, mock.Anything).Return(errorModel.NotFoundError{})
+				mockK8sCache.On("ListReleaseConfigsByDependency", mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			releaseTaskArgsStr: "{\"ReleaseRequest\":{}}",
 			err: nil,
@@ -74,7 +83,7 @@ func TestHelm_createReleaseTask(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.createReleaseTask(test.releaseTaskArgsStr)
+		err := mockReleaseManager.createReleaseTask(context.Background(), test.releaseTaskArgsStr)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -1,19 +1,21 @@
 package helm
 
 import (
+	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
-	"strings"
 	"time"
 	errorModel "WarpCloud/walm/pkg/models/error"
-	"WarpCloud/walm/pkg/release"
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	releaseModel "WarpCloud/walm/pkg/models/release"
 )
 
-func (helm *Helm) DeleteReleaseWithRetry(namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
+func (helm *Helm) DeleteReleaseWithRetry(ctx context.Context, namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
 	retryTimes := 5
 	for {
-		err := helm.DeleteRelease(namespace, releaseName, deletePvcs, async, timeoutSec)
+		err := helm.DeleteRelease(ctx, namespace, releaseName, deletePvcs, async, timeoutSec)
 		if err != nil {
-			if strings.Contains(err.Error(), release.WaitReleaseTaskMsgPrefix) && retryTimes > 0 {
+			if errorModel.IsConflictError(err) && retryTimes > 0 {
 				logrus.Warnf("retry to delete release %s/%s after 2 second", namespace, releaseName)
 				retryTimes --
 				time.Sleep(time.Second * 2)
@@ -24,7 +26,7 @@ func (helm *Helm) DeleteReleaseWithRetry(namespace, releaseName string, deletePv
 	}
 }
 
-func (helm *Helm) DeleteRelease(namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
+func (helm *Helm) DeleteRelease(ctx context.Context, namespace, releaseName string, deletePvcs bool, async bool, timeoutSec int64) error {
 	if timeoutSec == 0 {
 		timeoutSec = defaultTimeoutSec
 	}
@@ -45,7 +47,7 @@ func (helm *Helm) DeleteRelease(namespace, releaseName string, deletePvcs bool,
 		DeletePvcs:  deletePvcs,
 	}
 
-	err = helm.sendReleaseTask(namespace, releaseName, deleteReleaseTaskName, releaseTaskArgs, oldReleaseTask, timeoutSec, async)
+	err = helm.sendReleaseTask(ctx, namespace, releaseName, deleteReleaseTaskName, releaseTaskArgs, oldReleaseTask, timeoutSec, async)
 	if err != nil {
 		logrus.Errorf("async=%t, failed to send %s of %s/%s: %s", async, deleteReleaseTaskName, namespace, releaseName, err.Error())
 		return err
@@ -54,7 +56,43 @@ func (helm *Helm) DeleteRelease(namespace, releaseName string, deletePvcs bool,
 	return nil
 }
 
-func (helm *Helm) doDeleteRelease(namespace, releaseName string, deletePvcs bool) error {
+// DryRunDeleteRelease previews the live resources deleting the release would remove,
+// without mutating anything. The release and its pvc candidates are resolved the same
+// way the real delete resolves them, so the preview can not drift from reality.
+func (helm *Helm) DryRunDeleteRelease(namespace, releaseName string, deletePvcs bool) (*releaseModel.ReleaseDeletePreview, error) {
+	releaseCache, err := helm.releaseCache.GetReleaseCache(namespace, releaseName)
+	if err != nil {
+		if !errorModel.IsNotFoundError(err) {
+			logrus.Errorf("failed to get release cache %s : %s", releaseName, err.Error())
+		}
+		return nil, err
+	}
+	releaseInfo, err := helm.buildReleaseInfoV2(releaseCache)
+	if err != nil {
+		logrus.Errorf("failed to build release info : %s", err.Error())
+		return nil, err
+	}
+
+	preview := &releaseModel.ReleaseDeletePreview{
+		Name:      releaseName,
+		Namespace: namespace,
+		Resources: releaseInfo.Status,
+		Pvcs:      []*k8sModel.PersistentVolumeClaim{},
+	}
+	if deletePvcs {
+		for _, statefulSet := range releaseInfo.Status.StatefulSets {
+			pvcs, err := helm.k8sCache.ListPersistentVolumeClaims(statefulSet.Namespace, statefulSet.Selector)
+			if err != nil {
+				logrus.Errorf("failed to list pvcs related to stateful set %s/%s : %s", statefulSet.Namespace, statefulSet.Name, err.Error())
+				return nil, err
+			}
+			preview.Pvcs = append(preview.Pvcs, pvcs...)
+		}
+	}
+	return preview, nil
+}
+
+func (helm *Helm) doDeleteRelease(ctx context.Context, namespace, releaseName string, deletePvcs bool) error {
 	releaseCache, err := helm.releaseCache.GetReleaseCache(namespace, releaseName)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
@@ -70,7 +108,22 @@ func (helm *Helm) doDeleteRelease(namespace, releaseName string, deletePvcs bool
 		return err
 	}
 
-	err = helm.helm.DeleteRelease(namespace, releaseName)
+	// an explicit deletePvcs=true from the caller overrides the policy,
+	// otherwise the policy declared on the release decides what happens
+	// to the pvcs
+	deleteAfterDays := int64(0)
+	if !deletePvcs {
+		policyDelete, policyDays, err := releaseModel.ParsePvcPolicy(releaseInfo.PvcPolicy)
+		if err != nil {
+			logrus.Warnf("failed to parse pvc policy of release %s/%s : %s", namespace, releaseName, err.Error())
+		} else {
+			deletePvcs = policyDelete
+			deleteAfterDays = policyDays
+		}
+	}
+
+	// the release may live in a registered cluster, delete it where it was installed
+	err = helm.helm.DeleteClusterRelease(ctx, releaseInfo.Cluster, namespace, releaseName)
 	if err != nil {
 		logrus.Errorf("failed to delete release %s/%s from helm : %s", namespace, releaseName, err.Error())
 		return err
@@ -88,8 +141,15 @@ func (helm *Helm) doDeleteRelease(namespace, releaseName string, deletePvcs bool
 			logrus.Errorf("failed to delete stateful set pvcs : %s", err.Error())
 			return err
 		}
+	} else if deleteAfterDays > 0 {
+		err = helm.schedulePvcsDeletion(namespace, releaseName, releaseInfo, deleteAfterDays)
+		if err != nil {
+			logrus.Errorf("failed to schedule the deletion of the release pvcs : %s", err.Error())
+			return err
+		}
 	}
 
+	helm.recordReleaseEvent(namespace, releaseName, releaseModel.ReleaseEventDeleted, fmt.Sprintf("deleted release, deletePvcs=%t", deletePvcs))
 	logrus.Infof("succeed to delete release %s/%s", namespace, releaseName)
 	return nil
 }
@@ -1,8 +1,15 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"encoding/json"
+	"fmt"
+	"time"
+	"WarpCloud/walm/pkg/metrics"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	"WarpCloud/walm/pkg/models/release"
 )
 
 const (
@@ -19,19 +26,45 @@ func (helm *Helm)registerDeleteReleaseTask() error{
 	return helm.task.RegisterTask(deleteReleaseTaskName, helm.deleteReleaseTask)
 }
 
-func (helm *Helm)deleteReleaseTask(releaseTaskArgsStr string) error {
+func (helm *Helm)deleteReleaseTask(ctx context.Context, releaseTaskArgsStr string) error {
 	releaseTaskArgs := &DeleteReleaseTaskArgs{}
 	err := json.Unmarshal([]byte(releaseTaskArgsStr), releaseTaskArgs)
 	if err != nil {
 		logrus.Errorf("%s args is not valid : %s", deleteReleaseTaskName, err.Error())
 		return err
 	}
-	err = helm.doDeleteRelease(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName, releaseTaskArgs.DeletePvcs)
+	releaseSlot, err := helm.acquireNamespaceReleaseTaskSlot(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName)
+	if err != nil {
+		logrus.Errorf("failed to acquire a concurrency slot for release %s/%s : %s",
+			releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName, err.Error())
+		return err
+	}
+	defer releaseSlot()
+	taskUuid := helm.latestTaskUuid(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName)
+	helm.k8sOperator.RecordReleaseConfigEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName,
+		"Normal", "DeleteStarted", fmt.Sprintf("started delete task %s", taskUuid))
+
+	startTime := time.Now()
+	err = helm.doDeleteRelease(ctx, releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName, releaseTaskArgs.DeletePvcs)
+	metrics.ObserveReleaseOperation("delete", time.Since(startTime), err)
+	helm.writeAuditRecord("deleteRelease", releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName,
+		map[string]interface{}{"deletePvcs": releaseTaskArgs.DeletePvcs}, err)
 	if err != nil {
 		logrus.Errorf("failed to delete release %s/%s: %s", releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName, err.Error())
+		helm.recordReleaseEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName, release.ReleaseEventDeleteFailed, err.Error())
+		helm.k8sOperator.RecordReleaseConfigEvent(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName,
+			"Warning", release.ReleaseEventDeleteFailed, fmt.Sprintf("delete task %s failed : %s", taskUuid, err.Error()))
 		return err
 	}
 
+	helm.publishLifecycleEvent(&eventModel.LifecycleEvent{
+		Type:      release.ReleaseEventDeleted,
+		Namespace: releaseTaskArgs.Namespace,
+		Name:      releaseTaskArgs.ReleaseName,
+		TaskUuid:  taskUuid,
+		Succeeded: true,
+	})
+
 	err = helm.releaseCache.DeleteReleaseTask(releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName)
 	if err != nil {
 		logrus.Warnf("failed to delete release task %s/%s : %s", releaseTaskArgs.Namespace, releaseTaskArgs.ReleaseName, err.Error())
@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"errors"
 	"github.com/stretchr/testify/assert"
@@ -54,7 +55,10 @@ func TestHelm_deleteReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sOperator.On("RecordReleaseConfigEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockReleaseCache.On("AddReleaseEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			releaseTaskArgsStr: "{}",
 			err: errors.New("failed"),
@@ -62,6 +66,8 @@ func TestHelm_deleteReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sOperator.On("RecordReleaseConfigEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
 				mockReleaseCache.On("DeleteReleaseTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -71,6 +77,8 @@ func TestHelm_deleteReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sOperator.On("RecordReleaseConfigEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
 				mockReleaseCache.On("DeleteReleaseTask", mock.Anything, mock.Anything).Return(errors.New(""))
 			},
@@ -81,7 +89,7 @@ func TestHelm_deleteReleaseTask(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.deleteReleaseTask(test.releaseTaskArgsStr)
+		err := mockReleaseManager.deleteReleaseTask(context.Background(), test.releaseTaskArgsStr)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
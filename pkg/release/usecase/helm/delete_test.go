@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"WarpCloud/walm/pkg/models/release"
 	"errors"
@@ -76,7 +77,7 @@ func TestHelm_DeleteReleaseWithRetry(t *testing.T) {
 				}).Twice()
 				mockTaskState.On("IsTimeout").Return(false)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(errors.New(""))
@@ -87,7 +88,7 @@ func TestHelm_DeleteReleaseWithRetry(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.DeleteReleaseWithRetry("test-ns", "test-name", false, false, 0)
+		err := mockReleaseManager.DeleteReleaseWithRetry(context.Background(), "test-ns", "test-name", false, false, 0)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -150,7 +151,7 @@ func TestHelm_DeleteRelease(t *testing.T) {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(&release.ReleaseTask{}, nil)
 				mockTask.On("GetTaskState", mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -161,7 +162,7 @@ func TestHelm_DeleteRelease(t *testing.T) {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(&release.ReleaseTask{}, nil)
 				mockTask.On("GetTaskState", mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			err: errors.New(""),
 		},
@@ -169,7 +170,7 @@ func TestHelm_DeleteRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.DeleteRelease("test-ns", "test-name", false, false, 0)
+		err := mockReleaseManager.DeleteRelease(context.Background(), "test-ns", "test-name", false, false, 0)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -249,7 +250,7 @@ func TestHelm_doDeleteRelease(t *testing.T) {
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockK8sCache.On("GetResourceSet", mock.Anything).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", mock.Anything, mock.Anything, mock.Anything).Return(&k8s.ReleaseConfig{}, nil)
-				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything).Return(errors.New(""))
+				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
 			},
 			err: errors.New(""),
 		},
@@ -259,7 +260,7 @@ func TestHelm_doDeleteRelease(t *testing.T) {
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockK8sCache.On("GetResourceSet", mock.Anything).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", mock.Anything, mock.Anything, mock.Anything).Return(&k8s.ReleaseConfig{}, nil)
-				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything).Return(nil)
+				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 				mockReleaseCache.On("DeleteReleaseCache", mock.Anything, mock.Anything).Return(errors.New(""))
 			},
 			err: errors.New(""),
@@ -270,7 +271,7 @@ func TestHelm_doDeleteRelease(t *testing.T) {
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockK8sCache.On("GetResourceSet", mock.Anything).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", mock.Anything, mock.Anything, mock.Anything).Return(&k8s.ReleaseConfig{}, nil)
-				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything).Return(nil)
+				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 				mockReleaseCache.On("DeleteReleaseCache", mock.Anything, mock.Anything).Return(nil)
 				mockK8sOperator.On("DeleteStatefulSetPvcs", mock.Anything).Return(errors.New(""))
 			},
@@ -283,7 +284,7 @@ func TestHelm_doDeleteRelease(t *testing.T) {
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockK8sCache.On("GetResourceSet", mock.Anything).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", mock.Anything, mock.Anything, mock.Anything).Return(&k8s.ReleaseConfig{}, nil)
-				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything).Return(nil)
+				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 				mockReleaseCache.On("DeleteReleaseCache", mock.Anything, mock.Anything).Return(nil)
 				mockK8sOperator.On("DeleteStatefulSetPvcs", mock.Anything).Return(nil)
 			},
@@ -296,7 +297,7 @@ func TestHelm_doDeleteRelease(t *testing.T) {
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockK8sCache.On("GetResourceSet", mock.Anything).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", mock.Anything, mock.Anything, mock.Anything).Return(&k8s.ReleaseConfig{}, nil)
-				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything).Return(nil)
+				mockHelm.On("DeleteRelease", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 				mockReleaseCache.On("DeleteReleaseCache", mock.Anything, mock.Anything).Return(nil)
 			},
 			deletePvcs: false,
@@ -306,7 +307,7 @@ func TestHelm_doDeleteRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.doDeleteRelease("test-ns", "test-name", test.deletePvcs)
+		err := mockReleaseManager.doDeleteRelease(context.Background(), "test-ns", "test-name", test.deletePvcs)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -0,0 +1,62 @@
+package helm
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	"WarpCloud/walm/pkg/models/release"
+)
+
+// GetReleaseDependents returns the releases whose Dependencies point at the given release,
+// so users can learn what would break before deleting or recreating it.
+func (helm *Helm) GetReleaseDependents(namespace, releaseName string) ([]*release.ReleaseInfoV2, error) {
+	dependentReleaseConfigs, err := helm.getDependentReleaseConfigs(namespace, releaseName)
+	if err != nil {
+		logrus.Errorf("failed to get dependent release configs of %s/%s : %s", namespace, releaseName, err.Error())
+		return nil, err
+	}
+	return helm.listReleasesByReleaseConfigs(dependentReleaseConfigs)
+}
+
+// getDependentReleaseConfigs returns the release configs whose Dependencies point at the
+// given release, served by the k8s cache's dependency index.
+func (helm *Helm) getDependentReleaseConfigs(namespace, releaseName string) ([]*k8sModel.ReleaseConfig, error) {
+	dependentReleaseConfigs, err := helm.k8sCache.ListReleaseConfigsByDependency(namespace, releaseName)
+	if err != nil {
+		logrus.Errorf("failed to list release configs depending on %s/%s : %s", namespace, releaseName, err.Error())
+		return nil, err
+	}
+	return dependentReleaseConfigs, nil
+}
+
+// refreshDependentReleases enqueues async refresh upgrades of the releases depending on the
+// given release, so their dependency config values are re-resolved. It is called when a
+// release is created with a name existing dependency links still point at, as the dependents
+// may hold output config values of the previous incarnation. Best effort, failures are logged.
+func (helm *Helm) refreshDependentReleases(ctx context.Context, namespace, releaseName string) {
+	dependentReleaseConfigs, err := helm.getDependentReleaseConfigs(namespace, releaseName)
+	if err != nil {
+		logrus.Warnf("failed to get dependent releases of %s/%s : %s", namespace, releaseName, err.Error())
+		return
+	}
+
+	for _, releaseConfig := range dependentReleaseConfigs {
+		releaseRequest := &release.ReleaseRequestV2{
+			ReleaseRequest: release.ReleaseRequest{
+				Name:         releaseConfig.Name,
+				RepoName:     releaseConfig.Repo,
+				ChartName:    releaseConfig.ChartName,
+				ChartVersion: releaseConfig.ChartVersion,
+			},
+			ChartImage: releaseConfig.ChartImage,
+		}
+		err = helm.InstallUpgradeRelease(ctx, releaseConfig.Namespace, releaseRequest, nil, true, 0, nil)
+		if err != nil {
+			logrus.Warnf("failed to enqueue refresh upgrade of dependent release %s/%s : %s", releaseConfig.Namespace, releaseConfig.Name, err.Error())
+			continue
+		}
+		logrus.Infof("succeed to enqueue refresh upgrade of dependent release %s/%s", releaseConfig.Namespace, releaseConfig.Name)
+	}
+}
@@ -0,0 +1,103 @@
+package helm
+
+import (
+	helmMocks "WarpCloud/walm/pkg/helm/mocks"
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
+	"WarpCloud/walm/pkg/models/k8s"
+	"WarpCloud/walm/pkg/release/mocks"
+	taskMocks "WarpCloud/walm/pkg/task/mocks"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestHelm_GetDependentReleaseConfigs(t *testing.T) {
+	var mockReleaseCache *mocks.Cache
+	var mockHelm *helmMocks.Helm
+	var mockK8sOperator *k8sMocks.Operator
+	var mockK8sCache *k8sMocks.Cache
+	var mockTask *taskMocks.Task
+	var mockReleaseManager *Helm
+
+	refreshMocks := func() {
+		mockReleaseCache = &mocks.Cache{}
+		mockHelm = &helmMocks.Helm{}
+		mockK8sOperator = &k8sMocks.Operator{}
+		mockK8sCache = &k8sMocks.Cache{}
+		mockTask = &taskMocks.Task{}
+
+		mockTask.On("RegisterTask", mock.Anything, mock.Anything).Return(nil)
+
+		var err error
+		mockReleaseManager, err = NewHelm(mockReleaseCache, mockHelm, mockK8sCache, mockK8sOperator, mockTask)
+		assert.IsType(t, err, nil)
+	}
+
+	tests := []struct {
+		initMock                        func()
+		err                             error
+		expectedDependentReleaseConfigs []*k8s.ReleaseConfig
+	}{
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("ListReleaseConfigsByDependency", "test-ns", "test-name").Return(nil, errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("ListReleaseConfigsByDependency", "test-ns", "test-name").Return([]*k8s.ReleaseConfig{
+					{
+						Meta: k8s.Meta{
+							Namespace: "test-ns",
+							Name:      "dependent-name",
+						},
+						Dependencies: map[string]string{"zookeeper": "test-name"},
+					},
+					{
+						Meta: k8s.Meta{
+							Namespace: "test-ns2",
+							Name:      "dependent-name2",
+						},
+						Dependencies: map[string]string{"zookeeper": "test-ns/test-name"},
+					},
+				}, nil)
+			},
+			err: nil,
+			expectedDependentReleaseConfigs: []*k8s.ReleaseConfig{
+				{
+					Meta: k8s.Meta{
+						Namespace: "test-ns",
+						Name:      "dependent-name",
+					},
+					Dependencies: map[string]string{"zookeeper": "test-name"},
+				},
+				{
+					Meta: k8s.Meta{
+						Namespace: "test-ns2",
+						Name:      "dependent-name2",
+					},
+					Dependencies: map[string]string{"zookeeper": "test-ns/test-name"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test.initMock()
+		dependentReleaseConfigs, err := mockReleaseManager.getDependentReleaseConfigs("test-ns", "test-name")
+		assert.IsType(t, test.err, err)
+		if test.err == nil {
+			assert.Equal(t, test.expectedDependentReleaseConfigs, dependentReleaseConfigs)
+		}
+
+		mockReleaseCache.AssertExpectations(t)
+		mockHelm.AssertExpectations(t)
+		mockK8sOperator.AssertExpectations(t)
+		mockK8sCache.AssertExpectations(t)
+		mockTask.AssertExpectations(t)
+	}
+}
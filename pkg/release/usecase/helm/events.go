@@ -0,0 +1,51 @@
+package helm
+
+import (
+	errorModel "WarpCloud/walm/pkg/models/error"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// GetReleaseEvents returns the latest lifecycle events of a release, latest first.
+func (helm *Helm) GetReleaseEvents(namespace, releaseName string, limit int) ([]*releaseModel.ReleaseEvent, error) {
+	releaseEvents, err := helm.releaseCache.GetReleaseEvents(namespace, releaseName, limit)
+	if err != nil {
+		logrus.Errorf("failed to get release events of %s/%s : %s", namespace, releaseName, err.Error())
+		return nil, err
+	}
+	return releaseEvents, nil
+}
+
+// latestTaskUuid returns the uuid of the release's latest task, best effort :
+// an empty string is returned when the release has no task.
+func (helm *Helm) latestTaskUuid(namespace, releaseName string) string {
+	releaseTask, err := helm.releaseCache.GetReleaseTask(namespace, releaseName)
+	if err != nil {
+		if !errorModel.IsNotFoundError(err) {
+			logrus.Warnf("failed to get release task of %s/%s : %s", namespace, releaseName, err.Error())
+		}
+		return ""
+	}
+	if releaseTask.LatestReleaseTaskSig == nil {
+		return ""
+	}
+	return releaseTask.LatestReleaseTaskSig.UUID
+}
+
+// recordReleaseEvent appends a lifecycle event to the release's event log.
+// Recording is best effort : failures are logged but never fail the operation
+// the event describes.
+func (helm *Helm) recordReleaseEvent(namespace, releaseName, eventType, message string) {
+	taskUuid := helm.latestTaskUuid(namespace, releaseName)
+
+	err := helm.releaseCache.AddReleaseEvent(namespace, releaseName, &releaseModel.ReleaseEvent{
+		Type:      eventType,
+		Message:   message,
+		TaskUuid:  taskUuid,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("failed to record release event %s of %s/%s : %s", eventType, namespace, releaseName, err.Error())
+	}
+}
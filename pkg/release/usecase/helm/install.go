@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/release"
 	"github.com/sirupsen/logrus"
 	"fmt"
@@ -8,20 +10,22 @@ import (
 	"strings"
 	"WarpCloud/walm/pkg/models/common"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	"WarpCloud/walm/pkg/models/project"
 
-	releasei "WarpCloud/walm/pkg/release"
+	"WarpCloud/walm/pkg/release/hooks"
 )
 
 const (
 	defaultTimeoutSec int64 = 60 * 5
 )
 
-func (helm *Helm) InstallUpgradeReleaseWithRetry(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
+func (helm *Helm) InstallUpgradeReleaseWithRetry(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
 	retryTimes := 5
 	for {
-		err := helm.InstallUpgradeRelease(namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
+		err := helm.InstallUpgradeRelease(ctx, namespace, releaseRequest, chartFiles, async, timeoutSec, paused)
 		if err != nil {
-			if strings.Contains(err.Error(), releasei.WaitReleaseTaskMsgPrefix) && retryTimes > 0 {
+			if errorModel.IsConflictError(err) && retryTimes > 0 {
 				logrus.Warnf("retry to install or upgrade release %s/%s after 2 second", namespace, releaseRequest.Name)
 				retryTimes --
 				time.Sleep(time.Second * 2)
@@ -32,7 +36,7 @@ func (helm *Helm) InstallUpgradeReleaseWithRetry(namespace string, releaseReques
 	}
 }
 
-func (helm *Helm) InstallUpgradeRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
+func (helm *Helm) InstallUpgradeRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, async bool, timeoutSec int64, paused *bool) error {
 	err := validateParams(releaseRequest, chartFiles)
 	if err != nil {
 		logrus.Errorf("failed to validate params : %s", err.Error())
@@ -48,14 +52,21 @@ func (helm *Helm) InstallUpgradeRelease(namespace string, releaseRequest *releas
 		return err
 	}
 
+	err = helm.validateTenantStorageClasses(ctx, namespace, releaseRequest, chartFiles, paused)
+	if err != nil {
+		logrus.Errorf("failed to validate storage classes of release %s/%s : %s", namespace, releaseRequest.Name, err.Error())
+		return err
+	}
+
 	releaseTaskArgs := &CreateReleaseTaskArgs{
 		Namespace:      namespace,
 		ReleaseRequest: releaseRequest,
 		ChartFiles:     chartFiles,
 		Paused:         paused,
+		TimeoutSec:     timeoutSec,
 	}
 
-	err = helm.sendReleaseTask(namespace, releaseRequest.Name, createReleaseTaskName, releaseTaskArgs, oldReleaseTask, timeoutSec, async)
+	err = helm.sendReleaseTask(ctx, namespace, releaseRequest.Name, createReleaseTaskName, releaseTaskArgs, oldReleaseTask, timeoutSec, async)
 	if err != nil {
 		logrus.Errorf("async=%t, failed to send %s of %s/%s: %s", async, createReleaseTaskName, namespace, releaseRequest.Name, err.Error())
 		return err
@@ -73,10 +84,81 @@ func validateParams(releaseRequest *release.ReleaseRequestV2, chartFiles []*comm
 		return fmt.Errorf("at lease one of chart name or chart image or chart files should be supported")
 	}
 
+	_, _, err := release.ParsePvcPolicy(releaseRequest.PvcPolicy)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTenantStorageClasses dry runs the release and rejects rendered pvcs
+// referencing storage classes outside the tenant whitelist, so the request
+// fails before the release task is enqueued. An empty whitelist allows all.
+func (helm *Helm) validateTenantStorageClasses(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, paused *bool) error {
+	tenantInfo, err := helm.k8sCache.GetTenant(namespace)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			return nil
+		}
+		logrus.Errorf("failed to get tenant %s : %s", namespace, err.Error())
+		return err
+	}
+	if len(tenantInfo.StorageClassWhitelist) == 0 {
+		return nil
+	}
+
+	releaseCache, err := helm.doInstallUpgradeRelease(ctx, namespace, releaseRequest, chartFiles, true, paused)
+	if err != nil {
+		logrus.Errorf("failed to dry run release %s/%s : %s", namespace, releaseRequest.Name, err.Error())
+		return err
+	}
+	releaseResources, err := helm.k8sOperator.ComputeReleaseResourcesByManifest(namespace, releaseCache.Manifest)
+	if err != nil {
+		logrus.Errorf("failed to compute release resources of %s/%s : %s", namespace, releaseRequest.Name, err.Error())
+		return err
+	}
+
+	invalidStorageClasses := getNotWhitelistedStorageClasses(releaseResources, tenantInfo.StorageClassWhitelist)
+	if len(invalidStorageClasses) > 0 {
+		return fmt.Errorf("release %s uses storage classes [%s] not allowed for tenant %s, allowed storage classes : [%s]",
+			releaseRequest.Name, strings.Join(invalidStorageClasses, ", "), namespace, strings.Join(tenantInfo.StorageClassWhitelist, ", "))
+	}
 	return nil
 }
 
-func (helm *Helm) doInstallUpgradeRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, dryRun bool, paused *bool) (*release.ReleaseCache, error) {
+func getNotWhitelistedStorageClasses(releaseResources *release.ReleaseResources, storageClassWhitelist []string) []string {
+	whitelisted := map[string]bool{}
+	for _, storageClass := range storageClassWhitelist {
+		whitelisted[storageClass] = true
+	}
+
+	pvcStorages := append([]*release.ReleaseResourceStorage{}, releaseResources.Pvcs...)
+	for _, statefulSet := range releaseResources.StatefulSets {
+		// volume claim templates end up as pvc storage of the pod requests
+		pvcStorages = append(pvcStorages, statefulSet.PodRequests.Storage...)
+	}
+
+	invalidStorageClasses := []string{}
+	invalidSet := map[string]bool{}
+	for _, pvcStorage := range pvcStorages {
+		if pvcStorage.Type != release.PvcPodStorageType {
+			continue
+		}
+		// an empty storage class falls back to the cluster default,
+		// enforcing that one is left to the admission webhook
+		if pvcStorage.StorageClass == "" {
+			continue
+		}
+		if !whitelisted[pvcStorage.StorageClass] && !invalidSet[pvcStorage.StorageClass] {
+			invalidSet[pvcStorage.StorageClass] = true
+			invalidStorageClasses = append(invalidStorageClasses, pvcStorage.StorageClass)
+		}
+	}
+	return invalidStorageClasses
+}
+
+func (helm *Helm) doInstallUpgradeRelease(ctx context.Context, namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile, dryRun bool, paused *bool) (*release.ReleaseCache, error) {
 	update := true
 	oldReleaseCache, err := helm.releaseCache.GetReleaseCache(namespace, releaseRequest.Name)
 	if err != nil {
@@ -99,18 +181,104 @@ func (helm *Helm) doInstallUpgradeRelease(namespace string, releaseRequest *rele
 
 	preProcessRequest(releaseRequest)
 
-	releaseCache, err := helm.helm.InstallOrCreateRelease(namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, paused)
+	var pluginResults []*release.ReleasePluginResult
+	hookContext := &hooks.Context{
+		Namespace:      namespace,
+		ReleaseRequest: releaseRequest,
+		K8sOperator:    helm.k8sOperator,
+	}
+	if !dryRun {
+		preHookType := hooks.PreInstallHookType
+		if update {
+			preHookType = hooks.PreUpgradeHookType
+		}
+		results, err := hooks.ExecuteHooks(preHookType, hookContext)
+		pluginResults = append(pluginResults, results...)
+		if err != nil {
+			helm.recordPluginResults(namespace, releaseRequest.Name, pluginResults)
+			logrus.Errorf("failed to execute %s hooks of release %s/%s : %s", preHookType, namespace, releaseRequest.Name, err.Error())
+			return nil, err
+		}
+	}
+
+	releaseCache, err := helm.helm.InstallOrCreateRelease(ctx, namespace, releaseRequest, chartFiles, dryRun, update, oldReleaseInfo, paused)
 	if err != nil {
 		logrus.Errorf("failed to install or create release %s/%s : %s", namespace, releaseRequest.Name, err.Error())
+		helm.recordPluginResults(namespace, releaseRequest.Name, pluginResults)
 		return nil, err
 	}
+	// the pvc policy is declared on the request but consulted at delete time,
+	// persist it on the release cache
+	releaseCache.PvcPolicy = releaseRequest.PvcPolicy
 	if !dryRun {
 		err = helm.releaseCache.CreateOrUpdateReleaseCache(releaseCache)
 		if err != nil {
 			logrus.Errorf("failed to create of update release cache of %s/%s : %s", namespace, releaseRequest.Name, err.Error())
+			helm.recordPluginResults(namespace, releaseRequest.Name, pluginResults)
 			return nil, err
 		}
 		logrus.Infof("succeed to create or update release %s/%s", namespace, releaseRequest.Name)
+
+		if !update {
+			results, err := hooks.ExecuteHooks(hooks.PostInstallHookType, hookContext)
+			pluginResults = append(pluginResults, results...)
+			if err != nil {
+				helm.recordPluginResults(namespace, releaseRequest.Name, pluginResults)
+				logrus.Errorf("failed to execute %s hooks of release %s/%s : %s", hooks.PostInstallHookType, namespace, releaseRequest.Name, err.Error())
+				return nil, err
+			}
+		}
+		helm.recordPluginResults(namespace, releaseRequest.Name, pluginResults)
+
+		eventType := release.ReleaseEventInstalled
+		message := fmt.Sprintf("installed chart %s-%s", releaseCache.ChartName, releaseCache.ChartVersion)
+		if update {
+			eventType = release.ReleaseEventUpgraded
+			message = fmt.Sprintf("upgraded chart %s from version %s to %s", releaseCache.ChartName, oldReleaseCache.ChartVersion, releaseCache.ChartVersion)
+		}
+		if paused != nil {
+			if *paused {
+				eventType = release.ReleaseEventPaused
+				message = "release paused"
+			} else {
+				eventType = release.ReleaseEventRecovered
+				message = "release recovered"
+			}
+		}
+		helm.recordReleaseEvent(namespace, releaseRequest.Name, eventType, message)
+		helm.k8sOperator.RecordReleaseConfigEvent(namespace, releaseRequest.Name, "Normal", eventType, message)
+		helm.publishLifecycleEvent(&eventModel.LifecycleEvent{
+			Type:         eventType,
+			Namespace:    namespace,
+			Name:         releaseRequest.Name,
+			Project:      releaseRequest.ReleaseLabels[project.ProjectNameLabelKey],
+			ChartName:    releaseCache.ChartName,
+			ChartVersion: releaseCache.ChartVersion,
+			TaskUuid:     helm.latestTaskUuid(namespace, releaseRequest.Name),
+			Succeeded:    true,
+		})
+
+		// in warn mode an unverified chart still gets installed, leave a trace in the release events
+		if releaseCache.ChartProvenance != nil && !releaseCache.ChartProvenance.Verified {
+			provenanceMessage := fmt.Sprintf("chart %s-%s provenance is not verified : %s",
+				releaseCache.ChartName, releaseCache.ChartVersion, releaseCache.ChartProvenance.Message)
+			helm.recordReleaseEvent(namespace, releaseRequest.Name, release.ReleaseEventChartNotVerified, provenanceMessage)
+			helm.k8sOperator.RecordReleaseConfigEvent(namespace, releaseRequest.Name, "Warning", release.ReleaseEventChartNotVerified, provenanceMessage)
+		}
+
+		// mark on the release config status that the injected dependency values
+		// have been applied, best effort, the release itself is already installed.
+		// charts without a release config simply have nothing to mark.
+		err = helm.k8sOperator.UpdateReleaseConfigStatus(namespace, releaseRequest.Name)
+		if err != nil && !errorModel.IsNotFoundError(err) {
+			logrus.Warnf("failed to update release config status of %s/%s : %s", namespace, releaseRequest.Name, err.Error())
+		}
+
+		if !update {
+			// the release may be a recreation of a dependency other releases still point at,
+			// refresh them so they pick up the new output config values
+			helm.refreshDependentReleases(ctx, namespace, releaseRequest.Name)
+		}
 	} else {
 		logrus.Infof("succeed to dry run create or update release %s/%s", namespace, releaseRequest.Name)
 	}
@@ -118,6 +286,24 @@ func (helm *Helm) doInstallUpgradeRelease(namespace string, releaseRequest *rele
 	return releaseCache, nil
 }
 
+// recordPluginResults saves the plugin hook results on the release task, best effort,
+// so users can inspect them via the task state.
+func (helm *Helm) recordPluginResults(namespace, releaseName string, pluginResults []*release.ReleasePluginResult) {
+	if len(pluginResults) == 0 {
+		return
+	}
+	releaseTask, err := helm.releaseCache.GetReleaseTask(namespace, releaseName)
+	if err != nil {
+		logrus.Warnf("failed to get release task of %s/%s to record plugin results : %s", namespace, releaseName, err.Error())
+		return
+	}
+	releaseTask.PluginResults = pluginResults
+	err = helm.releaseCache.CreateOrUpdateReleaseTask(releaseTask)
+	if err != nil {
+		logrus.Warnf("failed to record plugin results of %s/%s : %s", namespace, releaseName, err.Error())
+	}
+}
+
 func preProcessRequest(releaseRequest *release.ReleaseRequestV2) {
 	if releaseRequest.ConfigValues == nil {
 		releaseRequest.ConfigValues = map[string]interface{}{}
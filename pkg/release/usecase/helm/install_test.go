@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"WarpCloud/walm/pkg/models/release"
 	"WarpCloud/walm/pkg/models/common"
@@ -13,6 +14,7 @@ import (
 	"WarpCloud/walm/pkg/release/mocks"
 	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/models/task"
+	"WarpCloud/walm/pkg/models/tenant"
 )
 
 func TestHelm_InstallUpgradeReleaseWithRetry(t *testing.T) {
@@ -52,7 +54,8 @@ func TestHelm_InstallUpgradeReleaseWithRetry(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -86,7 +89,8 @@ func TestHelm_InstallUpgradeReleaseWithRetry(t *testing.T) {
 				}).Twice()
 				mockTaskState.On("IsTimeout").Return(false)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(errors.New(""))
@@ -103,7 +107,7 @@ func TestHelm_InstallUpgradeReleaseWithRetry(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.InstallUpgradeReleaseWithRetry("test-ns", test.releaseRequest, nil, false, 0, nil)
+		err := mockReleaseManager.InstallUpgradeReleaseWithRetry(context.Background(), "test-ns", test.releaseRequest, nil, false, 0, nil)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -172,7 +176,8 @@ func TestHelm_InstallUpgradeRelease(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			releaseRequest: &release.ReleaseRequestV2{
 				ReleaseRequest: release.ReleaseRequest{
@@ -186,7 +191,57 @@ func TestHelm_InstallUpgradeRelease(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
+				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
+			},
+			releaseRequest: &release.ReleaseRequestV2{
+				ReleaseRequest: release.ReleaseRequest{
+					Name:      "test",
+					ChartName: "test",
+				},
+			},
+			err: nil,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sCache.On("GetTenant", mock.Anything).Return(&tenant.TenantInfo{
+					StorageClassWhitelist: []string{"local"},
+				}, nil)
+				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, true, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
+				mockK8sOperator.On("ComputeReleaseResourcesByManifest", mock.Anything, mock.Anything).Return(&release.ReleaseResources{
+					Pvcs: []*release.ReleaseResourceStorage{
+						{Name: "test-pvc", Type: release.PvcPodStorageType, StorageClass: "ssd"},
+					},
+				}, nil)
+			},
+			releaseRequest: &release.ReleaseRequestV2{
+				ReleaseRequest: release.ReleaseRequest{
+					Name:      "test",
+					ChartName: "test",
+				},
+			},
+			err: errors.New("failed"),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockReleaseCache.On("GetReleaseTask", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockK8sCache.On("GetTenant", mock.Anything).Return(&tenant.TenantInfo{
+					StorageClassWhitelist: []string{"local"},
+				}, nil)
+				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, true, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
+				mockK8sOperator.On("ComputeReleaseResourcesByManifest", mock.Anything, mock.Anything).Return(&release.ReleaseResources{
+					Pvcs: []*release.ReleaseResourceStorage{
+						{Name: "test-pvc", Type: release.PvcPodStorageType, StorageClass: "local"},
+					},
+				}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -202,7 +257,7 @@ func TestHelm_InstallUpgradeRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.InstallUpgradeRelease("test-ns", test.releaseRequest, nil, false, 0, nil)
+		err := mockReleaseManager.InstallUpgradeRelease(context.Background(), "test-ns", test.releaseRequest, nil, false, 0, nil)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -260,7 +315,7 @@ func TestHelm_doInstallUpgradeRelease(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			releaseRequest: &release.ReleaseRequestV2{},
 			err:            errors.New("failed"),
@@ -269,7 +324,7 @@ func TestHelm_doInstallUpgradeRelease(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 			},
 			dryRun: true,
 			releaseRequest: &release.ReleaseRequestV2{},
@@ -279,7 +334,7 @@ func TestHelm_doInstallUpgradeRelease(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseCache", mock.Anything).Return(errors.New(""))
 			},
 			dryRun: false,
@@ -290,7 +345,7 @@ func TestHelm_doInstallUpgradeRelease(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockReleaseCache.On("GetReleaseCache", mock.Anything, mock.Anything).Return(nil, errorModel.NotFoundError{})
-				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
+				mockHelm.On("InstallOrCreateRelease", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, false, mock.Anything, mock.Anything).Return(&release.ReleaseCache{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseCache", mock.Anything).Return(nil)
 			},
 			dryRun: false,
@@ -301,7 +356,7 @@ func TestHelm_doInstallUpgradeRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		_, err := mockReleaseManager.doInstallUpgradeRelease("test-ns", test.releaseRequest, nil, test.dryRun, nil)
+		_, err := mockReleaseManager.doInstallUpgradeRelease(context.Background(), "test-ns", test.releaseRequest, nil, test.dryRun, nil)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -371,3 +426,49 @@ func Test_validateParams(t *testing.T) {
 		assert.IsType(t, test.err, err)
 	}
 }
+
+func Test_getNotWhitelistedStorageClasses(t *testing.T) {
+	releaseResources := &release.ReleaseResources{
+		Pvcs: []*release.ReleaseResourceStorage{
+			{Name: "test-pvc1", Type: release.PvcPodStorageType, StorageClass: "local"},
+			{Name: "test-pvc2", Type: release.PvcPodStorageType, StorageClass: "ssd"},
+			{Name: "test-pvc3", Type: release.PvcPodStorageType, StorageClass: ""},
+		},
+		StatefulSets: []*release.ReleaseResourceStatefulSet{
+			{
+				ReleaseResourceBase: release.ReleaseResourceBase{
+					Name: "test-sts",
+					PodRequests: &release.ReleaseResourcePod{
+						Storage: []*release.ReleaseResourceStorage{
+							{Name: "data", Type: release.PvcPodStorageType, StorageClass: "ssd"},
+							{Name: "tos-data", Type: release.TosDiskPodStorageType, StorageClass: "silver"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		storageClassWhitelist []string
+		invalidStorageClasses []string
+	}{
+		{
+			storageClassWhitelist: []string{"local", "ssd"},
+			invalidStorageClasses: []string{},
+		},
+		{
+			storageClassWhitelist: []string{"local"},
+			invalidStorageClasses: []string{"ssd"},
+		},
+		{
+			storageClassWhitelist: []string{"silver"},
+			invalidStorageClasses: []string{"local", "ssd"},
+		},
+	}
+
+	for _, test := range tests {
+		invalidStorageClasses := getNotWhitelistedStorageClasses(releaseResources, test.storageClassWhitelist)
+		assert.Equal(t, test.invalidStorageClasses, invalidStorageClasses)
+	}
+}
@@ -6,21 +6,58 @@ import (
 	"fmt"
 	k8sModel "WarpCloud/walm/pkg/models/k8s"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
 	"sync"
 	"errors"
 	walmHelm "WarpCloud/walm/pkg/helm"
 )
 
 func (helm *Helm) GetRelease(namespace, name string) (releaseV2 *releaseModel.ReleaseInfoV2, err error) {
+	return helm.getRelease(namespace, name, false)
+}
+
+// GetReleaseWithLiveStatus is GetRelease with the workload statuses read from the
+// apiserver instead of the informer caches, for callers needing strict consistency.
+func (helm *Helm) GetReleaseWithLiveStatus(namespace, name string) (releaseV2 *releaseModel.ReleaseInfoV2, err error) {
+	return helm.getRelease(namespace, name, true)
+}
+
+func (helm *Helm) getRelease(namespace, name string, liveStatus bool) (releaseV2 *releaseModel.ReleaseInfoV2, err error) {
 	releaseTask, err := helm.releaseCache.GetReleaseTask(namespace, name)
 	if err != nil {
 		return nil, err
 	}
 
-	return helm.buildReleaseInfoV2ByReleaseTask(releaseTask, nil)
+	releaseV2, err = helm.buildReleaseInfoV2ByReleaseTask(releaseTask, nil, liveStatus)
+	if err != nil {
+		return nil, err
+	}
+	releaseV2.ScheduledOperations = helm.getScheduledOperations(namespace, name)
+	return releaseV2, nil
 }
 
-func (helm *Helm) buildReleaseInfoV2ByReleaseTask(releaseTask *releaseModel.ReleaseTask, releaseCache *releaseModel.ReleaseCache) (releaseV2 *releaseModel.ReleaseInfoV2, err error) {
+// getScheduledOperations lists the operations scheduled on the release, so
+// they show up in the release info as pending. Failing to list them does not
+// fail getting the release, it is only logged.
+func (helm *Helm) getScheduledOperations(namespace, name string) []*scheduleModel.ScheduledOperation {
+	if helm.scheduleStore == nil {
+		return nil
+	}
+	operationList, err := helm.scheduleStore.ListScheduledOperations(namespace)
+	if err != nil {
+		logrus.Warnf("failed to list scheduled operations of namespace %s : %s", namespace, err.Error())
+		return nil
+	}
+	scheduledOperations := []*scheduleModel.ScheduledOperation{}
+	for _, operation := range operationList.Items {
+		if operation.Kind == scheduleModel.ReleaseKind && operation.Name == name {
+			scheduledOperations = append(scheduledOperations, operation)
+		}
+	}
+	return scheduledOperations
+}
+
+func (helm *Helm) buildReleaseInfoV2ByReleaseTask(releaseTask *releaseModel.ReleaseTask, releaseCache *releaseModel.ReleaseCache, liveStatus bool) (releaseV2 *releaseModel.ReleaseInfoV2, err error) {
 	releaseV2 = &releaseModel.ReleaseInfoV2{
 		ReleaseInfo: releaseModel.ReleaseInfo{
 			ReleaseSpec: releaseModel.ReleaseSpec{
@@ -44,7 +81,7 @@ func (helm *Helm) buildReleaseInfoV2ByReleaseTask(releaseTask *releaseModel.Rele
 	}
 
 	if releaseCache != nil {
-		releaseV2, err = helm.buildReleaseInfoV2(releaseCache)
+		releaseV2, err = helm.doBuildReleaseInfoV2(releaseCache, liveStatus)
 		if err != nil {
 			logrus.Errorf("failed to build v2 release info : %s", err.Error())
 			return
@@ -74,7 +111,11 @@ func (helm *Helm) buildReleaseInfoV2ByReleaseTask(releaseTask *releaseModel.Rele
 }
 
 func (helm *Helm) buildReleaseInfoV2(releaseCache *releaseModel.ReleaseCache) (*releaseModel.ReleaseInfoV2, error) {
-	releaseV1, err := helm.buildReleaseInfo(releaseCache)
+	return helm.doBuildReleaseInfoV2(releaseCache, false)
+}
+
+func (helm *Helm) doBuildReleaseInfoV2(releaseCache *releaseModel.ReleaseCache, liveStatus bool) (*releaseModel.ReleaseInfoV2, error) {
+	releaseV1, err := helm.buildReleaseInfo(releaseCache, liveStatus)
 	if err != nil {
 		logrus.Errorf("failed to build release info: %s", err.Error())
 		return nil, err
@@ -100,10 +141,14 @@ func (helm *Helm) buildReleaseInfoV2(releaseCache *releaseModel.ReleaseCache) (*
 		releaseV2.ReleaseLabels = releaseConfig.Labels
 		releaseV2.RepoName = releaseConfig.Repo
 		releaseV2.ChartImage = releaseConfig.ChartImage
+		releaseV2.CreationTimestamp = releaseConfig.CreationTimestamp
 	}
 	releaseV2.ComputedValues = releaseCache.ComputedValues
 	releaseV2.MetaInfoValues = releaseCache.MetaInfoValues
+	releaseV2.ChartProvenance = releaseCache.ChartProvenance
+	releaseV2.PvcPolicy = releaseCache.PvcPolicy
 	releaseV2.Plugins, releaseV2.Paused, err = walmHelm.BuildReleasePluginsByConfigValues(releaseV2.ComputedValues)
+	releaseV2.ExternalDependencies = walmHelm.BuildReleaseExternalDependenciesByConfigValues(releaseV2.ComputedValues)
 
 	if releaseV2.Paused {
 		releaseV2.Ready = false
@@ -113,11 +158,15 @@ func (helm *Helm) buildReleaseInfoV2(releaseCache *releaseModel.ReleaseCache) (*
 	return releaseV2, nil
 }
 
-func (helm *Helm) buildReleaseInfo(releaseCache *releaseModel.ReleaseCache) (releaseInfo *releaseModel.ReleaseInfo, err error) {
+func (helm *Helm) buildReleaseInfo(releaseCache *releaseModel.ReleaseCache, liveStatus bool) (releaseInfo *releaseModel.ReleaseInfo, err error) {
 	releaseInfo = &releaseModel.ReleaseInfo{}
 	releaseInfo.ReleaseSpec = releaseCache.ReleaseSpec
 
-	releaseInfo.Status, err = helm.k8sCache.GetResourceSet(releaseCache.ReleaseResourceMetas)
+	if liveStatus {
+		releaseInfo.Status, err = helm.k8sCache.GetResourceSetLive(releaseCache.ReleaseResourceMetas)
+	} else {
+		releaseInfo.Status, err = helm.k8sCache.GetResourceSet(releaseCache.ReleaseResourceMetas)
+	}
 	if err != nil {
 		logrus.Errorf(fmt.Sprintf("Failed to build the status of releaseInfo: %s", releaseInfo.Name))
 		return
@@ -127,6 +176,7 @@ func (helm *Helm) buildReleaseInfo(releaseCache *releaseModel.ReleaseCache) (rel
 		releaseInfo.Ready = true
 	} else {
 		releaseInfo.Message = fmt.Sprintf("%s %s/%s is in state %s", notReadyResource.GetKind(), notReadyResource.GetNamespace(), notReadyResource.GetName(), notReadyResource.GetState().Status)
+		releaseInfo.ReadyReason = helm.buildNotReadyReason(notReadyResource)
 	}
 
 	return
@@ -191,7 +241,7 @@ func (helm *Helm) doListReleases(releaseTasks []*releaseModel.ReleaseTask, relea
 		wg.Add(1)
 		go func(releaseTask *releaseModel.ReleaseTask, releaseCache *releaseModel.ReleaseCache) {
 			defer wg.Done()
-			info, err1 := helm.buildReleaseInfoV2ByReleaseTask(releaseTask, releaseCache)
+			info, err1 := helm.buildReleaseInfoV2ByReleaseTask(releaseTask, releaseCache, false)
 			if err1 != nil {
 				err = errors.New(fmt.Sprintf("failed to build release info: %s", err1.Error()))
 				logrus.Error(err.Error())
@@ -355,7 +355,7 @@ func TestHelm_buildReleaseInfo(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		releaseInfo, err := mockReleaseManager.buildReleaseInfo(test.releaseCache)
+		releaseInfo, err := mockReleaseManager.buildReleaseInfo(test.releaseCache, false)
 		assert.IsType(t, test.err, err)
 		assert.Equal(t, test.releaseInfo, releaseInfo)
 
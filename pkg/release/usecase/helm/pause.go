@@ -1,11 +1,13 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 )
 
 
-func (helm *Helm) PauseRelease(namespace, releaseName string, async bool, timeoutSec int64) error {
+func (helm *Helm) PauseRelease(ctx context.Context, namespace, releaseName string, async bool, timeoutSec int64) error {
 	releaseInfo, err := helm.GetRelease(namespace, releaseName)
 	if err != nil {
 		logrus.Errorf("failed to get release %s/%s : %s", namespace, releaseName, err.Error())
@@ -19,7 +21,7 @@ func (helm *Helm) PauseRelease(namespace, releaseName string, async bool, timeou
 
 	releaseRequest := releaseInfo.BuildReleaseRequestV2()
 	paused := true
-	err = helm.InstallUpgradeRelease(namespace, releaseRequest,nil, async, timeoutSec, &paused)
+	err = helm.InstallUpgradeRelease(ctx, namespace, releaseRequest,nil, async, timeoutSec, &paused)
 	if err != nil {
 		logrus.Errorf("failed to upgrade release %s/%s : %s", namespace, releaseName, err.Error())
 		return err
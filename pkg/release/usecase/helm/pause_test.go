@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"errors"
 	"github.com/stretchr/testify/assert"
@@ -152,7 +153,7 @@ func TestHelm_PauseRelease(t *testing.T) {
 				mockK8sCache.On("GetResourceSet", ([]release.ReleaseResourceMeta)(nil)).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", k8s.ReleaseConfigKind, "test-ns", "test-name").Return(&k8s.ReleaseConfig{}, nil)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(nil)
@@ -163,7 +164,7 @@ func TestHelm_PauseRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.PauseRelease("test-ns", "test-name", false, 0)
+		err := mockReleaseManager.PauseRelease(context.Background(), "test-ns", "test-name", false, 0)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
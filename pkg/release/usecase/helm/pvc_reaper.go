@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+)
+
+const (
+	pvcReaperInterval = 10 * time.Minute
+
+	// pvcDeleteAtLabelValueLayout formats the scheduled deletion time into a
+	// valid label value, always in utc
+	pvcDeleteAtLabelValueLayout = "20060102T150405Z"
+)
+
+// schedulePvcsDeletion labels the pvcs of the release with the time the pvc
+// reaper will delete them at, computed from the delayed pvc policy, so
+// operators can see the deletion coming on the pvcs themselves.
+func (helm *Helm) schedulePvcsDeletion(namespace, releaseName string, releaseInfo *releaseModel.ReleaseInfoV2, deleteAfterDays int64) error {
+	deleteAt := time.Now().Add(time.Duration(deleteAfterDays) * 24 * time.Hour).UTC()
+	err := helm.k8sOperator.LabelStatefulSetPvcs(releaseInfo.Status.StatefulSets, map[string]string{
+		k8sModel.WalmPvcDeleteAtLabelKey: deleteAt.Format(pvcDeleteAtLabelValueLayout),
+	})
+	if err != nil {
+		logrus.Errorf("failed to label the pvcs of release %s/%s with their scheduled deletion time : %s", namespace, releaseName, err.Error())
+		return err
+	}
+	message := fmt.Sprintf("the pvcs of deleted release %s will be deleted at %s, as its pvc policy requested", releaseName, deleteAt.Format(time.RFC3339))
+	logrus.Infof("namespace %s : %s", namespace, message)
+	err = helm.k8sOperator.CreateNamespaceEvent(namespace, "PvcDeletionScheduled", "Normal", message)
+	if err != nil {
+		logrus.Warnf("failed to record the scheduled pvc deletion event of namespace %s : %s", namespace, err.Error())
+	}
+	return nil
+}
+
+// StartPvcReaper periodically deletes the pvcs whose scheduled deletion time,
+// stamped when their release was deleted under a delayed pvc policy, has
+// passed. It should only run on the leader, so multi-instance deployments do
+// not reap concurrently.
+func (helm *Helm) StartPvcReaper(stopCh <-chan struct{}) {
+	logrus.Infof("start to reap pvcs scheduled for deletion every %v", pvcReaperInterval)
+	go wait.Until(helm.reapScheduledPvcs, pvcReaperInterval, stopCh)
+}
+
+func (helm *Helm) reapScheduledPvcs() {
+	pvcs, err := helm.k8sCache.ListPersistentVolumeClaims("", k8sModel.WalmPvcDeleteAtLabelKey)
+	if err != nil {
+		logrus.Errorf("failed to list pvcs scheduled for deletion : %s", err.Error())
+		return
+	}
+	for _, pvc := range pvcs {
+		deleteAtStr := pvc.Labels[k8sModel.WalmPvcDeleteAtLabelKey]
+		deleteAt, err := time.Parse(pvcDeleteAtLabelValueLayout, deleteAtStr)
+		if err != nil {
+			logrus.Warnf("failed to parse the scheduled deletion time %s of pvc %s/%s : %s", deleteAtStr, pvc.Namespace, pvc.Name, err.Error())
+			continue
+		}
+		if time.Now().Before(deleteAt) {
+			continue
+		}
+		err = helm.k8sOperator.DeletePvc(pvc.Namespace, pvc.Name)
+		if err != nil {
+			logrus.Errorf("failed to delete pvc %s/%s scheduled for deletion : %s", pvc.Namespace, pvc.Name, err.Error())
+			continue
+		}
+		message := fmt.Sprintf("deleted pvc %s, its deletion was scheduled at %s by the pvc policy of its release", pvc.Name, deleteAt.Format(time.RFC3339))
+		logrus.Infof("pvc reaper : namespace %s : %s", pvc.Namespace, message)
+		err = helm.k8sOperator.CreateNamespaceEvent(pvc.Namespace, "PvcReaped", "Normal", message)
+		if err != nil {
+			logrus.Warnf("failed to record the pvc reaped event of namespace %s : %s", pvc.Namespace, err.Error())
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package helm
+
+import (
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+const (
+	podUnschedulableReason          = "Unschedulable"
+	containerCrashLoopBackOff       = "CrashLoopBackOff"
+	containerImagePullBackOff       = "ImagePullBackOff"
+	containerErrImagePull           = "ErrImagePull"
+	waitForFirstConsumerBindingMode = "WaitForFirstConsumer"
+)
+
+// buildNotReadyReason classifies why a resource is stuck not ready into a machine
+// readable ready reason, so that "pending forever" does not only show up as a
+// generic not-ready message.
+func (helm *Helm) buildNotReadyReason(notReadyResource k8sModel.Resource) string {
+	reason := releaseModel.ReadyReasonPending
+	for _, pod := range getResourcePods(notReadyResource) {
+		reason = releaseModel.MoreSevereReadyReason(reason, helm.buildPodNotReadyReason(pod))
+	}
+	return reason
+}
+
+func getResourcePods(resource k8sModel.Resource) []*k8sModel.Pod {
+	switch workload := resource.(type) {
+	case *k8sModel.Deployment:
+		return workload.Pods
+	case *k8sModel.StatefulSet:
+		return workload.Pods
+	case *k8sModel.DaemonSet:
+		return workload.Pods
+	case *k8sModel.Job:
+		return workload.Pods
+	}
+	return nil
+}
+
+func (helm *Helm) buildPodNotReadyReason(pod *k8sModel.Pod) string {
+	if pod.State.Reason == podUnschedulableReason {
+		if strings.Contains(strings.ToLower(pod.State.Message), "persistentvolumeclaim") {
+			return helm.buildPvcPendingReason(pod.Namespace)
+		}
+		return releaseModel.ReadyReasonUnschedulable
+	}
+
+	containers := append(pod.InitContainers, pod.Containers...)
+	for _, container := range containers {
+		switch container.State.Reason {
+		case containerCrashLoopBackOff:
+			return releaseModel.ReadyReasonCrashLoopBackOff
+		case containerImagePullBackOff, containerErrImagePull:
+			return releaseModel.ReadyReasonImagePullBackOff
+		}
+	}
+	return releaseModel.ReadyReasonPending
+}
+
+// buildPvcPendingReason tells a pvc pending on WaitForFirstConsumer binding apart
+// from a pvc whose storage class does not exist at all.
+func (helm *Helm) buildPvcPendingReason(namespace string) string {
+	pvcs, err := helm.k8sCache.ListPersistentVolumeClaims(namespace, "")
+	if err != nil {
+		logrus.Warnf("failed to list pvcs of namespace %s : %s", namespace, err.Error())
+		return releaseModel.ReadyReasonPending
+	}
+
+	storageClasses, err := helm.k8sCache.ListStorageClasses("", "")
+	if err != nil {
+		logrus.Warnf("failed to list storage classes : %s", err.Error())
+		return releaseModel.ReadyReasonPending
+	}
+	storageClassMap := map[string]*k8sModel.StorageClass{}
+	for _, storageClass := range storageClasses {
+		storageClassMap[storageClass.Name] = storageClass
+	}
+
+	reason := releaseModel.ReadyReasonPending
+	for _, pvc := range pvcs {
+		if pvc.State.Status == "Pending" {
+			storageClass, ok := storageClassMap[pvc.StorageClass]
+			if !ok {
+				return releaseModel.ReadyReasonPvcStorageClassMissing
+			}
+			if storageClass.VolumeBindingMode == waitForFirstConsumerBindingMode {
+				reason = releaseModel.MoreSevereReadyReason(reason, releaseModel.ReadyReasonPvcWaitForFirstConsumer)
+			}
+		}
+	}
+	return reason
+}
@@ -1,10 +1,12 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 )
 
-func (helm *Helm) RecoverRelease(namespace, releaseName string, async bool, timeoutSec int64) error {
+func (helm *Helm) RecoverRelease(ctx context.Context, namespace, releaseName string, async bool, timeoutSec int64) error {
 	releaseInfo, err := helm.GetRelease(namespace, releaseName)
 	if err != nil {
 		logrus.Errorf("failed to get release %s/%s : %s", namespace, releaseName, err.Error())
@@ -18,7 +20,7 @@ func (helm *Helm) RecoverRelease(namespace, releaseName string, async bool, time
 
 	releaseRequest := releaseInfo.BuildReleaseRequestV2()
 	paused := false
-	err = helm.InstallUpgradeRelease(namespace, releaseRequest, nil, async, timeoutSec, &paused)
+	err = helm.InstallUpgradeRelease(ctx, namespace, releaseRequest, nil, async, timeoutSec, &paused)
 	if err != nil {
 		logrus.Errorf("failed to upgrade release %s/%s : %s", namespace, releaseName, err.Error())
 		return err
@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"errors"
 	"github.com/stretchr/testify/assert"
@@ -168,7 +169,7 @@ func TestHelm_RecoverRelease(t *testing.T) {
 				mockK8sCache.On("GetResourceSet", ([]release.ReleaseResourceMeta)(nil)).Return(k8s.NewResourceSet(), nil)
 				mockK8sCache.On("GetResource", k8s.ReleaseConfigKind, "test-ns", "test-name").Return(&k8s.ReleaseConfig{}, nil)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(nil)
@@ -179,7 +180,7 @@ func TestHelm_RecoverRelease(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.RecoverRelease("test-ns", "test-name", false, 0)
+		err := mockReleaseManager.RecoverRelease(context.Background(), "test-ns", "test-name", false, 0)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
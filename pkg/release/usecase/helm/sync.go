@@ -0,0 +1,77 @@
+package helm
+
+import (
+	errorModel "WarpCloud/walm/pkg/models/error"
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"github.com/sirupsen/logrus"
+	"reflect"
+)
+
+// SyncReleaseCache rebuilds one release's cache entry from helm storage, repairs
+// the release config if it drifted and returns the refreshed release info.
+// It takes the same release task consideration as mutating operations so it can
+// not race an in-flight release task.
+func (helm *Helm) SyncReleaseCache(namespace, name string) (*releaseModel.ReleaseInfoV2, error) {
+	_, err := helm.validateReleaseTask(namespace, name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseCache, err := helm.helm.GetReleaseCache(namespace, name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			// the release is gone from helm storage : drop the stale cache entry
+			err = helm.releaseCache.DeleteReleaseCache(namespace, name)
+			if err != nil {
+				logrus.Errorf("failed to delete stale release cache of %s/%s : %s", namespace, name, err.Error())
+				return nil, err
+			}
+			logrus.Infof("deleted stale release cache of %s/%s : release is not found in helm storage", namespace, name)
+			return nil, errorModel.NotFoundError{}
+		}
+		logrus.Errorf("failed to get release %s/%s from helm storage : %s", namespace, name, err.Error())
+		return nil, err
+	}
+
+	err = helm.releaseCache.CreateOrUpdateReleaseCache(releaseCache)
+	if err != nil {
+		logrus.Errorf("failed to create or update release cache of %s/%s : %s", namespace, name, err.Error())
+		return nil, err
+	}
+
+	inconsistent, err := helm.isReleaseConfigInconsistent(releaseCache)
+	if err != nil {
+		return nil, err
+	}
+	if inconsistent {
+		err = helm.publishReleaseConfig(releaseCache, nil)
+		if err != nil {
+			logrus.Errorf("failed to repair release config of %s/%s : %s", namespace, name, err.Error())
+			return nil, err
+		}
+		logrus.Infof("repaired inconsistent release config of %s/%s", namespace, name)
+	}
+
+	logrus.Infof("succeed to sync release cache of %s/%s", namespace, name)
+	return helm.GetRelease(namespace, name)
+}
+
+func (helm *Helm) isReleaseConfigInconsistent(releaseCache *releaseModel.ReleaseCache) (bool, error) {
+	releaseConfigResource, err := helm.k8sCache.GetResource(k8sModel.ReleaseConfigKind, releaseCache.Namespace, releaseCache.Name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			return true, nil
+		}
+		logrus.Errorf("failed to get release config of %s/%s : %s", releaseCache.Namespace, releaseCache.Name, err.Error())
+		return false, err
+	}
+
+	releaseConfig := releaseConfigResource.(*k8sModel.ReleaseConfig)
+	if releaseConfig.ChartName != releaseCache.ChartName ||
+		releaseConfig.ChartVersion != releaseCache.ChartVersion ||
+		!reflect.DeepEqual(releaseConfig.ConfigValues, releaseCache.ConfigValues) {
+		return true, nil
+	}
+	return false, nil
+}
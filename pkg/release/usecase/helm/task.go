@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"WarpCloud/walm/pkg/models/release"
 	"encoding/json"
@@ -10,14 +12,14 @@ const (
 	defaultSleepTimeSecond int64 = 1
 )
 
-func (helm *Helm) sendReleaseTask(namespace, releaseName , taskName string, taskArgs interface{}, oldReleaseTask *release.ReleaseTask, timeoutSec int64, async bool) (error) {
+func (helm *Helm) sendReleaseTask(ctx context.Context, namespace, releaseName , taskName string, taskArgs interface{}, oldReleaseTask *release.ReleaseTask, timeoutSec int64, async bool) (error) {
 	taskArgsStr, err := json.Marshal(taskArgs)
 	if err != nil {
 		logrus.Errorf("failed to marshal task args : %s", err.Error())
 		return err
 	}
 
-	taskSig, err := helm.task.SendTask(taskName, string(taskArgsStr), timeoutSec)
+	taskSig, err := helm.task.SendTask(ctx, taskName, string(taskArgsStr), timeoutSec)
 	if err != nil {
 		logrus.Errorf("failed to send %s : %s", taskName, err.Error())
 		return err
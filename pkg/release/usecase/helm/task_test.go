@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 	"WarpCloud/walm/pkg/models/release"
 	"errors"
@@ -48,14 +49,14 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 			},
 			err:            errors.New("failed"),
 		},
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(errors.New(""))
 			},
 			err:            errors.New("failed"),
@@ -63,7 +64,7 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 			},
 			async: true,
@@ -72,7 +73,7 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(nil)
 			},
@@ -83,7 +84,7 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(errors.New(""))
 			},
@@ -94,7 +95,7 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(errors.New(""))
 			},
@@ -103,7 +104,7 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 		{
 			initMock: func() {
 				refreshMocks()
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -113,7 +114,7 @@ func TestHelm_sendReleaseTask(t *testing.T) {
 
 	for _, test := range tests {
 		test.initMock()
-		err := mockReleaseManager.sendReleaseTask("test-ns", "test", "test", nil, test.oldTask, 0, test.async)
+		err := mockReleaseManager.sendReleaseTask(context.Background(), "test-ns", "test", "test", nil, test.oldTask, 0, test.async)
 		assert.IsType(t, test.err, err)
 
 		mockReleaseCache.AssertExpectations(t)
@@ -1,13 +1,15 @@
 package helm
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/release"
 	"github.com/sirupsen/logrus"
 	"WarpCloud/walm/pkg/models/common"
 )
 
 func (helm *Helm) DryRunRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) ([]map[string]interface{}, error) {
-	releaseCache, err := helm.doInstallUpgradeRelease(namespace, releaseRequest, chartFiles, true, nil)
+	releaseCache, err := helm.doInstallUpgradeRelease(context.Background(), namespace, releaseRequest, chartFiles, true, nil)
 	if err != nil {
 		logrus.Errorf("failed to dry run install release : %s", err.Error())
 		return nil, err
@@ -22,8 +24,19 @@ func (helm *Helm) DryRunRelease(namespace string, releaseRequest *release.Releas
 	return resources, nil
 }
 
+// DryRunReleaseConfigValues returns the effective config values the release would be
+// upgraded or installed with, so users can verify the merge result before committing.
+func (helm *Helm) DryRunReleaseConfigValues(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) (map[string]interface{}, error) {
+	releaseCache, err := helm.doInstallUpgradeRelease(context.Background(), namespace, releaseRequest, chartFiles, true, nil)
+	if err != nil {
+		logrus.Errorf("failed to dry run install release : %s", err.Error())
+		return nil, err
+	}
+	return releaseCache.ConfigValues, nil
+}
+
 func (helm *Helm) ComputeResourcesByDryRunRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) (*release.ReleaseResources, error) {
-	r, err := helm.doInstallUpgradeRelease(namespace, releaseRequest, chartFiles, true, nil)
+	r, err := helm.doInstallUpgradeRelease(context.Background(), namespace, releaseRequest, chartFiles, true, nil)
 	if err != nil {
 		logrus.Errorf("failed to dry run install release : %s", err.Error())
 		return nil, err
@@ -37,8 +50,33 @@ func (helm *Helm) ComputeResourcesByDryRunRelease(namespace string, releaseReque
 	return resources, nil
 }
 
+// ComputeReleasesResources computes the resource footprints declared in the
+// manifests of all releases in the namespace, ready or not. Releases whose
+// footprint can not be computed are skipped and returned by name, so callers
+// can flag their aggregation as incomplete.
+func (helm *Helm) ComputeReleasesResources(namespace string) ([]*release.ReleaseResources, []string, error) {
+	releaseCaches, err := helm.releaseCache.GetReleaseCaches(namespace)
+	if err != nil {
+		logrus.Errorf("failed to get release caches with namespace=%s : %s", namespace, err.Error())
+		return nil, nil, err
+	}
+
+	releasesResources := []*release.ReleaseResources{}
+	incompleteReleases := []string{}
+	for _, releaseCache := range releaseCaches {
+		resources, err := helm.k8sOperator.ComputeReleaseResourcesByManifest(namespace, releaseCache.Manifest)
+		if err != nil {
+			logrus.Warnf("failed to compute release resources of %s/%s : %s", namespace, releaseCache.Name, err.Error())
+			incompleteReleases = append(incompleteReleases, releaseCache.Name)
+			continue
+		}
+		releasesResources = append(releasesResources, resources)
+	}
+	return releasesResources, incompleteReleases, nil
+}
+
 func (helm *Helm) DryRunUpdateRelease(namespace string, releaseRequest *release.ReleaseRequestV2, chartFiles []*common.BufferedFile) ([]*k8sModel.ReleaseConfig, error) {
-	releaseCache, err := helm.doInstallUpgradeRelease(namespace, releaseRequest, chartFiles, true,nil)
+	releaseCache, err := helm.doInstallUpgradeRelease(context.Background(), namespace, releaseRequest, chartFiles, true,nil)
 	if err != nil {
 		klog.Errorf("failed to dry run install release : %s", err.Error())
 		return nil, err
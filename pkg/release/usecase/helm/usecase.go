@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/k8s"
 	"WarpCloud/walm/pkg/helm"
 	"WarpCloud/walm/pkg/task"
@@ -10,14 +12,138 @@ import (
 	releaseModel "WarpCloud/walm/pkg/models/release"
 	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/release/utils"
+	"WarpCloud/walm/pkg/audit"
+	auditModel "WarpCloud/walm/pkg/models/audit"
+	"WarpCloud/walm/pkg/event"
+	eventModel "WarpCloud/walm/pkg/models/event"
+	tenantModel "WarpCloud/walm/pkg/models/tenant"
+	"WarpCloud/walm/pkg/redis"
+	"WarpCloud/walm/pkg/schedule"
+	"WarpCloud/walm/pkg/semaphore"
+	"WarpCloud/walm/pkg/setting"
+	"strconv"
+	"time"
 )
 
 type Helm struct {
-	releaseCache release.Cache
-	helm         helm.Helm
-	k8sCache     k8s.Cache
-	k8sOperator  k8s.Operator
-	task         task.Task
+	releaseCache       release.Cache
+	helm               helm.Helm
+	k8sCache           k8s.Cache
+	k8sOperator        k8s.Operator
+	task               task.Task
+	auditWriter        audit.Writer
+	eventPublisher     event.Publisher
+	namespaceSemaphore *semaphore.Semaphore
+	scheduleStore      schedule.Store
+}
+
+// SetAuditWriter enables audit logging of the mutating operations. The writer
+// stays optional, so callers not using audit keep the old behavior.
+func (helm *Helm) SetAuditWriter(writer audit.Writer) {
+	helm.auditWriter = writer
+}
+
+// SetEventPublisher enables publishing release lifecycle events to an external
+// system. The publisher stays optional, so callers not using it keep the old
+// behavior.
+func (helm *Helm) SetEventPublisher(publisher event.Publisher) {
+	helm.eventPublisher = publisher
+}
+
+// SetNamespaceSemaphore enables the per namespace concurrency budget for the
+// heavy release tasks. The semaphore stays optional, so callers not using it
+// keep the old behavior.
+func (helm *Helm) SetNamespaceSemaphore(namespaceSemaphore *semaphore.Semaphore) {
+	helm.namespaceSemaphore = namespaceSemaphore
+}
+
+// SetScheduleStore enables showing the operations scheduled on a release in
+// its release info. The store stays optional, so callers not using scheduled
+// operations keep the old behavior.
+func (helm *Helm) SetScheduleStore(scheduleStore schedule.Store) {
+	helm.scheduleStore = scheduleStore
+}
+
+// namespaceReleaseTaskBudget resolves the concurrency budget of the namespace :
+// the tenant annotation overrides the global config, zero disables the budget.
+func (helm *Helm) namespaceReleaseTaskBudget(namespace string) int64 {
+	budget := int64(0)
+	if setting.Config.ConcurrencyConfig != nil {
+		budget = setting.Config.ConcurrencyConfig.MaxConcurrentReleaseTasksPerNamespace
+	}
+	tenantInfo, err := helm.k8sCache.GetTenant(namespace)
+	if err != nil {
+		logrus.Warnf("failed to get tenant %s to resolve the concurrency budget : %s", namespace, err.Error())
+		return budget
+	}
+	if value, ok := tenantInfo.TenantAnnotitions[tenantModel.MaxConcurrentReleaseTasksAnnotationKey]; ok {
+		override, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			logrus.Warnf("failed to parse annotation %s of tenant %s : %s",
+				tenantModel.MaxConcurrentReleaseTasksAnnotationKey, namespace, err.Error())
+		} else {
+			budget = override
+		}
+	}
+	return budget
+}
+
+// acquireNamespaceReleaseTaskSlot blocks until the concurrency budget of the
+// namespace has a free slot, so one namespace can not pile all the workers
+// onto the apiserver at once. Having to wait is recorded as a release event,
+// so it shows up in the task progress. The returned function releases the
+// slot and is never nil.
+func (helm *Helm) acquireNamespaceReleaseTaskSlot(namespace, name string) (func(), error) {
+	if helm.namespaceSemaphore == nil {
+		return func() {}, nil
+	}
+	budget := helm.namespaceReleaseTaskBudget(namespace)
+	if budget <= 0 {
+		return func() {}, nil
+	}
+	return helm.namespaceSemaphore.Acquire(redis.WalmNamespaceReleaseTasksKeyPrefix+namespace, budget,
+		func(running int64) {
+			logrus.Infof("namespace %s already runs %d release tasks, release %s is waiting for a free slot",
+				namespace, running, name)
+			helm.recordReleaseEvent(namespace, name, releaseModel.ReleaseEventThrottled,
+				fmt.Sprintf("waiting for a free slot : %d release tasks already running in namespace %s", running, namespace))
+		})
+}
+
+// publishLifecycleEvent forwards a release lifecycle event to the external
+// publisher when one is configured. The publisher never blocks, so this is
+// safe to call from the task runners.
+func (helm *Helm) publishLifecycleEvent(lifecycleEvent *eventModel.LifecycleEvent) {
+	if helm.eventPublisher == nil {
+		return
+	}
+	lifecycleEvent.Kind = eventModel.ReleaseEventKind
+	lifecycleEvent.Timestamp = time.Now()
+	helm.eventPublisher.Publish(lifecycleEvent)
+}
+
+// writeAuditRecord appends the outcome of a mutating operation to the audit
+// log. It is called from the task runners too, so retried executions leave
+// their own records. Write failures are only logged.
+func (helm *Helm) writeAuditRecord(operation, namespace, name string, args map[string]interface{}, opErr error) {
+	if helm.auditWriter == nil {
+		return
+	}
+	record := &auditModel.Record{
+		Time:      time.Now(),
+		Operation: operation,
+		Namespace: namespace,
+		Name:      name,
+		Args:      args,
+		Succeeded: opErr == nil,
+	}
+	if opErr != nil {
+		record.Error = opErr.Error()
+	}
+	err := helm.auditWriter.Write(record)
+	if err != nil {
+		logrus.Warnf("failed to write audit record of %s %s/%s : %s", operation, namespace, name, err.Error())
+	}
 }
 
 // reload dependencies config values, if changes, upgrade release
@@ -47,11 +173,13 @@ func (helm *Helm) ReloadRelease(namespace, name string) error {
 
 	if utils.ConfigValuesDiff(oldDependenciesConfigValues, newDependenciesConfigValues) {
 		releaseRequest := releaseInfo.BuildReleaseRequestV2()
-		err = helm.InstallUpgradeRelease(namespace, releaseRequest, nil, false, 0, nil)
+		err = helm.InstallUpgradeRelease(context.Background(), namespace, releaseRequest, nil, false, 0, nil)
 		if err != nil {
 			logrus.Errorf("failed to upgrade release v2 %s/%s : %s", namespace, name, err.Error())
 			return err
 		}
+		helm.k8sOperator.RecordReleaseConfigEvent(namespace, name, "Normal", "DependencyValuesRefreshed",
+			"dependency output configs changed, reloading release")
 		logrus.Infof("succeed to reload release %s/%s", namespace, name)
 	} else {
 		logrus.Infof("ignore reloading release %s/%s : dependencies config value does not change", namespace, name)
@@ -84,7 +212,7 @@ func (helm *Helm) validateReleaseTask(namespace, name string, allowReleaseTaskNo
 		}
 
 		if !(taskState.IsFinished() || taskState.IsTimeout()) {
-			err = fmt.Errorf(release.WaitReleaseTaskMsgPrefix+ " %s-%s finished or timeout", releaseTask.LatestReleaseTaskSig.Name, releaseTask.LatestReleaseTaskSig.UUID)
+			err = errorModel.NewConflictError(fmt.Sprintf(release.WaitReleaseTaskMsgPrefix+" %s-%s finished or timeout", releaseTask.LatestReleaseTaskSig.Name, releaseTask.LatestReleaseTaskSig.UUID))
 			logrus.Warn(err.Error())
 			return releaseTask, err
 		}
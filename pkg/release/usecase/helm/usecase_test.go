@@ -214,7 +214,7 @@ func TestHelm_ReloadRelease(t *testing.T) {
 				mockHelm.On("GetChartDetailInfo", mock.Anything, mock.Anything, mock.Anything).Return(&release.ChartDetailInfo{}, nil)
 				mockHelm.On("GetDependencyOutputConfigs", mock.Anything, mock.Anything, mock.Anything).Return(map[string]interface{}{"test": "true"} , nil)
 
-				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
+				mockTask.On("SendTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&task.TaskSig{}, nil)
 				mockReleaseCache.On("CreateOrUpdateReleaseTask", mock.Anything).Return(nil)
 				mockTask.On("TouchTask", mock.Anything, mock.Anything).Return(nil)
 				mockTask.On("PurgeTaskState", mock.Anything).Return(nil)
@@ -320,7 +320,7 @@ func TestHelm_validateReleaseTask(t *testing.T) {
 				mockTaskState.On("IsFinished").Return(false)
 				mockTaskState.On("IsTimeout").Return(false)
 			},
-			err: errors.New("please wait for the last release task test-name-test-uuid finished or timeout"),
+			err: errorModel.ConflictError{},
 		},
 		{
 			initMock: func() {
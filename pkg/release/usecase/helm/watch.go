@@ -0,0 +1,117 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	errorModel "WarpCloud/walm/pkg/models/error"
+)
+
+const (
+	watchReadyInitialBackoff = 1 * time.Second
+	watchReadyMaxBackoff     = 10 * time.Second
+)
+
+// WatchReleaseReady blocks until the release becomes ready, its latest task
+// fails, the timeout passes, or the ctx is canceled. A release not existing
+// yet is waited for too, so callers can watch a release right after sending
+// its install task. The release status is polled with a doubling backoff, a
+// zero timeoutSec means the default.
+func (helm *Helm) WatchReleaseReady(ctx context.Context, namespace, name string, timeoutSec int64) error {
+	if timeoutSec == 0 {
+		timeoutSec = defaultTimeoutSec
+	}
+	deadline := time.Now().Add(time.Duration(timeoutSec) * time.Second)
+
+	backoff := watchReadyInitialBackoff
+	for {
+		releaseInfo, err := helm.GetRelease(namespace, name)
+		if err != nil && !errorModel.IsNotFoundError(err) {
+			logrus.Errorf("failed to get release %s/%s : %s", namespace, name, err.Error())
+			return err
+		}
+		if err == nil {
+			if releaseInfo.Ready {
+				return nil
+			}
+			failed, errorMsg, err := helm.isLatestReleaseTaskFailed(namespace, name)
+			if err != nil {
+				return err
+			}
+			if failed {
+				return fmt.Errorf("the latest task of release %s/%s failed : %s", namespace, name, errorMsg)
+			}
+		}
+		if time.Now().After(deadline) {
+			return errorModel.NewTimeoutError(fmt.Sprintf("timeout waiting for release %s/%s to be ready", namespace, name))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > watchReadyMaxBackoff {
+			backoff = watchReadyMaxBackoff
+		}
+	}
+}
+
+// WatchReleasesReady is WatchReleaseReady over a set of releases in the
+// namespace, watched concurrently. The first release failing cancels the
+// remaining watches and its error is returned.
+func (helm *Helm) WatchReleasesReady(ctx context.Context, namespace string, names []string, timeoutSec int64) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var err error
+	mux := &sync.Mutex{}
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err1 := helm.WatchReleaseReady(watchCtx, namespace, name, timeoutSec)
+			if err1 != nil {
+				mux.Lock()
+				if err == nil {
+					err = err1
+				}
+				mux.Unlock()
+				cancel()
+			}
+		}(name)
+	}
+	wg.Wait()
+	return err
+}
+
+// isLatestReleaseTaskFailed reports whether the latest task of the release
+// finished without success, so watchers can fail fast instead of waiting for
+// the timeout. A missing task or task state counts as not failed.
+func (helm *Helm) isLatestReleaseTaskFailed(namespace, name string) (failed bool, errorMsg string, err error) {
+	releaseTask, err := helm.releaseCache.GetReleaseTask(namespace, name)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			return false, "", nil
+		}
+		logrus.Errorf("failed to get release task : %s", err.Error())
+		return false, "", err
+	}
+	taskState, err := helm.task.GetTaskState(releaseTask.LatestReleaseTaskSig)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			return false, "", nil
+		}
+		logrus.Errorf("failed to get the last release task state : %s", err.Error())
+		return false, "", err
+	}
+	if taskState.IsFinished() && !taskState.IsSuccess() {
+		return true, taskState.GetErrorMsg(), nil
+	}
+	return false, "", nil
+}
@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// RequestIdHeader carries the request id, accepted from the caller and
+	// echoed on every response.
+	RequestIdHeader = "X-Request-Id"
+
+	requestIdAttribute = "walm-request-id"
+)
+
+// Filter accepts the request id from the caller or generates one, so every
+// log line, task and audit record produced by the request can be correlated.
+// It is mounted as a global go-restful filter in front of the logging filter.
+func Filter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	requestId := request.HeaderParameter(RequestIdHeader)
+	if requestId == "" {
+		if generated, err := uuid.NewV4(); err == nil {
+			requestId = generated.String()
+		}
+	}
+	request.SetAttribute(requestIdAttribute, requestId)
+	response.AddHeader(RequestIdHeader, requestId)
+	chain.ProcessFilter(request, response)
+}
+
+// GetRequestId returns the id the filter resolved for the request.
+func GetRequestId(request *restful.Request) string {
+	requestId, _ := request.Attribute(requestIdAttribute).(string)
+	return requestId
+}
@@ -0,0 +1,45 @@
+package requestid
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		headerId string
+	}{
+		{headerId: ""},
+		{headerId: "caller-supplied-id"},
+	}
+
+	for _, test := range tests {
+		requestId := ""
+		container := restful.NewContainer()
+		container.Filter(Filter)
+		ws := new(restful.WebService)
+		ws.Produces(restful.MIME_JSON)
+		ws.Route(ws.GET("/api/v1/project").To(func(request *restful.Request, response *restful.Response) {
+			requestId = GetRequestId(request)
+			response.WriteHeader(200)
+		}))
+		container.Add(ws)
+
+		httpRequest, _ := http.NewRequest("GET", "/api/v1/project", nil)
+		if test.headerId != "" {
+			httpRequest.Header.Set(RequestIdHeader, test.headerId)
+		}
+		httpWriter := httptest.NewRecorder()
+		container.ServeHTTP(httpWriter, httpRequest)
+
+		assert.Equal(t, 200, httpWriter.Code)
+		assert.NotEqual(t, "", requestId)
+		assert.Equal(t, requestId, httpWriter.Header().Get(RequestIdHeader))
+		if test.headerId != "" {
+			assert.Equal(t, test.headerId, requestId)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	errorModel "WarpCloud/walm/pkg/models/error"
+	"WarpCloud/walm/pkg/models/http"
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	"WarpCloud/walm/pkg/schedule"
+	httpUtils "WarpCloud/walm/pkg/util/http"
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful-openapi"
+)
+
+const (
+	scheduledOperationRootPath = http.ApiV1 + "/scheduledoperation"
+)
+
+type ScheduledOperationHandler struct {
+	store schedule.Store
+}
+
+func NewScheduledOperationHandler(store schedule.Store) *ScheduledOperationHandler {
+	return &ScheduledOperationHandler{store: store}
+}
+
+func RegisterScheduledOperationHandler(handler *ScheduledOperationHandler) *restful.WebService {
+	ws := new(restful.WebService)
+
+	ws.Path(scheduledOperationRootPath).
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML)
+
+	tags := []string{"scheduledoperation"}
+
+	ws.Route(ws.GET("/{namespace}").To(handler.ListScheduledOperations).
+		Doc("获取租户下未执行的定时操作列表").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Writes(scheduleModel.ScheduledOperationList{}).
+		Returns(200, "OK", scheduleModel.ScheduledOperationList{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.GET("/{namespace}/uuid/{uuid}").To(handler.GetScheduledOperation).
+		Doc("获取定时操作详情").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("uuid", "定时操作uuid").DataType("string")).
+		Writes(scheduleModel.ScheduledOperation{}).
+		Returns(200, "OK", scheduleModel.ScheduledOperation{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.DELETE("/{namespace}/uuid/{uuid}").To(handler.DeleteScheduledOperation).
+		Doc("取消未执行的定时操作, 已执行的操作不受影响").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("namespace", "租户名字").DataType("string")).
+		Param(ws.PathParameter("uuid", "定时操作uuid").DataType("string")).
+		Returns(200, "OK", nil).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	return ws
+}
+
+func (handler *ScheduledOperationHandler) ListScheduledOperations(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	operationList, err := handler.store.ListScheduledOperations(namespace)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to list scheduled operations : %s", err.Error()))
+		return
+	}
+	response.WriteEntity(operationList)
+}
+
+func (handler *ScheduledOperationHandler) GetScheduledOperation(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	uuid := request.PathParameter("uuid")
+	operation, err := handler.store.GetScheduledOperation(namespace, uuid)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("scheduled operation %s is not found", uuid))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get scheduled operation %s : %s", uuid, err.Error()))
+		return
+	}
+	response.WriteEntity(operation)
+}
+
+func (handler *ScheduledOperationHandler) DeleteScheduledOperation(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	uuid := request.PathParameter("uuid")
+	err := handler.store.DeleteScheduledOperation(namespace, uuid)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete scheduled operation %s : %s", uuid, err.Error()))
+		return
+	}
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	"WarpCloud/walm/pkg/redis"
+	"github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Store keeps the scheduled operations in a redis hash, one field per
+// operation keyed by namespace and uuid.
+type Store struct {
+	redis redis.Redis
+}
+
+func NewStore(redis redis.Redis) *Store {
+	return &Store{
+		redis: redis,
+	}
+}
+
+func (store *Store) CreateScheduledOperation(operation *scheduleModel.ScheduledOperation) error {
+	if operation.Uuid == "" {
+		generated, err := uuid.NewV4()
+		if err != nil {
+			logrus.Errorf("failed to generate uuid of scheduled operation : %s", err.Error())
+			return err
+		}
+		operation.Uuid = generated.String()
+	}
+	if operation.CreateTime.IsZero() {
+		operation.CreateTime = time.Now()
+	}
+
+	err := store.redis.SetFieldValues(redis.WalmScheduledOperationsKey,
+		map[string]interface{}{redis.BuildFieldName(operation.Namespace, operation.Uuid): operation})
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("succeed to create scheduled operation %s of %s/%s", operation.Uuid, operation.Namespace, operation.Name)
+	return nil
+}
+
+func (store *Store) GetScheduledOperation(namespace, uuid string) (*scheduleModel.ScheduledOperation, error) {
+	operationStr, err := store.redis.GetFieldValue(redis.WalmScheduledOperationsKey, namespace, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &scheduleModel.ScheduledOperation{}
+	err = json.Unmarshal([]byte(operationStr), operation)
+	if err != nil {
+		logrus.Errorf("failed to unmarshal scheduled operation %s : %s", operationStr, err.Error())
+		return nil, err
+	}
+	return operation, nil
+}
+
+func (store *Store) ListScheduledOperations(namespace string) (*scheduleModel.ScheduledOperationList, error) {
+	operationStrs, err := store.redis.GetFieldValues(redis.WalmScheduledOperationsKey, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	operationList := &scheduleModel.ScheduledOperationList{
+		Items: []*scheduleModel.ScheduledOperation{},
+	}
+	for _, operationStr := range operationStrs {
+		operation := &scheduleModel.ScheduledOperation{}
+		err = json.Unmarshal([]byte(operationStr), operation)
+		if err != nil {
+			logrus.Errorf("failed to unmarshal scheduled operation %s : %s", operationStr, err.Error())
+			return nil, err
+		}
+		operationList.Items = append(operationList.Items, operation)
+	}
+	sort.Slice(operationList.Items, func(i, j int) bool {
+		return operationList.Items[i].ScheduledAt.Before(operationList.Items[j].ScheduledAt)
+	})
+	operationList.Num = len(operationList.Items)
+	return operationList, nil
+}
+
+func (store *Store) DeleteScheduledOperation(namespace, uuid string) error {
+	err := store.redis.DeleteField(redis.WalmScheduledOperationsKey, namespace, uuid)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("succeed to delete scheduled operation %s of namespace %s", uuid, namespace)
+	return nil
+}
@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	"WarpCloud/walm/pkg/redis"
+	"WarpCloud/walm/pkg/redis/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStore_CreateScheduledOperation(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	store := NewStore(mockRedis)
+
+	storedFieldValues := map[string]interface{}{}
+	mockRedis.On("SetFieldValues", redis.WalmScheduledOperationsKey, mock.Anything).Run(func(args mock.Arguments) {
+		storedFieldValues = args.Get(1).(map[string]interface{})
+	}).Return(nil)
+
+	operation := &scheduleModel.ScheduledOperation{
+		Namespace:   "test-ns",
+		Name:        "test-name",
+		Kind:        scheduleModel.ReleaseKind,
+		Operation:   scheduleModel.InstallOperation,
+		ScheduledAt: time.Now().Add(time.Hour),
+	}
+	err := store.CreateScheduledOperation(operation)
+	assert.Nil(t, err)
+	mockRedis.AssertExpectations(t)
+
+	assert.NotEqual(t, "", operation.Uuid)
+	assert.False(t, operation.CreateTime.IsZero())
+	storedOperation, ok := storedFieldValues[redis.BuildFieldName("test-ns", operation.Uuid)].(*scheduleModel.ScheduledOperation)
+	assert.True(t, ok)
+	assert.Equal(t, operation, storedOperation)
+}
+
+func TestStore_GetScheduledOperation(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	store := NewStore(mockRedis)
+
+	operationBytes, err := json.Marshal(&scheduleModel.ScheduledOperation{Uuid: "test-uuid", Namespace: "test-ns"})
+	assert.Nil(t, err)
+	mockRedis.On("GetFieldValue", redis.WalmScheduledOperationsKey, "test-ns", "test-uuid").Return(string(operationBytes), nil)
+
+	operation, err := store.GetScheduledOperation("test-ns", "test-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "test-uuid", operation.Uuid)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestStore_ListScheduledOperations(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	store := NewStore(mockRedis)
+
+	laterBytes, err := json.Marshal(&scheduleModel.ScheduledOperation{Uuid: "later", ScheduledAt: time.Unix(2000, 0)})
+	assert.Nil(t, err)
+	soonerBytes, err := json.Marshal(&scheduleModel.ScheduledOperation{Uuid: "sooner", ScheduledAt: time.Unix(1000, 0)})
+	assert.Nil(t, err)
+	mockRedis.On("GetFieldValues", redis.WalmScheduledOperationsKey, "test-ns").Return([]string{string(laterBytes), string(soonerBytes)}, nil)
+
+	operationList, err := store.ListScheduledOperations("test-ns")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, operationList.Num)
+	// operations are listed in scheduling order
+	assert.Equal(t, "sooner", operationList.Items[0].Uuid)
+	assert.Equal(t, "later", operationList.Items[1].Uuid)
+	mockRedis.AssertExpectations(t)
+}
+
+func TestStore_DeleteScheduledOperation(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	store := NewStore(mockRedis)
+
+	mockRedis.On("DeleteField", redis.WalmScheduledOperationsKey, "test-ns", "test-uuid").Return(nil)
+
+	err := store.DeleteScheduledOperation("test-ns", "test-uuid")
+	assert.Nil(t, err)
+	mockRedis.AssertExpectations(t)
+}
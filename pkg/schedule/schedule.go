@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"WarpCloud/walm/pkg/models/schedule"
+)
+
+// Store persists the operations scheduled for later execution. Pending
+// operations live in redis, not in memory, so they survive walm restarts
+// and can be cancelled from any replica.
+type Store interface {
+	// CreateScheduledOperation stores the operation, generating its uuid
+	// when it is empty.
+	CreateScheduledOperation(operation *schedule.ScheduledOperation) error
+	GetScheduledOperation(namespace, uuid string) (*schedule.ScheduledOperation, error)
+	// ListScheduledOperations lists the pending operations of the namespace,
+	// all of them when the namespace is empty.
+	ListScheduledOperations(namespace string) (*schedule.ScheduledOperationList, error)
+	DeleteScheduledOperation(namespace, uuid string) error
+}
@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultPollIntervalSec int64 = 30
+
+// Executor submits one kind of scheduled operation through the normal task
+// path, so the usual per release and per project serialization applies.
+type Executor func(ctx context.Context, operation *scheduleModel.ScheduledOperation) error
+
+// Scheduler polls the store and submits the operations whose time has come.
+// It is meant to run on the leader only, the store being shared, running it
+// on every replica would submit the operations more than once.
+type Scheduler struct {
+	store           Store
+	executors       map[string]Executor
+	pollIntervalSec int64
+}
+
+func NewScheduler(store Store, pollIntervalSec int64) *Scheduler {
+	if pollIntervalSec == 0 {
+		pollIntervalSec = defaultPollIntervalSec
+	}
+	return &Scheduler{
+		store:           store,
+		executors:       map[string]Executor{},
+		pollIntervalSec: pollIntervalSec,
+	}
+}
+
+// RegisterExecutor registers the executor submitting the operations of the
+// kind, overwriting a previously registered one.
+func (scheduler *Scheduler) RegisterExecutor(kind, operation string, executor Executor) {
+	scheduler.executors[buildExecutorKey(kind, operation)] = executor
+}
+
+func buildExecutorKey(kind, operation string) string {
+	return kind + "/" + operation
+}
+
+func (scheduler *Scheduler) Start(stopCh <-chan struct{}) {
+	logrus.Info("scheduler started to submit scheduled operations")
+	ticker := time.NewTicker(time.Duration(scheduler.pollIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		scheduler.submitDueOperations()
+		select {
+		case <-stopCh:
+			logrus.Info("scheduler stopped submitting scheduled operations")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler *Scheduler) submitDueOperations() {
+	operationList, err := scheduler.store.ListScheduledOperations("")
+	if err != nil {
+		logrus.Errorf("failed to list scheduled operations : %s", err.Error())
+		return
+	}
+
+	now := time.Now()
+	for _, operation := range operationList.Items {
+		if operation.ScheduledAt.After(now) {
+			continue
+		}
+		executor, ok := scheduler.executors[buildExecutorKey(operation.Kind, operation.Operation)]
+		if !ok {
+			logrus.Errorf("failed to submit scheduled operation %s : no executor registered for %s %s",
+				operation.Uuid, operation.Kind, operation.Operation)
+			continue
+		}
+		// the operation is deleted before it is submitted, so a failing
+		// operation is reported once instead of being resubmitted forever
+		err = scheduler.store.DeleteScheduledOperation(operation.Namespace, operation.Uuid)
+		if err != nil {
+			logrus.Errorf("failed to delete scheduled operation %s before submitting it : %s", operation.Uuid, err.Error())
+			continue
+		}
+		err = executor(context.Background(), operation)
+		if err != nil {
+			logrus.Errorf("failed to submit scheduled %s %s of %s/%s : %s",
+				operation.Kind, operation.Operation, operation.Namespace, operation.Name, err.Error())
+			continue
+		}
+		logrus.Infof("succeed to submit scheduled %s %s of %s/%s",
+			operation.Kind, operation.Operation, operation.Namespace, operation.Name)
+	}
+}
@@ -0,0 +1,103 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	scheduleModel "WarpCloud/walm/pkg/models/schedule"
+	"WarpCloud/walm/pkg/schedule/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_submitDueOperations(t *testing.T) {
+	dueOperation := &scheduleModel.ScheduledOperation{
+		Uuid:        "due",
+		Namespace:   "test-ns",
+		Name:        "test-name",
+		Kind:        scheduleModel.ReleaseKind,
+		Operation:   scheduleModel.InstallOperation,
+		ScheduledAt: time.Now().Add(-time.Minute),
+	}
+	pendingOperation := &scheduleModel.ScheduledOperation{
+		Uuid:        "pending",
+		Namespace:   "test-ns",
+		Name:        "test-name",
+		Kind:        scheduleModel.ReleaseKind,
+		Operation:   scheduleModel.InstallOperation,
+		ScheduledAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		initMock       func(mockStore *mocks.Store)
+		executorErr    error
+		submittedUuids []string
+	}{
+		{
+			initMock: func(mockStore *mocks.Store) {
+				mockStore.On("ListScheduledOperations", "").Return(nil, errors.New(""))
+			},
+			submittedUuids: []string{},
+		},
+		{
+			// only due operations are submitted, pending ones stay stored
+			initMock: func(mockStore *mocks.Store) {
+				mockStore.On("ListScheduledOperations", "").Return(&scheduleModel.ScheduledOperationList{
+					Num: 2, Items: []*scheduleModel.ScheduledOperation{dueOperation, pendingOperation}}, nil)
+				mockStore.On("DeleteScheduledOperation", "test-ns", "due").Return(nil)
+			},
+			submittedUuids: []string{"due"},
+		},
+		{
+			// the operation is deleted even when submitting it fails
+			initMock: func(mockStore *mocks.Store) {
+				mockStore.On("ListScheduledOperations", "").Return(&scheduleModel.ScheduledOperationList{
+					Num: 1, Items: []*scheduleModel.ScheduledOperation{dueOperation}}, nil)
+				mockStore.On("DeleteScheduledOperation", "test-ns", "due").Return(nil)
+			},
+			executorErr:    errors.New(""),
+			submittedUuids: []string{"due"},
+		},
+		{
+			// an operation which can not be deleted is not submitted
+			initMock: func(mockStore *mocks.Store) {
+				mockStore.On("ListScheduledOperations", "").Return(&scheduleModel.ScheduledOperationList{
+					Num: 1, Items: []*scheduleModel.ScheduledOperation{dueOperation}}, nil)
+				mockStore.On("DeleteScheduledOperation", "test-ns", "due").Return(errors.New(""))
+			},
+			submittedUuids: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		mockStore := &mocks.Store{}
+		test.initMock(mockStore)
+
+		scheduler := NewScheduler(mockStore, 0)
+		submittedUuids := []string{}
+		scheduler.RegisterExecutor(scheduleModel.ReleaseKind, scheduleModel.InstallOperation,
+			func(ctx context.Context, operation *scheduleModel.ScheduledOperation) error {
+				submittedUuids = append(submittedUuids, operation.Uuid)
+				return test.executorErr
+			})
+
+		scheduler.submitDueOperations()
+		assert.Equal(t, test.submittedUuids, submittedUuids)
+		mockStore.AssertExpectations(t)
+	}
+}
+
+func TestScheduler_submitDueOperationsWithoutExecutor(t *testing.T) {
+	mockStore := &mocks.Store{}
+	mockStore.On("ListScheduledOperations", "").Return(&scheduleModel.ScheduledOperationList{
+		Num: 1, Items: []*scheduleModel.ScheduledOperation{
+			{Uuid: "due", Namespace: "test-ns", Kind: "unknown", Operation: "unknown", ScheduledAt: time.Now().Add(-time.Minute)},
+		}}, nil)
+
+	scheduler := NewScheduler(mockStore, 0)
+	// the operation stays stored, so registering the executor later recovers it
+	scheduler.submitDueOperations()
+	mockStore.AssertNotCalled(t, "DeleteScheduledOperation")
+	mockStore.AssertExpectations(t)
+}
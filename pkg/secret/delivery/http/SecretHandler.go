@@ -1,6 +1,7 @@
 package http
 
 import (
+	"WarpCloud/walm/pkg/auth"
 	"WarpCloud/walm/pkg/k8s"
 	"github.com/emicklei/go-restful"
 	"WarpCloud/walm/pkg/models/http"
@@ -111,7 +112,12 @@ func (handler *SecretHandler)CreateSecret(request *restful.Request, response *re
 		return
 	}
 
-	err = handler.k8sOperator.CreateSecret(namespace, createSecretRequestBody)
+	k8sOperator, err := handler.k8sOperator.WithCaller(auth.GetRequestPrincipal(request), auth.GetRequestPrincipalGroups(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build kube client impersonating the caller : %s", err.Error()))
+		return
+	}
+	err = k8sOperator.CreateSecret(namespace, createSecretRequestBody)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to create secret : %s", err.Error()))
 		return
@@ -127,7 +133,12 @@ func (handler *SecretHandler)UpdateSecret(request *restful.Request, response *re
 		return
 	}
 
-	err =  handler.k8sOperator.UpdateSecret(namespace, createSecretRequestBody)
+	k8sOperator, err := handler.k8sOperator.WithCaller(auth.GetRequestPrincipal(request), auth.GetRequestPrincipalGroups(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build kube client impersonating the caller : %s", err.Error()))
+		return
+	}
+	err =  k8sOperator.UpdateSecret(namespace, createSecretRequestBody)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to update secret : %s", err.Error()))
 		return
@@ -137,7 +148,12 @@ func (handler *SecretHandler)UpdateSecret(request *restful.Request, response *re
 func (handler *SecretHandler)DeleteSecret(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("secretname")
-	err := handler.k8sOperator.DeleteSecret(namespace, name)
+	k8sOperator, err := handler.k8sOperator.WithCaller(auth.GetRequestPrincipal(request), auth.GetRequestPrincipalGroups(request))
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build kube client impersonating the caller : %s", err.Error()))
+		return
+	}
+	err = k8sOperator.DeleteSecret(namespace, name)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete secret : %s", err.Error()))
 		return
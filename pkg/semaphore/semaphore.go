@@ -0,0 +1,69 @@
+package semaphore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"WarpCloud/walm/pkg/redis"
+)
+
+const (
+	// the expiration is refreshed on every acquire and release, so the slots
+	// of a crashed worker are reclaimed once its namespace goes quiet
+	slotExpiration = 30 * time.Minute
+	acquireBackoff = 2 * time.Second
+	acquireTimeout = 30 * time.Minute
+)
+
+// Semaphore bounds how many heavy operations run concurrently per key. The
+// slot counts live in redis, so the workers of all walm replicas share the
+// same budget.
+type Semaphore struct {
+	redis redis.Redis
+}
+
+func NewSemaphore(redisClient redis.Redis) *Semaphore {
+	return &Semaphore{redis: redisClient}
+}
+
+// Acquire blocks in a backoff loop until the budget of key has a free slot,
+// calling onWait once when it has to wait. The returned function releases the
+// slot and must be called exactly once.
+func (semaphore *Semaphore) Acquire(key string, budget int64, onWait func(running int64)) (release func(), err error) {
+	deadline := time.Now().Add(acquireTimeout)
+	waited := false
+	for {
+		count, err := semaphore.redis.IncrValue(key, 1, slotExpiration)
+		if err != nil {
+			logrus.Errorf("failed to acquire a slot of %s : %s", key, err.Error())
+			return nil, err
+		}
+		if count <= budget {
+			return func() {
+				semaphore.decr(key)
+			}, nil
+		}
+		// give the slot back while waiting, so the waiters do not count
+		// against the budget
+		semaphore.decr(key)
+		if !waited {
+			waited = true
+			if onWait != nil {
+				onWait(count - 1)
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to acquire a slot of %s within %s", key, acquireTimeout)
+		}
+		time.Sleep(acquireBackoff)
+	}
+}
+
+func (semaphore *Semaphore) decr(key string) {
+	_, err := semaphore.redis.IncrValue(key, -1, slotExpiration)
+	if err != nil {
+		logrus.Warnf("failed to release a slot of %s : %s", key, err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package semaphore
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"WarpCloud/walm/pkg/redis/mocks"
+)
+
+func TestSemaphore_Acquire(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	var mutex sync.Mutex
+	var count int64
+	mockRedis.On("IncrValue", "test-key", mock.Anything, mock.Anything).Return(
+		func(key string, delta int64, expiration time.Duration) int64 {
+			mutex.Lock()
+			defer mutex.Unlock()
+			count += delta
+			return count
+		}, nil)
+
+	semaphore := NewSemaphore(mockRedis)
+
+	release1, err := semaphore.Acquire("test-key", 2, nil)
+	assert.Nil(t, err)
+	release2, err := semaphore.Acquire("test-key", 2, nil)
+	assert.Nil(t, err)
+
+	// the third acquire waits in the backoff loop until a slot is released
+	waitingFor := make(chan int64, 1)
+	acquired := make(chan struct{})
+	go func() {
+		release3, err := semaphore.Acquire("test-key", 2, func(running int64) {
+			waitingFor <- running
+		})
+		assert.Nil(t, err)
+		release3()
+		close(acquired)
+	}()
+	assert.Equal(t, int64(2), <-waitingFor)
+	release1()
+	<-acquired
+	release2()
+
+	mutex.Lock()
+	assert.Equal(t, int64(0), count)
+	mutex.Unlock()
+}
+
+func TestSemaphore_AcquireRedisError(t *testing.T) {
+	mockRedis := &mocks.Redis{}
+	mockRedis.On("IncrValue", mock.Anything, mock.Anything, mock.Anything).
+		Return(int64(0), errors.New("redis down"))
+
+	semaphore := NewSemaphore(mockRedis)
+	_, err := semaphore.Acquire("test-key", 1, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"WarpCloud/walm/pkg/models/http"
+	"WarpCloud/walm/pkg/setting"
+	httpUtils "WarpCloud/walm/pkg/util/http"
+	"encoding/json"
+	"fmt"
+	"github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful-openapi"
+)
+
+type ConfigHandler struct {
+	watcher *setting.ConfigWatcher
+}
+
+// ConfigStatus reports the currently effective config with its secrets masked
+// and the result of the last hot reload.
+type ConfigStatus struct {
+	Config     *setting.WalmConfig   `json:"config" description:"currently effective config, secrets masked"`
+	LastReload *setting.ReloadStatus `json:"lastReload" description:"result of the last config reload, null when the config never changed"`
+}
+
+func RegisterConfigHandler(watcher *setting.ConfigWatcher) *restful.WebService {
+	handler := &ConfigHandler{watcher: watcher}
+
+	ws := new(restful.WebService)
+
+	ws.Path(http.ApiV1+"/config").
+		Doc("生效配置相关操作").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML)
+
+	tags := []string{"config"}
+
+	ws.Route(ws.GET("/").To(handler.GetConfig).
+		Doc("获取当前生效的配置(敏感信息用******屏蔽)和最近一次热加载的结果").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Writes(ConfigStatus{}).
+		Returns(200, "OK", ConfigStatus{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	return ws
+}
+
+func (handler *ConfigHandler) GetConfig(request *restful.Request, response *restful.Response) {
+	// deep copy through json so masking does not touch the running config
+	data, err := json.Marshal(setting.Config)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to marshal config : %s", err.Error()))
+		return
+	}
+	config := &setting.WalmConfig{}
+	err = json.Unmarshal(data, config)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to unmarshal config : %s", err.Error()))
+		return
+	}
+	config.MaskSensitiveValues()
+
+	var lastReload *setting.ReloadStatus
+	if handler.watcher != nil {
+		lastReload = handler.watcher.GetLastReload()
+	}
+	response.WriteEntity(ConfigStatus{Config: config, LastReload: lastReload})
+}
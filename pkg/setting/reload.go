@@ -0,0 +1,154 @@
+package setting
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/sirupsen/logrus"
+)
+
+const configWatchInterval = 10 * time.Second
+
+// ReloadStatus is the result of the last config reload attempt.
+type ReloadStatus struct {
+	ReloadTime time.Time `json:"reloadTime" description:"when the reload happened"`
+	Success    bool      `json:"success" description:"whether the reload succeeded"`
+	Message    string    `json:"message" description:"why the reload failed or which changes were rejected"`
+}
+
+// restartRequiredFields are the WalmConfig fields whose changes can not be
+// applied without a restart, e.g. the redis address or the task broker. A
+// changed field is rejected with a warning and the running value is kept.
+var restartRequiredFields = []string{
+	"HttpConfig",
+	"KubeConfig",
+	"RedisConfig",
+	"KafkaConfig",
+	"TaskConfig",
+	"TraceConfig",
+	"AuthConfig",
+	"AuditConfig",
+	"EventWebhookConfig",
+	"ClusterRegistryConfig",
+}
+
+// ConfigWatcher polls the config file and applies the reload-safe subset of
+// changes to the running config, so e.g. repo definitions, log levels and rate
+// limits can be changed without aborting in-flight tasks with a restart.
+type ConfigWatcher struct {
+	configPath  string
+	lastContent []byte
+
+	// called with the new config after a successful reload
+	hooks []func(newConfig *WalmConfig)
+
+	statusMux  sync.RWMutex
+	lastReload *ReloadStatus
+}
+
+func NewConfigWatcher(configPath string) *ConfigWatcher {
+	watcher := &ConfigWatcher{configPath: configPath}
+	// remember the content loaded at startup, the watcher only reacts to changes
+	content, err := ioutil.ReadFile(configPath)
+	if err == nil {
+		watcher.lastContent = content
+	}
+	return watcher
+}
+
+// AddReloadHook registers a function called with the new config after a
+// successful reload. Hooks must be added before Start.
+func (watcher *ConfigWatcher) AddReloadHook(hook func(newConfig *WalmConfig)) {
+	watcher.hooks = append(watcher.hooks, hook)
+}
+
+func (watcher *ConfigWatcher) Start(stopCh <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				watcher.reloadIfChanged()
+			}
+		}
+	}()
+}
+
+// GetLastReload returns the result of the last reload attempt, nil when the
+// config file never changed.
+func (watcher *ConfigWatcher) GetLastReload() *ReloadStatus {
+	watcher.statusMux.RLock()
+	defer watcher.statusMux.RUnlock()
+	return watcher.lastReload
+}
+
+func (watcher *ConfigWatcher) setLastReload(success bool, message string) {
+	watcher.statusMux.Lock()
+	defer watcher.statusMux.Unlock()
+	watcher.lastReload = &ReloadStatus{
+		ReloadTime: time.Now(),
+		Success:    success,
+		Message:    message,
+	}
+}
+
+func (watcher *ConfigWatcher) reloadIfChanged() {
+	content, err := ioutil.ReadFile(watcher.configPath)
+	if err != nil {
+		logrus.Errorf("failed to read config file %s : %s", watcher.configPath, err.Error())
+		return
+	}
+	if bytes.Equal(content, watcher.lastContent) {
+		return
+	}
+	watcher.lastContent = content
+
+	newConfig := &WalmConfig{}
+	err = yaml.Unmarshal(content, newConfig)
+	if err != nil {
+		logrus.Errorf("failed to unmarshal the changed config file : %s", err.Error())
+		watcher.setLastReload(false, fmt.Sprintf("failed to unmarshal the changed config file : %s", err.Error()))
+		return
+	}
+
+	rejected := rejectRestartRequiredChanges(&Config, newConfig)
+	Config = *newConfig
+	for _, hook := range watcher.hooks {
+		hook(newConfig)
+	}
+
+	message := ""
+	if len(rejected) > 0 {
+		message = fmt.Sprintf("changes of %s require a restart and were rejected, the running values are kept", strings.Join(rejected, ", "))
+	}
+	watcher.setLastReload(true, message)
+	logrus.Infof("config reloaded from %s %s", watcher.configPath, message)
+}
+
+// rejectRestartRequiredChanges copies the running values of the restart
+// required fields into the new config, returning the names of the rejected
+// changes.
+func rejectRestartRequiredChanges(currentConfig, newConfig *WalmConfig) []string {
+	rejected := []string{}
+	currentValue := reflect.ValueOf(currentConfig).Elem()
+	newValue := reflect.ValueOf(newConfig).Elem()
+	for _, field := range restartRequiredFields {
+		currentField := currentValue.FieldByName(field)
+		newField := newValue.FieldByName(field)
+		if !reflect.DeepEqual(currentField.Interface(), newField.Interface()) {
+			logrus.Warnf("config field %s can not be reloaded without a restart, keeping the running value", field)
+			newField.Set(currentField)
+			rejected = append(rejected, field)
+		}
+	}
+	return rejected
+}
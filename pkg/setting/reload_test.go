@@ -0,0 +1,95 @@
+package setting
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigWatcher_ReloadIfChanged(t *testing.T) {
+	oriConfig := Config
+	defer func() {
+		Config = oriConfig
+	}()
+
+	tmpDir, err := ioutil.TempDir("", "walm-config-reload-test")
+	assert.IsType(t, nil, err)
+	defer os.RemoveAll(tmpDir)
+	configPath := filepath.Join(tmpDir, "walm.yaml")
+
+	writeConfig := func(content string) {
+		err := ioutil.WriteFile(configPath, []byte(content), 0644)
+		assert.IsType(t, nil, err)
+	}
+
+	writeConfig(`
+repoList:
+- name: stable
+  url: http://stable.repo
+redisConfig:
+  addr: 127.0.0.1:6379
+`)
+	Config = WalmConfig{
+		RepoList:    []*ChartRepo{{Name: "stable", URL: "http://stable.repo"}},
+		RedisConfig: &RedisConfig{Addr: "127.0.0.1:6379"},
+	}
+
+	watcher := NewConfigWatcher(configPath)
+	var hookConfig *WalmConfig
+	watcher.AddReloadHook(func(newConfig *WalmConfig) {
+		hookConfig = newConfig
+	})
+
+	// unchanged content does not trigger a reload
+	watcher.reloadIfChanged()
+	assert.Nil(t, watcher.GetLastReload())
+
+	// the repo list is reload-safe, the redis address requires a restart
+	writeConfig(`
+repoList:
+- name: stable
+  url: http://new.repo
+redisConfig:
+  addr: 127.0.0.1:9999
+`)
+	watcher.reloadIfChanged()
+	lastReload := watcher.GetLastReload()
+	assert.Equal(t, true, lastReload.Success)
+	assert.Contains(t, lastReload.Message, "RedisConfig")
+	assert.Equal(t, "http://new.repo", Config.RepoList[0].URL)
+	assert.Equal(t, "127.0.0.1:6379", Config.RedisConfig.Addr)
+	assert.Equal(t, "http://new.repo", hookConfig.RepoList[0].URL)
+
+	// a broken config is rejected without touching the running config
+	writeConfig("{ not valid yaml")
+	watcher.reloadIfChanged()
+	lastReload = watcher.GetLastReload()
+	assert.Equal(t, false, lastReload.Success)
+	assert.Equal(t, "http://new.repo", Config.RepoList[0].URL)
+}
+
+func TestWalmConfig_MaskSensitiveValues(t *testing.T) {
+	config := &WalmConfig{
+		RedisConfig: &RedisConfig{Addr: "127.0.0.1:6379", Password: "redis-pass"},
+		AuthConfig: &AuthConfig{
+			Tokens: []*AuthToken{{Token: "secret-token", Principal: "admin"}},
+		},
+		EventWebhookConfig: &EventWebhookConfig{
+			Webhooks: []*EventWebhook{{Url: "http://hook", Secret: "hook-secret"}},
+		},
+		ClusterRegistryConfig: &ClusterRegistryConfig{EncryptionKey: "0123456789abcdef"},
+	}
+
+	config.MaskSensitiveValues()
+
+	assert.Equal(t, "******", config.RedisConfig.Password)
+	assert.Equal(t, "******", config.AuthConfig.Tokens[0].Token)
+	assert.Equal(t, "******", config.EventWebhookConfig.Webhooks[0].Secret)
+	assert.Equal(t, "******", config.ClusterRegistryConfig.EncryptionKey)
+	// non secret fields stay readable
+	assert.Equal(t, "127.0.0.1:6379", config.RedisConfig.Addr)
+	assert.Equal(t, "admin", config.AuthConfig.Tokens[0].Principal)
+}
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"github.com/sirupsen/logrus"
 	"github.com/ghodss/yaml"
+	"WarpCloud/walm/pkg/models/tenant"
 )
 
 var Config WalmConfig
@@ -18,10 +19,32 @@ type HttpConfig struct {
 type ChartRepo struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
+	// default config values merged into every release installed from this repo,
+	// dependency configs and user supplied config values always win
+	DefaultValues map[string]interface{} `json:"defaultValues"`
+}
+
+// chart provenance verification modes
+const (
+	ChartProvenanceModeOff     = "off"
+	ChartProvenanceModeWarn    = "warn"
+	ChartProvenanceModeEnforce = "enforce"
+)
+
+type ChartProvenanceConfig struct {
+	// mode is off, warn or enforce, off when unset
+	Mode string `json:"mode"`
+	// path of the gpg public keyring chart signatures are verified against
+	Keyring string `json:"keyring"`
 }
 
 type KubeConfig struct {
 	Config string `json:"config"`
+	// client-go rate limits, zero keeps the client-go defaults
+	Qps   float32 `json:"qps"`
+	Burst int     `json:"burst"`
+	// default timeout of a single api request, e.g. "30s"
+	Timeout string `json:"timeout"`
 }
 
 type RedisConfig struct {
@@ -37,6 +60,108 @@ type KafkaConfig struct {
 	KeyFile   string   `json:"keyFile"`
 	CaFile    string   `json:"caFile"`
 	VerifySsl bool     `json:"verifySsl"`
+	// lifecycle events are published to these topics when set, an empty topic
+	// disables publishing events of that kind
+	ReleaseEventsTopic string `json:"releaseEventsTopic"`
+	ProjectEventsTopic string `json:"projectEventsTopic"`
+}
+
+// EventWebhook is one http receiver of lifecycle events.
+type EventWebhook struct {
+	Url string `json:"url"`
+	// optional secret used to sign the request body with hmac sha256
+	Secret string `json:"secret"`
+	// event types delivered to this webhook, empty means all
+	EventTypes []string `json:"eventTypes"`
+}
+
+type ClusterRegistryConfig struct {
+	// kubeconfigs of registered clusters are encrypted with this key at rest,
+	// the key must be 16, 24 or 32 bytes long
+	EncryptionKey string `json:"encryptionKey"`
+	// decrypted kubeconfigs are materialized under this directory for the kube
+	// clients needing a file, the system temp directory when unset
+	KubeConfigRootDir string `json:"kubeConfigRootDir"`
+}
+
+type EventWebhookConfig struct {
+	Webhooks []*EventWebhook `json:"webhooks"`
+	// events that exhaust their delivery retries are appended to this file as
+	// json lines, empty disables the dead letter log
+	DeadLetterPath string `json:"deadLetterPath"`
+}
+
+type TenantConfig struct {
+	// recorded on tenants the template was applied to
+	TemplateVersion string `json:"templateVersion"`
+	// default tenant params merged underneath caller supplied params on tenant creation
+	DefaultTenantParams *tenant.TenantParams `json:"defaultTenantParams"`
+	// used/hard ratio above which a resource is reported under quota pressure, 0.85 when unset
+	QuotaPressureThreshold float64 `json:"quotaPressureThreshold"`
+}
+
+type AuthConfig struct {
+	// unauthenticated requests are only allowed when enable is false
+	Enable bool `json:"enable"`
+	// when enabled, kube operations of a request impersonate the authenticated
+	// principal, so the apiserver enforces the rbac of the tenant namespace
+	Impersonate bool         `json:"impersonate"`
+	Tokens      []*AuthToken `json:"tokens"`
+}
+
+type AuthToken struct {
+	Token     string `json:"token"`
+	Principal string `json:"principal"`
+	// allowed namespaces, "*" allows all
+	Namespaces []string `json:"namespaces"`
+	// allowed verbs: read, mutate, "*" allows both
+	Verbs []string `json:"verbs"`
+	// groups set on impersonated kube requests besides the principal
+	Groups []string `json:"groups"`
+	// privileged principals may reveal the sensitive values masked by default
+	Privileged bool `json:"privileged"`
+}
+
+type RateLimitConfig struct {
+	Enable bool `json:"enable"`
+	// per client token buckets, zero keeps the defaults
+	ReadQPS     float64 `json:"readQps"`
+	ReadBurst   int     `json:"readBurst"`
+	MutateQPS   float64 `json:"mutateQps"`
+	MutateBurst int     `json:"mutateBurst"`
+	// principals never throttled, e.g. the walm components calling each other
+	ExemptPrincipals []string `json:"exemptPrincipals"`
+}
+
+type ConcurrencyConfig struct {
+	// max heavy release tasks (install, upgrade, delete) running at once in one
+	// namespace across all walm replicas, zero disables the budget. Tenants can
+	// override it via TenantParams.
+	MaxConcurrentReleaseTasksPerNamespace int64 `json:"maxConcurrentReleaseTasksPerNamespace"`
+}
+
+type AuditConfig struct {
+	Enable bool `json:"enable"`
+	// backend is file or redis, file when unset
+	Backend string `json:"backend"`
+	// file backend, rotated once when the size cap is reached
+	FilePath     string `json:"filePath"`
+	MaxSizeBytes int64  `json:"maxSizeBytes"`
+	// redis backend, a capped stream
+	Stream string `json:"stream"`
+	MaxLen int64  `json:"maxLen"`
+}
+
+type TraceConfig struct {
+	// the tracer is a global set up at boot, so changes require a restart
+	Enable      bool   `json:"enable"`
+	ServiceName string `json:"serviceName"`
+	// jaeger agent host:port the spans are sent to
+	AgentHostPort string `json:"agentHostPort"`
+	// jaeger collector endpoint, used instead of the agent when set
+	CollectorEndpoint string `json:"collectorEndpoint"`
+	// fraction of traces sampled, zero means sample everything
+	SampleRate float64 `json:"sampleRate"`
 }
 
 type TaskConfig struct {
@@ -55,11 +180,26 @@ type WalmConfig struct {
 	RedisConfig   *RedisConfig   `json:"redisConfig"`
 	KafkaConfig   *KafkaConfig   `json:"kafkaConfig"`
 	TaskConfig    *TaskConfig    `json:"taskConfig"`
+	TenantConfig  *TenantConfig  `json:"tenantConfig"`
+	RateLimitConfig *RateLimitConfig `json:"rateLimitConfig"`
+	ConcurrencyConfig *ConcurrencyConfig `json:"concurrencyConfig"`
+	TraceConfig   *TraceConfig   `json:"traceConfig"`
+	AuditConfig   *AuditConfig   `json:"auditConfig"`
+	AuthConfig    *AuthConfig    `json:"authConfig"`
 	JsonnetConfig *JsonnetConfig `json:"jsonnetConfig"`
 	ChartImageConfig *ChartImageConfig `json:"chartImageConfig"`
+	ChartProvenanceConfig *ChartProvenanceConfig `json:"chartProvenanceConfig"`
+	EventWebhookConfig    *EventWebhookConfig    `json:"eventWebhookConfig"`
+	ClusterRegistryConfig *ClusterRegistryConfig `json:"clusterRegistryConfig"`
 
 	//only for test
 	ChartImageRegistry string `json:"chartImageRegistry"`
+
+	// patterns of config value keys whose values are masked in api responses
+	SensitiveKeyPatterns []string `json:"sensitiveKeyPatterns"`
+
+	// allow loading charts from local paths, only for development
+	EnableLocalChart bool `json:"enableLocalChart"`
 }
 
 type ChartImageConfig struct {
@@ -68,12 +208,42 @@ type ChartImageConfig struct {
 
 type LogConfig struct {
 	Level string `json:"level"`
+	// per module level overrides, changeable at runtime via the log api
+	ModuleLevels map[string]string `json:"moduleLevels"`
 }
 
 type JsonnetConfig struct {
 	CommonTemplateFilesPath string `json:"commonTemplateFilesPath"`
 }
 
+const maskedValue = "******"
+
+// MaskSensitiveValues masks the secrets held in the config, so the effective
+// config can be reported over the api. The caller must mask a copy, the
+// running config shares the nested pointers.
+func (config *WalmConfig) MaskSensitiveValues() {
+	if config.RedisConfig != nil && config.RedisConfig.Password != "" {
+		config.RedisConfig.Password = maskedValue
+	}
+	if config.AuthConfig != nil {
+		for _, token := range config.AuthConfig.Tokens {
+			if token.Token != "" {
+				token.Token = maskedValue
+			}
+		}
+	}
+	if config.EventWebhookConfig != nil {
+		for _, webhook := range config.EventWebhookConfig.Webhooks {
+			if webhook.Secret != "" {
+				webhook.Secret = maskedValue
+			}
+		}
+	}
+	if config.ClusterRegistryConfig != nil && config.ClusterRegistryConfig.EncryptionKey != "" {
+		config.ClusterRegistryConfig.EncryptionKey = maskedValue
+	}
+}
+
 // StartResyncReleaseCaches sets values from the environment.
 func InitConfig(configPath string) {
 	yamlFile, err := ioutil.ReadFile(configPath)
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"WarpCloud/walm/pkg/task"
+	"github.com/emicklei/go-restful"
+	httpUtils "WarpCloud/walm/pkg/util/http"
+	"WarpCloud/walm/pkg/models/http"
+	"github.com/emicklei/go-restful-openapi"
+	taskModel "WarpCloud/walm/pkg/models/task"
+	"fmt"
+	errorModel "WarpCloud/walm/pkg/models/error"
+)
+
+type TaskHandler struct {
+	task task.Task
+}
+
+func RegisterTaskHandler(task task.Task) *restful.WebService {
+	handler := TaskHandler{task: task}
+
+	ws := new(restful.WebService)
+
+	ws.Path(http.ApiV1 + "/task").
+		Doc("异步任务相关操作").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML)
+
+	tags := []string{"task"}
+
+	ws.Route(ws.GET("/{uuid}").To(handler.GetTaskState).
+		Doc("获取异步任务状态").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("uuid", "任务uuid").DataType("string")).
+		Param(ws.QueryParameter("name", "任务名字").DataType("string").Required(false)).
+		Writes(taskModel.TaskStateInfo{}).
+		Returns(200, "OK", taskModel.TaskStateInfo{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	return ws
+}
+
+func (handler *TaskHandler) GetTaskState(request *restful.Request, response *restful.Response) {
+	uuid := request.PathParameter("uuid")
+	taskSig := &taskModel.TaskSig{
+		UUID: uuid,
+		Name: request.QueryParameter("name"),
+	}
+	taskState, err := handler.task.GetTaskState(taskSig)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("task %s is not found", uuid))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to get task state : %s", err.Error()))
+		return
+	}
+	response.WriteEntity(&taskModel.TaskStateInfo{
+		Finished: taskState.IsFinished(),
+		Success:  taskState.IsSuccess(),
+		Timeout:  taskState.IsTimeout(),
+		ErrorMsg: taskState.GetErrorMsg(),
+	})
+}
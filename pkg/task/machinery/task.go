@@ -1,9 +1,11 @@
 package machinery
 
 import (
+	"context"
 	taskModel "WarpCloud/walm/pkg/models/task"
 	"WarpCloud/walm/pkg/task"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	"fmt"
 	"github.com/RichardKnop/machinery/v1/tasks"
 	"github.com/RichardKnop/machinery/v1"
 	"github.com/RichardKnop/machinery/v1/backends/result"
@@ -53,7 +55,18 @@ func convertTaskSig(sig *taskModel.TaskSig) *tasks.Signature {
 	}
 }
 
-func (task *Task) RegisterTask(taskName string, taskRunner func(taskArgs string) error) error{
+// CheckHealth verifies the task broker is reachable, so readiness probes can
+// pull walm out of rotation when the queue is down.
+func (task *Task) CheckHealth() error {
+	_, err := task.server.GetBroker().GetPendingTasks(task.server.GetConfig().DefaultQueue)
+	if err != nil {
+		logrus.Errorf("failed to check task broker : %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (task *Task) RegisterTask(taskName string, taskRunner func(ctx context.Context, taskArgs string) error) error{
 	err := task.server.RegisterTask(taskName, taskRunner)
 	if err != nil {
 		logrus.Errorf("failed to register task %s : %s", taskName, err.Error())
@@ -62,7 +75,7 @@ func (task *Task) RegisterTask(taskName string, taskRunner func(taskArgs string)
 	return nil
 }
 
-func (task *Task) SendTask(taskName, taskArgs string, timeoutSec int64) (*taskModel.TaskSig, error){
+func (task *Task) SendTask(ctx context.Context, taskName, taskArgs string, timeoutSec int64) (*taskModel.TaskSig, error){
 	taskSig := &tasks.Signature{
 		Name: taskName,
 		Args: []tasks.Arg{
@@ -72,7 +85,9 @@ func (task *Task) SendTask(taskName, taskArgs string, timeoutSec int64) (*taskMo
 			},
 		},
 	}
-	_, err := task.server.SendTask(taskSig)
+	// machinery injects the trace context of ctx into the signature headers,
+	// so the worker resumes the trace when it executes the task
+	_, err := task.server.SendTaskWithContext(ctx, taskSig)
 	if err != nil {
 		logrus.Errorf("failed to send %s : %s", taskName, err.Error())
 		return nil, err
@@ -96,6 +111,9 @@ func (task *Task) TouchTask(sig *taskModel.TaskSig, pollingIntervalSec int64) (e
 	_, err := asyncResult.GetWithTimeout(time.Duration(sig.TimeoutSec)*time.Second, time.Duration(pollingIntervalSec) * time.Second)
 	if err != nil {
 		logrus.Errorf("touch task %s-%s failed: %s", sig.Name, sig.UUID, err.Error())
+		if err == result.ErrTimeoutReached {
+			err = errorModel.NewTimeoutError(fmt.Sprintf("task %s-%s is not finished after %d seconds", sig.Name, sig.UUID, sig.TimeoutSec))
+		}
 		return err
 	}
 	return nil
@@ -2,6 +2,7 @@
 
 package mocks
 
+import context "context"
 import mock "github.com/stretchr/testify/mock"
 import pkgtask "WarpCloud/walm/pkg/task"
 import task "WarpCloud/walm/pkg/models/task"
@@ -11,6 +12,20 @@ type Task struct {
 	mock.Mock
 }
 
+// CheckHealth provides a mock function with given fields:
+func (_m *Task) CheckHealth() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetTaskState provides a mock function with given fields: sig
 func (_m *Task) GetTaskState(sig *task.TaskSig) (pkgtask.TaskState, error) {
 	ret := _m.Called(sig)
@@ -49,11 +64,11 @@ func (_m *Task) PurgeTaskState(sig *task.TaskSig) error {
 }
 
 // RegisterTask provides a mock function with given fields: taskName, _a1
-func (_m *Task) RegisterTask(taskName string, _a1 func(string) error) error {
+func (_m *Task) RegisterTask(taskName string, _a1 func(context.Context, string) error) error {
 	ret := _m.Called(taskName, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, func(string) error) error); ok {
+	if rf, ok := ret.Get(0).(func(string, func(context.Context, string) error) error); ok {
 		r0 = rf(taskName, _a1)
 	} else {
 		r0 = ret.Error(0)
@@ -62,13 +77,13 @@ func (_m *Task) RegisterTask(taskName string, _a1 func(string) error) error {
 	return r0
 }
 
-// SendTask provides a mock function with given fields: taskName, taskArgs, timeoutSec
-func (_m *Task) SendTask(taskName string, taskArgs string, timeoutSec int64) (*task.TaskSig, error) {
-	ret := _m.Called(taskName, taskArgs, timeoutSec)
+// SendTask provides a mock function with given fields: ctx, taskName, taskArgs, timeoutSec
+func (_m *Task) SendTask(ctx context.Context, taskName string, taskArgs string, timeoutSec int64) (*task.TaskSig, error) {
+	ret := _m.Called(ctx, taskName, taskArgs, timeoutSec)
 
 	var r0 *task.TaskSig
-	if rf, ok := ret.Get(0).(func(string, string, int64) *task.TaskSig); ok {
-		r0 = rf(taskName, taskArgs, timeoutSec)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) *task.TaskSig); ok {
+		r0 = rf(ctx, taskName, taskArgs, timeoutSec)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*task.TaskSig)
@@ -76,8 +91,8 @@ func (_m *Task) SendTask(taskName string, taskArgs string, timeoutSec int64) (*t
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, int64) error); ok {
-		r1 = rf(taskName, taskArgs, timeoutSec)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, taskName, taskArgs, timeoutSec)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,12 +1,19 @@
 package task
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/task"
 )
 
 type Task interface {
-	RegisterTask(taskName string, task func(taskArgs string) error) error
-	SendTask(taskName, taskArgs string, timeoutSec int64) (*task.TaskSig, error)
+	// CheckHealth verifies the task broker is reachable
+	CheckHealth() error
+	// the worker resumes the trace of the task sender on the task func's
+	// context, so spans started from it join the sender's trace
+	RegisterTask(taskName string, task func(ctx context.Context, taskArgs string) error) error
+	// the trace context of ctx is injected into the task signature
+	SendTask(ctx context.Context, taskName, taskArgs string, timeoutSec int64) (*task.TaskSig, error)
 	GetTaskState(sig *task.TaskSig) (TaskState, error)
 	TouchTask(sig *task.TaskSig, pollingIntervalSec int64) (error)
 	PurgeTaskState(sig *task.TaskSig) (error)
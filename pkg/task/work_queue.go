@@ -9,14 +9,24 @@ import (
 	"github.com/RichardKnop/machinery/v1/config"
 	"github.com/RichardKnop/machinery/v1/backends/result"
 	"github.com/RichardKnop/machinery/v1/log"
+	"sync"
 	"time"
 )
 
 var taskManager *TaskManager
+var taskManagerOnce sync.Once
 var registeredTasks map[string]interface{}
 
+func NewTaskManager(server *machinery.Server) *TaskManager {
+	manager := &TaskManager{
+		server: server,
+	}
+	manager.server.RegisterTasks(registeredTasks)
+	return manager
+}
+
 func GetDefaultTaskManager() *TaskManager {
-	if taskManager == nil {
+	taskManagerOnce.Do(func() {
 		taskConfig := &config.Config{
 			Broker:          setting.Config.TaskConfig.Broker,
 			DefaultQueue:    setting.Config.TaskConfig.DefaultQueue,
@@ -37,11 +47,8 @@ func GetDefaultTaskManager() *TaskManager {
 			logrus.Fatalf("Failed to init work queue server: %s", err.Error())
 		}
 		log.Set(logrus.StandardLogger())
-		taskManager = &TaskManager{
-			server: server,
-		}
-		taskManager.server.RegisterTasks(registeredTasks)
-	}
+		taskManager = NewTaskManager(server)
+	})
 	return taskManager
 }
 
@@ -30,6 +30,10 @@ func RegisterTenantHandler(usecase tenant.UseCase) *restful.WebService {
 	ws.Route(ws.GET("/").To(handler.ListTenants).
 		Doc("获取租户列表").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.QueryParameter("labelselector", "标签过滤").DataType("string").Required(false)).
+		Param(ws.QueryParameter("summary", "是否只返回租户概要(跳过配额用量查询)").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("limit", "分页大小, 0表示不分页").DataType("integer").Required(false)).
+		Param(ws.QueryParameter("continue", "上一页返回的continue token").DataType("string").Required(false)).
 		Writes(tenantModel.TenantInfoList{}).
 		Returns(200, "OK", tenantModel.TenantInfoList{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
@@ -44,19 +48,39 @@ func RegisterTenantHandler(usecase tenant.UseCase) *restful.WebService {
 		Returns(404, "Not Found", http.ErrorMessageResponse{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
+	ws.Route(ws.GET("/{tenantName}/services").To(handler.ListTenantServices).
+		Doc("获取租户下服务列表及访问入口").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("tenantName", "租户名字").DataType("string")).
+		Writes(tenantModel.TenantServiceInfoList{}).
+		Returns(200, "OK", tenantModel.TenantServiceInfoList{}).
+		Returns(404, "Not Found", http.ErrorMessageResponse{}).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
 	ws.Route(ws.DELETE("/{tenantName}").To(handler.DeleteTenant).
 		Doc("删除租户").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("tenantName", "租户名字").DataType("string")).
+		Param(ws.QueryParameter("force", "是否强制删除租户下的项目和发布").DataType("boolean").Required(false)).
+		Returns(200, "OK", nil).
+		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
+
+	ws.Route(ws.POST("/{tenantName}/template").To(handler.ReapplyTenantTemplate).
+		Doc("重新应用租户默认模板").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("tenantName", "租户名字").DataType("string")).
 		Returns(200, "OK", nil).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.POST("/{tenantName}").To(handler.CreateTenant).
-		Doc("创建租户").
+		Doc("创建租户(幂等, 已存在的walm租户会被调谐)").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Param(ws.PathParameter("tenantName", "租户名字").DataType("string")).
+		Param(ws.QueryParameter("adopt", "是否接管非walm管理的已存在namespace").DataType("boolean").Required(false)).
+		Param(ws.QueryParameter("dryRun", "只返回将被应用的对象, 不实际创建").DataType("boolean").Required(false)).
 		Reads(tenantModel.TenantParams{}).
-		Returns(200, "OK", nil).
+		Writes(tenantModel.CreateTenantResult{}).
+		Returns(200, "OK", tenantModel.CreateTenantResult{}).
 		Returns(500, "Internal Error", http.ErrorMessageResponse{}))
 
 	ws.Route(ws.PUT("/{tenantName}").To(handler.UpdateTenant).
@@ -71,27 +95,63 @@ func RegisterTenantHandler(usecase tenant.UseCase) *restful.WebService {
 }
 
 func (handler *TenantHandler)ListTenants(request *restful.Request, response *restful.Response) {
-	tenantInfoList, err := handler.usecase.ListTenants()
+	labelSelectorStr := request.QueryParameter("labelselector")
+	summary, err := httpUtils.GetSummaryQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param summary value is not valid : %s", err.Error()))
+		return
+	}
+	limit, err := httpUtils.GetLimitQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param limit value is not valid : %s", err.Error()))
+		return
+	}
+	continueToken := request.QueryParameter("continue")
+
+	tenantInfoList, err := handler.usecase.ListTenants(labelSelectorStr, summary)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to list tenants : %s", err.Error()))
 		return
 	}
-	response.WriteEntity(tenantInfoList)
+
+	// the full list keeps its shape unless pagination is asked for
+	if limit == 0 && continueToken == "" {
+		response.WriteEntity(tenantInfoList)
+		return
+	}
+
+	page, err := tenantModel.BuildTenantInfoPage(tenantInfoList.Items, limit, continueToken)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to build tenant page : %s", err.Error()))
+		return
+	}
+	response.WriteEntity(page)
 }
 
 func (handler *TenantHandler)CreateTenant(request *restful.Request, response *restful.Response) {
 	tenantName := request.PathParameter("tenantName")
+	adopt, err := httpUtils.GetAdoptQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param adopt value is not valid : %s", err.Error()))
+		return
+	}
+	dryRun, err := httpUtils.GetDryRunQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param dryRun value is not valid : %s", err.Error()))
+		return
+	}
 	tenantParams := new(tenantModel.TenantParams)
-	err := request.ReadEntity(&tenantParams)
+	err = request.ReadEntity(&tenantParams)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to read tenant params : %s", err.Error()))
 		return
 	}
-	err = handler.usecase.CreateTenant(tenantName, tenantParams)
+	result, err := handler.usecase.CreateTenant(tenantName, tenantParams, adopt, dryRun)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to create tenant : %s", err.Error()))
 		return
 	}
+	response.WriteEntity(result)
 }
 
 func (handler *TenantHandler)GetTenant(request *restful.Request, response *restful.Response) {
@@ -108,9 +168,28 @@ func (handler *TenantHandler)GetTenant(request *restful.Request, response *restf
 	response.WriteEntity(tenantInfo)
 }
 
+func (handler *TenantHandler)ListTenantServices(request *restful.Request, response *restful.Response) {
+	tenantName := request.PathParameter("tenantName")
+	tenantServiceInfoList, err := handler.usecase.ListTenantServices(tenantName)
+	if err != nil {
+		if errorModel.IsNotFoundError(err) {
+			httpUtils.WriteNotFoundResponse(response, -1, fmt.Sprintf("tenant %s is not found", tenantName))
+			return
+		}
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to list tenant services : %s", err.Error()))
+		return
+	}
+	response.WriteEntity(tenantServiceInfoList)
+}
+
 func (handler *TenantHandler)DeleteTenant(request *restful.Request, response *restful.Response) {
 	tenantName := request.PathParameter("tenantName")
-	err := handler.usecase.DeleteTenant(tenantName)
+	force, err := httpUtils.GetForceQueryParam(request)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("query param force value is not valid : %s", err.Error()))
+		return
+	}
+	err = handler.usecase.DeleteTenant(tenantName, force)
 	if err != nil {
 		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to delete namespace %s: %s" , tenantName, err.Error()))
 		return
@@ -119,6 +198,17 @@ func (handler *TenantHandler)DeleteTenant(request *restful.Request, response *re
 	response.WriteEntity(nil)
 }
 
+func (handler *TenantHandler)ReapplyTenantTemplate(request *restful.Request, response *restful.Response) {
+	tenantName := request.PathParameter("tenantName")
+	err := handler.usecase.ReapplyTenantTemplate(tenantName)
+	if err != nil {
+		httpUtils.WriteErrorResponse(response, -1, fmt.Sprintf("failed to reapply tenant template to %s : %s", tenantName, err.Error()))
+		return
+	}
+
+	response.WriteEntity(nil)
+}
+
 func (handler *TenantHandler)UpdateTenant(request *restful.Request, response *restful.Response) {
 	tenantName := request.PathParameter("tenantName")
 	tenantParams := new(tenantModel.TenantParams)
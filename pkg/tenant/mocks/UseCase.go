@@ -11,27 +11,36 @@ type UseCase struct {
 	mock.Mock
 }
 
-// CreateTenant provides a mock function with given fields: tenantName, tenantParams
-func (_m *UseCase) CreateTenant(tenantName string, tenantParams *tenant.TenantParams) error {
-	ret := _m.Called(tenantName, tenantParams)
+// CreateTenant provides a mock function with given fields: tenantName, tenantParams, adopt, dryRun
+func (_m *UseCase) CreateTenant(tenantName string, tenantParams *tenant.TenantParams, adopt bool, dryRun bool) (*tenant.CreateTenantResult, error) {
+	ret := _m.Called(tenantName, tenantParams, adopt, dryRun)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *tenant.TenantParams) error); ok {
-		r0 = rf(tenantName, tenantParams)
+	var r0 *tenant.CreateTenantResult
+	if rf, ok := ret.Get(0).(func(string, *tenant.TenantParams, bool, bool) *tenant.CreateTenantResult); ok {
+		r0 = rf(tenantName, tenantParams, adopt, dryRun)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tenant.CreateTenantResult)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *tenant.TenantParams, bool, bool) error); ok {
+		r1 = rf(tenantName, tenantParams, adopt, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// DeleteTenant provides a mock function with given fields: tenantName
-func (_m *UseCase) DeleteTenant(tenantName string) error {
-	ret := _m.Called(tenantName)
+// DeleteTenant provides a mock function with given fields: tenantName, force
+func (_m *UseCase) DeleteTenant(tenantName string, force bool) error {
+	ret := _m.Called(tenantName, force)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(tenantName)
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(tenantName, force)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,13 +71,36 @@ func (_m *UseCase) GetTenant(tenantName string) (*tenant.TenantInfo, error) {
 	return r0, r1
 }
 
-// ListTenants provides a mock function with given fields:
-func (_m *UseCase) ListTenants() (*tenant.TenantInfoList, error) {
-	ret := _m.Called()
+// ListTenantServices provides a mock function with given fields: tenantName
+func (_m *UseCase) ListTenantServices(tenantName string) (*tenant.TenantServiceInfoList, error) {
+	ret := _m.Called(tenantName)
+
+	var r0 *tenant.TenantServiceInfoList
+	if rf, ok := ret.Get(0).(func(string) *tenant.TenantServiceInfoList); ok {
+		r0 = rf(tenantName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tenant.TenantServiceInfoList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTenants provides a mock function with given fields: labelSelectorStr, summary
+func (_m *UseCase) ListTenants(labelSelectorStr string, summary bool) (*tenant.TenantInfoList, error) {
+	ret := _m.Called(labelSelectorStr, summary)
 
 	var r0 *tenant.TenantInfoList
-	if rf, ok := ret.Get(0).(func() *tenant.TenantInfoList); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string, bool) *tenant.TenantInfoList); ok {
+		r0 = rf(labelSelectorStr, summary)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*tenant.TenantInfoList)
@@ -76,8 +108,8 @@ func (_m *UseCase) ListTenants() (*tenant.TenantInfoList, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(labelSelectorStr, summary)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -85,6 +117,20 @@ func (_m *UseCase) ListTenants() (*tenant.TenantInfoList, error) {
 	return r0, r1
 }
 
+// ReapplyTenantTemplate provides a mock function with given fields: tenantName
+func (_m *UseCase) ReapplyTenantTemplate(tenantName string) error {
+	ret := _m.Called(tenantName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(tenantName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateTenant provides a mock function with given fields: tenantName, tenantParams
 func (_m *UseCase) UpdateTenant(tenantName string, tenantParams *tenant.TenantParams) error {
 	ret := _m.Called(tenantName, tenantParams)
@@ -3,9 +3,11 @@ package tenant
 import "WarpCloud/walm/pkg/models/tenant"
 
 type UseCase interface {
-	CreateTenant(tenantName string, tenantParams *tenant.TenantParams) error
+	CreateTenant(tenantName string, tenantParams *tenant.TenantParams, adopt bool, dryRun bool) (*tenant.CreateTenantResult, error)
 	GetTenant(tenantName string) (*tenant.TenantInfo, error)
-	ListTenants() (*tenant.TenantInfoList, error)
-	DeleteTenant(tenantName string) error
+	ListTenants(labelSelectorStr string, summary bool) (*tenant.TenantInfoList, error)
+	ListTenantServices(tenantName string) (*tenant.TenantServiceInfoList, error)
+	DeleteTenant(tenantName string, force bool) error
 	UpdateTenant(tenantName string, tenantParams *tenant.TenantParams) error
+	ReapplyTenantTemplate(tenantName string) error
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"WarpCloud/walm/pkg/models/tenant"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	quotaPressureCheckInterval = 5 * time.Minute
+	// emitting at most one event per tenant within this interval avoids spamming the namespace
+	quotaPressureEventInterval = time.Hour
+
+	QuotaPressureEventReason = "TenantQuotaPressure"
+)
+
+// StartQuotaPressureChecker periodically scans the tenants and emits a warning
+// event on every namespace whose quota usage crossed the pressure threshold.
+// It blocks until stopCh is closed.
+func (tenantImpl *Tenant) StartQuotaPressureChecker(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(quotaPressureCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			tenantImpl.checkQuotaPressures()
+		}
+	}
+}
+
+func (tenantImpl *Tenant) checkQuotaPressures() {
+	tenantInfoList, err := tenantImpl.k8sCache.ListTenants("")
+	if err != nil {
+		logrus.Errorf("failed to list tenants : %s", err.Error())
+		return
+	}
+	for _, tenantInfo := range tenantInfoList.Items {
+		if len(tenantInfo.QuotaPressures) == 0 {
+			continue
+		}
+		tenantImpl.emitQuotaPressureEvent(tenantInfo.TenantName, tenantInfo.QuotaPressures)
+	}
+}
+
+func (tenantImpl *Tenant) emitQuotaPressureEvent(tenantName string, quotaPressures []*tenant.TenantQuotaPressure) {
+	tenantImpl.quotaPressureMutex.Lock()
+	lastEmitted, ok := tenantImpl.quotaPressureEmitted[tenantName]
+	if ok && time.Since(lastEmitted) < quotaPressureEventInterval {
+		tenantImpl.quotaPressureMutex.Unlock()
+		return
+	}
+	tenantImpl.quotaPressureEmitted[tenantName] = time.Now()
+	tenantImpl.quotaPressureMutex.Unlock()
+
+	messages := []string{}
+	for _, quotaPressure := range quotaPressures {
+		messages = append(messages, fmt.Sprintf("%s in quota %s reached %.0f%% of its hard limit",
+			quotaPressure.ResourceName, quotaPressure.QuotaName, quotaPressure.Ratio*100))
+	}
+	err := tenantImpl.k8sOperator.CreateNamespaceEvent(tenantName, QuotaPressureEventReason, corev1.EventTypeWarning, strings.Join(messages, ", "))
+	if err != nil {
+		logrus.Errorf("failed to create quota pressure event on tenant %s : %s", tenantName, err.Error())
+	}
+}
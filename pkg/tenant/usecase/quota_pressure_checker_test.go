@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
+	"WarpCloud/walm/pkg/models/tenant"
+	projectMocks "WarpCloud/walm/pkg/project/mocks"
+	"WarpCloud/walm/pkg/release/mocks"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func TestTenant_CheckQuotaPressures(t *testing.T) {
+	var mockK8sCache *k8sMocks.Cache
+	var mockK8sOperator *k8sMocks.Operator
+	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
+	var mockTenantManager *Tenant
+
+	refreshMocks := func() {
+		mockK8sCache = &k8sMocks.Cache{}
+		mockK8sOperator = &k8sMocks.Operator{}
+		mockReleaseUseCase = &mocks.UseCase{}
+		mockProjectUseCase = &projectMocks.UseCase{}
+
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
+	}
+	pressuredTenantInfoList := &tenant.TenantInfoList{
+		Items: []*tenant.TenantInfo{
+			{
+				TenantName: "test-tenant",
+				QuotaPressures: []*tenant.TenantQuotaPressure{
+					{
+						QuotaName:    "test-rq",
+						ResourceName: "pods",
+						Hard:         100,
+						Used:         90,
+						Ratio:        0.9,
+					},
+				},
+			},
+			{
+				TenantName: "idle-tenant",
+			},
+		},
+	}
+	tests := []struct {
+		initMock func()
+		checks   int
+	}{
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("ListTenants", "").Return(nil, errors.New("failed"))
+			},
+			checks: 1,
+		},
+		{
+			// the event is rate limited : two consecutive checks emit only once
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("ListTenants", "").Return(pressuredTenantInfoList, nil)
+				mockK8sOperator.On("CreateNamespaceEvent", "test-tenant", QuotaPressureEventReason, corev1.EventTypeWarning, mock.Anything).Return(nil).Once()
+			},
+			checks: 2,
+		},
+	}
+	for _, test := range tests {
+		test.initMock()
+		for i := 0; i < test.checks; i++ {
+			mockTenantManager.checkQuotaPressures()
+		}
+		mockK8sCache.AssertExpectations(t)
+		mockK8sOperator.AssertExpectations(t)
+	}
+}
@@ -1,67 +1,261 @@
 package usecase
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/models/tenant"
 	"github.com/sirupsen/logrus"
 	"WarpCloud/walm/pkg/k8s"
 	k8sModel "WarpCloud/walm/pkg/models/k8s"
 	errorModel "WarpCloud/walm/pkg/models/error"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"WarpCloud/walm/pkg/project"
 	"WarpCloud/walm/pkg/release"
+	releaseModel "WarpCloud/walm/pkg/models/release"
+	"WarpCloud/walm/pkg/setting"
 )
 
 type Tenant struct {
 	k8sCache       k8s.Cache
 	k8sOperator    k8s.Operator
 	releaseUseCase release.UseCase
+	projectUseCase project.UseCase
+
+	quotaPressureMutex   sync.Mutex
+	quotaPressureEmitted map[string]time.Time
 }
 
-func (tenantImpl *Tenant) CreateTenant(tenantName string, tenantParams *tenant.TenantParams) error {
-	_, err := tenantImpl.GetTenant(tenantName)
+// CreateTenant is idempotent : an existing walm managed namespace is
+// reconciled to the params instead of failing, taking over a namespace
+// created outside walm requires the adopt flag. In dry run mode the objects
+// that would be applied are returned without touching the cluster.
+func (tenantImpl *Tenant) CreateTenant(tenantName string, tenantParams *tenant.TenantParams, adopt bool, dryRun bool) (*tenant.CreateTenantResult, error) {
+	tenantInfo, err := tenantImpl.k8sCache.GetTenant(tenantName)
+	if err != nil && !errorModel.IsNotFoundError(err) {
+		logrus.Errorf("failed to get tenant : %s", err.Error())
+		return nil, err
+	}
+	exists := err == nil
+	if exists && !adopt && (tenantInfo == nil || !tenantInfo.MultiTenant) {
+		return nil, fmt.Errorf("namespace %s already exists but is not managed by walm : set adopt to take it over", tenantName)
+	}
+
+	tenantParams = mergeTenantTemplate(tenantParams)
+	result := &tenant.CreateTenantResult{
+		Updated:   exists,
+		Namespace: buildSyncedNamespace(tenantName, tenantInfo, tenantParams),
+	}
+	for _, tenantQuota := range tenantParams.TenantQuotas {
+		result.ResourceQuotas = append(result.ResourceQuotas, buildResourceQuota(tenantName, tenantQuota))
+	}
+	for _, tenantLimitRange := range tenantParams.TenantLimitRanges {
+		result.LimitRanges = append(result.LimitRanges, buildLimitRange(tenantName, tenantLimitRange))
+	}
+	if !exists {
+		result.LimitRanges = append(result.LimitRanges, getDefaultLimitRange(tenantName))
+	}
+	if dryRun {
+		logrus.Infof("succeed to dry run create tenant %s", tenantName)
+		return result, nil
+	}
+
+	if exists {
+		err = tenantImpl.UpdateTenant(tenantName, tenantParams)
+		if err != nil {
+			return nil, err
+		}
+		logrus.Infof("succeed to reconcile existing tenant %s", tenantName)
+		return result, nil
+	}
+
+	err = tenantImpl.k8sOperator.CreateNamespace(result.Namespace)
 	if err != nil {
-		if errorModel.IsNotFoundError(err) {
-			namespace := buildNamespace(tenantParams, tenantName)
-			err = tenantImpl.k8sOperator.CreateNamespace(namespace)
-			if err != nil {
-				logrus.Errorf("failed to create namespace %s : %s", tenantName, err.Error())
-				return err
-			}
+		logrus.Errorf("failed to create namespace %s : %s", tenantName, err.Error())
+		return nil, err
+	}
 
-			err = tenantImpl.doCreateTenant(tenantName, tenantParams)
+	err = tenantImpl.doCreateTenant(tenantName, tenantParams)
+	if err != nil {
+		// rollback
+		err1 := tenantImpl.k8sOperator.DeleteNamespace(tenantName)
+		if err1 != nil {
+			logrus.Warnf("failed to rollback deleting namespace %s", err1.Error())
+		}
+		return nil, err
+	}
+	logrus.Infof("succeed to create tenant %s", tenantName)
+	return result, nil
+}
+
+// mergeTenantTemplate merges the configured default tenant params underneath
+// the caller supplied params : caller values win, defaults fill gaps. The
+// template version is recorded in the tenant annotations.
+func mergeTenantTemplate(tenantParams *tenant.TenantParams) *tenant.TenantParams {
+	tenantConfig := setting.Config.TenantConfig
+	if tenantConfig == nil || tenantConfig.DefaultTenantParams == nil {
+		return tenantParams
+	}
+	if tenantParams == nil {
+		tenantParams = &tenant.TenantParams{}
+	}
+	defaultTenantParams := tenantConfig.DefaultTenantParams
+	tenantParams.TenantLabels = mergeDefaults(tenantParams.TenantLabels, defaultTenantParams.TenantLabels)
+	tenantParams.TenantAnnotations = mergeDefaults(tenantParams.TenantAnnotations, defaultTenantParams.TenantAnnotations)
+	if len(tenantParams.TenantQuotas) == 0 {
+		tenantParams.TenantQuotas = defaultTenantParams.TenantQuotas
+	}
+	if len(tenantParams.TenantLimitRanges) == 0 {
+		tenantParams.TenantLimitRanges = defaultTenantParams.TenantLimitRanges
+	}
+	if len(tenantParams.TenantMembers) == 0 {
+		tenantParams.TenantMembers = defaultTenantParams.TenantMembers
+	}
+	if len(tenantParams.StorageClassWhitelist) == 0 {
+		tenantParams.StorageClassWhitelist = defaultTenantParams.StorageClassWhitelist
+	}
+	if tenantConfig.TemplateVersion != "" {
+		if tenantParams.TenantAnnotations == nil {
+			tenantParams.TenantAnnotations = map[string]string{}
+		}
+		tenantParams.TenantAnnotations[tenant.TemplateVersionAnnotationKey] = tenantConfig.TemplateVersion
+	}
+	return tenantParams
+}
+
+func mergeDefaults(values map[string]string, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return values
+	}
+	merged := map[string]string{}
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range values {
+		merged[key] = value
+	}
+	return merged
+}
+
+type managedNamespaceKeys struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// buildSyncedNamespace merges the declared labels and annotations into the
+// namespace's current metadata : keys walm set earlier but no longer declared
+// are dropped, keys owned by other systems are left alone. The keys walm
+// manages are tracked in a bookkeeping annotation.
+func buildSyncedNamespace(tenantName string, tenantInfo *tenant.TenantInfo, tenantParams *tenant.TenantParams) *k8sModel.Namespace {
+	labels := map[string]string{}
+	annotations := map[string]string{}
+	previouslyManaged := managedNamespaceKeys{}
+	if tenantInfo != nil {
+		for key, value := range tenantInfo.TenantLabels {
+			labels[key] = value
+		}
+		for key, value := range tenantInfo.TenantAnnotitions {
+			annotations[key] = value
+		}
+		if managedKeysStr, ok := annotations[tenant.ManagedKeysAnnotationKey]; ok {
+			err := json.Unmarshal([]byte(managedKeysStr), &previouslyManaged)
 			if err != nil {
-				// rollback
-				err1 := tenantImpl.k8sOperator.DeleteNamespace(tenantName)
-				if err1 != nil {
-					logrus.Warnf("failed to rollback deleting namespace %s", err1.Error())
-				}
-				return err
+				logrus.Warnf("failed to parse managed keys annotation of tenant %s : %s", tenantName, err.Error())
 			}
-			logrus.Infof("succeed to create tenant %s", tenantName)
-			return nil
 		}
-		logrus.Errorf("failed to get tenant : %s", err.Error())
-		return err
+	}
 
+	for _, key := range previouslyManaged.Labels {
+		if _, ok := tenantParams.TenantLabels[key]; !ok {
+			delete(labels, key)
+		}
+	}
+	for _, key := range previouslyManaged.Annotations {
+		if _, ok := tenantParams.TenantAnnotations[key]; !ok {
+			delete(annotations, key)
+		}
 	}
-	logrus.Warnf("namespace %s exists", tenantName)
-	return nil
-}
 
-func buildNamespace(tenantParams *tenant.TenantParams, tenantName string) *k8sModel.Namespace {
-	namespace := &k8sModel.Namespace{
+	managed := managedNamespaceKeys{}
+	for key, value := range tenantParams.TenantLabels {
+		labels[key] = value
+		managed.Labels = append(managed.Labels, key)
+	}
+	for key, value := range tenantParams.TenantAnnotations {
+		annotations[key] = value
+		managed.Annotations = append(managed.Annotations, key)
+	}
+	sort.Strings(managed.Labels)
+	sort.Strings(managed.Annotations)
+	managedKeysStr, err := json.Marshal(managed)
+	if err == nil {
+		annotations[tenant.ManagedKeysAnnotationKey] = string(managedKeysStr)
+	}
+
+	// the admission webhook enforces the whitelist from this annotation,
+	// an absent annotation means no restriction
+	if len(tenantParams.StorageClassWhitelist) > 0 {
+		annotations[tenant.StorageClassWhitelistAnnotationKey] = strings.Join(tenantParams.StorageClassWhitelist, ",")
+	} else {
+		delete(annotations, tenant.StorageClassWhitelistAnnotationKey)
+	}
+
+	// the release task runners read the per tenant concurrency budget from
+	// this annotation, an absent annotation keeps the global budget
+	if tenantParams.MaxConcurrentReleaseTasks > 0 {
+		annotations[tenant.MaxConcurrentReleaseTasksAnnotationKey] = strconv.FormatInt(tenantParams.MaxConcurrentReleaseTasks, 10)
+	} else {
+		delete(annotations, tenant.MaxConcurrentReleaseTasksAnnotationKey)
+	}
+
+	// the readiness computation checks the declared objects recorded here
+	// against the cluster, an absent annotation leaves readiness to the
+	// namespace phase alone
+	declared := buildDeclaredTenantObjects(tenantName, tenantParams)
+	if len(declared.ResourceQuotas) > 0 || len(declared.LimitRanges) > 0 {
+		declaredStr, err := json.Marshal(declared)
+		if err == nil {
+			annotations[tenant.DeclaredObjectsAnnotationKey] = string(declaredStr)
+		}
+	} else {
+		delete(annotations, tenant.DeclaredObjectsAnnotationKey)
+	}
+
+	labels[tenant.MultiTenantLabelKey] = fmt.Sprintf("tenant-tiller-%s", tenantName)
+	return &k8sModel.Namespace{
 		Meta: k8sModel.Meta{
 			Namespace: tenantName,
 			Name:      tenantName,
 		},
-		Labels:      tenantParams.TenantLabels,
-		Annotations: tenantParams.TenantAnnotations,
+		Labels:      labels,
+		Annotations: annotations,
 	}
-	if namespace.Labels == nil {
-		namespace.Labels = map[string]string{}
+}
+
+func buildDeclaredTenantObjects(tenantName string, tenantParams *tenant.TenantParams) tenant.DeclaredTenantObjects {
+	declared := tenant.DeclaredTenantObjects{}
+	for _, tenantQuota := range tenantParams.TenantQuotas {
+		resourceLimits := map[string]string{}
+		for resourceName, value := range buildResourceQuota(tenantName, tenantQuota).ResourceLimits {
+			if value != "" {
+				resourceLimits[string(resourceName)] = value
+			}
+		}
+		if declared.ResourceQuotas == nil {
+			declared.ResourceQuotas = map[string]map[string]string{}
+		}
+		declared.ResourceQuotas[tenantQuota.QuotaName] = resourceLimits
+	}
+	for _, tenantLimitRange := range tenantParams.TenantLimitRanges {
+		declared.LimitRanges = append(declared.LimitRanges, tenantLimitRange.LimitRangeName)
 	}
-	namespace.Labels[tenant.MultiTenantLabelKey] = fmt.Sprintf("tenant-tiller-%s", tenantName)
-	return namespace
+	sort.Strings(declared.LimitRanges)
+	return declared
 }
 
 func (tenantImpl *Tenant) doCreateTenant(tenantName string, tenantParams *tenant.TenantParams) error {
@@ -73,15 +267,82 @@ func (tenantImpl *Tenant) doCreateTenant(tenantName string, tenantParams *tenant
 		}
 	}
 
+	for _, tenantLimitRange := range tenantParams.TenantLimitRanges {
+		err := tenantImpl.k8sOperator.CreateLimitRange(buildLimitRange(tenantName, tenantLimitRange))
+		if err != nil {
+			logrus.Errorf("failed to create limitrange : %s", err.Error())
+			return err
+		}
+	}
+
 	err := tenantImpl.k8sOperator.CreateLimitRange(getDefaultLimitRange(tenantName))
 	if err != nil {
 		logrus.Errorf("failed to create limitrange : %s", err.Error())
 		return err
 	}
 
+	for _, tenantMember := range tenantParams.TenantMembers {
+		roleBinding, err := buildMemberRoleBinding(tenantName, tenantMember)
+		if err != nil {
+			return err
+		}
+		err = tenantImpl.k8sOperator.CreateOrUpdateRoleBinding(roleBinding)
+		if err != nil {
+			logrus.Errorf("failed to create role binding : %s", err.Error())
+			return err
+		}
+	}
+
 	return nil
 }
 
+func buildMemberRoleBinding(tenantName string, tenantMember *tenant.TenantMemberParams) (*k8sModel.RoleBinding, error) {
+	if tenantMember.Kind != tenant.MemberKindUser && tenantMember.Kind != tenant.MemberKindGroup {
+		return nil, fmt.Errorf("invalid tenant member kind %s : must be User or Group", tenantMember.Kind)
+	}
+	clusterRoleName, ok := tenant.TenantRoleClusterRoles[tenantMember.Role]
+	if !ok {
+		return nil, fmt.Errorf("invalid tenant member role %s : must be viewer, editor or admin", tenantMember.Role)
+	}
+	return &k8sModel.RoleBinding{
+		Meta: k8sModel.Meta{
+			Namespace: tenantName,
+			Name:      buildMemberRoleBindingName(tenantMember.Kind, tenantMember.Name),
+		},
+		Labels: map[string]string{
+			tenant.MemberLabelKey: "true",
+		},
+		SubjectKind:     tenantMember.Kind,
+		SubjectName:     tenantMember.Name,
+		ClusterRoleName: clusterRoleName,
+	}, nil
+}
+
+func buildMemberRoleBindingName(kind string, name string) string {
+	return fmt.Sprintf("walm-member-%s-%s", strings.ToLower(kind), name)
+}
+
+func buildLimitRange(tenantName string, tenantLimitRange *tenant.TenantLimitRangeParams) *k8sModel.LimitRange {
+	return &k8sModel.LimitRange{
+		Meta: k8sModel.Meta{
+			Namespace: tenantName,
+			Name:      tenantLimitRange.LimitRangeName,
+		},
+		DefaultLimit:   buildLimitRangeLimits(tenantLimitRange.DefaultLimit),
+		DefaultRequest: buildLimitRangeLimits(tenantLimitRange.DefaultRequest),
+		Max:            buildLimitRangeLimits(tenantLimitRange.Max),
+		Min:            buildLimitRangeLimits(tenantLimitRange.Min),
+	}
+}
+
+func buildLimitRangeLimits(limits map[string]string) map[k8sModel.ResourceName]string {
+	limitRangeLimits := map[k8sModel.ResourceName]string{}
+	for key, value := range limits {
+		limitRangeLimits[k8sModel.ResourceName(key)] = value
+	}
+	return limitRangeLimits
+}
+
 const (
 	limitRangeDefaultMem  = "128Mi"
 	limitRangeDefaultCpu  = "0.1"
@@ -127,6 +388,22 @@ func buildResourceQuota(tenantName string, tenantQuota *tenant.TenantQuotaParams
 			k8sModel.ResourceRequestsStorage: tenantQuota.Hard.RequestsStorage,
 		},
 	}
+	// the optional typed resources only land in the quota when the caller sets them
+	optionalLimits := map[k8sModel.ResourceName]string{
+		k8sModel.ResourceRequestsEphemeralStorage: tenantQuota.Hard.RequestsEphemeralStorage,
+		k8sModel.ResourceServices:                 tenantQuota.Hard.Services,
+		k8sModel.ResourceServicesNodePorts:        tenantQuota.Hard.ServicesNodePorts,
+		k8sModel.ResourcePersistentVolumeClaims:   tenantQuota.Hard.PersistentVolumeClaims,
+		k8sModel.ResourceCountConfigMaps:          tenantQuota.Hard.ConfigMaps,
+	}
+	for resourceName, value := range optionalLimits {
+		if value != "" {
+			resourceQuota.ResourceLimits[resourceName] = value
+		}
+	}
+	for resourceName, value := range tenantQuota.Hard.ExtendedResources {
+		resourceQuota.ResourceLimits[k8sModel.ResourceName(resourceName)] = value
+	}
 	return resourceQuota
 }
 
@@ -141,14 +418,111 @@ func (tenantImpl *Tenant) updateResourceQuota(tenantName string, tenantQuota *te
 }
 
 func (tenantImpl *Tenant) GetTenant(tenantName string) (*tenant.TenantInfo, error) {
-	return tenantImpl.k8sCache.GetTenant(tenantName)
+	tenantInfo, err := tenantImpl.k8sCache.GetTenant(tenantName)
+	if err != nil {
+		return nil, err
+	}
+	tenantImpl.fillReservedQuotas(tenantInfo)
+	return tenantInfo, nil
+}
+
+// fillReservedQuotas adds what the releases in the namespace declared as
+// resource requests, admitted or not, so capacity planning can see beyond
+// the used figures of the resource quota status. Best effort : a failure
+// only marks the reservation data incomplete.
+func (tenantImpl *Tenant) fillReservedQuotas(tenantInfo *tenant.TenantInfo) {
+	if len(tenantInfo.TenantQuotas) == 0 {
+		return
+	}
+	releasesResources, incompleteReleases, err := tenantImpl.releaseUseCase.ComputeReleasesResources(tenantInfo.TenantName)
+	if err != nil {
+		logrus.Warnf("failed to compute releases resources of tenant %s : %s", tenantInfo.TenantName, err.Error())
+		tenantInfo.ReservedIncomplete = true
+		return
+	}
+	if len(incompleteReleases) > 0 {
+		logrus.Warnf("reservation data of tenant %s is incomplete : failed to compute resources of releases [%s]",
+			tenantInfo.TenantName, strings.Join(incompleteReleases, ","))
+		tenantInfo.ReservedIncomplete = true
+	}
+
+	reserved := buildReservedQuotaInfo(releasesResources)
+	for _, unifyUnitTenantQuota := range tenantInfo.UnifyUnitTenantQuotas {
+		unifyUnitTenantQuota.Reserved = reserved
+	}
+	reservedStr := &tenant.TenantQuotaInfo{
+		LimitCpu:        fmt.Sprintf("%v", reserved.LimitCpu),
+		LimitMemory:     fmt.Sprintf("%dMi", reserved.LimitMemory),
+		RequestsCPU:     fmt.Sprintf("%v", reserved.RequestsCPU),
+		RequestsMemory:  fmt.Sprintf("%dMi", reserved.RequestsMemory),
+		RequestsStorage: fmt.Sprintf("%dGi", reserved.RequestsStorage),
+		Pods:            fmt.Sprintf("%d", reserved.Pods),
+	}
+	for _, tenantQuota := range tenantInfo.TenantQuotas {
+		tenantQuota.Reserved = reservedStr
+	}
+}
+
+// buildReservedQuotaInfo sums the pod requests and limits declared by the
+// release workloads, scaled by their replicas, plus the pvc capacities.
+// Daemon sets count once : their real footprint depends on the node count.
+func buildReservedQuotaInfo(releasesResources []*releaseModel.ReleaseResources) *tenant.UnifyUnitTenantQuotaInfo {
+	reserved := &tenant.UnifyUnitTenantQuotaInfo{}
+	for _, releaseResources := range releasesResources {
+		for _, deployment := range releaseResources.Deployments {
+			addReservedWorkload(reserved, int64(deployment.Replicas), deployment.PodRequests, deployment.PodLimits)
+		}
+		for _, statefulSet := range releaseResources.StatefulSets {
+			addReservedWorkload(reserved, int64(statefulSet.Replicas), statefulSet.PodRequests, statefulSet.PodLimits)
+		}
+		for _, daemonSet := range releaseResources.DaemonSets {
+			addReservedWorkload(reserved, 1, daemonSet.PodRequests, daemonSet.PodLimits)
+		}
+		for _, job := range releaseResources.Jobs {
+			addReservedWorkload(reserved, int64(job.Parallelism), job.PodRequests, job.PodLimits)
+		}
+		for _, pvc := range releaseResources.Pvcs {
+			if pvc.Type == releaseModel.PvcPodStorageType {
+				reserved.RequestsStorage += pvc.Size
+			}
+		}
+	}
+	return reserved
 }
 
-func (tenantImpl *Tenant) ListTenants() (*tenant.TenantInfoList, error) {
-	return tenantImpl.k8sCache.ListTenants("")
+func addReservedWorkload(reserved *tenant.UnifyUnitTenantQuotaInfo, replicas int64, podRequests *releaseModel.ReleaseResourcePod, podLimits *releaseModel.ReleaseResourcePod) {
+	reserved.Pods += replicas
+	if podRequests != nil {
+		reserved.RequestsCPU += float64(replicas) * podRequests.Cpu
+		reserved.RequestsMemory += replicas * podRequests.Memory
+		for _, storage := range podRequests.Storage {
+			if storage.Type == releaseModel.PvcPodStorageType {
+				reserved.RequestsStorage += replicas * storage.Size
+			}
+		}
+	}
+	if podLimits != nil {
+		reserved.LimitCpu += float64(replicas) * podLimits.Cpu
+		reserved.LimitMemory += replicas * podLimits.Memory
+	}
 }
 
-func (tenantImpl *Tenant) DeleteTenant(tenantName string) error {
+func (tenantImpl *Tenant) ListTenants(labelSelectorStr string, summary bool) (*tenant.TenantInfoList, error) {
+	if summary {
+		return tenantImpl.k8sCache.ListTenantsSummary(labelSelectorStr)
+	}
+	return tenantImpl.k8sCache.ListTenants(labelSelectorStr)
+}
+
+func (tenantImpl *Tenant) ListTenantServices(tenantName string) (*tenant.TenantServiceInfoList, error) {
+	_, err := tenantImpl.k8sCache.GetTenant(tenantName)
+	if err != nil {
+		return nil, err
+	}
+	return tenantImpl.k8sCache.ListTenantServices(tenantName)
+}
+
+func (tenantImpl *Tenant) DeleteTenant(tenantName string, force bool) error {
 	_, err := tenantImpl.k8sCache.GetTenant(tenantName)
 	if err != nil {
 		if errorModel.IsNotFoundError(err) {
@@ -158,18 +532,55 @@ func (tenantImpl *Tenant) DeleteTenant(tenantName string) error {
 		}
 	}
 
+	projects, err := tenantImpl.projectUseCase.ListProjects(tenantName, false)
+	if err != nil {
+		logrus.Errorf("failed to get projects in tenant %s : %s", tenantName, err.Error())
+		return err
+	}
+	projectNames := []string{}
+	if projects != nil {
+		for _, projectInfo := range projects.Items {
+			projectNames = append(projectNames, projectInfo.Name)
+		}
+	}
+
 	releases, err := tenantImpl.releaseUseCase.ListReleases(tenantName)
 	if err != nil {
 		logrus.Errorf("failed to get releases in tenant %s : %s", tenantName, err.Error())
 		return err
 	}
 
+	if !force && (len(projectNames) > 0 || len(releases) > 0) {
+		releaseNames := []string{}
+		for _, releaseInfo := range releases {
+			releaseNames = append(releaseNames, releaseInfo.Name)
+		}
+		return fmt.Errorf("failed to delete tenant %s : projects [%s] and releases [%s] still exist, delete them first or use force",
+			tenantName, strings.Join(projectNames, ","), strings.Join(releaseNames, ","))
+	}
+
+	for _, projectName := range projectNames {
+		_, err = tenantImpl.projectUseCase.DeleteProject(context.Background(), tenantName, projectName, false, 0, false, false, "", "")
+		if err != nil {
+			logrus.Errorf("failed to delete project %s under tenant %s : %s", projectName, tenantName, err.Error())
+			return err
+		}
+	}
+
+	if len(projectNames) > 0 {
+		releases, err = tenantImpl.releaseUseCase.ListReleases(tenantName)
+		if err != nil {
+			logrus.Errorf("failed to get releases in tenant %s : %s", tenantName, err.Error())
+			return err
+		}
+	}
+
 	var wg sync.WaitGroup
 	for _, release := range releases {
 		wg.Add(1)
 		go func(releaseName string) {
 			defer wg.Done()
-			err1 := tenantImpl.releaseUseCase.DeleteReleaseWithRetry(tenantName, releaseName, false, false, 0)
+			err1 := tenantImpl.releaseUseCase.DeleteReleaseWithRetry(context.Background(), tenantName, releaseName, false, false, 0)
 			if err1 != nil {
 				err = fmt.Errorf("failed to delete release %s under tenant %s : %s", releaseName, tenantName, err1.Error())
 				logrus.Error(err.Error())
@@ -193,13 +604,13 @@ func (tenantImpl *Tenant) DeleteTenant(tenantName string) error {
 }
 
 func (tenantImpl *Tenant) UpdateTenant(tenantName string, tenantParams *tenant.TenantParams) error {
-	_, err := tenantImpl.k8sCache.GetTenant(tenantName)
+	tenantInfo, err := tenantImpl.k8sCache.GetTenant(tenantName)
 	if err != nil {
 		logrus.Errorf("failed to get tenantInfo : %s", err.Error())
 		return err
 	}
 
-	namespace := buildNamespace(tenantParams, tenantName)
+	namespace := buildSyncedNamespace(tenantName, tenantInfo, tenantParams)
 	err = tenantImpl.k8sOperator.UpdateNamespace(namespace)
 	if err != nil {
 		logrus.Errorf("failed to update namespace : %s", err.Error())
@@ -213,14 +624,138 @@ func (tenantImpl *Tenant) UpdateTenant(tenantName string, tenantParams *tenant.T
 			return err
 		}
 	}
+
+	err = tenantImpl.reconcileLimitRanges(tenantName, tenantInfo, tenantParams)
+	if err != nil {
+		logrus.Errorf("failed to reconcile limit ranges : %s", err.Error())
+		return err
+	}
+
+	err = tenantImpl.reconcileMembers(tenantName, tenantInfo, tenantParams)
+	if err != nil {
+		logrus.Errorf("failed to reconcile members : %s", err.Error())
+		return err
+	}
 	logrus.Infof("succeed to update tenant %s", tenantName)
 	return nil
 }
 
-func NewTenant(k8sCache k8s.Cache, k8sOperator k8s.Operator, releaseUseCase release.UseCase) *Tenant {
+func (tenantImpl *Tenant) reconcileLimitRanges(tenantName string, tenantInfo *tenant.TenantInfo, tenantParams *tenant.TenantParams) error {
+	declaredLimitRanges := map[string]bool{}
+	for _, tenantLimitRange := range tenantParams.TenantLimitRanges {
+		declaredLimitRanges[tenantLimitRange.LimitRangeName] = true
+		err := tenantImpl.k8sOperator.CreateOrUpdateLimitRange(buildLimitRange(tenantName, tenantLimitRange))
+		if err != nil {
+			logrus.Errorf("failed to create or update limit range %s : %s", tenantLimitRange.LimitRangeName, err.Error())
+			return err
+		}
+	}
+
+	if tenantInfo == nil {
+		return nil
+	}
+	for _, tenantLimitRange := range tenantInfo.TenantLimitRanges {
+		if declaredLimitRanges[tenantLimitRange.LimitRangeName] || tenantLimitRange.LimitRangeName == LimitRangeDefaultName {
+			continue
+		}
+		err := tenantImpl.k8sOperator.DeleteLimitRange(tenantName, tenantLimitRange.LimitRangeName)
+		if err != nil {
+			logrus.Errorf("failed to delete limit range %s : %s", tenantLimitRange.LimitRangeName, err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileMembers only touches the walm managed role bindings : the declared
+// members are applied idempotently and the stale ones are removed, bindings
+// created outside walm are left alone.
+func (tenantImpl *Tenant) reconcileMembers(tenantName string, tenantInfo *tenant.TenantInfo, tenantParams *tenant.TenantParams) error {
+	declaredMembers := map[string]bool{}
+	for _, tenantMember := range tenantParams.TenantMembers {
+		roleBinding, err := buildMemberRoleBinding(tenantName, tenantMember)
+		if err != nil {
+			return err
+		}
+		declaredMembers[roleBinding.Name] = true
+		err = tenantImpl.k8sOperator.CreateOrUpdateRoleBinding(roleBinding)
+		if err != nil {
+			logrus.Errorf("failed to create or update role binding %s : %s", roleBinding.Name, err.Error())
+			return err
+		}
+	}
+
+	if tenantInfo == nil {
+		return nil
+	}
+	for _, tenantMember := range tenantInfo.TenantMembers {
+		roleBindingName := buildMemberRoleBindingName(tenantMember.Kind, tenantMember.Name)
+		if declaredMembers[roleBindingName] {
+			continue
+		}
+		err := tenantImpl.k8sOperator.DeleteRoleBinding(tenantName, roleBindingName)
+		if err != nil {
+			logrus.Errorf("failed to delete role binding %s : %s", roleBindingName, err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// ReapplyTenantTemplate re-merges the current default tenant template under the
+// tenant's existing settings. Template changes never propagate to existing
+// tenants except through this explicit call.
+func (tenantImpl *Tenant) ReapplyTenantTemplate(tenantName string) error {
+	tenantInfo, err := tenantImpl.k8sCache.GetTenant(tenantName)
+	if err != nil {
+		logrus.Errorf("failed to get tenant %s : %s", tenantName, err.Error())
+		return err
+	}
+	tenantParams := &tenant.TenantParams{}
+	if tenantInfo != nil {
+		// only the keys walm manages are declared again, the rest of the
+		// namespace metadata is left to the sync on update
+		previouslyManaged := managedNamespaceKeys{}
+		if managedKeysStr, ok := tenantInfo.TenantAnnotitions[tenant.ManagedKeysAnnotationKey]; ok {
+			err = json.Unmarshal([]byte(managedKeysStr), &previouslyManaged)
+			if err != nil {
+				logrus.Warnf("failed to parse managed keys annotation of tenant %s : %s", tenantName, err.Error())
+			}
+		}
+		for _, key := range previouslyManaged.Labels {
+			if value, ok := tenantInfo.TenantLabels[key]; ok {
+				if tenantParams.TenantLabels == nil {
+					tenantParams.TenantLabels = map[string]string{}
+				}
+				tenantParams.TenantLabels[key] = value
+			}
+		}
+		for _, key := range previouslyManaged.Annotations {
+			if value, ok := tenantInfo.TenantAnnotitions[key]; ok {
+				if tenantParams.TenantAnnotations == nil {
+					tenantParams.TenantAnnotations = map[string]string{}
+				}
+				tenantParams.TenantAnnotations[key] = value
+			}
+		}
+		for _, tenantMember := range tenantInfo.TenantMembers {
+			tenantParams.TenantMembers = append(tenantParams.TenantMembers, &tenant.TenantMemberParams{
+				Kind: tenantMember.Kind,
+				Name: tenantMember.Name,
+				Role: tenantMember.Role,
+			})
+		}
+		tenantParams.StorageClassWhitelist = tenantInfo.StorageClassWhitelist
+	}
+	return tenantImpl.UpdateTenant(tenantName, mergeTenantTemplate(tenantParams))
+}
+
+func NewTenant(k8sCache k8s.Cache, k8sOperator k8s.Operator, releaseUseCase release.UseCase, projectUseCase project.UseCase) *Tenant {
 	return &Tenant{
-		k8sCache:       k8sCache,
-		k8sOperator:    k8sOperator,
-		releaseUseCase: releaseUseCase,
+		k8sCache:             k8sCache,
+		k8sOperator:          k8sOperator,
+		releaseUseCase:       releaseUseCase,
+		projectUseCase:       projectUseCase,
+		quotaPressureEmitted: map[string]time.Time{},
 	}
 }
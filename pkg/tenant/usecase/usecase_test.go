@@ -4,9 +4,13 @@ import (
 	k8sMocks "WarpCloud/walm/pkg/k8s/mocks"
 	errorModel "WarpCloud/walm/pkg/models/error"
 	"WarpCloud/walm/pkg/models/release"
+	projectModel "WarpCloud/walm/pkg/models/project"
 	"WarpCloud/walm/pkg/models/tenant"
 
+	k8sModel "WarpCloud/walm/pkg/models/k8s"
+	projectMocks "WarpCloud/walm/pkg/project/mocks"
 	"WarpCloud/walm/pkg/release/mocks"
+	"WarpCloud/walm/pkg/setting"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -18,25 +22,31 @@ func TestTenant_CreateTenant(t *testing.T) {
 	var mockK8sCache *k8sMocks.Cache
 	var mockK8sOperator *k8sMocks.Operator
 	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
 	var mockTenantManager *Tenant
 
 	refreshMocks := func() {
 		mockK8sCache = &k8sMocks.Cache{}
 		mockK8sOperator = &k8sMocks.Operator{}
 		mockReleaseUseCase = &mocks.UseCase{}
-		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase)
+		mockProjectUseCase = &projectMocks.UseCase{}
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
 	}
 
 	tests := []struct {
 		initMock func()
+		adopt    bool
+		dryRun   bool
+		updated  bool
 		err      error
 	}{
 		{
+			// the namespace exists but is not managed by walm, adopt is required
 			initMock: func() {
 				refreshMocks()
-				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockK8sCache.On("GetTenant", mock.Anything).Return(&tenant.TenantInfo{}, nil)
 			},
-			err: nil,
+			err: errors.New("failed"),
 		},
 		{
 			initMock: func() {
@@ -74,6 +84,38 @@ func TestTenant_CreateTenant(t *testing.T) {
 			},
 			err: errors.New("failed"),
 		},
+		{
+			// the namespace is walm managed : it is reconciled instead of failing
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(&tenant.TenantInfo{MultiTenant: true}, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateResourceQuota", mock.Anything).Return(nil)
+			},
+			updated: true,
+			err:     nil,
+		},
+		{
+			// adopting a namespace created outside walm
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(&tenant.TenantInfo{}, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateResourceQuota", mock.Anything).Return(nil)
+			},
+			adopt:   true,
+			updated: true,
+			err:     nil,
+		},
+		{
+			// dry run returns the objects without touching the cluster
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, errorModel.NotFoundError{})
+			},
+			dryRun: true,
+			err:    nil,
+		},
 	}
 	testTenantParams := &tenant.TenantParams{
 		TenantLabels:      map[string]string{"labels1": "labels1"},
@@ -89,8 +131,12 @@ func TestTenant_CreateTenant(t *testing.T) {
 	}
 	for _, test := range tests {
 		test.initMock()
-		err := mockTenantManager.CreateTenant("test-tenant", testTenantParams)
+		result, err := mockTenantManager.CreateTenant("test-tenant", testTenantParams, test.adopt, test.dryRun)
 		assert.IsType(t, test.err, err)
+		if err == nil {
+			assert.Equal(t, test.updated, result.Updated)
+			assert.Equal(t, 1, len(result.ResourceQuotas))
+		}
 		mockK8sCache.AssertExpectations(t)
 		mockK8sOperator.AssertExpectations(t)
 		mockReleaseUseCase.AssertExpectations(t)
@@ -101,13 +147,15 @@ func TestTenant_GetTenant(t *testing.T) {
 	var mockK8sCache *k8sMocks.Cache
 	var mockK8sOperator *k8sMocks.Operator
 	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
 	var mockTenantManager *Tenant
 
 	refreshMocks := func() {
 		mockK8sCache = &k8sMocks.Cache{}
 		mockK8sOperator = &k8sMocks.Operator{}
 		mockReleaseUseCase = &mocks.UseCase{}
-		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase)
+		mockProjectUseCase = &projectMocks.UseCase{}
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
 	}
 
 	tests := []struct {
@@ -140,6 +188,90 @@ func TestTenant_GetTenant(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(
+					&tenant.TenantInfo{
+						TenantName: "test-tenant",
+						TenantQuotas: []*tenant.TenantQuota{
+							{QuotaName: "test-quota"},
+						},
+						UnifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{
+							{QuotaName: "test-quota"},
+						},
+					}, nil)
+				mockReleaseUseCase.On("ComputeReleasesResources", "test-tenant").Return([]*release.ReleaseResources{
+					{
+						Deployments: []*release.ReleaseResourceDeployment{
+							{
+								Replicas: 2,
+								ReleaseResourceBase: release.ReleaseResourceBase{
+									Name:        "test-deployment",
+									PodRequests: &release.ReleaseResourcePod{Cpu: 0.5, Memory: 512},
+									PodLimits:   &release.ReleaseResourcePod{Cpu: 1, Memory: 1024},
+								},
+							},
+						},
+						Pvcs: []*release.ReleaseResourceStorage{
+							{Name: "test-pvc", Type: release.PvcPodStorageType, Size: 10},
+						},
+					},
+				}, []string{"test-broken-release"}, nil)
+			},
+			tenantInfo: &tenant.TenantInfo{
+				TenantName: "test-tenant",
+				TenantQuotas: []*tenant.TenantQuota{
+					{
+						QuotaName: "test-quota",
+						Reserved: &tenant.TenantQuotaInfo{
+							LimitCpu:        "2",
+							LimitMemory:     "2048Mi",
+							RequestsCPU:     "1",
+							RequestsMemory:  "1024Mi",
+							RequestsStorage: "10Gi",
+							Pods:            "2",
+						},
+					},
+				},
+				UnifyUnitTenantQuotas: []*tenant.UnifyUnitTenantQuota{
+					{
+						QuotaName: "test-quota",
+						Reserved: &tenant.UnifyUnitTenantQuotaInfo{
+							LimitCpu:        2,
+							LimitMemory:     2048,
+							RequestsCPU:     1,
+							RequestsMemory:  1024,
+							RequestsStorage: 10,
+							Pods:            2,
+						},
+					},
+				},
+				ReservedIncomplete: true,
+			},
+			err: nil,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(
+					&tenant.TenantInfo{
+						TenantName: "test-tenant",
+						TenantQuotas: []*tenant.TenantQuota{
+							{QuotaName: "test-quota"},
+						},
+					}, nil)
+				mockReleaseUseCase.On("ComputeReleasesResources", "test-tenant").Return(nil, nil, errors.New("failed"))
+			},
+			tenantInfo: &tenant.TenantInfo{
+				TenantName: "test-tenant",
+				TenantQuotas: []*tenant.TenantQuota{
+					{QuotaName: "test-quota"},
+				},
+				ReservedIncomplete: true,
+			},
+			err: nil,
+		},
 	}
 	for _, test := range tests {
 		test.initMock()
@@ -156,17 +288,20 @@ func TestTenant_ListTenants(t *testing.T) {
 	var mockK8sCache *k8sMocks.Cache
 	var mockK8sOperator *k8sMocks.Operator
 	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
 	var mockTenantManager *Tenant
 
 	refreshMocks := func() {
 		mockK8sCache = &k8sMocks.Cache{}
 		mockK8sOperator = &k8sMocks.Operator{}
 		mockReleaseUseCase = &mocks.UseCase{}
-		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase)
+		mockProjectUseCase = &projectMocks.UseCase{}
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
 	}
 
 	tests := []struct {
 		initMock       func()
+		summary        bool
 		tenantInfoList *tenant.TenantInfoList
 		err            error
 	}{
@@ -193,6 +328,28 @@ func TestTenant_ListTenants(t *testing.T) {
 				}},
 			err: nil,
 		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("ListTenantsSummary", mock.Anything).Return(
+					&tenant.TenantInfoList{
+						Items: []*tenant.TenantInfo{
+							{
+								TenantName: "test-tenant",
+								Ready:      true,
+							},
+						}}, nil)
+			},
+			summary: true,
+			tenantInfoList: &tenant.TenantInfoList{
+				Items: []*tenant.TenantInfo{
+					{
+						TenantName: "test-tenant",
+						Ready:      true,
+					},
+				}},
+			err: nil,
+		},
 		{
 			initMock: func() {
 				refreshMocks()
@@ -203,7 +360,7 @@ func TestTenant_ListTenants(t *testing.T) {
 	}
 	for _, test := range tests {
 		test.initMock()
-		tenantInfoList, err := mockTenantManager.ListTenants()
+		tenantInfoList, err := mockTenantManager.ListTenants("", test.summary)
 		assert.IsType(t, test.err, err)
 		assert.Equal(t, test.tenantInfoList, tenantInfoList)
 		mockK8sCache.AssertExpectations(t)
@@ -216,16 +373,19 @@ func TestTenant_DeleteTenant(t *testing.T) {
 	var mockK8sCache *k8sMocks.Cache
 	var mockK8sOperator *k8sMocks.Operator
 	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
 	var mockTenantManager *Tenant
 
 	refreshMocks := func() {
 		mockK8sCache = &k8sMocks.Cache{}
 		mockK8sOperator = &k8sMocks.Operator{}
 		mockReleaseUseCase = &mocks.UseCase{}
-		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase)
+		mockProjectUseCase = &projectMocks.UseCase{}
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
 	}
 	tests := []struct {
 		initMock func()
+		force    bool
 		err      error
 	}{
 		{
@@ -246,6 +406,15 @@ func TestTenant_DeleteTenant(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(nil, errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(nil, nil)
 				mockReleaseUseCase.On("ListReleases", mock.Anything).Return(nil, errors.New("failed"))
 			},
 			err: errors.New("failed"),
@@ -254,6 +423,7 @@ func TestTenant_DeleteTenant(t *testing.T) {
 			initMock: func() {
 				refreshMocks()
 				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(nil, nil)
 				mockReleaseUseCase.On("ListReleases", mock.Anything).Return([]*release.ReleaseInfoV2{
 					{
 						ReleaseInfo: release.ReleaseInfo{
@@ -264,16 +434,36 @@ func TestTenant_DeleteTenant(t *testing.T) {
 						},
 					},
 				}, nil)
-				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything,false,false, int64(0)).Return(
+			},
+			err: errors.New("failed"),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(nil, nil)
+				mockReleaseUseCase.On("ListReleases", mock.Anything).Return([]*release.ReleaseInfoV2{
+					{
+						ReleaseInfo: release.ReleaseInfo{
+							ReleaseSpec: release.ReleaseSpec{
+								Name:      "test-release",
+								Namespace: "test-tenant",
+							},
+						},
+					},
+				}, nil)
+				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything,false,false, int64(0)).Return(
 					errors.New("failed"),
 				)
 			},
-			err: errors.New("failed"),
+			force: true,
+			err:   errors.New("failed"),
 		},
 		{
 			initMock: func() {
 				refreshMocks()
 				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(nil, nil)
 				mockReleaseUseCase.On("ListReleases", mock.Anything).Return([]*release.ReleaseInfoV2{
 					{
 						ReleaseInfo: release.ReleaseInfo{
@@ -284,17 +474,19 @@ func TestTenant_DeleteTenant(t *testing.T) {
 						},
 					},
 				}, nil)
-				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything,false,false,int64(0)).Return(
+				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything,false,false,int64(0)).Return(
 					nil,
 				)
 				mockK8sOperator.On("DeleteNamespace", mock.Anything).Return(errors.New("failed"))
 			},
-			err: errors.New("failed"),
+			force: true,
+			err:   errors.New("failed"),
 		},
 		{
 			initMock: func() {
 				refreshMocks()
 				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(nil, nil)
 				mockReleaseUseCase.On("ListReleases", mock.Anything).Return([]*release.ReleaseInfoV2{
 					{
 						ReleaseInfo: release.ReleaseInfo{
@@ -305,21 +497,58 @@ func TestTenant_DeleteTenant(t *testing.T) {
 						},
 					},
 				}, nil)
-				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything,false,false, int64(0)).Return(
+				mockReleaseUseCase.On("DeleteReleaseWithRetry", mock.Anything, mock.Anything, mock.Anything,false,false, int64(0)).Return(
 					nil,
 				)
 				mockK8sOperator.On("DeleteNamespace", mock.Anything).Return(nil)
 			},
-			err: nil,
+			force: true,
+			err:   nil,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(&projectModel.ProjectInfoList{
+					Items: []*projectModel.ProjectInfo{
+						{
+							Name:      "test-project",
+							Namespace: "test-tenant",
+						},
+					},
+				}, nil)
+				mockReleaseUseCase.On("ListReleases", mock.Anything).Return(nil, nil)
+			},
+			err: errors.New("failed"),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("ListProjects", mock.Anything, mock.Anything).Return(&projectModel.ProjectInfoList{
+					Items: []*projectModel.ProjectInfo{
+						{
+							Name:      "test-project",
+							Namespace: "test-tenant",
+						},
+					},
+				}, nil)
+				mockReleaseUseCase.On("ListReleases", mock.Anything).Return(nil, nil)
+				mockProjectUseCase.On("DeleteProject", mock.Anything, "test-tenant", "test-project", false, int64(0), false, false, "", "").Return(nil, nil)
+				mockK8sOperator.On("DeleteNamespace", mock.Anything).Return(nil)
+			},
+			force: true,
+			err:   nil,
 		},
 	}
 	for _, test := range tests {
 		test.initMock()
-		err := mockTenantManager.DeleteTenant("test-tenant")
+		err := mockTenantManager.DeleteTenant("test-tenant", test.force)
 		assert.IsType(t, test.err, err)
 		mockK8sCache.AssertExpectations(t)
 		mockK8sOperator.AssertExpectations(t)
 		mockReleaseUseCase.AssertExpectations(t)
+		mockProjectUseCase.AssertExpectations(t)
 	}
 }
 
@@ -327,14 +556,16 @@ func TestTenant_UpdateTenant(t *testing.T) {
 	var mockK8sCache *k8sMocks.Cache
 	var mockK8sOperator *k8sMocks.Operator
 	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
 	var mockTenantManager *Tenant
 
 	refreshMocks := func() {
 		mockK8sCache = &k8sMocks.Cache{}
 		mockK8sOperator = &k8sMocks.Operator{}
 		mockReleaseUseCase = &mocks.UseCase{}
+		mockProjectUseCase = &projectMocks.UseCase{}
 
-		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase)
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
 	}
 	tests := []struct {
 		initMock func()
@@ -393,3 +624,408 @@ func TestTenant_UpdateTenant(t *testing.T) {
 		mockReleaseUseCase.AssertExpectations(t)
 	}
 }
+
+func TestTenant_UpdateTenantMembers(t *testing.T) {
+	var mockK8sCache *k8sMocks.Cache
+	var mockK8sOperator *k8sMocks.Operator
+	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
+	var mockTenantManager *Tenant
+
+	refreshMocks := func() {
+		mockK8sCache = &k8sMocks.Cache{}
+		mockK8sOperator = &k8sMocks.Operator{}
+		mockReleaseUseCase = &mocks.UseCase{}
+		mockProjectUseCase = &projectMocks.UseCase{}
+
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
+	}
+	existingTenantInfo := &tenant.TenantInfo{
+		TenantName: "test-tenant",
+		TenantMembers: []*tenant.TenantMember{
+			{
+				Kind: tenant.MemberKindUser,
+				Name: "test-user",
+				Role: tenant.TenantRoleAdmin,
+			},
+		},
+	}
+	tests := []struct {
+		tenantMembers []*tenant.TenantMemberParams
+		initMock      func()
+		err           error
+	}{
+		{
+			// add a member
+			tenantMembers: []*tenant.TenantMemberParams{
+				{
+					Kind: tenant.MemberKindUser,
+					Name: "test-user",
+					Role: tenant.TenantRoleAdmin,
+				},
+				{
+					Kind: tenant.MemberKindGroup,
+					Name: "test-group",
+					Role: tenant.TenantRoleEditor,
+				},
+			},
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateRoleBinding", &k8sModel.RoleBinding{
+					Meta: k8sModel.Meta{
+						Namespace: "test-tenant",
+						Name:      "walm-member-group-test-group",
+					},
+					Labels:          map[string]string{tenant.MemberLabelKey: "true"},
+					SubjectKind:     tenant.MemberKindGroup,
+					SubjectName:     "test-group",
+					ClusterRoleName: "walm-tenant-editor",
+				}).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateRoleBinding", mock.Anything).Return(nil)
+			},
+			err: nil,
+		},
+		{
+			// demote a member
+			tenantMembers: []*tenant.TenantMemberParams{
+				{
+					Kind: tenant.MemberKindUser,
+					Name: "test-user",
+					Role: tenant.TenantRoleViewer,
+				},
+			},
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateRoleBinding", &k8sModel.RoleBinding{
+					Meta: k8sModel.Meta{
+						Namespace: "test-tenant",
+						Name:      "walm-member-user-test-user",
+					},
+					Labels:          map[string]string{tenant.MemberLabelKey: "true"},
+					SubjectKind:     tenant.MemberKindUser,
+					SubjectName:     "test-user",
+					ClusterRoleName: "walm-tenant-viewer",
+				}).Return(nil)
+			},
+			err: nil,
+		},
+		{
+			// remove a member
+			tenantMembers: []*tenant.TenantMemberParams{},
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("DeleteRoleBinding", "test-tenant", "walm-member-user-test-user").Return(nil)
+			},
+			err: nil,
+		},
+		{
+			tenantMembers: []*tenant.TenantMemberParams{
+				{
+					Kind: tenant.MemberKindUser,
+					Name: "test-user",
+					Role: "owner",
+				},
+			},
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+			},
+			err: errors.New("failed"),
+		},
+		{
+			tenantMembers: []*tenant.TenantMemberParams{
+				{
+					Kind: tenant.MemberKindUser,
+					Name: "test-user",
+					Role: tenant.TenantRoleAdmin,
+				},
+			},
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateRoleBinding", mock.Anything).Return(errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+	}
+	for _, test := range tests {
+		test.initMock()
+		err := mockTenantManager.UpdateTenant("test-tenant", &tenant.TenantParams{
+			TenantMembers: test.tenantMembers,
+		})
+		assert.IsType(t, test.err, err)
+		mockK8sCache.AssertExpectations(t)
+		mockK8sOperator.AssertExpectations(t)
+		mockReleaseUseCase.AssertExpectations(t)
+	}
+}
+
+func TestTenant_BuildSyncedNamespace(t *testing.T) {
+	tests := []struct {
+		tenantInfo   *tenant.TenantInfo
+		tenantParams *tenant.TenantParams
+		namespace    *k8sModel.Namespace
+	}{
+		{
+			tenantInfo: nil,
+			tenantParams: &tenant.TenantParams{
+				TenantLabels: map[string]string{"test-label": "test-value"},
+			},
+			namespace: &k8sModel.Namespace{
+				Meta: k8sModel.Meta{
+					Namespace: "test-tenant",
+					Name:      "test-tenant",
+				},
+				Labels: map[string]string{
+					"test-label":                "test-value",
+					tenant.MultiTenantLabelKey: "tenant-tiller-test-tenant",
+				},
+				Annotations: map[string]string{
+					tenant.ManagedKeysAnnotationKey: `{"labels":["test-label"]}`,
+				},
+			},
+		},
+		{
+			tenantInfo: &tenant.TenantInfo{
+				TenantName: "test-tenant",
+				TenantLabels: map[string]string{
+					"foreign-label": "foreign-value",
+					"stale-label":   "stale-value",
+					"kept-label":    "old-value",
+				},
+				TenantAnnotitions: map[string]string{
+					"foreign-annotation":            "foreign-value",
+					"stale-annotation":              "stale-value",
+					tenant.ManagedKeysAnnotationKey: `{"labels":["kept-label","stale-label"],"annotations":["stale-annotation"]}`,
+				},
+			},
+			tenantParams: &tenant.TenantParams{
+				TenantLabels: map[string]string{
+					"kept-label": "new-value",
+					"new-label":  "new-value",
+				},
+			},
+			namespace: &k8sModel.Namespace{
+				Meta: k8sModel.Meta{
+					Namespace: "test-tenant",
+					Name:      "test-tenant",
+				},
+				Labels: map[string]string{
+					"foreign-label":             "foreign-value",
+					"kept-label":                "new-value",
+					"new-label":                 "new-value",
+					tenant.MultiTenantLabelKey: "tenant-tiller-test-tenant",
+				},
+				Annotations: map[string]string{
+					"foreign-annotation":            "foreign-value",
+					tenant.ManagedKeysAnnotationKey: `{"labels":["kept-label","new-label"]}`,
+				},
+			},
+		},
+		{
+			tenantInfo: nil,
+			tenantParams: &tenant.TenantParams{
+				TenantQuotas: []*tenant.TenantQuotaParams{
+					{
+						QuotaName: "test-quota",
+						Hard: &tenant.TenantQuotaInfo{
+							Pods:        "5",
+							LimitMemory: "2Gi",
+						},
+					},
+				},
+				TenantLimitRanges: []*tenant.TenantLimitRangeParams{
+					{LimitRangeName: "test-limitrange"},
+				},
+			},
+			namespace: &k8sModel.Namespace{
+				Meta: k8sModel.Meta{
+					Namespace: "test-tenant",
+					Name:      "test-tenant",
+				},
+				Labels: map[string]string{
+					tenant.MultiTenantLabelKey: "tenant-tiller-test-tenant",
+				},
+				Annotations: map[string]string{
+					tenant.ManagedKeysAnnotationKey:     `{}`,
+					tenant.DeclaredObjectsAnnotationKey: `{"resourceQuotas":{"test-quota":{"limits.memory":"2Gi","pods":"5"}},"limitRanges":["test-limitrange"]}`,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		namespace := buildSyncedNamespace("test-tenant", test.tenantInfo, test.tenantParams)
+		assert.Equal(t, test.namespace, namespace)
+	}
+}
+
+func TestTenant_MergeTenantTemplate(t *testing.T) {
+	oriTenantConfig := setting.Config.TenantConfig
+	defer func() {
+		setting.Config.TenantConfig = oriTenantConfig
+	}()
+
+	templateQuotas := []*tenant.TenantQuotaParams{
+		{
+			QuotaName: "walm-default-quota",
+			Hard: &tenant.TenantQuotaInfo{
+				LimitCpu: "10",
+			},
+		},
+	}
+	tests := []struct {
+		tenantConfig *setting.TenantConfig
+		tenantParams *tenant.TenantParams
+		mergedParams *tenant.TenantParams
+	}{
+		{
+			tenantConfig: nil,
+			tenantParams: &tenant.TenantParams{
+				TenantLabels: map[string]string{"test1": "test1"},
+			},
+			mergedParams: &tenant.TenantParams{
+				TenantLabels: map[string]string{"test1": "test1"},
+			},
+		},
+		{
+			tenantConfig: &setting.TenantConfig{
+				TemplateVersion: "v1",
+				DefaultTenantParams: &tenant.TenantParams{
+					TenantLabels: map[string]string{"test1": "default", "default1": "default1"},
+					TenantQuotas: templateQuotas,
+				},
+			},
+			tenantParams: &tenant.TenantParams{
+				TenantLabels: map[string]string{"test1": "test1"},
+			},
+			mergedParams: &tenant.TenantParams{
+				TenantLabels:      map[string]string{"test1": "test1", "default1": "default1"},
+				TenantAnnotations: map[string]string{tenant.TemplateVersionAnnotationKey: "v1"},
+				TenantQuotas:      templateQuotas,
+			},
+		},
+		{
+			tenantConfig: &setting.TenantConfig{
+				TemplateVersion: "v1",
+				DefaultTenantParams: &tenant.TenantParams{
+					TenantQuotas: templateQuotas,
+				},
+			},
+			tenantParams: &tenant.TenantParams{
+				TenantQuotas: []*tenant.TenantQuotaParams{
+					{
+						QuotaName: "test-quota",
+						Hard: &tenant.TenantQuotaInfo{
+							LimitCpu: "1",
+						},
+					},
+				},
+			},
+			mergedParams: &tenant.TenantParams{
+				TenantAnnotations: map[string]string{tenant.TemplateVersionAnnotationKey: "v1"},
+				TenantQuotas: []*tenant.TenantQuotaParams{
+					{
+						QuotaName: "test-quota",
+						Hard: &tenant.TenantQuotaInfo{
+							LimitCpu: "1",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		setting.Config.TenantConfig = test.tenantConfig
+		assert.Equal(t, test.mergedParams, mergeTenantTemplate(test.tenantParams))
+	}
+}
+
+func TestTenant_UpdateTenantLimitRanges(t *testing.T) {
+	var mockK8sCache *k8sMocks.Cache
+	var mockK8sOperator *k8sMocks.Operator
+	var mockReleaseUseCase *mocks.UseCase
+	var mockProjectUseCase *projectMocks.UseCase
+	var mockTenantManager *Tenant
+
+	refreshMocks := func() {
+		mockK8sCache = &k8sMocks.Cache{}
+		mockK8sOperator = &k8sMocks.Operator{}
+		mockReleaseUseCase = &mocks.UseCase{}
+		mockProjectUseCase = &projectMocks.UseCase{}
+
+		mockTenantManager = NewTenant(mockK8sCache, mockK8sOperator, mockReleaseUseCase, mockProjectUseCase)
+	}
+	existingTenantInfo := &tenant.TenantInfo{
+		TenantName: "test-tenant",
+		TenantLimitRanges: []*tenant.TenantLimitRange{
+			{
+				LimitRangeName: LimitRangeDefaultName,
+			},
+			{
+				LimitRangeName: "test-limit-range",
+			},
+			{
+				LimitRangeName: "stale-limit-range",
+			},
+		},
+	}
+	tests := []struct {
+		initMock func()
+		err      error
+	}{
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateLimitRange", mock.Anything).Return(nil)
+				mockK8sOperator.On("DeleteLimitRange", "test-tenant", "stale-limit-range").Return(nil)
+			},
+			err: nil,
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateLimitRange", mock.Anything).Return(errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+		{
+			initMock: func() {
+				refreshMocks()
+				mockK8sCache.On("GetTenant", mock.Anything).Return(existingTenantInfo, nil)
+				mockK8sOperator.On("UpdateNamespace", mock.Anything).Return(nil)
+				mockK8sOperator.On("CreateOrUpdateLimitRange", mock.Anything).Return(nil)
+				mockK8sOperator.On("DeleteLimitRange", "test-tenant", "stale-limit-range").Return(errors.New("failed"))
+			},
+			err: errors.New("failed"),
+		},
+	}
+	for _, test := range tests {
+		test.initMock()
+		err := mockTenantManager.UpdateTenant("test-tenant", &tenant.TenantParams{
+			TenantLimitRanges: []*tenant.TenantLimitRangeParams{
+				{
+					LimitRangeName: "test-limit-range",
+					DefaultLimit:   map[string]string{"cpu": "0.5", "memory": "256Mi"},
+					DefaultRequest: map[string]string{"cpu": "0.1"},
+				},
+			},
+		})
+		assert.IsType(t, test.err, err)
+		mockK8sCache.AssertExpectations(t)
+		mockK8sOperator.AssertExpectations(t)
+		mockReleaseUseCase.AssertExpectations(t)
+	}
+}
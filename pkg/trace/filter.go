@@ -0,0 +1,31 @@
+package trace
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// Filter starts a span per api request, resuming the trace of a caller whose
+// request carries one. The span is put on the request context, so the
+// usecases propagate it into the task signatures they submit. It is mounted
+// as a global go-restful filter behind the request id filter.
+func Filter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	wireContext, _ := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders,
+		opentracing.HTTPHeadersCarrier(request.Request.Header))
+
+	span := opentracing.StartSpan(request.Request.Method+" "+request.Request.URL.Path,
+		ext.RPCServerOption(wireContext))
+	defer span.Finish()
+	ext.HTTPMethod.Set(span, request.Request.Method)
+	ext.HTTPUrl.Set(span, request.Request.URL.Path)
+
+	request.Request = request.Request.WithContext(
+		opentracing.ContextWithSpan(request.Request.Context(), span))
+	chain.ProcessFilter(request, response)
+
+	ext.HTTPStatusCode.Set(span, uint16(response.StatusCode()))
+	if response.StatusCode() >= 500 {
+		ext.Error.Set(span, true)
+	}
+}
@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"io"
+
+	"WarpCloud/walm/pkg/setting"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/uber/jaeger-client-go"
+	jaegerConfig "github.com/uber/jaeger-client-go/config"
+)
+
+const defaultServiceName = "walm"
+
+type noopCloser struct{}
+
+func (noopCloser) Close() error { return nil }
+
+// Init sets up the global opentracing tracer exporting to jaeger, so the api
+// filter, the task queue and the workers all join up into the same traces.
+// The returned closer flushes the buffered spans and must be closed on
+// shutdown. A nil or disabled config keeps the noop tracer, every span api
+// stays safe to call.
+func Init(config *setting.TraceConfig) (io.Closer, error) {
+	if config == nil || !config.Enable {
+		return noopCloser{}, nil
+	}
+
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	// zero means sample everything, tracing is usually switched on to debug
+	sampleRate := config.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+
+	tracer, closer, err := (&jaegerConfig.Configuration{
+		ServiceName: serviceName,
+		Sampler: &jaegerConfig.SamplerConfig{
+			Type:  jaeger.SamplerTypeProbabilistic,
+			Param: sampleRate,
+		},
+		Reporter: &jaegerConfig.ReporterConfig{
+			LocalAgentHostPort: config.AgentHostPort,
+			CollectorEndpoint:  config.CollectorEndpoint,
+		},
+	}).NewTracer()
+	if err != nil {
+		logrus.Errorf("failed to init the jaeger tracer : %s", err.Error())
+		return nil, err
+	}
+
+	opentracing.SetGlobalTracer(tracer)
+	logrus.Infof("tracing enabled, exporting spans of service %s to jaeger", serviceName)
+	return closer, nil
+}
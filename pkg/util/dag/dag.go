@@ -154,17 +154,99 @@ func (g *AcyclicGraph) Cycles() [][]Vertex {
 	return cycles
 }
 
+// WalkOpts configures the behavior of WalkWithOptions.
+type WalkOpts struct {
+	// MaxParallelism caps how many callbacks run at the same time. Zero or
+	// negative means no limit.
+	MaxParallelism int
+
+	// FailFast stops scheduling new vertices once any callback returns an
+	// error. Callbacks that are already running are left to finish, and
+	// vertices whose callback never ran are reported as skipped in the
+	// returned error.
+	FailFast bool
+}
+
 // Walk walks the graph, calling your callback as each node is visited.
 // This will walk nodes in parallel if it can. Because the walk is done
 // in parallel, the error returned will be a multierror.
 func (g *AcyclicGraph) Walk(cb WalkFunc) error {
+	return g.WalkWithOptions(WalkOpts{}, cb)
+}
+
+// WalkWithOptions walks the graph like Walk, with the parallelism and
+// error handling tuned by the given options.
+func (g *AcyclicGraph) WalkWithOptions(opts WalkOpts, cb WalkFunc) error {
 	defer g.debug.BeginOperation(typeWalk, "").End("")
 
-	w := &Walker{Callback: cb, Reverse: true}
+	w := &Walker{
+		Callback:       cb,
+		Reverse:        true,
+		MaxParallelism: opts.MaxParallelism,
+		FailFast:       opts.FailFast,
+	}
 	w.Update(g)
 	return w.Wait()
 }
 
+// TopologicalOrder returns the vertices in dependency first order, breaking
+// ties with the edge weights and the key function so identical graphs always
+// yield the identical order. A nil key function falls back to fmt.Sprint of
+// the vertex. Soft edges carry information only and do not constrain the
+// order. An error is returned when a cycle keeps some vertices unreachable.
+func (g *AcyclicGraph) TopologicalOrder(key func(Vertex) string) ([]Vertex, error) {
+	if key == nil {
+		key = func(v Vertex) string { return fmt.Sprint(v) }
+	}
+
+	vertices := g.Vertices()
+	remaining := make(map[interface{}]int, len(vertices))
+	waiters := make(map[interface{}][]Vertex, len(vertices))
+	weights := make(map[interface{}]int, len(vertices))
+	for _, e := range g.Edges() {
+		annotation := EdgeAnnotationOf(e)
+		if annotation.Weight > weights[hashcode(e.Target())] {
+			weights[hashcode(e.Target())] = annotation.Weight
+		}
+		if annotation.Soft {
+			continue
+		}
+		remaining[hashcode(e.Source())]++
+		waiters[hashcode(e.Target())] = append(waiters[hashcode(e.Target())], e.Source())
+	}
+
+	ready := make([]Vertex, 0, len(vertices))
+	for _, v := range vertices {
+		if remaining[hashcode(v)] == 0 {
+			ready = append(ready, v)
+		}
+	}
+
+	order := make([]Vertex, 0, len(vertices))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			if weights[hashcode(ready[i])] != weights[hashcode(ready[j])] {
+				return weights[hashcode(ready[i])] > weights[hashcode(ready[j])]
+			}
+			return key(ready[i]) < key(ready[j])
+		})
+		v := ready[0]
+		ready = ready[1:]
+		order = append(order, v)
+		for _, up := range waiters[hashcode(v)] {
+			remaining[hashcode(up)]--
+			if remaining[hashcode(up)] == 0 {
+				ready = append(ready, up)
+			}
+		}
+	}
+
+	if len(order) != len(vertices) {
+		return nil, fmt.Errorf("cannot compute a topological order on a graph with cycles")
+	}
+	return order, nil
+}
+
 // simple convenience helper for converting a dag.Set to a []Vertex
 func AsVertexList(s *Set) []Vertex {
 	rawList := s.List()
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -197,6 +198,138 @@ func TestAcyclicGraphValidate_cycleSelf(t *testing.T) {
 	}
 }
 
+func TestAcyclicGraphCycles(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(3, 1))
+
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+}
+
+func TestAcyclicGraphCycles_cycle(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 1))
+
+	cycles := g.Cycles()
+	if len(cycles) != 1 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+	if len(cycles[0]) != 3 {
+		t.Fatalf("bad: %#v", cycles[0])
+	}
+}
+
+func TestAcyclicGraphCycles_multiple(t *testing.T) {
+	var g AcyclicGraph
+	for i := 1; i <= 6; i++ {
+		g.Add(i)
+	}
+	// two cycles overlapping at vertex 2 collapse into one component
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 1))
+	g.Connect(BasicEdge(2, 4))
+	g.Connect(BasicEdge(4, 2))
+	// a separate cycle stays its own component
+	g.Connect(BasicEdge(5, 6))
+	g.Connect(BasicEdge(6, 5))
+
+	cycles := g.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+	sizes := []int{len(cycles[0]), len(cycles[1])}
+	sort.Ints(sizes)
+	if sizes[0] != 2 || sizes[1] != 4 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+}
+
+func TestAcyclicGraphTopologicalOrder(t *testing.T) {
+	var g AcyclicGraph
+	for _, v := range []string{"d", "b", "c", "a"} {
+		g.Add(v)
+	}
+	g.Connect(BasicEdge("a", "b"))
+	g.Connect(BasicEdge("a", "c"))
+	g.Connect(BasicEdge("b", "d"))
+	g.Connect(BasicEdge("c", "d"))
+
+	// the order is stable no matter how the graph was built
+	for i := 0; i < 10; i++ {
+		order, err := g.TopologicalOrder(nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		expected := []Vertex{"d", "b", "c", "a"}
+		if !reflect.DeepEqual(order, expected) {
+			t.Fatalf("bad: %#v", order)
+		}
+	}
+}
+
+func TestAcyclicGraphTopologicalOrder_weights(t *testing.T) {
+	var g AcyclicGraph
+	for _, v := range []string{"a", "b", "c"} {
+		g.Add(v)
+	}
+	g.Connect(AnnotatedEdge("a", "b", EdgeAnnotation{Weight: 1}))
+	g.Connect(AnnotatedEdge("a", "c", EdgeAnnotation{Weight: 5}))
+
+	// the target of the heavier edge wins the tie
+	for i := 0; i < 10; i++ {
+		order, err := g.TopologicalOrder(nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		expected := []Vertex{"c", "b", "a"}
+		if !reflect.DeepEqual(order, expected) {
+			t.Fatalf("bad: %#v", order)
+		}
+	}
+}
+
+func TestAcyclicGraphTopologicalOrder_soft(t *testing.T) {
+	var g AcyclicGraph
+	for _, v := range []string{"a", "b", "c"} {
+		g.Add(v)
+	}
+	g.Connect(BasicEdge("a", "b"))
+	g.Connect(AnnotatedEdge("a", "c", EdgeAnnotation{Soft: true}))
+
+	// the soft edge does not constrain the order, so "a" only waits for "b"
+	order, err := g.TopologicalOrder(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []Vertex{"b", "a", "c"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Fatalf("bad: %#v", order)
+	}
+}
+
+func TestAcyclicGraphTopologicalOrder_cycle(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 1))
+
+	if _, err := g.TopologicalOrder(nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
 func TestAcyclicGraphAncestors(t *testing.T) {
 	var g AcyclicGraph
 	g.Add(1)
@@ -331,6 +464,56 @@ func TestAcyclicGraphWalk_error(t *testing.T) {
 	t.Fatalf("bad: %#v", visits)
 }
 
+func TestAcyclicGraphWalkWithOptions_failFast(t *testing.T) {
+	// A diamond: "a" depends on "b" and "c", which both depend on "d".
+	var g AcyclicGraph
+	g.Add("a")
+	g.Add("b")
+	g.Add("c")
+	g.Add("d")
+	g.Connect(BasicEdge("a", "b"))
+	g.Connect(BasicEdge("a", "c"))
+	g.Connect(BasicEdge("b", "d"))
+	g.Connect(BasicEdge("c", "d"))
+
+	var visits []Vertex
+	var lock sync.Mutex
+	err := g.WalkWithOptions(WalkOpts{FailFast: true}, func(v Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if v == "b" {
+			return fmt.Errorf("error")
+		}
+
+		visits = append(visits, v)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	// "d" must have run, "a" must have been skipped; "c" races with the
+	// failure of "b" so either outcome is fine.
+	for _, v := range visits {
+		if v == "a" {
+			t.Fatalf("bad: %#v", visits)
+		}
+	}
+	if len(visits) == 0 || visits[0] != "d" {
+		t.Fatalf("bad: %#v", visits)
+	}
+
+	for _, expected := range []string{
+		"b: error",
+		"a: skipped after another vertex failed",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q in error: %s", expected, err)
+		}
+	}
+}
+
 func TestAcyclicGraph_ReverseDepthFirstWalk_WithRemoval(t *testing.T) {
 	var g AcyclicGraph
 	g.Add(1)
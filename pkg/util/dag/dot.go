@@ -41,6 +41,42 @@ type DotNode struct {
 	Attrs map[string]string
 }
 
+// MarshalDOT renders the graph in graphviz dot format. Vertex labels come
+// from the label function, since vertices are arbitrary values a caller
+// usually renders better than their VertexName; a nil label function falls
+// back to VertexName. Nodes and edges are written in sorted order, so the
+// output is stable enough for golden tests.
+func (g *AcyclicGraph) MarshalDOT(label func(Vertex) string) []byte {
+	if label == nil {
+		label = VertexName
+	}
+
+	vertices := g.Vertices()
+	nodes := make([]string, 0, len(vertices))
+	for _, v := range vertices {
+		nodes = append(nodes, fmt.Sprintf("\t%q\n", label(v)))
+	}
+	sort.Strings(nodes)
+
+	graphEdges := g.Edges()
+	edges := make([]string, 0, len(graphEdges))
+	for _, e := range graphEdges {
+		edges = append(edges, fmt.Sprintf("\t%q -> %q\n", label(e.Source()), label(e.Target())))
+	}
+	sort.Strings(edges)
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph {\n")
+	for _, n := range nodes {
+		buf.WriteString(n)
+	}
+	for _, e := range edges {
+		buf.WriteString(e)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
 // Returns the DOT representation of this Graph.
 func (g *marshalGraph) Dot(opts *DotOpts) []byte {
 	if opts == nil {
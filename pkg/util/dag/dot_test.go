@@ -24,6 +24,55 @@ func TestGraphDot_opts(t *testing.T) {
 	}
 }
 
+func TestAcyclicGraphMarshalDOT(t *testing.T) {
+	// A diamond: "a" depends on "b" and "c", which both depend on "d".
+	var g AcyclicGraph
+	g.Add("a")
+	g.Add("b")
+	g.Add("c")
+	g.Add("d")
+	g.Connect(BasicEdge("a", "b"))
+	g.Connect(BasicEdge("a", "c"))
+	g.Connect(BasicEdge("b", "d"))
+	g.Connect(BasicEdge("c", "d"))
+
+	expected := `digraph {
+	"a"
+	"b"
+	"c"
+	"d"
+	"a" -> "b"
+	"a" -> "c"
+	"b" -> "d"
+	"c" -> "d"
+}
+`
+	for i := 0; i < 10; i++ {
+		if actual := string(g.MarshalDOT(nil)); actual != expected {
+			t.Fatalf("bad:\n%s", actual)
+		}
+	}
+
+	// labels come from the caller-supplied function
+	expectedLabeled := `digraph {
+	"release-a"
+	"release-b"
+	"release-c"
+	"release-d"
+	"release-a" -> "release-b"
+	"release-a" -> "release-c"
+	"release-b" -> "release-d"
+	"release-c" -> "release-d"
+}
+`
+	actual := string(g.MarshalDOT(func(v Vertex) string {
+		return "release-" + v.(string)
+	}))
+	if actual != expectedLabeled {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
 type testDotVertex struct {
 	DotNodeCalled bool
 	DotNodeTitle  string
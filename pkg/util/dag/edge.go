@@ -18,6 +18,40 @@ func BasicEdge(source, target Vertex) Edge {
 	return &basicEdge{S: source, T: target}
 }
 
+// EdgeAnnotation carries optional metadata of an edge. The zero value
+// describes a plain hard edge, which is what BasicEdge edges report.
+type EdgeAnnotation struct {
+	// Soft, if true, means the edge carries information only and must not
+	// constrain the traversal order.
+	Soft bool
+
+	// Weight breaks ties when an ordering has several candidates; targets
+	// of heavier edges are handled first.
+	Weight int
+}
+
+// AnnotatedEdge returns an Edge like BasicEdge which additionally carries
+// the given annotation. It hashes like BasicEdge, so at most one edge can
+// exist between the same source and target.
+func AnnotatedEdge(source, target Vertex, annotation EdgeAnnotation) Edge {
+	return &annotatedEdge{basicEdge: basicEdge{S: source, T: target}, A: annotation}
+}
+
+// EdgeAnnotationOf returns the annotation of the edge, or the zero
+// annotation when the edge does not carry one.
+func EdgeAnnotationOf(e Edge) EdgeAnnotation {
+	if annotated, ok := e.(*annotatedEdge); ok {
+		return annotated.A
+	}
+	return EdgeAnnotation{}
+}
+
+// annotatedEdge is an Edge carrying an EdgeAnnotation.
+type annotatedEdge struct {
+	basicEdge
+	A EdgeAnnotation
+}
+
 // basicEdge is a basic implementation of Edge that has the source and
 // target vertex.
 type basicEdge struct {
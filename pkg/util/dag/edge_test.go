@@ -12,6 +12,23 @@ func TestBasicEdgeHashcode(t *testing.T) {
 	}
 }
 
+func TestAnnotatedEdge(t *testing.T) {
+	annotation := EdgeAnnotation{Soft: true, Weight: 3}
+	e1 := AnnotatedEdge(1, 2, annotation)
+	if EdgeAnnotationOf(e1) != annotation {
+		t.Fatalf("bad: %#v", EdgeAnnotationOf(e1))
+	}
+
+	// annotated edges hash like basic edges between the same vertices
+	e2 := BasicEdge(1, 2)
+	if e1.Hashcode() != e2.Hashcode() {
+		t.Fatalf("bad")
+	}
+	if EdgeAnnotationOf(e2) != (EdgeAnnotation{}) {
+		t.Fatalf("bad: %#v", EdgeAnnotationOf(e2))
+	}
+}
+
 func TestBasicEdgeHashcode_pointer(t *testing.T) {
 	type test struct {
 		Value string
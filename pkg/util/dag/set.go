@@ -1,6 +1,8 @@
 package dag
 
 import (
+	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -118,6 +120,20 @@ func (s *Set) List() []interface{} {
 	return r
 }
 
+// SortedList returns the list of set elements sorted by the given key
+// function, so callers get a stable order out of the underlying map. A nil
+// key function falls back to fmt.Sprint of the element.
+func (s *Set) SortedList(key func(interface{}) string) []interface{} {
+	list := s.List()
+	if key == nil {
+		key = func(v interface{}) string { return fmt.Sprint(v) }
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return key(list[i]) < key(list[j])
+	})
+	return list
+}
+
 func (s *Set) init() {
 	s.m = make(map[interface{}]interface{})
 }
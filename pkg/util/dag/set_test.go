@@ -2,6 +2,7 @@ package dag
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -96,3 +97,24 @@ func TestSetFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSortedList(t *testing.T) {
+	var s Set
+	for _, v := range []interface{}{3, 1, 2} {
+		s.Add(v)
+	}
+
+	expected := []interface{}{1, 2, 3}
+	if !reflect.DeepEqual(s.SortedList(nil), expected) {
+		t.Fatalf("bad: %#v", s.SortedList(nil))
+	}
+
+	// a key function reverses the default order
+	reversed := s.SortedList(func(v interface{}) string {
+		return fmt.Sprint(3 - v.(int))
+	})
+	expectedReversed := []interface{}{3, 2, 1}
+	if !reflect.DeepEqual(reversed, expectedReversed) {
+		t.Fatalf("bad: %#v", reversed)
+	}
+}
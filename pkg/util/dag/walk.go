@@ -3,11 +3,11 @@ package dag
 import (
 	"errors"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
 )
 
 // Walker is used to walk every vertex of a graph in parallel.
@@ -42,6 +42,20 @@ type Walker struct {
 	// When false (default), the target depends on the source.
 	Reverse bool
 
+	// MaxParallelism, when positive, caps how many callbacks execute at the
+	// same time. Zero or negative means no limit.
+	MaxParallelism int
+
+	// FailFast, if true, stops scheduling new vertices once any callback
+	// returns an error. Callbacks that are already running are left to
+	// finish, and vertices whose callback never ran are reported as skipped
+	// by Wait.
+	FailFast bool
+
+	// sem, when non nil, is the semaphore limiting callback parallelism.
+	// It is initialized by the first Update.
+	sem chan struct{}
+
 	// changeLock must be held to modify any of the fields below. Only Update
 	// should modify these fields. Modifying them outside of Update can cause
 	// serious problems.
@@ -56,7 +70,11 @@ type Walker struct {
 
 	// errMap contains the errors recorded so far for execution. Reading
 	// and writing should hold errLock.
-	errMap  map[Vertex]error
+	errMap map[Vertex]error
+
+	// failed is set when a callback returns an error. Reading and writing
+	// should hold errLock.
+	failed  bool
 	errLock sync.Mutex
 }
 
@@ -98,6 +116,10 @@ type walkerVertex struct {
 // user-returned error.
 var errWalkUpstream = errors.New("upstream dependency failed")
 
+// errWalkSkipped is used in the errMap of a walk to note that a vertex was
+// never visited because the walk was failing fast.
+var errWalkSkipped = errors.New("skipped because the walk is failing fast")
+
 // Wait waits for the completion of the walk and returns any errors (
 // in the form of a multierror) that occurred. Update should be called
 // to populate the walk with vertices and edges prior to calling this.
@@ -116,7 +138,20 @@ func (w *Walker) Wait() error {
 	// Build the error
 	var result error
 	for v, err := range w.errMap {
-		if err != nil && err != errWalkUpstream {
+		switch err {
+		case nil:
+		case errWalkUpstream:
+			// Dependents of a failed vertex are usually hidden since the
+			// failure itself is reported, but a fail fast walk promises to
+			// name every vertex it never ran.
+			if w.FailFast {
+				result = multierror.Append(result, fmt.Errorf(
+					"%s: skipped after another vertex failed", VertexName(v)))
+			}
+		case errWalkSkipped:
+			result = multierror.Append(result, fmt.Errorf(
+				"%s: skipped after another vertex failed", VertexName(v)))
+		default:
 			result = multierror.Append(result, fmt.Errorf(
 				"%s: %s", VertexName(v), err))
 		}
@@ -125,6 +160,13 @@ func (w *Walker) Wait() error {
 	return result
 }
 
+// hasFailed reports whether any callback has returned an error so far.
+func (w *Walker) hasFailed() bool {
+	w.errLock.Lock()
+	defer w.errLock.Unlock()
+	return w.failed
+}
+
 // Update updates the currently executing walk with the given graph.
 // This will perform a diff of the vertices and edges and update the walker.
 // Already completed vertices remain completed (including any errors during
@@ -151,6 +193,9 @@ func (w *Walker) Update(g *AcyclicGraph) {
 	if w.vertexMap == nil {
 		w.vertexMap = make(map[Vertex]*walkerVertex)
 	}
+	if w.sem == nil && w.MaxParallelism > 0 {
+		w.sem = make(chan struct{}, w.MaxParallelism)
+	}
 
 	// Calculate all our sets
 	newEdges := e.Difference(&w.edges)
@@ -166,7 +211,7 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		w.wait.Add(1)
 
 		// Add to our own set so we know about it already
-		log.Printf("[TRACE] dag/walk: added new vertex: %q", VertexName(v))
+		logrus.Tracef("dag/walk: added new vertex: %q", VertexName(v))
 		w.vertices.Add(raw)
 
 		// Initialize the vertex info
@@ -198,7 +243,7 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		// Delete it out of the map
 		delete(w.vertexMap, v)
 
-		log.Printf("[TRACE] dag/walk: removed vertex: %q", VertexName(v))
+		logrus.Tracef("dag/walk: removed vertex: %q", VertexName(v))
 		w.vertices.Delete(raw)
 	}
 
@@ -208,6 +253,12 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		edge := raw.(Edge)
 		waiter, dep := w.edgeParts(edge)
 
+		// Soft edges carry information only; the waiter must not wait on them
+		if EdgeAnnotationOf(edge).Soft {
+			w.edges.Add(raw)
+			continue
+		}
+
 		// Get the info for the waiter
 		waiterInfo, ok := w.vertexMap[waiter]
 		if !ok {
@@ -228,8 +279,8 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		// Record that the deps changed for this waiter
 		changedDeps.Add(waiter)
 
-		log.Printf(
-			"[TRACE] dag/walk: added edge: %q waiting on %q",
+		logrus.Tracef(
+			"dag/walk: added edge: %q waiting on %q",
 			VertexName(waiter), VertexName(dep))
 		w.edges.Add(raw)
 	}
@@ -239,6 +290,12 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		edge := raw.(Edge)
 		waiter, dep := w.edgeParts(edge)
 
+		// Soft edges never registered a dependency to clean up
+		if EdgeAnnotationOf(edge).Soft {
+			w.edges.Delete(raw)
+			continue
+		}
+
 		// Get the info for the waiter
 		waiterInfo, ok := w.vertexMap[waiter]
 		if !ok {
@@ -252,8 +309,8 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		// Record that the deps changed for this waiter
 		changedDeps.Add(waiter)
 
-		log.Printf(
-			"[TRACE] dag/walk: removed edge: %q waiting on %q",
+		logrus.Tracef(
+			"dag/walk: removed edge: %q waiting on %q",
 			VertexName(waiter), VertexName(dep))
 		w.edges.Delete(raw)
 	}
@@ -295,8 +352,8 @@ func (w *Walker) Update(g *AcyclicGraph) {
 		}
 		info.depsCancelCh = cancelCh
 
-		log.Printf(
-			"[TRACE] dag/walk: dependencies changed for %q, sending new deps",
+		logrus.Tracef(
+			"dag/walk: dependencies changed for %q, sending new deps",
 			VertexName(v))
 
 		// Start the waiter
@@ -383,10 +440,25 @@ func (w *Walker) walkVertex(v Vertex, info *walkerVertex) {
 	// Run our callback or note that our upstream failed
 	var err error
 	if depsSuccess {
-		log.Printf("[TRACE] dag/walk: walking %q", VertexName(v))
-		err = w.Callback(v)
+		// Wait for a semaphore slot when parallelism is limited
+		if w.sem != nil {
+			select {
+			case w.sem <- struct{}{}:
+				defer func() { <-w.sem }()
+			case <-info.CancelCh:
+				return
+			}
+		}
+
+		if w.FailFast && w.hasFailed() {
+			logrus.Tracef("dag/walk: walk is failing fast, not walking %q", VertexName(v))
+			err = errWalkSkipped
+		} else {
+			logrus.Tracef("dag/walk: walking %q", VertexName(v))
+			err = w.Callback(v)
+		}
 	} else {
-		log.Printf("[TRACE] dag/walk: upstream errored, not walking %q", VertexName(v))
+		logrus.Tracef("dag/walk: upstream errored, not walking %q", VertexName(v))
 		err = errWalkUpstream
 	}
 
@@ -399,6 +471,9 @@ func (w *Walker) walkVertex(v Vertex, info *walkerVertex) {
 			w.errMap = make(map[Vertex]error)
 		}
 		w.errMap[v] = err
+		if err != errWalkUpstream && err != errWalkSkipped {
+			w.failed = true
+		}
 	}
 }
 
@@ -423,7 +498,7 @@ func (w *Walker) waitDeps(
 				return
 
 			case <-time.After(time.Second * 5):
-				log.Printf("[TRACE] dag/walk: vertex %q, waiting for: %q",
+				logrus.Tracef("dag/walk: vertex %q, waiting for: %q",
 					VertexName(v), VertexName(dep))
 			}
 		}
@@ -3,7 +3,9 @@ package dag
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -275,6 +277,136 @@ func TestWalker_removeEdge(t *testing.T) {
 	}
 }
 
+func TestWalker_softEdge(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(AnnotatedEdge(1, 2, EdgeAnnotation{Soft: true}))
+
+	// A soft edge must not serialize the walk: 1 waits on a channel only 2
+	// closes, so the walk can only finish when 2 does not wait for 1.
+	gateCh := make(chan struct{})
+	cb := func(v Vertex) error {
+		switch v {
+		case 1:
+			select {
+			case <-gateCh:
+			case <-time.After(50 * time.Millisecond):
+				return fmt.Errorf("timeout 1 waiting for 2")
+			}
+
+		case 2:
+			defer close(gateCh)
+		}
+
+		return nil
+	}
+
+	w := &Walker{Callback: cb}
+	w.Update(&g)
+
+	// Wait
+	if err := w.Wait(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWalker_maxParallelism(t *testing.T) {
+	var g AcyclicGraph
+	for i := 0; i < 10; i++ {
+		g.Add(i)
+	}
+
+	// Count how many callbacks run at once and keep the high water mark
+	var running, max int32
+	cb := func(v Vertex) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	w := &Walker{Callback: cb, MaxParallelism: 3}
+	w.Update(&g)
+
+	if err := w.Wait(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if max > 3 {
+		t.Fatalf("bad: %d callbacks ran at once", max)
+	}
+}
+
+func TestWalker_failFast(t *testing.T) {
+	var g AcyclicGraph
+	for _, v := range []interface{}{"a", "b", "c", "d", "e"} {
+		g.Add(v)
+	}
+	g.Connect(BasicEdge("a", "b"))
+	g.Connect(BasicEdge("a", "c"))
+	g.Connect(BasicEdge("b", "d"))
+	g.Connect(BasicEdge("c", "e"))
+
+	// Record function
+	var order []interface{}
+	recordF := walkCbRecord(&order)
+
+	// "b" fails after its dependency "d" ran. "e" blocks until that failure
+	// is recorded, so by the time "c" is schedulable the walk is already
+	// failing fast and "c" must be skipped; "a" is skipped as a dependent.
+	var w *Walker
+	cb := func(v Vertex) error {
+		switch v {
+		case "b":
+			return fmt.Errorf("error!")
+
+		case "e":
+			deadline := time.Now().Add(5 * time.Second)
+			for !w.hasFailed() {
+				if time.Now().After(deadline) {
+					return fmt.Errorf("timeout e waiting for the failure of b")
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		return recordF(v)
+	}
+
+	w = &Walker{Callback: cb, Reverse: true, FailFast: true}
+	w.Update(&g)
+
+	// Wait
+	err := w.Wait()
+	if err == nil {
+		t.Fatal("expect error")
+	}
+
+	// Check the visited vertices: "c" and "a" must not have run
+	for _, v := range order {
+		if v == "a" || v == "c" {
+			t.Fatalf("bad: %#v", order)
+		}
+	}
+
+	// Check the aggregate error names the failed and the skipped vertices
+	for _, expected := range []string{
+		"b: error!",
+		"a: skipped after another vertex failed",
+		"c: skipped after another vertex failed",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q in error: %s", expected, err)
+		}
+	}
+}
+
 // walkCbRecord is a test helper callback that just records the order called.
 func walkCbRecord(order *[]interface{}) WalkFunc {
 	var l sync.Mutex
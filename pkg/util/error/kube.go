@@ -0,0 +1,28 @@
+package error
+
+import (
+	errorModel "WarpCloud/walm/pkg/models/error"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// TranslateKubeError wraps errors returned by the kubernetes clients into the
+// corresponding walm error types, so retry logic and the http error mapping
+// can react to the error class without parsing messages. The original error
+// stays reachable via errors.Unwrap. Errors of unrecognized classes and nil
+// are returned as is.
+func TranslateKubeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case apierrors.IsNotFound(err):
+		return errorModel.WrapNotFound(err, err.Error())
+	case apierrors.IsConflict(err):
+		return errorModel.WrapConflict(err, err.Error())
+	case apierrors.IsForbidden(err):
+		return errorModel.WrapValidation(err, err.Error())
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return errorModel.WrapTimeout(err, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package error
+
+import (
+	"errors"
+	"testing"
+
+	errorModel "WarpCloud/walm/pkg/models/error"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTranslateKubeError(t *testing.T) {
+	testResource := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	notFoundErr := TranslateKubeError(apierrors.NewNotFound(testResource, "test-deployment"))
+	assert.True(t, errorModel.IsNotFoundError(notFoundErr))
+	assert.True(t, apierrors.IsNotFound(errors.Unwrap(notFoundErr)))
+
+	conflictErr := TranslateKubeError(apierrors.NewConflict(testResource, "test-deployment", errors.New("the object has been modified")))
+	assert.True(t, errorModel.IsConflictError(conflictErr))
+	assert.True(t, apierrors.IsConflict(errors.Unwrap(conflictErr)))
+
+	forbiddenErr := TranslateKubeError(apierrors.NewForbidden(testResource, "test-deployment", errors.New("not allowed")))
+	assert.True(t, errorModel.IsValidationError(forbiddenErr))
+	assert.True(t, apierrors.IsForbidden(errors.Unwrap(forbiddenErr)))
+
+	timeoutErr := TranslateKubeError(apierrors.NewTimeoutError("request timed out", 1))
+	assert.True(t, errorModel.IsTimeoutError(timeoutErr))
+	assert.True(t, apierrors.IsTimeout(errors.Unwrap(timeoutErr)))
+
+	serverTimeoutErr := TranslateKubeError(apierrors.NewServerTimeout(testResource, "get", 1))
+	assert.True(t, errorModel.IsTimeoutError(serverTimeoutErr))
+	assert.True(t, apierrors.IsServerTimeout(errors.Unwrap(serverTimeoutErr)))
+
+	assert.Nil(t, TranslateKubeError(nil))
+
+	plainErr := errors.New("not a kube error")
+	assert.Equal(t, plainErr, TranslateKubeError(plainErr))
+
+	statusErr := &apierrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonBadRequest}}
+	assert.Equal(t, error(statusErr), TranslateKubeError(statusErr))
+}
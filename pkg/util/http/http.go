@@ -5,8 +5,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/emicklei/go-restful"
 	"net/http"
+	errorModel "WarpCloud/walm/pkg/models/error"
 	httpModel "WarpCloud/walm/pkg/models/http"
 	"fmt"
+	"time"
 )
 
 func WriteErrorResponse(response *restful.Response, code int, errMsg string) error {
@@ -17,6 +19,33 @@ func WriteNotFoundResponse(response *restful.Response, code int, errMsg string)
 	return response.WriteHeaderAndEntity(http.StatusNotFound, httpModel.ErrorMessageResponse{code, errMsg})
 }
 
+func WriteForbiddenResponse(response *restful.Response, code int, errMsg string) error {
+	return response.WriteHeaderAndEntity(http.StatusForbidden, httpModel.ErrorMessageResponse{code, errMsg})
+}
+
+// WriteTypedErrorResponse picks the http status code and the error code from
+// the error's type, so handlers do not repeat the type switch. Errors of
+// unknown types are treated as internal errors.
+func WriteTypedErrorResponse(response *restful.Response, err error, errMsg string) error {
+	status := http.StatusInternalServerError
+	code := errorModel.InternalErrorCode
+	switch {
+	case errorModel.IsValidationError(err):
+		status, code = http.StatusBadRequest, errorModel.ValidationErrorCode
+	case errorModel.IsNotFoundError(err):
+		status, code = http.StatusNotFound, errorModel.NotFoundErrorCode
+	case errorModel.IsChartNotFoundError(err):
+		status, code = http.StatusNotFound, errorModel.ChartNotFoundErrorCode
+	case errorModel.IsConflictError(err):
+		status, code = http.StatusConflict, errorModel.ConflictErrorCode
+	case errorModel.IsTimeoutError(err):
+		status, code = http.StatusGatewayTimeout, errorModel.TimeoutErrorCode
+	case errorModel.IsDependencyMissingError(err):
+		status, code = http.StatusUnprocessableEntity, errorModel.DependencyMissingErrorCode
+	}
+	return response.WriteHeaderAndEntity(status, httpModel.ErrorMessageResponse{code, errMsg})
+}
+
 func GetDeletePvcsQueryParam(request *restful.Request) (deletePvcs bool, err error) {
 	deletePvcsStr := request.QueryParameter("deletePvcs")
 	if len(deletePvcsStr) > 0 {
@@ -29,6 +58,131 @@ func GetDeletePvcsQueryParam(request *restful.Request) (deletePvcs bool, err err
 	return
 }
 
+func GetLimitQueryParam(request *restful.Request) (limit int, err error) {
+	limitStr := request.QueryParameter("limit")
+	if len(limitStr) > 0 {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter limit %s : %s", limitStr, err.Error())
+			return
+		}
+		if limit < 0 {
+			err = fmt.Errorf("query parameter limit %s should not be less than zero", limitStr)
+			logrus.Error(err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetDryRunQueryParam(request *restful.Request) (dryRun bool, err error) {
+	dryRunStr := request.QueryParameter("dryRun")
+	if len(dryRunStr) > 0 {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter dryRun %s : %s", dryRunStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetAdoptQueryParam(request *restful.Request) (adopt bool, err error) {
+	adoptStr := request.QueryParameter("adopt")
+	if len(adoptStr) > 0 {
+		adopt, err = strconv.ParseBool(adoptStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter adopt %s : %s", adoptStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetRevealQueryParam(request *restful.Request) (reveal bool, err error) {
+	revealStr := request.QueryParameter("reveal")
+	if len(revealStr) > 0 {
+		reveal, err = strconv.ParseBool(revealStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter reveal %s : %s", revealStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetSummaryQueryParam(request *restful.Request) (summary bool, err error) {
+	summaryStr := request.QueryParameter("summary")
+	if len(summaryStr) > 0 {
+		summary, err = strconv.ParseBool(summaryStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter summary %s : %s", summaryStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetDetailQueryParam(request *restful.Request) (detail bool, err error) {
+	detailStr := request.QueryParameter("detail")
+	if len(detailStr) > 0 {
+		detail, err = strconv.ParseBool(detailStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter detail %s : %s", detailStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetFastQueryParam(request *restful.Request) (fast bool, err error) {
+	fastStr := request.QueryParameter("fast")
+	if len(fastStr) > 0 {
+		fast, err = strconv.ParseBool(fastStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter fast %s : %s", fastStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetForceQueryParam(request *restful.Request) (force bool, err error) {
+	forceStr := request.QueryParameter("force")
+	if len(forceStr) > 0 {
+		force, err = strconv.ParseBool(forceStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter force %s : %s", forceStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetRefreshQueryParam(request *restful.Request) (refresh bool, err error) {
+	refreshStr := request.QueryParameter("refresh")
+	if len(refreshStr) > 0 {
+		refresh, err = strconv.ParseBool(refreshStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter refresh %s : %s", refreshStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
+func GetForceRefreshQueryParam(request *restful.Request) (forceRefresh bool, err error) {
+	forceRefreshStr := request.QueryParameter("forceRefresh")
+	if len(forceRefreshStr) > 0 {
+		forceRefresh, err = strconv.ParseBool(forceRefreshStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter forceRefresh %s : %s", forceRefreshStr, err.Error())
+			return
+		}
+	}
+	return
+}
+
 func GetAsyncQueryParam(request *restful.Request) (async bool, err error) {
 	asyncStr := request.QueryParameter("async")
 	if len(asyncStr) > 0 {
@@ -41,6 +195,25 @@ func GetAsyncQueryParam(request *restful.Request) (async bool, err error) {
 	return
 }
 
+// GetScheduledAtQueryParam parses the optional scheduledAt query parameter
+// as a RFC3339 timestamp, a zero time means the request is not scheduled.
+func GetScheduledAtQueryParam(request *restful.Request) (scheduledAt time.Time, err error) {
+	scheduledAtStr := request.QueryParameter("scheduledAt")
+	if len(scheduledAtStr) > 0 {
+		scheduledAt, err = time.Parse(time.RFC3339, scheduledAtStr)
+		if err != nil {
+			logrus.Errorf("failed to parse query parameter scheduledAt %s : %s", scheduledAtStr, err.Error())
+			return
+		}
+		if scheduledAt.Before(time.Now()) {
+			err = fmt.Errorf("query parameter scheduledAt %s should not be in the past", scheduledAtStr)
+			logrus.Error(err.Error())
+			return
+		}
+	}
+	return
+}
+
 func GetTimeoutSecQueryParam(request *restful.Request) (timeoutSec int64, err error) {
 	timeoutStr := request.QueryParameter("timeoutSec")
 	if len(timeoutStr) > 0 {
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"strings"
+)
+
+const MaskedSensitiveValue = "******"
+
+var sensitiveKeyPatterns = []string{"password", "secret", "token", "key"}
+
+// SetSensitiveKeyPatterns overrides the default patterns of config value keys
+// whose values should be masked. Empty patterns keep the default ones.
+func SetSensitiveKeyPatterns(patterns []string) {
+	if len(patterns) > 0 {
+		sensitiveKeyPatterns = patterns
+	}
+}
+
+// IsSensitiveKey tells whether the values of a config value key should be masked.
+// Matching is a case insensitive substring match against the sensitive key patterns.
+func IsSensitiveKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, pattern := range sensitiveKeyPatterns {
+		if strings.Contains(lowerKey, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSensitiveValues returns a copy of values with the values of sensitive keys
+// replaced by ******. Nested maps and lists are masked recursively, the original
+// values are never modified.
+func MaskSensitiveValues(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	maskedValues := map[string]interface{}{}
+	for key, value := range values {
+		maskedValues[key] = maskValue(value, IsSensitiveKey(key))
+	}
+	return maskedValues
+}
+
+func maskValue(value interface{}, sensitive bool) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		maskedValues := map[string]interface{}{}
+		for key, subValue := range typedValue {
+			maskedValues[key] = maskValue(subValue, sensitive || IsSensitiveKey(key))
+		}
+		return maskedValues
+	case []interface{}:
+		maskedValues := make([]interface{}, 0, len(typedValue))
+		for _, subValue := range typedValue {
+			maskedValues = append(maskedValues, maskValue(subValue, sensitive))
+		}
+		return maskedValues
+	default:
+		if sensitive {
+			return MaskedSensitiveValue
+		}
+		return value
+	}
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MaskSensitiveValues(t *testing.T) {
+	tests := []struct{
+		values      map[string]interface{}
+		expectedMap map[string]interface{}
+	} {
+		{
+			map[string]interface{}{
+				"image":    "zookeeper:5.2",
+				"password": "123456",
+			},
+			map[string]interface{}{
+				"image":    "zookeeper:5.2",
+				"password": MaskedSensitiveValue,
+			},
+		},
+		{
+			map[string]interface{}{
+				"zookeeper": map[string]interface{}{
+					"replicas":    float64(3),
+					"adminSecret": "123456",
+					"clientToken": "abcdef",
+				},
+			},
+			map[string]interface{}{
+				"zookeeper": map[string]interface{}{
+					"replicas":    float64(3),
+					"adminSecret": MaskedSensitiveValue,
+					"clientToken": MaskedSensitiveValue,
+				},
+			},
+		},
+		{
+			// all the leaves under a sensitive key are masked
+			map[string]interface{}{
+				"passwords": map[string]interface{}{
+					"admin": "123456",
+					"guest": "654321",
+				},
+			},
+			map[string]interface{}{
+				"passwords": map[string]interface{}{
+					"admin": MaskedSensitiveValue,
+					"guest": MaskedSensitiveValue,
+				},
+			},
+		},
+		{
+			map[string]interface{}{
+				"sshKeys": []interface{}{"ssh-rsa aaa", "ssh-rsa bbb"},
+				"ports":   []interface{}{float64(2181), float64(2182)},
+				"envs": []interface{}{
+					map[string]interface{}{
+						"name":     "ZK_PASSWORD",
+						"password": "123456",
+					},
+				},
+			},
+			map[string]interface{}{
+				"sshKeys": []interface{}{MaskedSensitiveValue, MaskedSensitiveValue},
+				"ports":   []interface{}{float64(2181), float64(2182)},
+				"envs": []interface{}{
+					map[string]interface{}{
+						"name":     "ZK_PASSWORD",
+						"password": MaskedSensitiveValue,
+					},
+				},
+			},
+		},
+		{
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		maskedValues := MaskSensitiveValues(test.values)
+		assert.Equal(t, test.expectedMap, maskedValues)
+	}
+}
+
+func Test_IsSensitiveKey(t *testing.T) {
+	tests := []struct{
+		key       string
+		sensitive bool
+	} {
+		{"password", true},
+		{"adminPassword", true},
+		{"Secret", true},
+		{"accessToken", true},
+		{"sshKey", true},
+		{"image", false},
+		{"replicas", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.sensitive, IsSensitiveKey(test.key))
+	}
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"encoding/json"
+)
+
+// PruneToFields keeps only the requested top-level json fields of an entity,
+// so large list responses do not serialize and transfer fields the caller
+// does not need. Fields that do not exist are silently dropped.
+func PruneToFields(entity interface{}, fields []string) (map[string]interface{}, error) {
+	entityBytes, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	entityMap := map[string]interface{}{}
+	err = json.Unmarshal(entityBytes, &entityMap)
+	if err != nil {
+		return nil, err
+	}
+	prunedEntity := map[string]interface{}{}
+	for _, field := range fields {
+		if value, ok := entityMap[field]; ok {
+			prunedEntity[field] = value
+		}
+	}
+	return prunedEntity, nil
+}
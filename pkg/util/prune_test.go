@@ -0,0 +1,22 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPruneToFields(t *testing.T) {
+	entity := struct {
+		Name   string                 `json:"name"`
+		Ready  bool                   `json:"ready"`
+		Values map[string]interface{} `json:"values"`
+	}{
+		Name:   "test-name",
+		Ready:  true,
+		Values: map[string]interface{}{"key": "value"},
+	}
+
+	pruned, err := PruneToFields(entity, []string{"name", "ready", "not-a-field"})
+	assert.IsType(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"name": "test-name", "ready": true}, pruned)
+}
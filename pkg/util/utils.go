@@ -1,7 +1,20 @@
 package util
 
+import (
+	"strings"
+)
 
 func MergeValues(dest map[string]interface{}, src map[string]interface{}, deleteKey bool) map[string]interface{} {
+	return mergeValues(dest, src, deleteKey, false)
+}
+
+// MergeValuesWithAppendList behaves like MergeValues, except that when a key holds a list
+// in both dest and src, the src list is appended to the dest list instead of replacing it.
+func MergeValuesWithAppendList(dest map[string]interface{}, src map[string]interface{}, deleteKey bool) map[string]interface{} {
+	return mergeValues(dest, src, deleteKey, true)
+}
+
+func mergeValues(dest map[string]interface{}, src map[string]interface{}, deleteKey bool, appendList bool) map[string]interface{} {
 	for k, v := range src {
 		if deleteKey && v == nil{
 			delete(dest, k)
@@ -13,6 +26,14 @@ func MergeValues(dest map[string]interface{}, src map[string]interface{}, delete
 			dest[k] = v
 			continue
 		}
+		if appendList {
+			if srcList, isList := v.([]interface{}); isList {
+				if destList, isDestList := dest[k].([]interface{}); isDestList {
+					dest[k] = append(destList, srcList...)
+					continue
+				}
+			}
+		}
 		nextMap, ok := v.(map[string]interface{})
 		// If it isn't another map, overwrite the value
 		if !ok {
@@ -27,7 +48,44 @@ func MergeValues(dest map[string]interface{}, src map[string]interface{}, delete
 			continue
 		}
 		// If we got to this point, it is a map in both, so merge them
-		dest[k] = MergeValues(destMap, nextMap, deleteKey)
+		dest[k] = mergeValues(destMap, nextMap, deleteKey, appendList)
 	}
 	return dest
 }
+
+// GetValueByPath reads a value from nested maps by a dot separated path.
+// The second return reports whether the full path exists, so a stored nil
+// can be told apart from a missing key.
+func GetValueByPath(values map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			return nil, false
+		}
+		if i == len(keys)-1 {
+			return value, true
+		}
+		values, ok = value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// SetValueByPath writes a value into nested maps by a dot separated path,
+// creating the intermediate maps. An intermediate key holding a non-map
+// value is overwritten with a map.
+func SetValueByPath(values map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nextValues, ok := values[key].(map[string]interface{})
+		if !ok {
+			nextValues = map[string]interface{}{}
+			values[key] = nextValues
+		}
+		values = nextValues
+	}
+	values[keys[len(keys)-1]] = value
+}
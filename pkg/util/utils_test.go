@@ -183,3 +183,167 @@ func Test_MergeValue(t *testing.T) {
 		assert.Equal(t, test.expectedMap, test.destMap)
 	}
 }
+
+func Test_MergeValuesWithAppendList(t *testing.T) {
+	tests := []struct{
+		destMap     map[string]interface{}
+		srcMap      map[string]interface{}
+		deleteKey   bool
+		expectedMap map[string]interface{}
+	} {
+		{
+			map[string]interface{}{
+				"key1": []interface{}{"value1"},
+			},
+			map[string]interface{}{
+				"key1": []interface{}{"value2"},
+			},
+			false,
+			map[string]interface{}{
+				"key1": []interface{}{"value1", "value2"},
+			},
+		},
+		{
+			map[string]interface{}{
+				"key1": "value1",
+			},
+			map[string]interface{}{
+				"key1": []interface{}{"value2"},
+			},
+			false,
+			map[string]interface{}{
+				"key1": []interface{}{"value2"},
+			},
+		},
+		{
+			map[string]interface{}{
+				"embededKey": map[string]interface{}{
+					"list": []interface{}{
+						map[string]interface{}{
+							"name": "value1",
+						},
+					},
+				},
+			},
+			map[string]interface{}{
+				"embededKey": map[string]interface{}{
+					"list": []interface{}{
+						map[string]interface{}{
+							"name": "value2",
+						},
+					},
+				},
+			},
+			false,
+			map[string]interface{}{
+				"embededKey": map[string]interface{}{
+					"list": []interface{}{
+						map[string]interface{}{
+							"name": "value1",
+						},
+						map[string]interface{}{
+							"name": "value2",
+						},
+					},
+				},
+			},
+		},
+		{
+			map[string]interface{}{
+				"key1": []interface{}{"value1"},
+				"key2": "value2",
+			},
+			map[string]interface{}{
+				"key2": nil,
+			},
+			true,
+			map[string]interface{}{
+				"key1": []interface{}{"value1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		MergeValuesWithAppendList(test.destMap, test.srcMap, test.deleteKey)
+		assert.Equal(t, test.expectedMap, test.destMap)
+	}
+}
+
+func Test_GetValueByPath(t *testing.T) {
+	values := map[string]interface{}{
+		"key1": "value1",
+		"key2": map[string]interface{}{
+			"key3": "value3",
+			"key4": nil,
+		},
+	}
+
+	tests := []struct {
+		path  string
+		value interface{}
+		found bool
+	}{
+		{"key1", "value1", true},
+		{"key2.key3", "value3", true},
+		{"key2.key4", nil, true},
+		{"key2.key5", nil, false},
+		{"key1.key3", nil, false},
+		{"key3", nil, false},
+	}
+
+	for _, test := range tests {
+		value, found := GetValueByPath(values, test.path)
+		assert.Equal(t, test.value, value)
+		assert.Equal(t, test.found, found)
+	}
+}
+
+func Test_SetValueByPath(t *testing.T) {
+	tests := []struct {
+		values      map[string]interface{}
+		path        string
+		value       interface{}
+		expectedMap map[string]interface{}
+	}{
+		{
+			map[string]interface{}{},
+			"key1",
+			"value1",
+			map[string]interface{}{
+				"key1": "value1",
+			},
+		},
+		{
+			map[string]interface{}{
+				"key1": map[string]interface{}{
+					"key2": "value2",
+				},
+			},
+			"key1.key3",
+			"value3",
+			map[string]interface{}{
+				"key1": map[string]interface{}{
+					"key2": "value2",
+					"key3": "value3",
+				},
+			},
+		},
+		{
+			map[string]interface{}{
+				"key1": "value1",
+			},
+			"key1.key2",
+			"value2",
+			map[string]interface{}{
+				"key1": map[string]interface{}{
+					"key2": "value2",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		SetValueByPath(test.values, test.path, test.value)
+		assert.Equal(t, test.expectedMap, test.values)
+	}
+}
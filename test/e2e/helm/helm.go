@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"context"
+
 	"WarpCloud/walm/pkg/helm/impl"
 	"WarpCloud/walm/pkg/k8s/cache/informer"
 	"WarpCloud/walm/pkg/models/common"
@@ -87,7 +89,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
 				"0.2.0", "7", 1)
@@ -115,7 +117,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, nil, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, nil, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
 				"0.2.0", "7", 1)
@@ -141,7 +143,7 @@ var _ = Describe("HelmRelease", func() {
 				ChartImage: framework.GetTomcatChartImage(),
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, nil, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, nil, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
 				"0.2.0", "7", 1)
@@ -175,7 +177,7 @@ var _ = Describe("HelmRelease", func() {
 			chartFiles, err := framework.LoadChartArchive(chartPath)
 			Expect(err).NotTo(HaveOccurred())
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, chartFiles, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, chartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "zookeeper-test", "", "zookeeper",
 				"6.1.0", "6.1", 1)
@@ -215,7 +217,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, true, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, true, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			manifest := fmt.Sprintf("\n---\napiVersion: v1\nkind: Service\nmetadata:\n  labels:\n    app: tomcat\n    chart: tomcat-0.2.0\n    heritage: Helm\n    release: tomcat-test\n  name: tomcat-test\n  namespace: %s\nspec:\n  ports:\n  - name: http\n    port: 80\n    protocol: TCP\n    targetPort: 8080\n  selector:\n    app: tomcat\n    release: tomcat-test\n  type: NodePort\n\n---\napiVersion: apps/v1beta2\nkind: Deployment\nmetadata:\n  labels:\n    app: tomcat\n    chart: tomcat-0.2.0\n    heritage: Helm\n    release: tomcat-test\n  name: tomcat-test\n  namespace: %s\nspec:\n  replicas: 1\n  selector:\n    matchLabels:\n      app: tomcat\n      release: tomcat-test\n  template:\n    metadata:\n      labels:\n        app: tomcat\n        release: tomcat-test\n    spec:\n      containers:\n      - image: tomcat:7.0\n        imagePullPolicy: Always\n        livenessProbe:\n          httpGet:\n            path: /sample\n            port: 8080\n          initialDelaySeconds: 60\n          periodSeconds: 30\n        name: tomcat\n        ports:\n        - containerPort: 8080\n          hostPort: 8009\n        readinessProbe:\n          failureThreshold: 6\n          httpGet:\n            path: /sample\n            port: 8080\n          initialDelaySeconds: 60\n          periodSeconds: 30\n        resources:\n          limits:\n            cpu: 0.2\n            memory: 200Mi\n          requests:\n            cpu: 0.1\n            memory: 100Mi\n        volumeMounts:\n        - mountPath: /usr/local/tomcat/webapps\n          name: app-volume\n      initContainers:\n      - command:\n        - sh\n        - -c\n        - cp /*.war /app\n        image: ananwaresystems/webarchive:1.0\n        imagePullPolicy: Always\n        name: war\n        volumeMounts:\n        - mountPath: /app\n          name: app-volume\n      volumes:\n      - emptyDir: {}\n        name: app-volume\n\n---\napiVersion: apiextensions.transwarp.io/v1beta1\nkind: ReleaseConfig\nmetadata:\n  creationTimestamp: null\n  name: tomcat-test\n  namespace: %s\nspec:\n  chartAppVersion: \"7\"\n  chartImage: \"\"\n  chartName: tomcat\n  chartVersion: 0.2.0\n  chartWalmVersion: v2\n  configValues: {}\n  dependencies: null\n  dependenciesConfigValues: {}\n  isomateConfig: null\n  outputConfig: {}\n  repo: \"\"\nstatus: {}\n",
 				namespace, namespace, namespace)
@@ -241,7 +243,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
 				"0.2.0", "7", 1)
@@ -269,7 +271,7 @@ var _ = Describe("HelmRelease", func() {
 				ReleaseLabels: map[string]string{"walm-test": "true"},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
 				"0.2.0", "7", 1)
@@ -300,7 +302,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
 				"0.2.0", "7", 1)
@@ -373,7 +375,7 @@ var _ = Describe("HelmRelease", func() {
 				chartFiles, err := framework.LoadChartArchive(chartPath)
 				Expect(err).NotTo(HaveOccurred())
 
-				_, err = helm.InstallOrCreateRelease(fixedNamespace1, releaseRequest, chartFiles, false, false, nil)
+				_, err = helm.InstallOrCreateRelease(context.Background(), fixedNamespace1, releaseRequest, chartFiles, false, false, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				By("install kafka which depends on zookeeper")
@@ -393,11 +395,11 @@ var _ = Describe("HelmRelease", func() {
 				chartFiles, err = framework.LoadChartArchive(chartPath)
 				Expect(err).NotTo(HaveOccurred())
 
-				_, err = helm.InstallOrCreateRelease(fixedNamespace1, releaseRequest, chartFiles, false, false, nil)
+				_, err = helm.InstallOrCreateRelease(context.Background(), fixedNamespace1, releaseRequest, chartFiles, false, false, nil)
 				Expect(err).To(HaveOccurred())
 
 				releaseRequest.Dependencies = map[string]string{"zookeeper": "zookeeper-test"}
-				releaseCache, err := helm.InstallOrCreateRelease(fixedNamespace1, releaseRequest, chartFiles, false, false, nil)
+				releaseCache, err := helm.InstallOrCreateRelease(context.Background(), fixedNamespace1, releaseRequest, chartFiles, false, false, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				assertReleaseCacheBasic(releaseCache, fixedNamespace1, "kafka-test", "", "kafka",
@@ -436,7 +438,7 @@ var _ = Describe("HelmRelease", func() {
 					},
 				}
 
-				releaseCache, err = helm.InstallOrCreateRelease(fixedNamespace2, releaseRequest, chartFiles, false, false, nil)
+				releaseCache, err = helm.InstallOrCreateRelease(context.Background(), fixedNamespace2, releaseRequest, chartFiles, false, false, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				assertReleaseCacheBasic(releaseCache, fixedNamespace2, "kafka-test", "", "kafka",
@@ -477,7 +479,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			_, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+			_, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			releaseRequest.ConfigValues = map[string]interface{}{
@@ -485,7 +487,7 @@ var _ = Describe("HelmRelease", func() {
 			}
 			releaseInfo := &release.ReleaseInfoV2{}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, true, releaseInfo)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, true, releaseInfo)
 			Expect(err).NotTo(HaveOccurred())
 
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
@@ -510,7 +512,7 @@ var _ = Describe("HelmRelease", func() {
 			releaseInfo.ConfigValues = map[string]interface{}{
 				"replicaCount": 2,
 			}
-			releaseCache, err = helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, true, releaseInfo)
+			releaseCache, err = helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, true, releaseInfo)
 			Expect(err).NotTo(HaveOccurred())
 
 			assertReleaseCacheBasic(releaseCache, namespace, "tomcat-test", "", "tomcat",
@@ -530,7 +532,7 @@ var _ = Describe("HelmRelease", func() {
 				},
 			}
 
-			releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+			releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("pause release")
@@ -633,10 +635,10 @@ var _ = Describe("HelmRelease", func() {
 					},
 				}
 
-				_, err := helm.InstallOrCreateRelease(namespace, releaseRequest, tomcatChartFiles, false, false, nil)
+				_, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, tomcatChartFiles, false, false, nil)
 				Expect(err).NotTo(HaveOccurred())
 
-				_, err = helm.InstallOrCreateRelease(anotherNamespace, releaseRequest, tomcatChartFiles, false, false, nil)
+				_, err = helm.InstallOrCreateRelease(context.Background(), anotherNamespace, releaseRequest, tomcatChartFiles, false, false, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				releaseCaches, err := helm.ListAllReleases()
@@ -671,10 +673,10 @@ var _ = Describe("HelmRelease", func() {
 				Expect(releaseCache1.MetaInfoValues).To(Equal(mataInfoParams))
 
 				By("delete release")
-				err = helm.DeleteRelease(namespace, "tomcat-test")
+				err = helm.DeleteRelease(context.Background(), namespace, "tomcat-test")
 				Expect(err).NotTo(HaveOccurred())
 
-				err = helm.DeleteRelease(namespace, "not-existed")
+				err = helm.DeleteRelease(context.Background(), namespace, "not-existed")
 				Expect(err).NotTo(HaveOccurred())
 
 				releaseCaches, err = helm.ListAllReleases()
@@ -747,7 +749,7 @@ var _ = Describe("HelmRelease", func() {
 					},
 				}
 
-				releaseCache, err := helm.InstallOrCreateRelease(namespace, releaseRequest, zookeeperChartFiles, false, false, nil)
+				releaseCache, err := helm.InstallOrCreateRelease(context.Background(), namespace, releaseRequest, zookeeperChartFiles, false, false, nil)
 				Expect(err).NotTo(HaveOccurred())
 				assertReleaseCacheBasic(releaseCache, namespace, "zookeeper-test", "", "zookeeper",
 					"5.2.0", "5.2", 1)
@@ -825,9 +827,9 @@ var _ = Describe("HelmRelease", func() {
 			//		Expect(releaseCache.ReleaseResourceMetas).To(Equal(getZookeeperDefaultV1ReleaseResourceMeta(anotherNamespace, "helmreleasetest-zk")))
 			//
 			//		By("delete v1 release")
-			//		err = helm.DeleteRelease(namespace, "helmreleasetest-zk")
+			//		err = helm.DeleteRelease(context.Background(), namespace, "helmreleasetest-zk")
 			//		Expect(err).NotTo(HaveOccurred())
-			//		err = helm.DeleteRelease(namespace, "not-existed")
+			//		err = helm.DeleteRelease(context.Background(), namespace, "not-existed")
 			//		Expect(err).NotTo(HaveOccurred())
 			//
 			//		releaseCaches, err = helm.ListAllReleases()